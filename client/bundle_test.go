@@ -0,0 +1,219 @@
+package client_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"networkDev/models"
+	"networkDev/services"
+)
+
+// pythonDriver 使用接入包中的 Python 接入代码调用接口，结果以 JSON 输出
+// 配置从标准输入读取：url、tampered_url、secret、keys（接口类型 -> [提交密钥, 返回密钥]）、card
+const pythonDriver = `
+import json, sys
+sys.path.insert(0, sys.argv[1])
+import netdev_client as n
+
+cfg = json.load(sys.stdin)
+keys = dict((int(k), tuple(v)) for k, v in cfg["keys"].items())
+client = n.Client(cfg["url"], cfg["secret"], keys)
+out = {
+    "bulletin": client.call(1),
+    "card": client.call(4, {"card": cfg["card"]}),
+}
+try:
+    client.call(4, {"card": "NOT-A-CARD"})
+except n.APIError as e:
+    out["missing"] = e.error
+try:
+    n.Client(cfg["url"], "wrong-secret", keys).call(1)
+except n.APIError as e:
+    out["forged"] = e.error
+try:
+    n.Client(cfg["tampered_url"], cfg["secret"], keys).call(1)
+except n.SignatureError:
+    out["tampered"] = True
+print(json.dumps(out, ensure_ascii=False))
+`
+
+// bundleApp 接入包测试使用的独立应用
+type bundleApp struct {
+	app   *models.App
+	apis  []models.API
+	keys  map[int][2]string // 接口类型 -> [提交密钥, 返回密钥]
+	card  string
+	proxy *httptest.Server // 转发到服务端并篡改响应签名的代理
+}
+
+// newBundleApp 创建接入包测试应用
+// 公告接口 RC4 提交、易加密返回，卡密信息接口易加密提交、RC4 返回，不依赖各语言的 RSA 库
+func newBundleApp(t *testing.T) *bundleApp {
+	t.Helper()
+	announcement := base64.StdEncoding.EncodeToString([]byte("接入包公告"))
+	b := &bundleApp{
+		app:  &models.App{Name: "bundle", Status: 1, Version: "1.0.0", Announcement: announcement},
+		keys: make(map[int][2]string),
+	}
+	if err := env.db.Create(b.app).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		env.db.Where("app_uuid = ?", b.app.UUID).Delete(&models.Card{})
+		env.db.Where("app_uuid = ?", b.app.UUID).Delete(&models.API{})
+		env.db.Delete(b.app)
+	})
+
+	algorithms := map[int][2]int{
+		models.APITypeGetBulletin: {models.AlgorithmRC4, models.AlgorithmEasy},
+		models.APITypeGetCardInfo: {models.AlgorithmEasy, models.AlgorithmRC4},
+	}
+	for apiType, pair := range algorithms {
+		api := models.API{AppUUID: b.app.UUID, APIType: apiType, Status: 1, SubmitAlgorithm: pair[0], ReturnAlgorithm: pair[1]}
+		var err error
+		if api.SubmitPublicKey, api.SubmitPrivateKey, err = generateKeys(pair[0]); err != nil {
+			t.Fatal(err)
+		}
+		if api.ReturnPublicKey, api.ReturnPrivateKey, err = generateKeys(pair[1]); err != nil {
+			t.Fatal(err)
+		}
+		if err := env.db.Create(&api).Error; err != nil {
+			t.Fatal(err)
+		}
+		b.apis = append(b.apis, api)
+		b.keys[apiType] = [2]string{api.SubmitPrivateKey, api.ReturnPrivateKey}
+	}
+	card := models.Card{AppUUID: b.app.UUID, Duration: 60}
+	if err := env.db.Create(&card).Error; err != nil {
+		t.Fatal(err)
+	}
+	b.card = card.CardKey
+
+	b.proxy = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := http.Post(env.server.URL+r.URL.Path, "application/json", r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		var body map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		if data, ok := body["data"].(map[string]interface{}); ok {
+			data["sign"] = "0000"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(b.proxy.Close)
+	return b
+}
+
+// config 传给接入代码驱动程序的配置
+func (b *bundleApp) config() []byte {
+	config, _ := json.Marshal(map[string]interface{}{
+		"url":          env.server.URL,
+		"tampered_url": b.proxy.URL,
+		"secret":       b.app.Secret,
+		"keys":         b.keys,
+		"card":         b.card,
+	})
+	return config
+}
+
+// check 校验驱动程序输出的调用结果
+func (b *bundleApp) check(t *testing.T, output []byte) {
+	t.Helper()
+	var result struct {
+		Bulletin struct {
+			Announcement string `json:"announcement"`
+		} `json:"bulletin"`
+		Card struct {
+			Status   int `json:"status"`
+			Duration int `json:"duration"`
+		} `json:"card"`
+		Missing  string `json:"missing"`
+		Forged   string `json:"forged"`
+		Tampered bool   `json:"tampered"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("解析输出失败: %v\n%s", err, output)
+	}
+	if result.Bulletin.Announcement != "接入包公告" {
+		t.Errorf("公告 = %q", result.Bulletin.Announcement)
+	}
+	if result.Card.Status != models.CardStatusUnused || result.Card.Duration != 60 {
+		t.Errorf("卡密信息 = %+v", result.Card)
+	}
+	if result.Missing != "CARD_NOT_FOUND" || result.Forged != "SIGNATURE_INVALID" {
+		t.Errorf("错误码 = %q / %q", result.Missing, result.Forged)
+	}
+	if !result.Tampered {
+		t.Error("篡改签名的响应应被拒绝")
+	}
+}
+
+// TestPythonBundleClient 接入包生成的 Python 代码按协议签名、加密，能被 ClientHandler 接受并校验其响应签名
+func TestPythonBundleClient(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("未安装 python3")
+	}
+	b := newBundleApp(t)
+	dir := t.TempDir()
+	writeBundleFile(t, services.BuildIntegrationManifest(b.app, b.apis), "python/netdev_client.py", filepath.Join(dir, "netdev_client.py"))
+
+	cmd := exec.Command(python, "-c", pythonDriver, dir)
+	cmd.Stdin = bytes.NewReader(b.config())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("运行 Python 接入代码失败: %v\n%s", err, stderr.String())
+	}
+	b.check(t, output)
+}
+
+// writeBundleFile 生成接入包并把其中一个文件写到 dest
+func writeBundleFile(t *testing.T, manifest *services.IntegrationManifest, name, dest string) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := services.WriteIntegrationBundle(&buf, manifest); err != nil {
+		t.Fatalf("WriteIntegrationBundle: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range zr.File {
+		if file.Name != name {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dest, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	t.Fatalf("接入包中没有 %s", name)
+}
@@ -0,0 +1,216 @@
+package client
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"networkDev/utils/encrypt"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 算法类型，与 models.Algorithm* 取值一致
+// 客户端包不依赖 models，避免引入数据库相关依赖
+const (
+	AlgorithmNone       = 0 // 不加密
+	AlgorithmRC4        = 1 // RC4
+	AlgorithmRSA        = 2 // RSA
+	AlgorithmRSADynamic = 3 // RSA（动态）
+	AlgorithmEasy       = 4 // 易加密
+)
+
+// 易加密字符偏移量
+// 服务端 encrypt.DecryptWithKey 还原时加 40，encrypt.EncryptWithKey 加密时减 207，
+// 两个方向并不对称，客户端需按相反的偏移量处理
+const (
+	easySubmitOffset = 40  // 提交方向
+	easyReturnOffset = 207 // 返回方向
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Cipher 客户端一侧的加解密器
+// 与服务端 services.APICipher 使用相同的密钥格式与算法实现：
+// - 提交方向：使用后台「接口」页面的提交公钥（RSA/RSA动态）或提交密钥（RC4/易加密）加密请求数据
+// - 返回方向：使用返回私钥（RSA/RSA动态）或返回密钥（RC4/易加密）解密响应数据
+type Cipher struct {
+	Algorithm  int
+	rc4Key     []byte
+	easyKey    []int
+	publicKey  *rsa.PublicKey
+	privateKey *rsa.PrivateKey
+}
+
+// ============================================================================
+// 构造函数
+// ============================================================================
+
+// NewCipher 根据算法与密钥创建加解密器
+// - RC4：key 为十六进制密钥（兼容 base64），publicKey 不使用
+// - RSA/RSA动态：publicKey、key 为 PEM，提交方向只需公钥，返回方向只需私钥
+// - 易加密：key 为逗号分隔的整数数组，publicKey 不使用
+//
+// 易加密的 Encrypt 生成服务端可解密的提交数据，Decrypt 还原服务端返回的数据
+func NewCipher(algorithm int, publicKey, key string) (*Cipher, error) {
+	c := &Cipher{Algorithm: algorithm}
+
+	switch algorithm {
+	case AlgorithmNone:
+	case AlgorithmRC4:
+		rc4Key, err := parseRC4Key(key)
+		if err != nil {
+			return nil, err
+		}
+		c.rc4Key = rc4Key
+	case AlgorithmRSA, AlgorithmRSADynamic:
+		var err error
+		if strings.TrimSpace(publicKey) != "" {
+			if c.publicKey, err = encrypt.PublicKeyFromPEM(publicKey); err != nil {
+				return nil, err
+			}
+		}
+		if strings.TrimSpace(key) != "" {
+			if c.privateKey, err = encrypt.PrivateKeyFromPEM(key); err != nil {
+				return nil, err
+			}
+		}
+		if c.publicKey == nil && c.privateKey == nil {
+			return nil, errors.New("RSA公钥与私钥不能同时为空")
+		}
+	case AlgorithmEasy:
+		c.easyKey = encrypt.ParseKeyFromString(key)
+		if len(c.easyKey) == 0 {
+			return nil, errors.New("易加密密钥为空或格式错误")
+		}
+	default:
+		return nil, fmt.Errorf("不支持的算法类型: %d", algorithm)
+	}
+	return c, nil
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Encrypt 按算法加密数据，不加密时原样返回
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	switch c.Algorithm {
+	case AlgorithmNone:
+		return plaintext, nil
+	case AlgorithmRC4:
+		return encrypt.NewRC4Encrypt(c.rc4Key).Encrypt(plaintext)
+	case AlgorithmRSA:
+		if c.publicKey == nil {
+			return "", errors.New("未配置RSA公钥")
+		}
+		return encrypt.NewRSAEncrypt(c.publicKey, c.privateKey).EncryptLargeData(plaintext)
+	case AlgorithmRSADynamic:
+		if c.publicKey == nil {
+			return "", errors.New("未配置RSA公钥")
+		}
+		return encrypt.NewRSADynamicEncryptFromKeys(c.publicKey, c.privateKey).Encrypt(plaintext)
+	case AlgorithmEasy:
+		return easyEncode(plaintext, c.easyKey, easySubmitOffset), nil
+	default:
+		return "", fmt.Errorf("不支持的算法类型: %d", c.Algorithm)
+	}
+}
+
+// Decrypt 按算法解密数据，不加密时原样返回
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	switch c.Algorithm {
+	case AlgorithmNone:
+		return ciphertext, nil
+	case AlgorithmRC4:
+		return encrypt.NewRC4Encrypt(c.rc4Key).Decrypt(ciphertext)
+	case AlgorithmRSA:
+		if c.privateKey == nil {
+			return "", errors.New("未配置RSA私钥")
+		}
+		return encrypt.NewRSAEncrypt(c.publicKey, c.privateKey).DecryptLargeData(ciphertext)
+	case AlgorithmRSADynamic:
+		if c.privateKey == nil {
+			return "", errors.New("未配置RSA私钥")
+		}
+		return encrypt.NewRSADynamicEncryptFromKeys(c.publicKey, c.privateKey).Decrypt(ciphertext)
+	case AlgorithmEasy:
+		return easyDecode(ciphertext, c.easyKey, easyReturnOffset)
+	default:
+		return "", fmt.Errorf("不支持的算法类型: %d", c.Algorithm)
+	}
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// parseRC4Key 解析RC4密钥
+// 后台生成的密钥为十六进制字符串，无法按十六进制解析时尝试 base64
+func parseRC4Key(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, errors.New("RC4密钥不能为空")
+	}
+	if decoded, err := hex.DecodeString(key); err == nil {
+		return decoded, nil
+	}
+	return encrypt.ParseRC4KeyFromString(key)
+}
+
+// easyEncode 易加密编码：每个字节减去偏移量后与密钥异或，以十六进制逗号分隔后 base64
+func easyEncode(input string, key []int, offset int) string {
+	if input == "" || len(key) == 0 {
+		return ""
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(input); i++ {
+		code := (int(input[i]) - offset) ^ key[i%len(key)]
+		if code < 0 {
+			code = -code
+			result.WriteString("-")
+		}
+		result.WriteString(strconv.FormatInt(int64(code), 16))
+		result.WriteString(",")
+	}
+	return base64.StdEncoding.EncodeToString([]byte(result.String()))
+}
+
+// easyDecode 易加密解码：easyEncode 的逆过程
+func easyDecode(input string, key []int, offset int) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(input)
+	if err != nil {
+		return "", fmt.Errorf("易加密解密失败: %w", err)
+	}
+
+	var result strings.Builder
+	for i, part := range strings.Split(string(decoded), ",") {
+		if part == "" {
+			continue
+		}
+		negative := strings.HasPrefix(part, "-")
+		val, err := strconv.ParseInt(strings.TrimPrefix(part, "-"), 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("易加密解密失败: %w", err)
+		}
+		code := int(val)
+		if negative {
+			code = -code
+		}
+		result.WriteByte(byte((code ^ key[i%len(key)]) + offset))
+	}
+	return result.String(), nil
+}
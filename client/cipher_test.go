@@ -0,0 +1,153 @@
+package client_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"networkDev/client"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/encrypt"
+)
+
+// TestCipherInterop 客户端与服务端加解密器互通：客户端加密的提交数据服务端可解密，服务端加密的返回数据客户端可解密
+func TestCipherInterop(t *testing.T) {
+	short := `{"card":"ABCD-1234","machine_code":"机器码-01","t":1760000000}`
+	long := `{"data":"` + strings.Repeat("变量值0123456789", 60) + `"}`
+
+	algorithms := []struct {
+		name      string
+		algorithm int
+		payloads  []string
+	}{
+		{"none", models.AlgorithmNone, []string{short, long}},
+		{"rc4", models.AlgorithmRC4, []string{short, long}},
+		{"rsa", models.AlgorithmRSA, []string{short, long}},
+		{"rsa_dynamic", models.AlgorithmRSADynamic, []string{short}},
+		{"easy", models.AlgorithmEasy, []string{short, long}},
+	}
+	for _, tc := range algorithms {
+		t.Run(tc.name, func(t *testing.T) {
+			submitPublic, submitPrivate, err := generateKeys(tc.algorithm)
+			if err != nil {
+				t.Fatalf("生成提交密钥失败: %v", err)
+			}
+			returnPublic, returnPrivate, err := generateKeys(tc.algorithm)
+			if err != nil {
+				t.Fatalf("生成返回密钥失败: %v", err)
+			}
+
+			// 客户端只持有提交公钥与返回私钥（RC4/易加密为对应密钥）
+			submitClient, err := client.NewCipher(tc.algorithm, submitPublic, submitKeyForClient(tc.algorithm, submitPrivate))
+			if err != nil {
+				t.Fatalf("创建客户端提交加密器失败: %v", err)
+			}
+			returnClient, err := client.NewCipher(tc.algorithm, "", returnPrivate)
+			if err != nil {
+				t.Fatalf("创建客户端返回解密器失败: %v", err)
+			}
+			submitServer, err := services.NewAPICipher(tc.algorithm, submitPublic, submitPrivate)
+			if err != nil {
+				t.Fatalf("创建服务端提交解密器失败: %v", err)
+			}
+			returnServer, err := services.NewAPICipher(tc.algorithm, returnPublic, returnPrivate)
+			if err != nil {
+				t.Fatalf("创建服务端返回加密器失败: %v", err)
+			}
+
+			for _, payload := range tc.payloads {
+				encrypted, err := submitClient.Encrypt(payload)
+				if err != nil {
+					t.Fatalf("客户端加密失败: %v", err)
+				}
+				if tc.algorithm != models.AlgorithmNone && encrypted == payload {
+					t.Fatal("加密后的数据不应与明文相同")
+				}
+				if plain, err := submitServer.Decrypt(encrypted); err != nil || plain != payload {
+					t.Fatalf("服务端解密提交数据 = %q, %v", plain, err)
+				}
+
+				encrypted, err = returnServer.Encrypt(payload)
+				if err != nil {
+					t.Fatalf("服务端加密失败: %v", err)
+				}
+				if plain, err := returnClient.Decrypt(encrypted); err != nil || plain != payload {
+					t.Fatalf("客户端解密返回数据 = %q, %v", plain, err)
+				}
+			}
+		})
+	}
+}
+
+// TestCipherErrors 密钥缺失或格式错误时返回错误
+func TestCipherErrors(t *testing.T) {
+	if _, err := client.NewCipher(99, "", ""); err == nil {
+		t.Error("不支持的算法应返回错误")
+	}
+	if _, err := client.NewCipher(client.AlgorithmRC4, "", " "); err == nil {
+		t.Error("RC4密钥为空应返回错误")
+	}
+	if _, err := client.NewCipher(client.AlgorithmRSA, "", ""); err == nil {
+		t.Error("RSA公钥与私钥同时为空应返回错误")
+	}
+	if _, err := client.NewCipher(client.AlgorithmRSA, "not a pem", ""); err == nil {
+		t.Error("RSA公钥格式错误应返回错误")
+	}
+	if _, err := client.NewCipher(client.AlgorithmEasy, "", "abc"); err == nil {
+		t.Error("易加密密钥格式错误应返回错误")
+	}
+
+	// 只有私钥时不能加密提交数据，只有公钥时不能解密返回数据
+	public, private, err := generateKeys(models.AlgorithmRSA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryptOnly, err := client.NewCipher(client.AlgorithmRSA, "", private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decryptOnly.Encrypt("data"); err == nil {
+		t.Error("未配置公钥时加密应返回错误")
+	}
+	encryptOnly, err := client.NewCipher(client.AlgorithmRSA, public, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := encryptOnly.Decrypt("data"); err == nil {
+		t.Error("未配置私钥时解密应返回错误")
+	}
+
+	easy, err := client.NewCipher(client.AlgorithmEasy, "", "1,2,3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := easy.Decrypt("!!not base64!!"); err == nil {
+		t.Error("易加密数据格式错误应返回错误")
+	}
+}
+
+// generateKeys 按算法生成接口密钥，返回公钥与私钥（对称算法只有私钥）
+func generateKeys(algorithm int) (string, string, error) {
+	switch algorithm {
+	case models.AlgorithmRC4:
+		key, err := encrypt.GenerateRC4Key(8)
+		return "", strings.ToUpper(hex.EncodeToString(key)), err
+	case models.AlgorithmRSA:
+		return encrypt.GenerateRSAKeyPairPEM(2048)
+	case models.AlgorithmRSADynamic:
+		return encrypt.GenerateRSADynamicKeyPair(2048)
+	case models.AlgorithmEasy:
+		key, _, err := encrypt.GenerateEasyKey()
+		return "", encrypt.FormatKeyAsString(key), err
+	}
+	return "", "", nil
+}
+
+// submitKeyForClient 客户端提交方向使用的密钥：RSA 只需公钥，RC4/易加密使用同一个密钥
+func submitKeyForClient(algorithm int, key string) string {
+	if algorithm == models.AlgorithmRSA || algorithm == models.AlgorithmRSADynamic {
+		return ""
+	}
+	return key
+}
@@ -0,0 +1,533 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"networkDev/utils/encrypt"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 接口类型，与 models.APIType* 取值一致
+const (
+	APITypeBulletin     = 1  // 获取程序公告
+	APITypeUpdateURL    = 2  // 获取更新地址
+	APITypeCheckVersion = 3  // 检测最新版本
+	APITypeCardInfo     = 4  // 获取卡密信息
+	APITypeCardLogin    = 10 // 卡密登录
+	APITypeLogin        = 20 // 用户登录
+	APITypeRegister     = 21 // 用户注册
+	APITypeRecharge     = 22 // 用户充值
+	APITypeLogout       = 30 // 退出登录
+	APITypeExpiry       = 40 // 获取到期时间
+	APITypeHeartbeat    = 41 // 检测账号状态
+	APITypeAppData      = 42 // 获取程序数据
+	APITypeVariable     = 43 // 获取变量数据
+	APITypeFunction     = 44 // 执行远程函数
+	APITypeChangePwd    = 50 // 修改账号密码
+	APITypeRebindMac    = 51 // 机器码转绑
+	APITypeRebindIP     = 52 // IP转绑
+	APITypeSendReset    = 53 // 发送找回密码验证码
+	APITypeResetPwd     = 54 // 找回账号密码
+	APITypeBan          = 60 // 封停用户
+	APITypeBlacklist    = 61 // 添加黑名单
+	APITypeDeductTime   = 62 // 扣除时间
+)
+
+// clientPath 客户端验证接口路径
+const clientPath = "/api/client"
+
+// nonceBytes 请求随机串的随机字节数
+const nonceBytes = 16
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Config 客户端配置
+type Config struct {
+	BaseURL     string       // 服务端地址，如 https://auth.example.com
+	AppUUID     string       // 应用UUID
+	Secret      string       // 应用密钥，用于请求与响应签名
+	Version     string       // 客户端版本号，用于检测更新与强制更新
+	MachineCode string       // 机器码，应用开启机器验证或注册限制时必须设置
+	HTTPClient  *http.Client // 为空时使用超时 10 秒的默认客户端
+}
+
+// Client 客户端验证接口调用方
+// 登录成功后保存会话令牌，后续需要登录的接口自动携带；可以在多个 goroutine 中使用
+type Client struct {
+	config  Config
+	http    *http.Client
+	mu      sync.RWMutex
+	ciphers map[int]cipherPair
+	token   string
+	status  *Status
+}
+
+// Params 业务参数，各接口按需填写
+// machine_code、version、token 为空时使用客户端配置与当前会话
+type Params struct {
+	Card        string        `json:"card,omitempty"`
+	Username    string        `json:"username,omitempty"`
+	Password    string        `json:"password,omitempty"`
+	NewPassword string        `json:"new_password,omitempty"`
+	Email       string        `json:"email,omitempty"`
+	Code        string        `json:"code,omitempty"`
+	MachineCode string        `json:"machine_code,omitempty"`
+	Token       string        `json:"token,omitempty"`
+	Version     string        `json:"version,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	Args        []interface{} `json:"args,omitempty"`
+	Minutes     int           `json:"minutes,omitempty"`
+	Reason      string        `json:"reason,omitempty"`
+}
+
+// Status 登录主体的到期状态
+type Status struct {
+	Kind          string     `json:"kind"`           // card 或 account
+	Name          string     `json:"name"`           // 卡密或用户名
+	EndAt         *time.Time `json:"end_at"`         // 到期时间
+	Remaining     int64      `json:"remaining"`      // 剩余秒数
+	CheckInterval int        `json:"check_interval"` // 建议的心跳间隔（秒）
+}
+
+// LoginResult 登录结果
+type LoginResult struct {
+	Token string `json:"token"`
+	Status
+}
+
+// UpdateInfo 版本更新信息
+type UpdateInfo struct {
+	Version      string `json:"version"`       // 最新版本号
+	HasUpdate    bool   `json:"has_update"`    // 当前版本是否低于最新版本
+	ForceUpdate  bool   `json:"force_update"`  // 是否强制更新
+	DownloadType int    `json:"download_type"` // 0=不启用更新，1=自动更新，2=手动下载
+	DownloadURL  string `json:"download_url"`
+}
+
+// CardInfo 卡密信息
+type CardInfo struct {
+	Card       string     `json:"card"`
+	Status     int        `json:"status"`
+	StatusText string     `json:"status_text"`
+	Duration   int        `json:"duration"` // 面值时长（分钟）
+	UsedAt     *time.Time `json:"used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // 激活截止时间
+	EndAt      *time.Time `json:"end_at"`     // 卡密登录的到期时间
+}
+
+// RegisterResult 注册结果
+type RegisterResult struct {
+	Username string     `json:"username"`
+	EndAt    *time.Time `json:"end_at"`
+	Trial    bool       `json:"trial"` // 是否获得试用时长
+}
+
+// RechargeResult 充值结果
+type RechargeResult struct {
+	Username string     `json:"username"`
+	Duration int        `json:"duration"` // 充值时长（分钟）
+	EndAt    *time.Time `json:"end_at"`
+}
+
+// RebindResult 换绑结果
+type RebindResult struct {
+	EndAt    *time.Time `json:"end_at"`
+	Rebinds  int        `json:"rebinds"`  // 当前计数周期内已换绑次数
+	Deducted int        `json:"deducted"` // 本次扣除的时长（分钟）
+}
+
+// Identity 换绑时使用的身份凭据，卡密登录填写 Card，账号登录填写 Username 与 Password
+type Identity struct {
+	Card     string
+	Username string
+	Password string
+}
+
+// cipherPair 一个接口的提交与返回加解密器
+type cipherPair struct {
+	submit *Cipher
+	ret    *Cipher
+}
+
+// request 请求体
+type request struct {
+	AppUUID   string `json:"app_uuid"`
+	APIType   int    `json:"api_type"`
+	Timestamp int64  `json:"timestamp"`
+	Nonce     string `json:"nonce"`
+	Data      string `json:"data"`
+	Sign      string `json:"sign"`
+}
+
+// signedPayload 成功响应的 data
+type signedPayload struct {
+	Payload   string `json:"payload"`
+	Timestamp int64  `json:"timestamp"`
+	Nonce     string `json:"nonce"`
+	Sign      string `json:"sign"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// ErrResponseSignature 响应签名校验失败，响应可能被篡改或来自伪造的服务端
+var ErrResponseSignature = errors.New("响应签名校验失败")
+
+// ============================================================================
+// 构造函数
+// ============================================================================
+
+// New 创建客户端
+// 未设置加解密器的接口按不加密处理，需要与后台接口的算法设置一致
+func New(config Config) *Client {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &Client{config: config, http: httpClient, ciphers: make(map[int]cipherPair)}
+}
+
+// ============================================================================
+// 配置方法
+// ============================================================================
+
+// SetCipher 设置接口的提交与返回加解密器
+func (c *Client) SetCipher(apiType int, submit, ret *Cipher) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ciphers[apiType] = cipherPair{submit: submit, ret: ret}
+}
+
+// UseManifest 按接入包清单设置所有接口的加解密器
+// keys 按接口类型提供清单中不包含的密钥，清单标记需要密钥而未提供时返回错误
+func (c *Client) UseManifest(manifest *Manifest, keys map[int]ManifestKeys) error {
+	for _, api := range manifest.APIs {
+		key := keys[api.APIType]
+		if (api.Submit.KeyRequired && key.Submit == "") || (api.Return.KeyRequired && key.Return == "") {
+			return fmt.Errorf("接口 %d 缺少接入包之外的密钥", api.APIType)
+		}
+		submit, err := NewCipher(api.Submit.Algorithm, api.Submit.PublicKey, key.Submit)
+		if err != nil {
+			return fmt.Errorf("接口 %d 提交密钥: %w", api.APIType, err)
+		}
+		ret, err := NewCipher(api.Return.Algorithm, api.Return.PublicKey, key.Return)
+		if err != nil {
+			return fmt.Errorf("接口 %d 返回密钥: %w", api.APIType, err)
+		}
+		c.SetCipher(api.APIType, submit, ret)
+	}
+	return nil
+}
+
+// Token 当前会话令牌，未登录时为空
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken 设置会话令牌，用于恢复之前保存的会话
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// ============================================================================
+// 接口方法
+// ============================================================================
+
+// Call 调用任意接口类型，返回数据解码到 result（可为 nil）
+// 业务错误返回 *Error，可使用 errors.Is(err, constants.CodeXxx) 判断
+func (c *Client) Call(ctx context.Context, apiType int, params Params, result interface{}) error {
+	c.mu.RLock()
+	pair := c.ciphers[apiType]
+	if params.Token == "" {
+		params.Token = c.token
+	}
+	c.mu.RUnlock()
+	if params.MachineCode == "" {
+		params.MachineCode = c.config.MachineCode
+	}
+	if params.Version == "" {
+		params.Version = c.config.Version
+	}
+
+	plaintext, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	data := string(plaintext)
+	if pair.submit != nil {
+		if data, err = pair.submit.Encrypt(data); err != nil {
+			return fmt.Errorf("加密请求数据失败: %w", err)
+		}
+	}
+
+	nonce := make([]byte, nonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	req := request{
+		AppUUID:   c.config.AppUUID,
+		APIType:   apiType,
+		Timestamp: time.Now().Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+		Data:      data,
+	}
+	req.Sign = encrypt.ClientRequestSign(c.config.Secret, req.AppUUID, req.APIType, req.Timestamp, req.Nonce, req.Data)
+
+	resp, err := c.post(ctx, req)
+	if err != nil {
+		return err
+	}
+	var signed signedPayload
+	if err := resp.Decode(&signed); err != nil {
+		return fmt.Errorf("解析响应数据失败: %w", err)
+	}
+	expected := encrypt.ClientResponseSign(c.config.Secret, signed.Payload, signed.Timestamp, signed.Nonce)
+	if signed.Nonce != req.Nonce || !hmac.Equal([]byte(expected), []byte(signed.Sign)) {
+		return ErrResponseSignature
+	}
+
+	payload := signed.Payload
+	if pair.ret != nil {
+		if payload, err = pair.ret.Decrypt(payload); err != nil {
+			return fmt.Errorf("解密响应数据失败: %w", err)
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(payload), result)
+}
+
+// Announcement 获取程序公告
+func (c *Client) Announcement(ctx context.Context) (string, error) {
+	var result struct {
+		Announcement string `json:"announcement"`
+	}
+	err := c.Call(ctx, APITypeBulletin, Params{}, &result)
+	return result.Announcement, err
+}
+
+// CheckUpdate 按配置的版本号检测最新版本
+func (c *Client) CheckUpdate(ctx context.Context) (*UpdateInfo, error) {
+	var info UpdateInfo
+	if err := c.Call(ctx, APITypeCheckVersion, Params{}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// CardInfo 获取卡密信息
+func (c *Client) CardInfo(ctx context.Context, card string) (*CardInfo, error) {
+	var info CardInfo
+	if err := c.Call(ctx, APITypeCardInfo, Params{Card: card}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// LoginCard 卡密登录，成功后保存会话令牌
+func (c *Client) LoginCard(ctx context.Context, card string) (*LoginResult, error) {
+	return c.login(ctx, APITypeCardLogin, Params{Card: card})
+}
+
+// LoginAccount 账号登录，成功后保存会话令牌
+func (c *Client) LoginAccount(ctx context.Context, username, password string) (*LoginResult, error) {
+	return c.login(ctx, APITypeLogin, Params{Username: username, Password: password})
+}
+
+// Register 注册账号，email 用于找回密码，可为空
+func (c *Client) Register(ctx context.Context, username, password, email string) (*RegisterResult, error) {
+	var result RegisterResult
+	if err := c.Call(ctx, APITypeRegister, Params{Username: username, Password: password, Email: email}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Recharge 使用卡密为账号充值
+func (c *Client) Recharge(ctx context.Context, username, card string) (*RechargeResult, error) {
+	var result RechargeResult
+	if err := c.Call(ctx, APITypeRecharge, Params{Username: username, Card: card}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Logout 退出登录并清除会话令牌
+func (c *Client) Logout(ctx context.Context) error {
+	err := c.Call(ctx, APITypeLogout, Params{}, nil)
+	c.SetToken("")
+	return err
+}
+
+// Heartbeat 发送一次心跳，返回最新的到期状态
+func (c *Client) Heartbeat(ctx context.Context) (*Status, error) {
+	return c.statusCall(ctx, APITypeHeartbeat, Params{})
+}
+
+// Expiry 获取到期时间
+func (c *Client) Expiry(ctx context.Context) (*Status, error) {
+	return c.statusCall(ctx, APITypeExpiry, Params{})
+}
+
+// AppData 获取程序数据（需要登录）
+func (c *Client) AppData(ctx context.Context) (string, error) {
+	var result struct {
+		AppData string `json:"app_data"`
+	}
+	err := c.Call(ctx, APITypeAppData, Params{}, &result)
+	return result.AppData, err
+}
+
+// Variable 获取变量数据（需要登录）
+func (c *Client) Variable(ctx context.Context, name string) (string, error) {
+	var result struct {
+		Data string `json:"data"`
+	}
+	err := c.Call(ctx, APITypeVariable, Params{Name: name}, &result)
+	return result.Data, err
+}
+
+// CallFunction 执行远程函数（需要登录），args 依次作为 main 函数的参数，返回值解码到 result（可为 nil）
+func (c *Client) CallFunction(ctx context.Context, name string, args []interface{}, result interface{}) error {
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := c.Call(ctx, APITypeFunction, Params{Name: name, Args: args}, &resp); err != nil {
+		return err
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// ChangePassword 修改账号密码，成功后该账号的所有会话下线
+func (c *Client) ChangePassword(ctx context.Context, username, password, newPassword string) error {
+	return c.Call(ctx, APITypeChangePwd, Params{Username: username, Password: password, NewPassword: newPassword}, nil)
+}
+
+// SendResetCode 向账号注册时填写的邮箱发送找回密码验证码
+func (c *Client) SendResetCode(ctx context.Context, username, email string) error {
+	return c.Call(ctx, APITypeSendReset, Params{Username: username, Email: email}, nil)
+}
+
+// ResetPassword 使用邮箱验证码重置账号密码，成功后该账号的所有会话下线
+func (c *Client) ResetPassword(ctx context.Context, username, email, code, newPassword string) error {
+	return c.Call(ctx, APITypeResetPwd, Params{Username: username, Email: email, Code: code, NewPassword: newPassword}, nil)
+}
+
+// RebindMachine 将卡密或账号换绑到配置的机器码，成功后需要重新登录
+func (c *Client) RebindMachine(ctx context.Context, identity Identity) (*RebindResult, error) {
+	return c.rebind(ctx, APITypeRebindMac, identity)
+}
+
+// RebindIP 将卡密或账号换绑到当前IP，成功后需要重新登录
+func (c *Client) RebindIP(ctx context.Context, identity Identity) (*RebindResult, error) {
+	return c.rebind(ctx, APITypeRebindIP, identity)
+}
+
+// Ban 封停当前登录的卡密或账号（检测到破解等行为时调用）
+func (c *Client) Ban(ctx context.Context, reason string) error {
+	return c.Call(ctx, APITypeBan, Params{Reason: reason}, nil)
+}
+
+// Blacklist 将当前设备的机器码与IP加入黑名单
+func (c *Client) Blacklist(ctx context.Context, reason string) error {
+	return c.Call(ctx, APITypeBlacklist, Params{Reason: reason}, nil)
+}
+
+// DeductTime 扣除当前登录的卡密或账号的剩余时长（分钟）
+func (c *Client) DeductTime(ctx context.Context, minutes int) (*Status, error) {
+	return c.statusCall(ctx, APITypeDeductTime, Params{Minutes: minutes})
+}
+
+// ============================================================================
+// 私有方法
+// ============================================================================
+
+// login 登录并保存会话令牌
+func (c *Client) login(ctx context.Context, apiType int, params Params) (*LoginResult, error) {
+	var result LoginResult
+	if err := c.Call(ctx, apiType, params, &result); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.token = result.Token
+	c.status = &result.Status
+	c.mu.Unlock()
+	return &result, nil
+}
+
+// statusCall 调用返回到期状态的接口
+func (c *Client) statusCall(ctx context.Context, apiType int, params Params) (*Status, error) {
+	var status Status
+	if err := c.Call(ctx, apiType, params, &status); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.status = &status
+	c.mu.Unlock()
+	return &status, nil
+}
+
+// rebind 换绑机器码或IP
+func (c *Client) rebind(ctx context.Context, apiType int, identity Identity) (*RebindResult, error) {
+	var result RebindResult
+	params := Params{Card: identity.Card, Username: identity.Username, Password: identity.Password}
+	if err := c.Call(ctx, apiType, params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// post 发送请求并解析统一响应结构
+func (c *Client) post(ctx context.Context, req request) (*Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+clientPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := DecodeResponse(respBody)
+	if resp == nil && err != nil {
+		return nil, fmt.Errorf("HTTP %d: %w", httpResp.StatusCode, err)
+	}
+	return resp, err
+}
@@ -0,0 +1,768 @@
+package client_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"networkDev/client"
+	"networkDev/constants"
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/server"
+	"networkDev/services"
+	"networkDev/utils"
+	"networkDev/utils/encrypt"
+	"networkDev/utils/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 测试环境
+// ============================================================================
+
+// testEnv 端到端测试共用的服务端与应用
+type testEnv struct {
+	server   *httptest.Server
+	db       *gorm.DB
+	app      *models.App
+	manifest *client.Manifest
+	keys     map[int]client.ManifestKeys // 接入包之外由接入方保管的密钥
+}
+
+var env *testEnv
+
+// TestMain 使用内存 SQLite 启动完整的服务端路由
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("database.type", "sqlite")
+	viper.Set("database.sqlite.path", ":memory:")
+	viper.Set("security.encryption_key", "client-e2e-test-key")
+	// 找回密码的验证码邮件写入队列后发送失败，测试从队列记录中读取验证码
+	viper.Set("mail.host", "127.0.0.1")
+	viper.Set("mail.port", 1)
+	viper.Set("mail.encryption", "none")
+
+	var err error
+	env, err = setupEnv()
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	env.server.Close()
+	os.Exit(code)
+}
+
+// setupEnv 初始化数据库、创建应用与接口并启动测试服务端
+// 各接口使用不同的提交、返回算法，覆盖全部加解密实现
+func setupEnv() (*testEnv, error) {
+	if err := utils.InitCrypto(); err != nil {
+		return nil, err
+	}
+	db, err := database.Init()
+	if err != nil {
+		return nil, err
+	}
+	if err := database.AutoMigrate(); err != nil {
+		return nil, err
+	}
+	if err := database.SeedDefaultSettings(); err != nil {
+		return nil, err
+	}
+
+	app := &models.App{
+		Name:                 "e2e",
+		Status:               1,
+		Version:              "1.2.0",
+		Announcement:         base64.StdEncoding.EncodeToString([]byte("系统公告")),
+		AppData:              base64.StdEncoding.EncodeToString([]byte(`{"theme":"dark"}`)),
+		LoginType:            0,
+		MultiOpenScope:       2,
+		MultiOpenCount:       1,
+		CheckInterval:        1,
+		MachineVerify:        1,
+		MachineRebindEnabled: 1,
+		MachineRebindLimit:   1,
+		MachineFreeCount:     1,
+		MachineRebindCount:   2,
+		MachineRebindDeduct:  30,
+		RegisterEnabled:      1,
+		RegisterLimitEnabled: 1,
+		RegisterLimitTime:    1,
+		RegisterCount:        3,
+		TrialEnabled:         1,
+		TrialLimitTime:       1,
+		TrialDuration:        60,
+	}
+	if err := db.Create(app).Error; err != nil {
+		return nil, err
+	}
+
+	algorithms := map[int][2]int{
+		models.APITypeSingleLogin:      {models.AlgorithmRSA, models.AlgorithmRC4},
+		models.APITypeUserLogin:        {models.AlgorithmRSADynamic, models.AlgorithmRSA},
+		models.APITypeCheckUserStatus:  {models.AlgorithmRC4, models.AlgorithmEasy},
+		models.APITypeGetVariable:      {models.AlgorithmEasy, models.AlgorithmRSADynamic},
+		models.APITypeExecuteFunction:  {models.AlgorithmEasy, models.AlgorithmEasy},
+		models.APITypeUserDeductedTime: {models.AlgorithmRC4, models.AlgorithmRC4},
+	}
+	var apis []models.API
+	keys := make(map[int]client.ManifestKeys)
+	for _, apiType := range models.GetDefaultAPITypes() {
+		api := models.API{AppUUID: app.UUID, APIType: apiType, Status: 1}
+		pair := algorithms[apiType]
+		api.SubmitAlgorithm = pair[0]
+		api.ReturnAlgorithm = pair[1]
+		if api.SubmitPublicKey, api.SubmitPrivateKey, err = generateKeys(pair[0]); err != nil {
+			return nil, err
+		}
+		if api.ReturnPublicKey, api.ReturnPrivateKey, err = generateKeys(pair[1]); err != nil {
+			return nil, err
+		}
+		if err := db.Create(&api).Error; err != nil {
+			return nil, err
+		}
+		apis = append(apis, api)
+		keys[apiType] = client.ManifestKeys{Submit: submitKeyForClient(pair[0], api.SubmitPrivateKey), Return: api.ReturnPrivateKey}
+	}
+
+	// 通过接入包清单配置客户端，与后台导出的接入包保持一致
+	raw, err := json.Marshal(services.BuildIntegrationManifest(app, apis))
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := client.ParseManifest(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := []models.Variable{
+		{AppUUID: app.UUID, Alias: "e2e_notice", Data: "应用变量"},
+		{AppUUID: "0", Alias: "e2e_global", Data: "全局变量"},
+		{AppUUID: "OTHER-APP", Alias: "e2e_other", Data: "其他应用变量"},
+	}
+	for i := range variables {
+		if err := db.Create(&variables[i]).Error; err != nil {
+			return nil, err
+		}
+		// 编号为毫秒时间戳且唯一，避免同一毫秒内创建
+		time.Sleep(2 * time.Millisecond)
+	}
+	functions := []models.Function{
+		{AppUUID: app.UUID, Alias: "e2e_add", Code: "function main(a, b) { return {sum: a + b}; }"},
+		{AppUUID: "0", Alias: "e2e_throw", Code: "function main() { throw new Error('boom'); }"},
+		{AppUUID: app.UUID, Alias: "e2e_loop", Code: "function main() { for (;;) {} }"},
+	}
+	for i := range functions {
+		if err := db.Create(&functions[i]).Error; err != nil {
+			return nil, err
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// 订阅客户端事件的 Webhook，推送到只返回成功的接收端
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	webhook := &models.Webhook{
+		AppUUID: app.UUID,
+		Name:    "e2e",
+		URL:     receiver.URL,
+		Secret:  "e2e-webhook-secret",
+		Status:  models.WebhookStatusEnabled,
+		Events: strings.Join([]string{
+			models.WebhookEventCardActivated, models.WebhookEventAccountRegistered, models.WebhookEventAccountRecharged,
+			models.WebhookEventDeviceRebind, models.WebhookEventAccountBanned, models.WebhookEventBlacklistAdded,
+		}, ","),
+	}
+	if err := db.Create(webhook).Error; err != nil {
+		return nil, err
+	}
+
+	router := gin.New()
+	server.RegisterRoutes(router)
+	return &testEnv{server: httptest.NewServer(router), db: db, app: app, manifest: manifest, keys: keys}, nil
+}
+
+// newClient 创建使用接入包清单的客户端
+func newClient(t *testing.T, machineCode string) *client.Client {
+	t.Helper()
+	c := client.New(client.Config{
+		BaseURL:     env.server.URL,
+		AppUUID:     env.app.UUID,
+		Secret:      env.app.Secret,
+		Version:     "1.0.0",
+		MachineCode: machineCode,
+	})
+	if err := c.UseManifest(env.manifest, env.keys); err != nil {
+		t.Fatalf("加载接入包清单失败: %v", err)
+	}
+	return c
+}
+
+// newCard 创建卡密
+func newCard(t *testing.T, duration int) string {
+	t.Helper()
+	card := models.Card{AppUUID: env.app.UUID, Duration: duration}
+	if err := env.db.Create(&card).Error; err != nil {
+		t.Fatalf("创建卡密失败: %v", err)
+	}
+	return card.CardKey
+}
+
+// updateApp 修改应用设置并使缓存失效，测试结束后恢复
+func updateApp(t *testing.T, updates map[string]interface{}) {
+	t.Helper()
+	var original models.App
+	if err := env.db.First(&original, env.app.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := env.db.Model(&models.App{}).Where("id = ?", env.app.ID).Updates(updates).Error; err != nil {
+		t.Fatal(err)
+	}
+	services.InvalidateApps(context.Background(), env.app.UUID)
+	t.Cleanup(func() {
+		env.db.Save(&original)
+		services.InvalidateApps(context.Background(), env.app.UUID)
+	})
+}
+
+// expectCode 断言错误为指定的错误码
+func expectCode(t *testing.T, err error, code constants.ErrorCode) {
+	t.Helper()
+	if !errors.Is(err, code) {
+		t.Fatalf("期望错误码 %s，实际: %v", code.Name, err)
+	}
+}
+
+// metricValue 汇总指标中 app 标签为指定应用的计数
+func metricValue(t *testing.T, name, appUUID string) float64 {
+	t.Helper()
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "app" && label.GetValue() == appUUID {
+					total += metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return total
+}
+
+// expectWebhook 等待包含指定内容的事件写入投递记录（事件在后台异步创建）
+func expectWebhook(t *testing.T, event, contains string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var count int64
+		env.db.Model(&models.WebhookDelivery{}).
+			Where("event = ? AND payload LIKE ?", event, "%"+contains+"%").Count(&count)
+		if count > 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("未收到 %s 事件（%s）", event, contains)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// loginFailures 返回应用当前统计窗口内的登录失败次数（读取本身计为一次）
+func loginFailures(t *testing.T) int64 {
+	t.Helper()
+	count, err := utils.IncrCounter(context.Background(), "login_failures:"+env.app.UUID, 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// ============================================================================
+// 测试用例
+// ============================================================================
+
+// TestIntegrationBundlePublicOnly 接入包只包含公开信息，缺少接入包之外的密钥时不能加载清单
+func TestIntegrationBundlePublicOnly(t *testing.T) {
+	var apis []models.API
+	if err := env.db.Where("app_uuid = ?", env.app.UUID).Find(&apis).Error; err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := services.WriteIntegrationBundle(&buf, services.BuildIntegrationManifest(env.app, apis)); err != nil {
+		t.Fatalf("WriteIntegrationBundle: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(content, []byte("PRIVATE KEY")) || bytes.Contains(content, []byte(env.app.Secret)) {
+			t.Errorf("%s 包含私钥或应用密钥", file.Name)
+		}
+		for _, api := range apis {
+			for _, key := range []string{api.SubmitPrivateKey, api.ReturnPrivateKey} {
+				if key != "" && bytes.Contains(content, []byte(key)) {
+					t.Errorf("%s 包含接口 %d 的密钥", file.Name, api.APIType)
+				}
+			}
+		}
+	}
+
+	c := client.New(client.Config{BaseURL: env.server.URL, AppUUID: env.app.UUID, Secret: env.app.Secret})
+	if err := c.UseManifest(env.manifest, nil); err == nil {
+		t.Fatal("未提供接入包之外的密钥时应返回错误")
+	}
+}
+
+// TestPublicInfo 公告、更新检查与卡密信息不需要登录
+func TestPublicInfo(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, "PUBLIC")
+
+	announcement, err := c.Announcement(ctx)
+	if err != nil || announcement != "系统公告" {
+		t.Fatalf("Announcement = %q, %v", announcement, err)
+	}
+
+	info, err := c.CheckUpdate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.2.0" || !info.HasUpdate || info.ForceUpdate {
+		t.Fatalf("CheckUpdate = %+v", info)
+	}
+
+	card := newCard(t, 60)
+	cardInfo, err := c.CardInfo(ctx, card)
+	if err != nil || cardInfo.Status != models.CardStatusUnused || cardInfo.Duration != 60 {
+		t.Fatalf("CardInfo = %+v, %v", cardInfo, err)
+	}
+	_, err = c.CardInfo(ctx, "NOT-A-CARD")
+	expectCode(t, err, constants.CodeCardNotFound)
+
+	// 需要登录的接口
+	_, err = c.AppData(ctx)
+	expectCode(t, err, constants.CodeSessionInvalid)
+}
+
+// TestCardLoginFlow 卡密登录、心跳、变量与函数调用、退出
+func TestCardLoginFlow(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, "MACHINE-A")
+	card := newCard(t, 60)
+	logins := metricValue(t, "networkdev_client_logins_total", env.app.UUID)
+	activations := metricValue(t, "networkdev_card_activations_total", env.app.UUID)
+
+	login, err := c.LoginCard(ctx, card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := metricValue(t, "networkdev_client_logins_total", env.app.UUID); got != logins+1 {
+		t.Fatalf("登录次数指标 = %v，期望 %v", got, logins+1)
+	}
+	if got := metricValue(t, "networkdev_card_activations_total", env.app.UUID); got != activations+1 {
+		t.Fatalf("卡密激活指标 = %v，期望 %v", got, activations+1)
+	}
+	expectWebhook(t, models.WebhookEventCardActivated, card)
+	if login.Token == "" || login.Kind != "card" || login.EndAt == nil || login.CheckInterval != 60 {
+		t.Fatalf("LoginCard = %+v", login)
+	}
+	if remaining := time.Until(*login.EndAt); remaining < 59*time.Minute || remaining > 61*time.Minute {
+		t.Fatalf("到期时间不正确: %v", login.EndAt)
+	}
+
+	status, err := c.Heartbeat(ctx)
+	if err != nil || status.Remaining <= 0 {
+		t.Fatalf("Heartbeat = %+v, %v", status, err)
+	}
+	if _, err := c.Expiry(ctx); err != nil {
+		t.Fatal(err)
+	}
+	appData, err := c.AppData(ctx)
+	if err != nil || appData != `{"theme":"dark"}` {
+		t.Fatalf("AppData = %q, %v", appData, err)
+	}
+
+	if data, err := c.Variable(ctx, "e2e_notice"); err != nil || data != "应用变量" {
+		t.Fatalf("Variable(e2e_notice) = %q, %v", data, err)
+	}
+	if data, err := c.Variable(ctx, "e2e_global"); err != nil || data != "全局变量" {
+		t.Fatalf("Variable(e2e_global) = %q, %v", data, err)
+	}
+	_, err = c.Variable(ctx, "e2e_other")
+	expectCode(t, err, constants.CodeVariableNotFound)
+
+	var result struct {
+		Sum float64 `json:"sum"`
+	}
+	if err := c.CallFunction(ctx, "e2e_add", []interface{}{2, 3}, &result); err != nil || result.Sum != 5 {
+		t.Fatalf("CallFunction(e2e_add) = %+v, %v", result, err)
+	}
+	expectCode(t, c.CallFunction(ctx, "e2e_throw", nil, nil), constants.CodeFunctionFailed)
+	expectCode(t, c.CallFunction(ctx, "e2e_loop", nil, nil), constants.CodeFunctionFailed)
+	expectCode(t, c.CallFunction(ctx, "e2e_missing", nil, nil), constants.CodeFunctionNotFound)
+
+	if err := c.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+	c.SetToken(login.Token)
+	_, err = c.Heartbeat(ctx)
+	expectCode(t, err, constants.CodeSessionInvalid)
+
+	// 已激活的卡密再次登录时保持原到期时间
+	again, err := c.LoginCard(ctx, card)
+	if err != nil || !again.EndAt.Equal(*login.EndAt) {
+		t.Fatalf("再次登录 = %+v, %v", again, err)
+	}
+}
+
+// TestMachineBindingAndKick 机器码绑定、换绑与顶号，心跳循环收到下线回调
+func TestMachineBindingAndKick(t *testing.T) {
+	ctx := context.Background()
+	card := newCard(t, 120)
+	first := newClient(t, "MACHINE-1")
+	if _, err := first.LoginCard(ctx, card); err != nil {
+		t.Fatal(err)
+	}
+
+	second := newClient(t, "MACHINE-2")
+	_, err := second.LoginCard(ctx, card)
+	expectCode(t, err, constants.CodeMachineMismatch)
+
+	// 第一次换绑免费，第二次扣除 30 分钟，第三次超过次数
+	rebind, err := second.RebindMachine(ctx, client.Identity{Card: card})
+	if err != nil || rebind.Rebinds != 1 || rebind.Deducted != 0 {
+		t.Fatalf("RebindMachine = %+v, %v", rebind, err)
+	}
+	expectWebhook(t, models.WebhookEventDeviceRebind, card)
+	_, err = first.Heartbeat(ctx)
+	expectCode(t, err, constants.CodeSessionKicked)
+
+	login, err := second.LoginCard(ctx, card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rebind, err = first.RebindMachine(ctx, client.Identity{Card: card})
+	if err != nil || rebind.Rebinds != 2 || rebind.Deducted != 30 || !rebind.EndAt.Before(*login.EndAt) {
+		t.Fatalf("RebindMachine = %+v, %v", rebind, err)
+	}
+	_, err = second.RebindMachine(ctx, client.Identity{Card: card})
+	expectCode(t, err, constants.CodeRebindExhausted)
+
+	// 多开数量为 1：同一机器上的新登录顶掉旧会话
+	kicked := make(chan error, 1)
+	if _, err := first.LoginCard(ctx, card); err != nil {
+		t.Fatal(err)
+	}
+	done := first.StartHeartbeat(ctx, client.HeartbeatOptions{
+		Interval: 20 * time.Millisecond,
+		OnKicked: func(err error) { kicked <- err },
+		OnError:  func(err error) { t.Errorf("心跳错误: %v", err) },
+	})
+	third := newClient(t, "MACHINE-1")
+	if _, err := third.LoginCard(ctx, card); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-kicked:
+		expectCode(t, err, constants.CodeSessionKicked)
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到下线回调")
+	}
+	<-done
+
+	// 非顶号登录：超过多开数量时拒绝新的登录
+	updateApp(t, map[string]interface{}{"login_type": 1})
+	_, err = newClient(t, "MACHINE-1").LoginCard(ctx, card)
+	expectCode(t, err, constants.CodeMultiOpenLimit)
+}
+
+// TestExpiryCallback 扣除时长后卡密到期，心跳循环收到到期回调
+func TestExpiryCallback(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, "MACHINE-EXPIRY")
+	if _, err := c.LoginCard(ctx, newCard(t, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DeductTime(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	var kicked atomic.Bool
+	expired := make(chan error, 1)
+	done := c.StartHeartbeat(ctx, client.HeartbeatOptions{
+		Interval:  10 * time.Millisecond,
+		OnKicked:  func(error) { kicked.Store(true) },
+		OnExpired: func(err error) { expired <- err },
+	})
+	select {
+	case err := <-expired:
+		expectCode(t, err, constants.CodeCardExpired)
+	case <-time.After(5 * time.Second):
+		t.Fatal("未收到到期回调")
+	}
+	<-done
+	if kicked.Load() {
+		t.Fatal("到期不应触发下线回调")
+	}
+}
+
+// TestAccountFlow 注册（试用）、登录、充值、改密与封停
+func TestAccountFlow(t *testing.T) {
+	ctx := context.Background()
+	// 账号与试用记录按数据库统计，测试结束后删除，保证重复运行结果一致
+	t.Cleanup(func() { env.db.Where("app_uuid = ?", env.app.UUID).Delete(&models.Account{}) })
+	c := newClient(t, "MACHINE-ACCOUNT")
+
+	reg, err := c.Register(ctx, "alice", "secret123", "alice@example.com")
+	if err != nil || !reg.Trial || reg.EndAt == nil {
+		t.Fatalf("Register = %+v, %v", reg, err)
+	}
+	var account models.Account
+	if err := env.db.Where("app_uuid = ? AND username = ?", env.app.UUID, "alice").First(&account).Error; err != nil {
+		t.Fatal(err)
+	}
+	expectWebhook(t, models.WebhookEventAccountRegistered, account.UUID)
+	_, err = c.Register(ctx, "alice", "secret123", "")
+	expectCode(t, err, constants.CodeAccountExists)
+	// 同一机器只领取一次试用
+	second, err := c.Register(ctx, "alice2", "secret123", "")
+	if err != nil || second.Trial || second.EndAt != nil {
+		t.Fatalf("Register(alice2) = %+v, %v", second, err)
+	}
+	_, err = c.LoginAccount(ctx, "alice2", "secret123")
+	expectCode(t, err, constants.CodeAccountExpired)
+
+	// 登录被拒绝计入登录失败次数，加上读取计数本身，两次读取之间增加 2
+	before := loginFailures(t)
+	_, err = c.LoginAccount(ctx, "alice", "wrong-password")
+	expectCode(t, err, constants.CodePasswordIncorrect)
+	if got := loginFailures(t); got != before+2 {
+		t.Fatalf("登录失败次数 = %d，期望 %d", got, before+2)
+	}
+	login, err := c.LoginAccount(ctx, "alice", "secret123")
+	if err != nil || login.Kind != "account" {
+		t.Fatalf("LoginAccount = %+v, %v", login, err)
+	}
+
+	card := newCard(t, 24*60)
+	recharge, err := c.Recharge(ctx, "alice", card)
+	if err != nil || recharge.EndAt.Sub(*reg.EndAt) != 24*time.Hour {
+		t.Fatalf("Recharge = %+v, %v", recharge, err)
+	}
+	expectWebhook(t, models.WebhookEventAccountRecharged, card)
+	_, err = c.Recharge(ctx, "alice", card)
+	expectCode(t, err, constants.CodeCardUsed)
+	_, err = c.LoginCard(ctx, card)
+	expectCode(t, err, constants.CodeCardUsed)
+
+	if err := c.ChangePassword(ctx, "alice", "secret123", "newsecret456"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Heartbeat(ctx)
+	expectCode(t, err, constants.CodeSessionKicked)
+	_, err = c.LoginAccount(ctx, "alice", "secret123")
+	expectCode(t, err, constants.CodePasswordIncorrect)
+	if _, err := c.LoginAccount(ctx, "alice", "newsecret456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Ban(ctx, "检测到调试器"); err != nil {
+		t.Fatal(err)
+	}
+	expectWebhook(t, models.WebhookEventAccountBanned, account.UUID)
+	_, err = c.Heartbeat(ctx)
+	expectCode(t, err, constants.CodeSessionKicked)
+	_, err = c.LoginAccount(ctx, "alice", "newsecret456")
+	expectCode(t, err, constants.CodeAccountDisabled)
+}
+
+// TestPasswordReset 通过邮箱验证码找回密码
+func TestPasswordReset(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() {
+		viper.Set("mail.enabled", false)
+		env.db.Where("app_uuid = ?", env.app.UUID).Delete(&models.Account{})
+	})
+	c := newClient(t, "MACHINE-RESET")
+	// 验证码发送频率按邮箱限制，每次运行使用不同的邮箱
+	email := fmt.Sprintf("bob-%d@example.com", time.Now().UnixNano())
+	if _, err := c.Register(ctx, "bob", "secret123", email); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.LoginAccount(ctx, "bob", "secret123"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectCode(t, c.SendResetCode(ctx, "bob", email), constants.CodeServiceUnavailable)
+	viper.Set("mail.enabled", true)
+	expectCode(t, c.SendResetCode(ctx, "bob", "other@example.com"), constants.CodeAccountNotFound)
+	if err := c.SendResetCode(ctx, "bob", strings.ToUpper(email)); err != nil {
+		t.Fatal(err)
+	}
+	expectCode(t, c.SendResetCode(ctx, "bob", email), constants.CodeTooManyRequests)
+
+	var message models.MailMessage
+	if err := env.db.Where("template = ? AND `to` = ?", models.MailTemplatePasswordReset, email).First(&message).Error; err != nil {
+		t.Fatal(err)
+	}
+	code := regexp.MustCompile(`\b\d{6}\b`).FindString(message.TextBody)
+	if code == "" {
+		t.Fatalf("邮件中没有验证码: %s", message.TextBody)
+	}
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	expectCode(t, c.ResetPassword(ctx, "bob", email, wrong, "newsecret456"), constants.CodeResetCodeInvalid)
+	if err := c.ResetPassword(ctx, "bob", email, code, "newsecret456"); err != nil {
+		t.Fatal(err)
+	}
+	// 验证码只能使用一次，重置后旧会话下线
+	expectCode(t, c.ResetPassword(ctx, "bob", email, code, "another789"), constants.CodeResetCodeInvalid)
+	_, err := c.Heartbeat(ctx)
+	expectCode(t, err, constants.CodeSessionKicked)
+	_, err = c.LoginAccount(ctx, "bob", "secret123")
+	expectCode(t, err, constants.CodePasswordIncorrect)
+	if _, err := c.LoginAccount(ctx, "bob", "newsecret456"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestBlacklist 加入黑名单后该设备不能再登录
+func TestBlacklist(t *testing.T) {
+	ctx := context.Background()
+	// 黑名单包含IP，测试结束后移除，避免影响其他用例
+	t.Cleanup(func() { env.db.Where("app_uuid = ?", env.app.UUID).Delete(&models.Blacklist{}) })
+	c := newClient(t, "MACHINE-BLACK")
+	card := newCard(t, 60)
+	if _, err := c.LoginCard(ctx, card); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Blacklist(ctx, "内存修改"); err != nil {
+		t.Fatal(err)
+	}
+	expectWebhook(t, models.WebhookEventBlacklistAdded, card)
+	_, err := c.LoginCard(ctx, newCard(t, 60))
+	expectCode(t, err, constants.CodeDeviceBlacklisted)
+}
+
+// TestRequestVerification 签名、重放与应用状态校验
+func TestRequestVerification(t *testing.T) {
+	ctx := context.Background()
+
+	forged := client.New(client.Config{BaseURL: env.server.URL, AppUUID: env.app.UUID, Secret: "wrong-secret"})
+	_, err := forged.Announcement(ctx)
+	expectCode(t, err, constants.CodeSignatureInvalid)
+
+	missing := client.New(client.Config{BaseURL: env.server.URL, AppUUID: "NO-SUCH-APP", Secret: "x"})
+	_, err = missing.Announcement(ctx)
+	expectCode(t, err, constants.CodeAppNotFound)
+
+	// 相同的请求只处理一次
+	timestamp := time.Now().Unix()
+	nonce := fmt.Sprintf("replay-%d", time.Now().UnixNano())
+	body, _ := json.Marshal(map[string]interface{}{
+		"app_uuid":  env.app.UUID,
+		"api_type":  client.APITypeBulletin,
+		"timestamp": timestamp,
+		"nonce":     nonce,
+		"data":      "",
+		"sign":      encrypt.ClientRequestSign(env.app.Secret, env.app.UUID, client.APITypeBulletin, timestamp, nonce, ""),
+	})
+	for i, want := range []constants.ErrorCode{constants.CodeOK, constants.CodeRequestReplayed} {
+		resp, err := http.Post(env.server.URL+"/api/client", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		decoded, _ := client.DecodeResponse(raw)
+		if decoded == nil || decoded.Code != want.Code {
+			t.Fatalf("第 %d 次请求: 期望 %s，实际 %s", i+1, want.Name, raw)
+		}
+	}
+
+	// 过期的时间戳
+	stale := time.Now().Add(-time.Hour).Unix()
+	body, _ = json.Marshal(map[string]interface{}{
+		"app_uuid": env.app.UUID, "api_type": client.APITypeBulletin, "timestamp": stale, "nonce": "stale-nonce-0001",
+		"sign": encrypt.ClientRequestSign(env.app.Secret, env.app.UUID, client.APITypeBulletin, stale, "stale-nonce-0001", ""),
+	})
+	resp, err := http.Post(env.server.URL+"/api/client", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	_, err = client.DecodeResponse(raw)
+	expectCode(t, err, constants.CodeTimestampInvalid)
+
+	// 随机串只能包含字母、数字、下划线与连字符，含换行符时即使签名正确也被拒绝
+	now := time.Now().Unix()
+	for _, bad := range []string{"short", "nonce-0001\nextra", "nonce 0001", strings.Repeat("n", 65)} {
+		body, _ = json.Marshal(map[string]interface{}{
+			"app_uuid": env.app.UUID, "api_type": client.APITypeBulletin, "timestamp": now, "nonce": bad,
+			"sign": encrypt.ClientRequestSign(env.app.Secret, env.app.UUID, client.APITypeBulletin, now, bad, ""),
+		})
+		resp, err := http.Post(env.server.URL+"/api/client", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		_, err = client.DecodeResponse(raw)
+		expectCode(t, err, constants.CodeValidationError)
+	}
+
+	// 强制更新时低版本客户端不能登录
+	updateApp(t, map[string]interface{}{"force_update": 1})
+	_, err = newClient(t, "MACHINE-OLD").LoginCard(ctx, newCard(t, 60))
+	expectCode(t, err, constants.CodeVersionOutdated)
+
+	// 应用维护时返回维护响应，未设置说明时使用系统维护说明
+	updateApp(t, map[string]interface{}{"maintenance_mode": 1, "maintenance_message": "升级数据库"})
+	_, err = newClient(t, "MACHINE-OLD").Announcement(ctx)
+	var maintenance *client.Error
+	if !errors.As(err, &maintenance) || maintenance.Code.Code != constants.CodeMaintenance.Code || maintenance.Msg != "升级数据库" {
+		t.Fatalf("应用维护: %v", err)
+	}
+
+	updateApp(t, map[string]interface{}{"status": 0})
+	_, err = newClient(t, "MACHINE-OLD").Announcement(ctx)
+	expectCode(t, err, constants.CodeAppDisabled)
+}
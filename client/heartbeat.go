@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"networkDev/constants"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// defaultHeartbeatInterval 服务端未返回校验间隔时使用的心跳间隔
+const defaultHeartbeatInterval = time.Minute
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// HeartbeatOptions 心跳循环设置
+type HeartbeatOptions struct {
+	// Interval 心跳间隔，为 0 时使用服务端返回的校验间隔
+	Interval time.Duration
+	// OnKicked 会话已失效（被顶号、被下线、改密、换绑或长时间未心跳），循环随后结束
+	OnKicked func(err error)
+	// OnExpired 卡密或账号已到期、被禁用，或设备被列入黑名单，循环随后结束
+	OnExpired func(err error)
+	// OnError 网络错误等其他错误，循环继续，下一次心跳时重试
+	OnError func(err error)
+	// OnStatus 每次心跳成功后调用
+	OnStatus func(status *Status)
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 结束心跳循环的错误码
+var (
+	kickedCodes  = []constants.ErrorCode{constants.CodeSessionInvalid, constants.CodeSessionKicked}
+	expiredCodes = []constants.ErrorCode{
+		constants.CodeCardExpired, constants.CodeCardDisabled,
+		constants.CodeAccountExpired, constants.CodeAccountDisabled,
+		constants.CodeDeviceBlacklisted,
+	}
+)
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// StartHeartbeat 在后台按间隔发送心跳，直到 ctx 取消或会话失效、到期
+// 返回的通道在循环结束时关闭
+func (c *Client) StartHeartbeat(ctx context.Context, opts HeartbeatOptions) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		timer := time.NewTimer(c.heartbeatInterval(opts))
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			status, err := c.Heartbeat(ctx)
+			switch {
+			case err == nil:
+				if opts.OnStatus != nil {
+					opts.OnStatus(status)
+				}
+			case ctx.Err() != nil:
+				return
+			case isAnyCode(err, kickedCodes):
+				if opts.OnKicked != nil {
+					opts.OnKicked(err)
+				}
+				return
+			case isAnyCode(err, expiredCodes):
+				if opts.OnExpired != nil {
+					opts.OnExpired(err)
+				}
+				return
+			default:
+				if opts.OnError != nil {
+					opts.OnError(err)
+				}
+			}
+			timer.Reset(c.heartbeatInterval(opts))
+		}
+	}()
+	return done
+}
+
+// heartbeatInterval 下一次心跳的间隔
+func (c *Client) heartbeatInterval(opts HeartbeatOptions) time.Duration {
+	if opts.Interval > 0 {
+		return opts.Interval
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.status != nil && c.status.CheckInterval > 0 {
+		return time.Duration(c.status.CheckInterval) * time.Second
+	}
+	return defaultHeartbeatInterval
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// isAnyCode 判断错误是否为其中任一错误码
+func isAnyCode(err error, codes []constants.ErrorCode) bool {
+	for _, code := range codes {
+		if errors.Is(err, code) {
+			return true
+		}
+	}
+	return false
+}
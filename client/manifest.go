@@ -0,0 +1,58 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Manifest 客户端接入包清单（manifest.json），与 services.IntegrationManifest 格式一致
+type Manifest struct {
+	Version int `json:"version"`
+	App     struct {
+		UUID    string `json:"uuid"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"app"`
+	APIs []ManifestAPI `json:"apis"`
+}
+
+// ManifestAPI 接入包中的接口配置
+type ManifestAPI struct {
+	UUID    string         `json:"uuid"`
+	APIType int            `json:"api_type"`
+	Name    string         `json:"name"`
+	Enabled bool           `json:"enabled"`
+	Submit  ManifestCipher `json:"submit"`
+	Return  ManifestCipher `json:"return"`
+}
+
+// ManifestCipher 接入包中一个方向的算法与公钥
+// KeyRequired 为 true 时需要通过 ManifestKeys 另行提供密钥（返回私钥或 RC4/易加密 密钥）
+type ManifestCipher struct {
+	Algorithm   int    `json:"algorithm"`
+	PublicKey   string `json:"public_key"`
+	KeyRequired bool   `json:"key_required"`
+}
+
+// ManifestKeys 接入包不包含的接口密钥，从后台接口配置中取得
+type ManifestKeys struct {
+	Submit string // 提交密钥（RC4/易加密）
+	Return string // 返回密钥（RSA私钥 或 RC4/易加密 密钥）
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// ParseManifest 解析后台「导出接入包」中的 manifest.json
+func ParseManifest(data []byte) (*Manifest, error) {
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("解析接入包清单失败: %w", err)
+	}
+	return &manifest, nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"networkDev/constants"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Response 服务端统一响应结构，与 utils.Response 对应
+// data 保留原始 JSON，由调用方按接口解码
+type Response struct {
+	Code      int             `json:"code"`
+	Msg       string          `json:"msg"`
+	Error     string          `json:"error,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	Count     *int64          `json:"count,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+}
+
+// Error 服务端返回的业务错误
+// 可使用 errors.Is(err, constants.CodeCardExpired) 按错误码判断
+type Error struct {
+	Code      constants.ErrorCode // 错误码目录项，未知错误码时仅 Code 字段有值
+	Msg       string              // 服务端返回的提示信息
+	RequestID string              // 请求ID，反馈问题时提供给服务端排查日志
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// DecodeResponse 解析服务端响应
+// code 不为 0 时返回 *Error，调用方仍可从返回的 Response 读取 data
+func DecodeResponse(body []byte) (*Response, error) {
+	var resp Response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if resp.Code != constants.CodeOK.Code {
+		return &resp, resp.Err()
+	}
+	return &resp, nil
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Err 将失败响应转换为 *Error，成功时返回 nil
+func (r *Response) Err() error {
+	if r.Code == constants.CodeOK.Code {
+		return nil
+	}
+	ec, ok := constants.LookupErrorCode(r.Code)
+	if !ok {
+		ec = constants.ErrorCode{Code: r.Code, Name: r.Error}
+	}
+	return &Error{Code: ec, Msg: r.Msg, RequestID: r.RequestID}
+}
+
+// Decode 将 data 解码到 v
+func (r *Response) Decode(v interface{}) error {
+	if len(r.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Data, v)
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = e.Code.Message
+	}
+	return fmt.Sprintf("%s(%d): %s", e.Code.Name, e.Code.Code, msg)
+}
+
+// Is 按数字错误码比较，支持 errors.Is(err, constants.CodeXxx)
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case constants.ErrorCode:
+		return e.Code.Code == t.Code
+	case *Error:
+		return e.Code.Code == t.Code.Code
+	}
+	return false
+}
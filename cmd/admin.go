@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 管理员密码相关常量
+const (
+	adminPasswordMinLength       = 6
+	adminGeneratedPasswordLength = 16
+	adminPasswordCharset         = "ABCDEFGHJKMNPQRSTUVWXYZabcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// adminCmd 管理员账号命令
+var adminCmd = &cobra.Command{
+	Use:              "admin",
+	Short:            "管理员账号维护",
+	PersistentPreRun: setupLogrusForCLI,
+}
+
+// adminResetPasswordCmd 重置管理员密码
+var adminResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password",
+	Short: "重置管理员密码",
+	Long: `重置后台管理员密码，重新生成密码盐值并更新 admin_password 与 admin_password_salt 设置。
+未指定 --password 时自动生成随机密码并输出。重置后已登录的管理员会话将失效。`,
+	Run: runAdminResetPassword,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminResetPasswordCmd)
+
+	adminResetPasswordCmd.Flags().StringP("password", "p", "", "新密码（留空则自动生成）")
+	adminResetPasswordCmd.Flags().StringP("username", "u", "", "同时修改管理员用户名（可选）")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runAdminResetPassword 重置管理员密码
+func runAdminResetPassword(cmd *cobra.Command, args []string) {
+	password, _ := cmd.Flags().GetString("password")
+	username, _ := cmd.Flags().GetString("username")
+	username = strings.TrimSpace(username)
+
+	generated := false
+	if password == "" {
+		var err error
+		if password, err = generateAdminPassword(); err != nil {
+			logrus.WithError(err).Fatal("生成随机密码失败")
+		}
+		generated = true
+	}
+	if len(password) < adminPasswordMinLength {
+		logrus.Fatalf("新密码长度不能少于%d位", adminPasswordMinLength)
+	}
+
+	initDatabase()
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取数据库连接失败")
+	}
+
+	// 生成新的密码盐值与哈希
+	salt, err := utils.GenerateRandomSalt()
+	if err != nil {
+		logrus.WithError(err).Fatal("生成密码盐失败")
+	}
+	hash, err := utils.HashPasswordWithSalt(password, salt)
+	if err != nil {
+		logrus.WithError(err).Fatal("生成密码哈希失败")
+	}
+
+	// 在同一事务中更新，避免密码与盐值不一致导致无法登录
+	err = db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]string{
+			"admin_password":      hash,
+			"admin_password_salt": salt,
+		}
+		if username != "" {
+			updates["admin_username"] = username
+		}
+		for name, value := range updates {
+			result := tx.Model(&models.Settings{}).Where("name = ?", name).Update("value", value)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("设置项 %s 不存在", name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("重置管理员密码失败")
+	}
+
+	logrus.Info("管理员密码已重置")
+	if username != "" {
+		fmt.Printf("管理员用户名: %s\n", username)
+	}
+	if generated {
+		fmt.Printf("新密码: %s\n", password)
+	} else {
+		fmt.Println("管理员密码已重置")
+	}
+}
+
+// ============================================================================
+// 辅助函数
+// ============================================================================
+
+// generateAdminPassword 生成随机管理员密码
+func generateAdminPassword() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(adminPasswordCharset)))
+	for i := 0; i < adminGeneratedPasswordLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(adminPasswordCharset[n.Int64()])
+	}
+	return sb.String(), nil
+}
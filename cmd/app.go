@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// appCmd 应用管理命令
+var appCmd = &cobra.Command{
+	Use:              "app",
+	Short:            "应用管理",
+	PersistentPreRun: setupLogrusForCLI,
+}
+
+// appListCmd 列出应用
+var appListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有应用",
+	Run:   runAppList,
+}
+
+// appCreateCmd 创建应用
+var appCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "创建应用（自动生成UUID与密钥）",
+	Run:   runAppCreate,
+}
+
+// appDisableCmd 禁用应用
+var appDisableCmd = &cobra.Command{
+	Use:   "disable <uuid|id>",
+	Short: "禁用应用",
+	Args:  cobra.ExactArgs(1),
+	Run:   runAppDisable,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(appCmd)
+	appCmd.AddCommand(appListCmd)
+	appCmd.AddCommand(appCreateCmd)
+	appCmd.AddCommand(appDisableCmd)
+
+	addOutputFlag(appListCmd)
+	appListCmd.Flags().Bool("reveal", false, "显示应用密钥明文")
+
+	addOutputFlag(appCreateCmd)
+	appCreateCmd.Flags().StringP("name", "n", "", "应用名称（必填）")
+	appCreateCmd.Flags().String("version", "1.0.0", "应用版本号")
+	appCreateCmd.Flags().Bool("disabled", false, "创建后保持禁用状态")
+	_ = appCreateCmd.MarkFlagRequired("name")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runAppList 列出所有应用
+func runAppList(cmd *cobra.Command, args []string) {
+	format := getOutputFormat(cmd)
+	reveal, _ := cmd.Flags().GetBool("reveal")
+
+	initDatabase()
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取数据库连接失败")
+	}
+
+	var apps []models.App
+	if err := db.Order("id ASC").Find(&apps).Error; err != nil {
+		logrus.WithError(err).Fatal("查询应用列表失败")
+	}
+	if !reveal {
+		for i := range apps {
+			apps[i].Redact()
+		}
+	}
+
+	if format == outputJSON {
+		printJSON(apps)
+		return
+	}
+	printApps(apps)
+}
+
+// runAppCreate 创建应用
+func runAppCreate(cmd *cobra.Command, args []string) {
+	format := getOutputFormat(cmd)
+	name, _ := cmd.Flags().GetString("name")
+	version, _ := cmd.Flags().GetString("version")
+	disabled, _ := cmd.Flags().GetBool("disabled")
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		logrus.Fatal("应用名称不能为空")
+	}
+	if strings.TrimSpace(version) == "" {
+		version = "1.0.0"
+	}
+
+	initDatabase()
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取数据库连接失败")
+	}
+
+	app := models.App{
+		Name:    name,
+		Version: strings.TrimSpace(version),
+		Status:  1,
+	}
+	if disabled {
+		app.Status = 0
+	}
+	// 与后台创建应用一致，同时创建全部默认接口（默认禁用、不加密）
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&app).Error; err != nil {
+			return err
+		}
+		for _, apiType := range models.GetDefaultAPITypes() {
+			api := models.API{
+				APIType:         apiType,
+				AppUUID:         app.UUID,
+				Status:          0,
+				SubmitAlgorithm: models.AlgorithmNone,
+				ReturnAlgorithm: models.AlgorithmNone,
+			}
+			if err := tx.Create(&api).Error; err != nil {
+				return fmt.Errorf("创建默认接口 %d 失败: %w", apiType, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("创建应用失败")
+	}
+	logrus.WithField("app_uuid", app.UUID).Info("应用创建成功")
+
+	// 创建时输出密钥明文，便于接入方配置
+	if format == outputJSON {
+		printJSON(app)
+		return
+	}
+	printApps([]models.App{app})
+}
+
+// runAppDisable 禁用应用
+func runAppDisable(cmd *cobra.Command, args []string) {
+	initDatabase()
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取数据库连接失败")
+	}
+
+	app, err := findApp(db, args[0])
+	if err != nil {
+		logrus.WithError(err).Fatal("查找应用失败")
+	}
+	if app.Status == 0 {
+		fmt.Printf("应用 %s（%s）已处于禁用状态\n", app.Name, app.UUID)
+		return
+	}
+
+	oldStatus := app.Status
+	if err := db.Model(&app).Update("status", 0).Error; err != nil {
+		logrus.WithError(err).Fatal("禁用应用失败")
+	}
+	logrus.WithField("app_uuid", app.UUID).Info("应用已禁用")
+
+	// 与后台修改应用状态一致：删除Redis缓存并通知运行中的服务实例，推送应用状态变更事件
+	// 命令行进程随即退出，事件只写入投递记录，由服务端的 webhook_retry 任务推送
+	ctx := context.Background()
+	services.InvalidateApps(ctx, app.UUID)
+	if err := services.QueueWebhookEvent(ctx, models.WebhookEventAppStatusChanged, app.UUID, services.AppStatusChangedData(&app, oldStatus)); err != nil {
+		logrus.WithError(err).Warn("写入应用状态变更事件失败")
+	}
+	fmt.Printf("已禁用应用 %s（%s）\n", app.Name, app.UUID)
+}
+
+// ============================================================================
+// 辅助函数
+// ============================================================================
+
+// findApp 根据UUID或数字ID查找应用
+func findApp(db *gorm.DB, ref string) (models.App, error) {
+	var app models.App
+	ref = strings.TrimSpace(ref)
+
+	query := db.Where("uuid = ?", strings.ToUpper(ref))
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		query = db.Where("id = ?", id)
+	}
+	if err := query.First(&app).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return app, fmt.Errorf("应用 %s 不存在", ref)
+		}
+		return app, err
+	}
+	return app, nil
+}
+
+// printApps 以表格形式输出应用列表
+func printApps(apps []models.App) {
+	rows := make([][]string, 0, len(apps))
+	for _, app := range apps {
+		status := "禁用"
+		if app.Status == 1 {
+			status = "启用"
+		}
+		rows = append(rows, []string{
+			strconv.FormatUint(uint64(app.ID), 10),
+			app.UUID,
+			app.Name,
+			app.Version,
+			status,
+			app.Secret,
+			formatTime(&app.CreatedAt),
+		})
+	}
+	printTable([]string{"ID", "UUID", "名称", "版本", "状态", "密钥", "创建时间"}, rows)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"networkDev/database"
+	"networkDev/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// backupCmd 备份数据库
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "备份全部数据表到压缩归档",
+	Long: `将全部数据表导出为与数据库类型无关的 tar.gz 归档，可用于在 SQLite、MySQL、PostgreSQL 之间迁移数据。
+加密字段以密文形式导出，恢复到其他实例时需要配置相同的加密密钥。
+未指定 --file 时保存到 backup.dir 目录（默认 ./backups）。`,
+	PersistentPreRun: setupLogrusForCLI,
+	Run:              runBackup,
+}
+
+// restoreCmd 从归档恢复数据库
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "从备份归档恢复数据",
+	Long: `在单个事务中将备份归档导入当前配置的数据库，任一失败则全部回滚。
+--mode replace（默认）清空归档中包含的表后导入；--mode merge 仅导入不冲突的行。
+建议先使用 --dry-run 查看各表行数与冲突情况。`,
+	Args:             cobra.ExactArgs(1),
+	PersistentPreRun: setupLogrusForCLI,
+	Run:              runRestore,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+
+	backupCmd.Flags().StringP("file", "f", "", "备份文件路径（默认保存到 backup.dir 目录）")
+	backupCmd.Flags().String("dir", "", "备份目录（覆盖 backup.dir 配置）")
+	backupCmd.Flags().Int("retention", 0, "备份完成后仅保留最近的备份数量（0 表示不清理）")
+
+	addOutputFlag(restoreCmd)
+	restoreCmd.Flags().String("mode", database.RestoreModeReplace, "恢复模式：replace 或 merge")
+	restoreCmd.Flags().Bool("dry-run", false, "仅统计行数与冲突，不写入数据库")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runBackup 备份数据库
+func runBackup(cmd *cobra.Command, args []string) {
+	file, _ := cmd.Flags().GetString("file")
+	dir, _ := cmd.Flags().GetString("dir")
+	retention, _ := cmd.Flags().GetInt("retention")
+
+	initDatabase()
+
+	var manifest *database.BackupManifest
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			logrus.WithError(err).Fatal("创建备份文件失败")
+		}
+		manifest, err = database.CreateBackup(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(file)
+			logrus.WithError(err).Fatal("备份失败")
+		}
+	} else {
+		if dir == "" {
+			dir = viper.GetString("backup.dir")
+		}
+		if dir == "" {
+			dir = "./backups"
+		}
+
+		var err error
+		file, manifest, err = database.CreateBackupFile(dir)
+		if err != nil {
+			logrus.WithError(err).Fatal("备份失败")
+		}
+		if removed, err := database.PruneBackups(dir, retention); err != nil {
+			logrus.WithError(err).Error("清理旧备份失败")
+		} else {
+			for _, name := range removed {
+				fmt.Printf("已清理旧备份 %s\n", name)
+			}
+		}
+	}
+
+	rows := make([][]string, 0, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		rows = append(rows, []string{table.Name, strconv.Itoa(table.Rows)})
+	}
+	printTable([]string{"表名", "行数"}, rows)
+	fmt.Printf("备份已保存到 %s（表结构版本 %04d）\n", file, manifest.SchemaVersion)
+}
+
+// runRestore 从归档恢复数据库
+func runRestore(cmd *cobra.Command, args []string) {
+	format := getOutputFormat(cmd)
+	mode, _ := cmd.Flags().GetString("mode")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		logrus.WithError(err).Fatal("打开备份文件失败")
+	}
+	defer f.Close()
+
+	if dryRun {
+		// 预演只读取目标库，不执行迁移，也不写入默认设置
+		openDatabase()
+	} else {
+		// 先执行迁移，确保目标库表结构完整
+		initDatabase()
+	}
+
+	report, err := database.RestoreBackup(f, database.RestoreOptions{Mode: mode, DryRun: dryRun})
+	if err != nil {
+		logrus.WithError(err).Fatal("恢复失败")
+	}
+
+	// 清空Redis中的缓存并通知运行中的服务实例，恢复的数据立即生效
+	if !dryRun {
+		services.InvalidateAllCache(context.Background())
+	}
+
+	if format == outputJSON {
+		printJSON(report)
+		return
+	}
+
+	fmt.Printf("备份时间: %s，来源数据库: %s，表结构版本: %04d\n",
+		report.Manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"), report.Manifest.SourceDialect, report.Manifest.SchemaVersion)
+
+	rows := make([][]string, 0, len(report.Tables))
+	for _, table := range report.Tables {
+		rows = append(rows, []string{
+			table.Name,
+			strconv.Itoa(table.Rows),
+			strconv.FormatInt(table.Existing, 10),
+			strconv.Itoa(table.Conflicts),
+			strconv.Itoa(table.Imported),
+		})
+	}
+	printTable([]string{"表名", "归档行数", "现有行数", "冲突行数", "导入行数"}, rows)
+
+	for _, warning := range report.Warnings {
+		fmt.Printf("警告: %s\n", warning)
+	}
+	if dryRun {
+		fmt.Printf("预演模式（%s），未写入数据库\n", report.Mode)
+	} else {
+		fmt.Printf("恢复完成（%s）\n", report.Mode)
+	}
+}
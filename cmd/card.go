@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// cardCmd 卡密管理命令
+var cardCmd = &cobra.Command{
+	Use:              "card",
+	Short:            "卡密管理",
+	PersistentPreRun: setupLogrusForCLI,
+}
+
+// cardGenerateCmd 批量生成卡密
+var cardGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "批量生成卡密",
+	Long: `为指定应用批量生成卡密，同一次生成的卡密共享一个批次号。
+--duration 支持 m（分钟）、h（小时）、d（天）后缀，不带后缀时按分钟计算，例如 30d、12h、90。
+--valid 设置卡密的有效期，格式与 --duration 相同，超过有效期仍未使用的卡密会被定时任务标记为已过期。`,
+	Run: runCardGenerate,
+}
+
+// cardExportCmd 导出卡密
+var cardExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出卡密",
+	Long:  `按应用、状态、批次导出卡密，支持 txt（每行一个卡密）、csv、json 格式。`,
+	Run:   runCardExport,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(cardCmd)
+	cardCmd.AddCommand(cardGenerateCmd)
+	cardCmd.AddCommand(cardExportCmd)
+
+	addOutputFlag(cardGenerateCmd)
+	cardGenerateCmd.Flags().StringP("app", "a", "", "应用UUID或ID（必填）")
+	cardGenerateCmd.Flags().IntP("count", "c", 1, "生成数量")
+	cardGenerateCmd.Flags().StringP("duration", "d", "", "卡密时长，例如 30d、12h、90m（必填）")
+	cardGenerateCmd.Flags().String("valid", "", "有效期，例如 90d（默认长期有效）")
+	cardGenerateCmd.Flags().String("remark", "", "备注信息")
+	_ = cardGenerateCmd.MarkFlagRequired("app")
+	_ = cardGenerateCmd.MarkFlagRequired("duration")
+
+	cardExportCmd.Flags().StringP("app", "a", "", "应用UUID或ID（必填）")
+	cardExportCmd.Flags().String("status", "unused", "卡密状态：unused、used、disabled、expired、all")
+	cardExportCmd.Flags().String("batch", "", "按批次号筛选")
+	cardExportCmd.Flags().String("format", "txt", "导出格式：txt、csv、json")
+	cardExportCmd.Flags().StringP("file", "f", "", "导出到文件（默认输出到标准输出）")
+	_ = cardExportCmd.MarkFlagRequired("app")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runCardGenerate 批量生成卡密
+func runCardGenerate(cmd *cobra.Command, args []string) {
+	format := getOutputFormat(cmd)
+	appRef, _ := cmd.Flags().GetString("app")
+	count, _ := cmd.Flags().GetInt("count")
+	durationText, _ := cmd.Flags().GetString("duration")
+	validText, _ := cmd.Flags().GetString("valid")
+	remark, _ := cmd.Flags().GetString("remark")
+
+	if count < 1 || count > services.CardMaxGenerateCount {
+		logrus.Fatalf("生成数量必须在 1~%d 之间", services.CardMaxGenerateCount)
+	}
+	duration, err := services.ParseCardDuration(durationText)
+	if err != nil {
+		logrus.WithError(err).Fatal("卡密时长格式错误")
+	}
+	var expiresAt *time.Time
+	if strings.TrimSpace(validText) != "" {
+		valid, err := services.ParseCardDuration(validText)
+		if err != nil {
+			logrus.WithError(err).Fatal("卡密有效期格式错误")
+		}
+		t := time.Now().Add(time.Duration(valid) * time.Minute)
+		expiresAt = &t
+	}
+
+	initDatabase()
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取数据库连接失败")
+	}
+
+	app, err := findApp(db, appRef)
+	if err != nil {
+		logrus.WithError(err).Fatal("查找应用失败")
+	}
+
+	cards, err := services.GenerateCards(context.Background(), services.CardGenerateOptions{
+		AppUUID:   app.UUID,
+		Count:     count,
+		Duration:  duration,
+		ExpiresAt: expiresAt,
+		Remark:    remark,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("生成卡密失败")
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"app_uuid": app.UUID,
+		"count":    count,
+		"batch_no": cards[0].BatchNo,
+	}).Info("卡密生成成功")
+
+	if format == outputJSON {
+		printJSON(cards)
+		return
+	}
+	printCards(cards)
+}
+
+// runCardExport 导出卡密
+func runCardExport(cmd *cobra.Command, args []string) {
+	appRef, _ := cmd.Flags().GetString("app")
+	statusText, _ := cmd.Flags().GetString("status")
+	batchNo, _ := cmd.Flags().GetString("batch")
+	format, _ := cmd.Flags().GetString("format")
+	file, _ := cmd.Flags().GetString("file")
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != "txt" && format != "csv" && format != outputJSON {
+		logrus.WithField("format", format).Fatal("不支持的导出格式，可选值：txt、csv、json")
+	}
+	status, err := parseCardStatus(statusText)
+	if err != nil {
+		logrus.WithError(err).Fatal("卡密状态参数错误")
+	}
+
+	initDatabase()
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取数据库连接失败")
+	}
+
+	app, err := findApp(db, appRef)
+	if err != nil {
+		logrus.WithError(err).Fatal("查找应用失败")
+	}
+
+	query := db.Where("app_uuid = ?", app.UUID)
+	if status >= 0 {
+		query = query.Where("status = ?", status)
+	}
+	if batchNo = strings.TrimSpace(batchNo); batchNo != "" {
+		query = query.Where("batch_no = ?", batchNo)
+	}
+
+	var cards []models.Card
+	if err := query.Order("id ASC").Find(&cards).Error; err != nil {
+		logrus.WithError(err).Fatal("查询卡密失败")
+	}
+
+	var out io.Writer = os.Stdout
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			logrus.WithError(err).Fatal("创建导出文件失败")
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := writeCards(out, cards, format); err != nil {
+		logrus.WithError(err).Fatal("导出卡密失败")
+	}
+	if file != "" {
+		fmt.Printf("已导出 %d 个卡密到 %s\n", len(cards), file)
+	}
+}
+
+// ============================================================================
+// 辅助函数
+// ============================================================================
+
+// parseCardStatus 解析卡密状态参数，all 返回 -1
+func parseCardStatus(text string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "", "unused":
+		return models.CardStatusUnused, nil
+	case "used":
+		return models.CardStatusUsed, nil
+	case "disabled":
+		return models.CardStatusDisabled, nil
+	case "expired":
+		return models.CardStatusExpired, nil
+	case "all":
+		return -1, nil
+	default:
+		return 0, fmt.Errorf("无效的卡密状态: %s", text)
+	}
+}
+
+// writeCards 按指定格式写出卡密
+func writeCards(w io.Writer, cards []models.Card, format string) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(cards)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"card_key", "app_uuid", "duration", "status", "batch_no", "remark", "created_at"}); err != nil {
+			return err
+		}
+		for _, card := range cards {
+			record := []string{
+				card.CardKey,
+				card.AppUUID,
+				strconv.Itoa(card.Duration),
+				models.CardStatusText(card.Status),
+				card.BatchNo,
+				card.Remark,
+				formatTime(&card.CreatedAt),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		for _, card := range cards {
+			if _, err := fmt.Fprintln(w, card.CardKey); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// printCards 以表格形式输出卡密列表
+func printCards(cards []models.Card) {
+	rows := make([][]string, 0, len(cards))
+	for _, card := range cards {
+		rows = append(rows, []string{
+			card.CardKey,
+			formatCardDuration(card.Duration),
+			models.CardStatusText(card.Status),
+			card.BatchNo,
+		})
+	}
+	printTable([]string{"卡密", "时长", "状态", "批次号"}, rows)
+}
+
+// formatCardDuration 将分钟数格式化为易读的时长
+func formatCardDuration(minutes int) string {
+	switch {
+	case minutes%(24*60) == 0:
+		return fmt.Sprintf("%d天", minutes/(24*60))
+	case minutes%60 == 0:
+		return fmt.Sprintf("%d小时", minutes/60)
+	default:
+		return fmt.Sprintf("%d分钟", minutes)
+	}
+}
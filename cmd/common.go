@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"networkDev/database"
+	"networkDev/utils"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 命令行输出格式
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// ============================================================================
+// 数据库初始化
+// ============================================================================
+
+// openDatabase 初始化加密组件与数据库连接，不执行迁移
+// 部分迁移需要加解密数据，因此同样要求加密密钥配置正确
+func openDatabase() {
+	if err := utils.InitCrypto(); err != nil {
+		logrus.WithError(err).Fatal("加密密钥初始化失败")
+	}
+	if _, err := database.Init(); err != nil {
+		logrus.WithError(err).Fatal("数据库初始化失败")
+	}
+}
+
+// initDatabase 按服务启动相同的流程初始化数据库
+// 依次初始化加密组件、数据库连接、执行迁移并写入默认系统设置
+func initDatabase() {
+	openDatabase()
+
+	// 执行自动迁移（确保表结构存在）
+	if err := database.AutoMigrate(); err != nil {
+		logrus.WithError(err).Fatal("数据库自动迁移失败")
+	}
+	// 初始化默认系统设置（包含管理员账号）
+	if err := database.SeedDefaultSettings(); err != nil {
+		logrus.WithError(err).Fatal("默认系统设置初始化失败")
+	}
+}
+
+// ============================================================================
+// 输出辅助
+// ============================================================================
+
+// setupLogrusForCLI 为输出数据的管理命令配置日志
+// 日志改为输出到标准错误，标准输出只保留命令结果，便于管道处理
+func setupLogrusForCLI(cmd *cobra.Command, args []string) {
+	logOutput = os.Stderr
+	setupLogrusForNonHTTP()
+}
+
+// addOutputFlag 为命令添加 --output 输出格式参数
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", outputTable, "输出格式：table 或 json")
+}
+
+// getOutputFormat 获取并校验输出格式
+func getOutputFormat(cmd *cobra.Command) string {
+	format, _ := cmd.Flags().GetString("output")
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format != outputTable && format != outputJSON {
+		logrus.WithField("output", format).Fatal("不支持的输出格式，可选值：table、json")
+	}
+	return format
+}
+
+// printJSON 以缩进格式输出 JSON
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		logrus.WithError(err).Fatal("输出 JSON 失败")
+	}
+}
+
+// printTable 以对齐的表格形式输出
+func printTable(headers []string, rows [][]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// formatTime 格式化时间，零值显示为 -
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
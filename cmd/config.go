@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"networkDev/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// configCmd 配置文件命令
+// 覆盖根命令的 PersistentPreRun：配置文件可能尚不存在或内容有误，不能提前加载
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "配置文件管理",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		logOutput = os.Stderr
+		setupBasicLogrus()
+	},
+}
+
+// configInitCmd 生成默认配置文件
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "生成默认配置文件（包含随机生成的安全密钥）",
+	Run:   runConfigInit,
+}
+
+// configValidateCmd 校验配置文件
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "校验配置文件内容",
+	Run:   runConfigValidate,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configInitCmd.Flags().BoolP("force", "f", false, "覆盖已存在的配置文件")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runConfigInit 生成默认配置文件
+func runConfigInit(cmd *cobra.Command, args []string) {
+	force, _ := cmd.Flags().GetBool("force")
+	path := resolveConfigPath()
+
+	if _, err := os.Stat(path); err == nil && !force {
+		logrus.WithField("file", path).Fatal("配置文件已存在，如需覆盖请使用 --force")
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logrus.WithError(err).Fatal("检查配置文件失败")
+	}
+
+	if err := config.CreateDefaultConfig(path); err != nil {
+		logrus.WithError(err).Fatal("写入默认配置文件失败")
+	}
+	fmt.Printf("已生成默认配置文件: %s\n", path)
+}
+
+// runConfigValidate 校验配置文件
+func runConfigValidate(cmd *cobra.Command, args []string) {
+	path := resolveConfigPath()
+
+	viper.SetConfigFile(path)
+	viper.SetConfigType("json")
+	config.BindEnv(viper.GetViper())
+	if err := viper.ReadInConfig(); err != nil {
+		logrus.WithError(err).WithField("file", path).Fatal("读取配置文件失败")
+	}
+
+	if _, err := config.ValidateConfig(); err != nil {
+		logrus.WithError(err).WithField("file", path).Fatal("配置文件校验未通过")
+	}
+	fmt.Printf("配置文件校验通过: %s\n", path)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"networkDev/config"
+	"networkDev/database"
+	"networkDev/utils"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// cryptoCmd 加密密钥管理命令
+var cryptoCmd = &cobra.Command{
+	Use:   "crypto",
+	Short: "加密密钥管理",
+	Long:  `管理用于加密应用密钥、接口私钥等敏感数据的主密钥。`,
+}
+
+// cryptoRotateCmd 使用活动密钥重新加密所有敏感数据
+var cryptoRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "使用活动密钥重新加密所有敏感数据",
+	Long: `密钥轮换步骤：
+  1. 在 security.encryption_keys 中添加新密钥，并将 security.active_key_id 指向新密钥
+  2. 执行本命令，将所有使用旧密钥加密的数据重新加密
+  3. 确认无误后，从配置中移除旧密钥`,
+	Run: runCryptoRotate,
+}
+
+// cryptoGenerateKeyCmd 生成新的加密密钥
+var cryptoGenerateKeyCmd = &cobra.Command{
+	Use:   "generate-key",
+	Short: "生成新的加密密钥",
+	Run:   runCryptoGenerateKey,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(cryptoCmd)
+	cryptoCmd.AddCommand(cryptoRotateCmd)
+	cryptoCmd.AddCommand(cryptoGenerateKeyCmd)
+
+	cryptoRotateCmd.Flags().Bool("dry-run", false, "只统计需要重新加密的数据，不写入数据库")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runCryptoRotate 执行密钥轮换
+func runCryptoRotate(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	openDatabase()
+	activeID, _ := utils.ActiveKeyID()
+
+	count, err := database.RotateEncryptionKey(dryRun)
+	if err != nil {
+		logrus.WithError(err).Fatal("重新加密敏感数据失败")
+	}
+
+	if dryRun {
+		fmt.Printf("活动密钥: %s，需要重新加密 %d 项数据\n", activeID, count)
+		return
+	}
+	fmt.Printf("活动密钥: %s，已重新加密 %d 项数据\n", activeID, count)
+}
+
+// runCryptoGenerateKey 生成并输出新的加密密钥
+func runCryptoGenerateKey(cmd *cobra.Command, args []string) {
+	key, err := config.GenerateSecureEncryptionKey()
+	if err != nil {
+		logrus.WithError(err).Fatal("生成加密密钥失败")
+	}
+	fmt.Println(key)
+}
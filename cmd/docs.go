@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"networkDev/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// docsCmd 接口文档命令
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "接口文档（OpenAPI）",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		setupLogrusForCLI(cmd, args)
+		// 构建路由表时不输出 gin 的调试信息
+		gin.SetMode(gin.ReleaseMode)
+	},
+}
+
+// docsExportCmd 导出 OpenAPI 文档
+var docsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出 OpenAPI 3 文档（JSON）",
+	Long: `按当前配置（后台路径前缀、指标接口路径）生成 OpenAPI 3 文档。
+默认输出到标准输出，可使用 --file 写入文件，便于客户端生成SDK或导入接口调试工具。`,
+	Run: runDocsExport,
+}
+
+// docsCheckCmd 检查接口文档是否与路由一致
+var docsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "检查所有路由是否都已登记到接口文档",
+	Long: `按当前配置构建完整路由表并与 openapi 包中的接口登记表比对。
+存在未登记的路由或已登记但不存在的接口时列出差异并以非零状态码退出，可在CI中执行。`,
+	Run: runDocsCheck,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsExportCmd)
+	docsCmd.AddCommand(docsCheckCmd)
+
+	docsExportCmd.Flags().StringP("file", "f", "", "输出文件路径（默认输出到标准输出）")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runDocsExport 导出 OpenAPI 文档
+func runDocsExport(cmd *cobra.Command, args []string) {
+	data, err := json.MarshalIndent(server.OpenAPIDocument(), "", "  ")
+	if err != nil {
+		logrus.WithError(err).Fatal("生成接口文档失败")
+	}
+	data = append(data, '\n')
+
+	file, _ := cmd.Flags().GetString("file")
+	if file == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		logrus.WithError(err).WithField("file", file).Fatal("写入接口文档失败")
+	}
+	fmt.Printf("已导出接口文档: %s\n", file)
+}
+
+// runDocsCheck 检查接口文档
+func runDocsCheck(cmd *cobra.Command, args []string) {
+	undocumented, stale := server.CheckDocs()
+	for _, route := range undocumented {
+		fmt.Printf("未登记的路由: %s\n", route)
+	}
+	for _, route := range stale {
+		fmt.Printf("已登记但未注册的接口: %s\n", route)
+	}
+	if len(undocumented) > 0 || len(stale) > 0 {
+		fmt.Println("请在 openapi/operations.go 中同步接口登记表")
+		os.Exit(1)
+	}
+	fmt.Println("接口文档与路由一致")
+}
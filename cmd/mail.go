@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"networkDev/models"
+	"networkDev/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// mailCmd 邮件命令
+var mailCmd = &cobra.Command{
+	Use:              "mail",
+	Short:            "邮件发送维护",
+	PersistentPreRun: setupLogrusForCLI,
+}
+
+// mailTestCmd 发送测试邮件
+var mailTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "发送测试邮件",
+	Long: `使用配置文件中的 mail 段同步发送一封测试邮件，用于检查 SMTP 配置。
+发送结果会写入邮件发送记录，失败时输出SMTP服务器返回的错误信息，不会自动重试。`,
+	Run: runMailTest,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(mailCmd)
+	mailCmd.AddCommand(mailTestCmd)
+
+	mailTestCmd.Flags().StringP("to", "t", "", "收件人地址（必填）")
+	_ = mailTestCmd.MarkFlagRequired("to")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runMailTest 发送测试邮件
+func runMailTest(cmd *cobra.Command, args []string) {
+	to, _ := cmd.Flags().GetString("to")
+	to = strings.TrimSpace(to)
+	if _, err := mail.ParseAddress(to); err != nil {
+		logrus.Fatal("收件人地址格式错误")
+	}
+
+	initDatabase()
+	message, err := services.SendTestMail(context.Background(), to)
+	if err != nil {
+		logrus.WithError(err).Fatal("发送测试邮件失败")
+	}
+	if message.Status != models.MailStatusSent {
+		logrus.Fatalf("发送测试邮件失败: %s", message.Error)
+	}
+	fmt.Printf("测试邮件已发送到 %s\n", to)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"networkDev/database"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// ============================================================================
+// 命令定义
+// ============================================================================
+
+// migrateCmd 数据库迁移命令
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "数据库版本化迁移",
+	Long:  `管理数据库表结构版本。迁移记录保存在 schema_migrations 表中，服务启动时会自动执行未应用的迁移。`,
+}
+
+// migrateUpCmd 执行未应用的迁移
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "执行未应用的迁移",
+	Run:   runMigrateUp,
+}
+
+// migrateDownCmd 回滚最近的迁移
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "回滚最近执行的迁移",
+	Run:   runMigrateDown,
+}
+
+// migrateStatusCmd 查看迁移状态
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看迁移执行状态",
+	Run:   runMigrateStatus,
+}
+
+// ============================================================================
+// 初始化函数
+// ============================================================================
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+
+	migrateUpCmd.Flags().Int("to", 0, "升级到指定版本（默认升级到最新版本）")
+	migrateDownCmd.Flags().Int("steps", 1, "回滚的迁移数量")
+}
+
+// ============================================================================
+// 主要函数
+// ============================================================================
+
+// runMigrateUp 执行未应用的迁移
+func runMigrateUp(cmd *cobra.Command, args []string) {
+	target, _ := cmd.Flags().GetInt("to")
+	openDatabase()
+
+	applied, err := database.MigrateUp(target)
+	for _, m := range applied {
+		fmt.Printf("已执行 %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		logrus.WithError(err).Fatal("数据库迁移执行失败")
+	}
+	if len(applied) == 0 {
+		fmt.Println("没有需要执行的迁移")
+	}
+}
+
+// runMigrateDown 回滚最近的迁移
+func runMigrateDown(cmd *cobra.Command, args []string) {
+	steps, _ := cmd.Flags().GetInt("steps")
+	openDatabase()
+
+	rolled, err := database.MigrateDown(steps)
+	for _, m := range rolled {
+		fmt.Printf("已回滚 %04d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		logrus.WithError(err).Fatal("数据库迁移回滚失败")
+	}
+	if len(rolled) == 0 {
+		fmt.Println("没有可以回滚的迁移")
+	}
+}
+
+// runMigrateStatus 输出迁移状态
+func runMigrateStatus(cmd *cobra.Command, args []string) {
+	openDatabase()
+
+	states, err := database.MigrationStatus()
+	if err != nil {
+		logrus.WithError(err).Fatal("获取迁移状态失败")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "版本\t名称\t状态\t执行时间")
+	for _, state := range states {
+		status := "未执行"
+		appliedAt := "-"
+		if state.Applied {
+			status = "已执行"
+			appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+	}
+	w.Flush()
+}
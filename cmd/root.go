@@ -19,6 +19,10 @@ import (
 
 var cfgFile string
 
+// logOutput 控制台日志输出目标
+// 输出数据的管理命令将其切换为标准错误，避免日志混入表格或 JSON 输出
+var logOutput io.Writer = os.Stdout
+
 // ============================================================================
 // 命令定义
 // ============================================================================
@@ -64,34 +68,10 @@ func init() {
 // setupLogrusForNonHTTP 配置logrus用于非HTTP日志
 // 在加载配置文件之前进行基本的logrus设置
 func setupLogrusForNonHTTP() {
-	// 设置日志格式
-	logrus.SetFormatter(&logrus.TextFormatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-		FullTimestamp:   true,
-		ForceColors:     false,
-		DisableColors:   true,
-	})
+	setupBasicLogrus()
 
-	// 设置默认日志级别
-	logrus.SetLevel(logrus.InfoLevel)
-
-	// 设置输出目标（稍后会根据配置文件调整）
-	logrus.SetOutput(os.Stdout)
-	if cfgFile != "" {
-		// 使用命令行指定的配置文件
-		config.Init(cfgFile)
-	} else {
-		// 使用默认配置文件路径，基于可执行文件所在目录
-		execPath, err := os.Executable()
-		if err != nil {
-			// 如果获取可执行文件路径失败，使用当前工作目录
-			config.Init("./config.json")
-		} else {
-			execDir := filepath.Dir(execPath)
-			configPath := filepath.Join(execDir, "config.json")
-			config.Init(configPath)
-		}
-	}
+	// 加载配置文件
+	config.Init(resolveConfigPath())
 
 	// 根据配置文件进一步配置logrus
 	setupLogrusFromConfig()
@@ -103,6 +83,32 @@ func setupLogrusForNonHTTP() {
 	logrus.Info("配置文件加载完成")
 }
 
+// setupBasicLogrus 设置基础日志格式、级别与输出目标（稍后会根据配置文件调整）
+func setupBasicLogrus() {
+	// 设置日志格式
+	logger.SetFormat(logger.FormatText)
+
+	// 设置默认日志级别
+	logrus.SetLevel(logrus.InfoLevel)
+
+	// 设置输出目标
+	logrus.SetOutput(logOutput)
+}
+
+// resolveConfigPath 获取配置文件路径
+// 优先使用命令行指定的配置文件，否则使用可执行文件所在目录下的 config.json
+func resolveConfigPath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		// 如果获取可执行文件路径失败，使用当前工作目录
+		return "./config.json"
+	}
+	return filepath.Join(filepath.Dir(execPath), "config.json")
+}
+
 // initConfig 读取配置文件和环境变量
 func initConfig() {
 
@@ -111,6 +117,9 @@ func initConfig() {
 // setupLogrusFromConfig 根据配置文件进一步配置logrus
 // 设置日志级别和输出目标，支持日志切割功能
 func setupLogrusFromConfig() {
+	// 设置日志格式（text/json）
+	logger.SetFormat(viper.GetString("log.format"))
+
 	// 设置日志级别
 	if level := viper.GetString("log.level"); level != "" {
 		if logLevel, err := logrus.ParseLevel(level); err == nil {
@@ -138,7 +147,7 @@ func setupLogrusFromConfig() {
 		}
 
 		// 同时输出到控制台和文件（带日志切割）
-		multiWriter := io.MultiWriter(os.Stdout, lumberjackLogger)
+		multiWriter := io.MultiWriter(logOutput, lumberjackLogger)
 		logrus.SetOutput(multiWriter)
 
 		logrus.WithFields(logrus.Fields{
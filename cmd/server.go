@@ -10,11 +10,14 @@ import (
 	"syscall"
 	"time"
 
-	"networkDev/database"
+	"networkDev/config"
 	"networkDev/middleware"
 	"networkDev/server"
+	"networkDev/services"
 	"networkDev/utils"
 	"networkDev/utils/logger"
+	"networkDev/utils/tlsutil"
+	"networkDev/utils/tracing"
 	"networkDev/web"
 
 	"github.com/gin-gonic/gin"
@@ -35,6 +38,20 @@ var serverCmd = &cobra.Command{
 	Run:   runServer,
 }
 
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// listener 服务监听
+// - tls：使用 server.TLSConfig 中的证书提供HTTPS
+// - critical：启动失败时退出进程（主服务）
+type listener struct {
+	name     string
+	server   *http.Server
+	tls      bool
+	critical bool
+}
+
 // ============================================================================
 // 初始化函数
 // ============================================================================
@@ -63,28 +80,68 @@ func runServer(cmd *cobra.Command, args []string) {
 	logger := logger.GetLogger()
 	logger.LogServerStart(host, port)
 
+	// 初始化链路追踪（需要在数据库初始化之前，以便注册查询回调）
+	if err := tracing.Init(); err != nil {
+		logrus.WithError(err).Fatal("链路追踪初始化失败")
+	}
+	if tracing.Enabled() {
+		logrus.WithField("exporter", viper.GetString("tracing.exporter")).Info("已启用链路追踪")
+	}
+
 	// 初始化Redis（如果配置存在，失败不致命）
 	utils.InitRedis()
 
-	// 初始化数据库（根据 viper 配置选择 SQLite 或 MySQL）
-	// 如果初始化失败则回退并退出
-	if _, err := database.Init(); err != nil {
-		logrus.WithError(err).Fatal("数据库初始化失败")
+	// 初始化加密密钥、数据库连接、迁移与默认系统设置
+	// 任一步骤失败都会终止启动，避免使用不安全的默认密钥或不完整的表结构
+	initDatabase()
+
+	// 订阅其他实例发送的缓存失效、设置刷新等事件（未配置Redis时直接返回）
+	services.StartClusterSync()
+
+	// 加载告警通知渠道并订阅数据库健康检查结果
+	services.StartNotifier()
+
+	// 启动定时任务调度（会话清理、卡密过期、日志清理、定时备份等）
+	services.StartScheduler()
+
+	// 设置可信代理，决定是否采信 X-Forwarded-For 等客户端地址头部
+	if err := utils.SetTrustedProxies(viper.GetStringSlice("server.trusted_proxies")); err != nil {
+		logrus.WithError(err).Fatal("可信代理配置错误")
 	}
-	// 执行自动迁移（确保表结构存在）
-	if err := database.AutoMigrate(); err != nil {
-		logrus.WithError(err).Fatal("数据库自动迁移失败")
+
+	// 创建HTTP服务器
+	httpServer := createHTTPServer(addr)
+	listeners := []listener{{name: "HTTP服务器", server: httpServer, critical: true}}
+
+	// 创建独立监听的管理后台服务（未配置 server.admin.listen 时为 nil）
+	adminServer := createAdminServer()
+
+	// 启用HTTPS时加载证书，并按需启动HTTP跳转监听
+	reloader := setupTLS(httpServer)
+	if reloader != nil {
+		listeners[0].name = "HTTPS服务器"
+		listeners[0].tls = true
+		if redirectServer := newRedirectServer(host, port); redirectServer != nil {
+			listeners = append(listeners, listener{name: "HTTP跳转服务", server: redirectServer})
+		}
 	}
-	// 初始化默认系统设置（包含管理员账号）
-	if err := database.SeedDefaultSettings(); err != nil {
-		logrus.WithError(err).Fatal("默认系统设置初始化失败")
+
+	// 后台服务与主服务共用证书，保证启用HTTPS后的Secure Cookie可用
+	if adminServer != nil {
+		adminServer.TLSConfig = httpServer.TLSConfig
+		listeners = append(listeners, listener{name: "管理后台服务", server: adminServer, tls: reloader != nil})
 	}
 
-	// 创建HTTP服务器
-	server := createHTTPServer(addr)
+	// 创建独立监听的指标服务（未配置 metrics.listen 时为 nil）
+	if metricsServer := server.NewMetricsServer(); metricsServer != nil {
+		listeners = append(listeners, listener{name: "指标服务", server: metricsServer})
+	}
+
+	// 监听配置文件变化，运行时应用可热重载的配置项
+	config.Watch(applyLiveConfig)
 
 	// 启动服务器
-	startServer(server)
+	startServer(listeners, reloader)
 }
 
 // ============================================================================
@@ -109,6 +166,36 @@ func getServerPort(cmd *cobra.Command) int {
 
 // createHTTPServer 创建HTTP服务器
 func createHTTPServer(addr string) *http.Server {
+	router := newRouter()
+
+	// 注册路由
+	registerRoutes(router)
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+}
+
+// createAdminServer 创建独立监听的管理后台服务
+// 未配置 server.admin.listen 时返回 nil
+func createAdminServer() *http.Server {
+	listen := viper.GetString("server.admin.listen")
+	if listen == "" {
+		return nil
+	}
+
+	router := newRouter()
+	server.RegisterAdminServerRoutes(router)
+
+	return &http.Server{
+		Addr:    listen,
+		Handler: router,
+	}
+}
+
+// newRouter 创建挂载通用中间件与模板的Gin引擎
+func newRouter() *gin.Engine {
 	// 配置Gin模式和日志
 	configureGin()
 
@@ -121,6 +208,9 @@ func createHTTPServer(addr string) *http.Server {
 	// 添加日志中间件
 	router.Use(middleware.WrapHandler())
 
+	// 添加链路追踪中间件（在日志中间件之后，span中可以附带请求ID）
+	router.Use(middleware.Tracing())
+
 	// 添加开发模式中间件（统一管理开发模式功能）
 	router.Use(middleware.DevModeMiddleware(router))
 
@@ -129,13 +219,7 @@ func createHTTPServer(addr string) *http.Server {
 		logrus.WithError(err).Fatal("模板加载失败")
 	}
 
-	// 注册路由
-	registerRoutes(router)
-
-	return &http.Server{
-		Addr:    addr,
-		Handler: router,
-	}
+	return router
 }
 
 // loadTemplates 加载模板到Gin引擎
@@ -145,6 +229,7 @@ func loadTemplates(router *gin.Engine) error {
 		return err
 	}
 	router.SetHTMLTemplate(tmpl)
+	server.SetTemplatesLoaded()
 	return nil
 }
 
@@ -154,8 +239,9 @@ func registerRoutes(router *gin.Engine) {
 	server.RegisterRoutes(router)
 }
 
-// startServer 启动服务器并处理优雅关闭
-func startServer(server *http.Server) {
+// startServer 启动所有监听并处理优雅关闭与证书重新加载
+// reloader 为HTTPS证书加载器，未启用HTTPS时为 nil
+func startServer(listeners []listener, reloader *tlsutil.CertReloader) {
 	// 获取全局日志实例
 	logger := logger.GetLogger()
 
@@ -163,30 +249,108 @@ func startServer(server *http.Server) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// 在goroutine中启动服务器
-	go func() {
-		logger.WithField("addr", server.Addr).Info("HTTP服务器已启动")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogError(err, "服务器启动失败")
-			os.Exit(1)
-		}
-	}()
+	// SIGHUP 触发证书重新加载
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	// 在goroutine中启动各个监听
+	for _, l := range listeners {
+		go l.serve(logger)
+	}
 
-	// 等待中断信号
-	<-sigChan
+	// 等待中断信号，期间处理证书重新加载
+	for waiting := true; waiting; {
+		select {
+		case <-hupChan:
+			if reloader == nil {
+				logger.Info("收到 SIGHUP 信号，未启用HTTPS，忽略")
+				continue
+			}
+			if err := reloader.Reload(); err != nil {
+				logger.LogError(err, "重新加载证书失败，继续使用旧证书")
+			} else {
+				logger.Info("收到 SIGHUP 信号，已重新加载证书")
+			}
+		case <-sigChan:
+			waiting = false
+		}
+	}
 	// 清除终端上的 ^C 字符并移动光标到行首
 	fmt.Print("\r\033[K")
 	logger.Info("收到关闭信号，正在优雅关闭服务器...")
 
+	// 先将就绪状态置为失败，等待负载均衡摘除流量后再关闭服务
+	server.SetShuttingDown()
+	if delay := viper.GetInt("server.shutdown_delay"); delay > 0 {
+		logger.WithField("delay", delay).Info("等待负载均衡摘除流量")
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
 	// 创建一个带超时的上下文
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// 优雅关闭服务器
-	if err := server.Shutdown(ctx); err != nil {
-		logger.LogError(err, "服务器关闭时出错")
+	// 按启动的相反顺序关闭，主服务最后关闭
+	for i := len(listeners) - 1; i >= 0; i-- {
+		l := listeners[i]
+		if err := l.server.Shutdown(ctx); err != nil {
+			logger.LogError(err, l.name+"关闭时出错")
+		} else if l.critical {
+			logger.LogServerStop()
+		}
+	}
+
+	// 停止定时任务调度，等待正在执行的任务结束
+	if err := services.StopScheduler(ctx); err != nil {
+		logger.LogError(err, "等待定时任务结束超时")
+	}
+
+	// 服务关闭后导出剩余的span
+	if err := tracing.Shutdown(ctx); err != nil {
+		logger.LogError(err, "链路追踪关闭时出错")
+	}
+}
+
+// serve 启动监听，返回前阻塞
+func (l listener) serve(logger *logger.Logger) {
+	logger.WithField("addr", l.server.Addr).Info(l.name + "已启动")
+
+	var err error
+	if l.tls {
+		// 证书由 TLSConfig.GetCertificate 提供
+		err = l.server.ListenAndServeTLS("", "")
 	} else {
-		logger.LogServerStop()
+		err = l.server.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		logger.LogError(err, l.name+"启动失败")
+		if l.critical {
+			os.Exit(1)
+		}
+	}
+}
+
+// applyLiveConfig 应用热重载后变化的配置项
+func applyLiveConfig(cfg *config.AppConfig, changed []string) {
+	for _, key := range changed {
+		switch key {
+		case "log.level":
+			level, err := logrus.ParseLevel(cfg.Log.Level)
+			if err != nil {
+				level = logrus.InfoLevel
+			}
+			logrus.SetLevel(level)
+		case "log.format":
+			logger.SetFormat(cfg.Log.Format)
+		case "server.dev_mode":
+			middleware.SetDevMode(cfg.Server.DevMode)
+		case "server.trusted_proxies":
+			if err := utils.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+				logrus.WithError(err).Error("应用可信代理配置失败")
+			}
+		case "server.list_format":
+			utils.SetListFormat(cfg.Server.ListFormat)
+		}
 	}
 }
 
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/utils/tlsutil"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 辅助函数
+// ============================================================================
+
+// setupTLS 根据 server.tls 配置为服务加载证书
+// 未启用HTTPS时返回 nil；证书加载失败时终止启动
+func setupTLS(httpServer *http.Server) *tlsutil.CertReloader {
+	if !viper.GetBool("server.tls.enabled") {
+		return nil
+	}
+
+	minVersion, err := tlsutil.ParseMinVersion(viper.GetString("server.tls.min_version"))
+	if err != nil {
+		logrus.WithError(err).Fatal("HTTPS配置错误")
+	}
+	clientAuth, err := tlsutil.ParseClientAuth(viper.GetString("server.tls.client_auth"))
+	if err != nil {
+		logrus.WithError(err).Fatal("HTTPS配置错误")
+	}
+
+	reloader, err := tlsutil.NewCertReloader(
+		viper.GetString("server.tls.cert_file"),
+		viper.GetString("server.tls.key_file"),
+		viper.GetString("server.tls.client_ca_file"),
+	)
+	if err != nil {
+		logrus.WithError(err).Fatal("HTTPS证书加载失败")
+	}
+	httpServer.TLSConfig = reloader.TLSConfig(minVersion, clientAuth)
+
+	// 定期检查证书文件变化（进程退出时一并结束）
+	if interval := viper.GetInt("server.tls.reload_interval"); interval > 0 {
+		reloader.Watch(time.Duration(interval)*time.Second, nil)
+	}
+	return reloader
+}
+
+// newRedirectServer 创建HTTP跳转HTTPS的监听服务
+// 未启用 server.tls.redirect_http 时返回 nil
+func newRedirectServer(host string, httpsPort int) *http.Server {
+	if !viper.GetBool("server.tls.redirect_http") {
+		return nil
+	}
+
+	httpPort := viper.GetInt("server.tls.http_port")
+	if httpPort == 0 {
+		httpPort = 80
+	}
+
+	return &http.Server{
+		Addr:              net.JoinHostPort(host, strconv.Itoa(httpPort)),
+		Handler:           redirectToHTTPS(httpsPort),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// redirectToHTTPS 将请求跳转到相同主机的HTTPS地址
+// GET/HEAD 使用 301，其他方法使用 308 保留请求方法与请求体
+func redirectToHTTPS(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]")
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+
+		status := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), status)
+	})
+}
@@ -19,18 +19,46 @@ import (
 // ServerConfig 服务器配置结构体
 // 包含服务器运行相关的配置信息
 type ServerConfig struct {
-	Host    string `json:"host" mapstructure:"host"`         // 服务器监听地址
-	Port    int    `json:"port" mapstructure:"port"`         // 服务器监听端口
-	Dist    string `json:"dist" mapstructure:"dist"`         // 静态文件目录
-	DevMode bool   `json:"dev_mode" mapstructure:"dev_mode"` // 开发模式（跳过验证码等）
+	Host           string      `json:"host" mapstructure:"host"`                       // 服务器监听地址
+	Port           int         `json:"port" mapstructure:"port"`                       // 服务器监听端口
+	Dist           string      `json:"dist" mapstructure:"dist"`                       // 静态文件目录
+	DevMode        bool        `json:"dev_mode" mapstructure:"dev_mode"`               // 开发模式（跳过验证码等）
+	ShutdownDelay  int         `json:"shutdown_delay" mapstructure:"shutdown_delay"`   // 优雅关闭前等待负载均衡摘除流量的时间（秒）
+	TrustedProxies []string    `json:"trusted_proxies" mapstructure:"trusted_proxies"` // 可信反向代理的IP或CIDR，仅信任其转发的客户端地址头部
+	ListFormat     string      `json:"list_format" mapstructure:"list_format"`         // 分页列表响应格式（layui/standard）
+	TLS            TLSConfig   `json:"tls" mapstructure:"tls"`                         // HTTPS配置
+	Admin          AdminConfig `json:"admin" mapstructure:"admin"`                     // 管理后台配置
+}
+
+// AdminConfig 管理后台配置结构体
+// 配置 listen 后管理后台只在独立地址上提供，主服务不再注册后台路由
+type AdminConfig struct {
+	Prefix   string   `json:"prefix" mapstructure:"prefix"`       // 后台路径前缀（如 /admin）
+	Listen   string   `json:"listen" mapstructure:"listen"`       // 独立监听地址（如 127.0.0.1:8081），为空时与主服务共用端口
+	AllowIPs []string `json:"allow_ips" mapstructure:"allow_ips"` // 允许访问后台的IP或CIDR，为空时不限制
+}
+
+// TLSConfig HTTPS配置结构体
+// 证书文件变化或收到 SIGHUP 信号时自动重新加载，不中断已建立的连接
+type TLSConfig struct {
+	Enabled        bool   `json:"enabled" mapstructure:"enabled"`                 // 是否启用HTTPS
+	CertFile       string `json:"cert_file" mapstructure:"cert_file"`             // 证书文件路径（可包含中间证书）
+	KeyFile        string `json:"key_file" mapstructure:"key_file"`               // 私钥文件路径
+	MinVersion     string `json:"min_version" mapstructure:"min_version"`         // 最低TLS版本（1.0/1.1/1.2/1.3）
+	ClientCAFile   string `json:"client_ca_file" mapstructure:"client_ca_file"`   // 客户端证书CA文件（可选）
+	ClientAuth     string `json:"client_auth" mapstructure:"client_auth"`         // 客户端证书校验方式（none/request/require）
+	RedirectHTTP   bool   `json:"redirect_http" mapstructure:"redirect_http"`     // 是否启动HTTP跳转HTTPS监听
+	HTTPPort       int    `json:"http_port" mapstructure:"http_port"`             // HTTP跳转监听端口
+	ReloadInterval int    `json:"reload_interval" mapstructure:"reload_interval"` // 证书文件变化检查间隔（秒），0表示仅在SIGHUP时重新加载
 }
 
 // DatabaseConfig 数据库配置结构体
 // 包含数据库连接相关的配置信息
 type DatabaseConfig struct {
-	Type   string       `json:"type" mapstructure:"type"`     // 数据库类型（mysql/sqlite）
-	MySQL  MySQLConfig  `json:"mysql" mapstructure:"mysql"`   // MySQL配置
-	SQLite SQLiteConfig `json:"sqlite" mapstructure:"sqlite"` // SQLite配置
+	Type     string         `json:"type" mapstructure:"type"`         // 数据库类型（mysql/sqlite/postgres）
+	MySQL    MySQLConfig    `json:"mysql" mapstructure:"mysql"`       // MySQL配置
+	SQLite   SQLiteConfig   `json:"sqlite" mapstructure:"sqlite"`     // SQLite配置
+	Postgres PostgresConfig `json:"postgres" mapstructure:"postgres"` // PostgreSQL配置
 }
 
 // MySQLConfig MySQL数据库配置结构体
@@ -46,6 +74,20 @@ type MySQLConfig struct {
 	MaxOpenConns int    `json:"max_open_conns" mapstructure:"max_open_conns"` // 最大打开连接数
 }
 
+// PostgresConfig PostgreSQL数据库配置结构体
+// 包含PostgreSQL数据库连接的详细配置信息
+type PostgresConfig struct {
+	Host         string `json:"host" mapstructure:"host"`                     // 数据库主机地址
+	Port         int    `json:"port" mapstructure:"port"`                     // 数据库端口
+	Username     string `json:"username" mapstructure:"username"`             // 数据库用户名
+	Password     string `json:"password" mapstructure:"password"`             // 数据库密码
+	Database     string `json:"database" mapstructure:"database"`             // 数据库名称
+	SSLMode      string `json:"sslmode" mapstructure:"sslmode"`               // SSL模式（disable/require/verify-ca/verify-full等）
+	Schema       string `json:"schema" mapstructure:"schema"`                 // 使用的schema，默认public
+	MaxIdleConns int    `json:"max_idle_conns" mapstructure:"max_idle_conns"` // 最大空闲连接数
+	MaxOpenConns int    `json:"max_open_conns" mapstructure:"max_open_conns"` // 最大打开连接数
+}
+
 // SQLiteConfig SQLite数据库配置结构体
 // 包含SQLite数据库文件路径配置
 type SQLiteConfig struct {
@@ -65,6 +107,7 @@ type RedisConfig struct {
 // 包含日志记录相关的配置信息
 type LogConfig struct {
 	Level      string `json:"level" mapstructure:"level"`             // 日志级别
+	Format     string `json:"format" mapstructure:"format"`           // 日志格式（text/json），json 便于日志系统采集解析
 	File       string `json:"file" mapstructure:"file"`               // 日志文件路径
 	MaxSize    int    `json:"max_size" mapstructure:"max_size"`       // 单个日志文件最大大小(MB)
 	MaxBackups int    `json:"max_backups" mapstructure:"max_backups"` // 保留的旧日志文件数量
@@ -80,22 +123,114 @@ type CookieConfig struct {
 	MaxAge   int    `json:"max_age" mapstructure:"max_age"`     // Cookie最大存活时间（秒）
 }
 
+// EncryptionKeyConfig 加密密钥配置结构体
+// 用于密钥轮换：每个密钥带有唯一ID，密文以密钥ID为前缀
+type EncryptionKeyConfig struct {
+	ID  string `json:"id" mapstructure:"id"`   // 密钥ID（字母、数字、下划线、短横线）
+	Key string `json:"key" mapstructure:"key"` // 密钥值
+}
+
 // SecurityConfig 安全配置结构体
 // 包含应用程序安全相关的配置信息
 type SecurityConfig struct {
-	JWTSecret     string       `json:"jwt_secret" mapstructure:"jwt_secret"`         // JWT签名密钥
-	EncryptionKey string       `json:"encryption_key" mapstructure:"encryption_key"` // 数据加密密钥
-	JWTRefresh    int          `json:"jwt_refresh" mapstructure:"jwt_refresh"`       // JWT令牌刷新阈值（小时）
-	Cookie        CookieConfig `json:"cookie" mapstructure:"cookie"`                 // Cookie配置
+	JWTSecret        string                `json:"jwt_secret" mapstructure:"jwt_secret"`                     // JWT签名密钥
+	EncryptionKey    string                `json:"encryption_key" mapstructure:"encryption_key"`             // 数据加密密钥（密钥ID为 default）
+	EncryptionKeys   []EncryptionKeyConfig `json:"encryption_keys,omitempty" mapstructure:"encryption_keys"` // 轮换密钥列表
+	ActiveKeyID      string                `json:"active_key_id,omitempty" mapstructure:"active_key_id"`     // 用于加密的活动密钥ID，其余密钥仅用于解密
+	JWTRefresh       int                   `json:"jwt_refresh" mapstructure:"jwt_refresh"`                   // JWT令牌刷新阈值（小时）
+	AuditHistoryDays int                   `json:"audit_history_days" mapstructure:"audit_history_days"`     // 审计日志保留天数，0 表示不清理
+	Cookie           CookieConfig          `json:"cookie" mapstructure:"cookie"`                             // Cookie配置
+}
+
+// BackupConfig 定时备份配置结构体
+// 启用后服务运行期间按固定间隔生成备份归档，并按数量保留最近的备份
+type BackupConfig struct {
+	Enabled   bool   `json:"enabled" mapstructure:"enabled"`     // 是否启用定时备份
+	Dir       string `json:"dir" mapstructure:"dir"`             // 备份文件保存目录
+	Interval  int    `json:"interval" mapstructure:"interval"`   // 备份间隔（小时）
+	Retention int    `json:"retention" mapstructure:"retention"` // 保留的备份数量，0 表示不清理
+}
+
+// MetricsConfig Prometheus 指标配置结构体
+// - 配置 listen 时在独立地址上提供指标接口，不挂载到主服务
+// - 配置 token 时需要携带 Authorization: Bearer <token> 访问
+// - 两者都未配置时仅允许本机访问
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled" mapstructure:"enabled"` // 是否启用指标接口
+	Path    string `json:"path" mapstructure:"path"`       // 指标接口路径
+	Token   string `json:"token" mapstructure:"token"`     // 访问令牌
+	Listen  string `json:"listen" mapstructure:"listen"`   // 独立监听地址（如 127.0.0.1:9090）
+}
+
+// CacheConfig 客户端接口热点数据缓存配置结构体
+// 应用、接口配置、变量与函数按 进程内LRU -> Redis -> 数据库 的顺序读取，
+// 后台修改后立即失效，多实例之间通过 Redis 发布订阅同步失效
+type CacheConfig struct {
+	LocalSize int `json:"local_size" mapstructure:"local_size"` // 进程内缓存条目上限，0 表示关闭进程内缓存
+	LocalTTL  int `json:"local_ttl" mapstructure:"local_ttl"`   // 进程内缓存有效期（秒），失效消息丢失时的兜底，0 表示不过期
+	RedisTTL  int `json:"redis_ttl" mapstructure:"redis_ttl"`   // Redis缓存有效期（秒），0 表示不过期
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置结构体
+// 启用后为HTTP请求、数据库查询、Redis缓存与加解密操作创建span
+// - otlp：通过 OTLP/HTTP 发送到 Collector、Jaeger、Tempo 等
+// - stdout/file：以JSON格式输出到标准输出或文件，便于本地排查
+type TracingConfig struct {
+	Enabled     bool    `json:"enabled" mapstructure:"enabled"`           // 是否启用链路追踪
+	Exporter    string  `json:"exporter" mapstructure:"exporter"`         // 导出方式（otlp/stdout/file）
+	Endpoint    string  `json:"endpoint" mapstructure:"endpoint"`         // OTLP/HTTP 接收地址（如 localhost:4318）
+	Insecure    bool    `json:"insecure" mapstructure:"insecure"`         // OTLP 是否使用明文HTTP连接
+	File        string  `json:"file" mapstructure:"file"`                 // file 导出方式的输出文件路径
+	SampleRatio float64 `json:"sample_ratio" mapstructure:"sample_ratio"` // 采样比例（0-1），上游已采样的请求始终记录
+	ServiceName string  `json:"service_name" mapstructure:"service_name"` // 上报的服务名称
+}
+
+// SchedulerConfig 定时任务配置结构体
+// 内置任务的启用状态与执行计划在管理后台修改，这里只控制调度器本身
+type SchedulerConfig struct {
+	Enabled     bool `json:"enabled" mapstructure:"enabled"`           // 是否启用定时任务调度器
+	HistoryDays int  `json:"history_days" mapstructure:"history_days"` // 任务执行记录保留天数，0 表示不清理
+}
+
+// WebhookConfig Webhook 推送配置结构体
+// 推送失败时按 1、2、4、8... 分钟的间隔重试，达到最大尝试次数后标记为失败
+type WebhookConfig struct {
+	Timeout     int `json:"timeout" mapstructure:"timeout"`           // 单次推送超时时间（秒），0 使用默认值
+	MaxAttempts int `json:"max_attempts" mapstructure:"max_attempts"` // 最大尝试次数（包括首次推送），0 使用默认值
+	HistoryDays int `json:"history_days" mapstructure:"history_days"` // 投递记录保留天数，0 表示不清理
+}
+
+// MailConfig SMTP 邮件配置结构体
+// 邮件写入发送队列后在后台发送，失败时按 1、2、4、8... 分钟的间隔重试
+type MailConfig struct {
+	Enabled            bool   `json:"enabled" mapstructure:"enabled"`                           // 是否启用邮件发送
+	Host               string `json:"host" mapstructure:"host"`                                 // SMTP 服务器地址
+	Port               int    `json:"port" mapstructure:"port"`                                 // SMTP 端口，0 按加密方式使用默认端口（tls 为 465，其他为 587）
+	Encryption         string `json:"encryption" mapstructure:"encryption"`                     // 加密方式：starttls、tls（隐式TLS）或 none（仅用于本地测试）
+	Username           string `json:"username" mapstructure:"username"`                         // SMTP 用户名，为空时不认证
+	Password           string `json:"password" mapstructure:"password"`                         // SMTP 密码或授权码
+	From               string `json:"from" mapstructure:"from"`                                 // 发件人地址
+	FromName           string `json:"from_name" mapstructure:"from_name"`                       // 发件人名称，为空时使用站点标题
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" mapstructure:"insecure_skip_verify"` // 跳过服务器证书校验（仅用于自签名证书的测试环境）
+	Timeout            int    `json:"timeout" mapstructure:"timeout"`                           // 单次发送超时时间（秒），0 使用默认值
+	MaxAttempts        int    `json:"max_attempts" mapstructure:"max_attempts"`                 // 最大尝试次数（包括首次发送），0 使用默认值
+	HistoryDays        int    `json:"history_days" mapstructure:"history_days"`                 // 发送记录保留天数，0 表示不清理
 }
 
 // AppConfig 应用配置结构体
 type AppConfig struct {
-	Server   ServerConfig   `json:"server" mapstructure:"server"`
-	Database DatabaseConfig `json:"database" mapstructure:"database"`
-	Redis    RedisConfig    `json:"redis" mapstructure:"redis"`
-	Log      LogConfig      `json:"log" mapstructure:"log"`
-	Security SecurityConfig `json:"security" mapstructure:"security"`
+	Server    ServerConfig    `json:"server" mapstructure:"server"`
+	Database  DatabaseConfig  `json:"database" mapstructure:"database"`
+	Redis     RedisConfig     `json:"redis" mapstructure:"redis"`
+	Log       LogConfig       `json:"log" mapstructure:"log"`
+	Security  SecurityConfig  `json:"security" mapstructure:"security"`
+	Backup    BackupConfig    `json:"backup" mapstructure:"backup"`
+	Metrics   MetricsConfig   `json:"metrics" mapstructure:"metrics"`
+	Tracing   TracingConfig   `json:"tracing" mapstructure:"tracing"`
+	Cache     CacheConfig     `json:"cache" mapstructure:"cache"`
+	Scheduler SchedulerConfig `json:"scheduler" mapstructure:"scheduler"`
+	Webhook   WebhookConfig   `json:"webhook" mapstructure:"webhook"`
+	Mail      MailConfig      `json:"mail" mapstructure:"mail"`
 }
 
 // ============================================================================
@@ -106,10 +241,29 @@ type AppConfig struct {
 func GetDefaultAppConfig() *AppConfig {
 	return &AppConfig{
 		Server: ServerConfig{
-			Host:    "0.0.0.0",
-			Port:    8080,
-			Dist:    "",
-			DevMode: false,
+			Host:           "0.0.0.0",
+			Port:           8080,
+			Dist:           "",
+			DevMode:        false,
+			ShutdownDelay:  0,
+			TrustedProxies: []string{},
+			ListFormat:     "layui",
+			TLS: TLSConfig{
+				Enabled:        false,
+				CertFile:       "",
+				KeyFile:        "",
+				MinVersion:     "1.2",
+				ClientCAFile:   "",
+				ClientAuth:     "none",
+				RedirectHTTP:   false,
+				HTTPPort:       80,
+				ReloadInterval: 60,
+			},
+			Admin: AdminConfig{
+				Prefix:   "/admin",
+				Listen:   "",
+				AllowIPs: []string{},
+			},
 		},
 		Database: DatabaseConfig{
 			Type: "sqlite",
@@ -126,6 +280,17 @@ func GetDefaultAppConfig() *AppConfig {
 			SQLite: SQLiteConfig{
 				Path: "./database.db",
 			},
+			Postgres: PostgresConfig{
+				Host:         "localhost",
+				Port:         5432,
+				Username:     "",
+				Password:     "",
+				Database:     "",
+				SSLMode:      "disable",
+				Schema:       "public",
+				MaxIdleConns: 10,
+				MaxOpenConns: 100,
+			},
 		},
 		Redis: RedisConfig{
 			Host:     "localhost",
@@ -135,15 +300,17 @@ func GetDefaultAppConfig() *AppConfig {
 		},
 		Log: LogConfig{
 			Level:      "info",
+			Format:     "text",
 			File:       "./logs/app.log",
 			MaxSize:    100,
 			MaxBackups: 5,
 			MaxAge:     30,
 		},
 		Security: SecurityConfig{
-			JWTSecret:     "",
-			EncryptionKey: "",
-			JWTRefresh:    6,
+			JWTSecret:        "",
+			EncryptionKey:    "",
+			JWTRefresh:       6,
+			AuditHistoryDays: 180,
 			Cookie: CookieConfig{
 				Secure:   true,
 				SameSite: "Lax",
@@ -151,6 +318,49 @@ func GetDefaultAppConfig() *AppConfig {
 				MaxAge:   86400,
 			},
 		},
+		Backup: BackupConfig{
+			Enabled:   false,
+			Dir:       "./backups",
+			Interval:  24,
+			Retention: 7,
+		},
+		Metrics: MetricsConfig{
+			Enabled: false,
+			Path:    "/metrics",
+			Token:   "",
+			Listen:  "",
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			Exporter:    "otlp",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			File:        "./logs/traces.json",
+			SampleRatio: 1,
+			ServiceName: "networkDev",
+		},
+		Cache: CacheConfig{
+			LocalSize: 1000,
+			LocalTTL:  60,
+			RedisTTL:  300,
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:     true,
+			HistoryDays: 30,
+		},
+		Webhook: WebhookConfig{
+			Timeout:     10,
+			MaxAttempts: 6,
+			HistoryDays: 30,
+		},
+		Mail: MailConfig{
+			Enabled:     false,
+			Port:        587,
+			Encryption:  "starttls",
+			Timeout:     15,
+			MaxAttempts: 5,
+			HistoryDays: 30,
+		},
 	}
 }
 
@@ -177,6 +387,9 @@ func Init(cfgFilePath string) {
 	viper.SetConfigType("json")
 	viper.AddConfigPath(".")
 
+	// 绑定 NETWORKDEV_* 环境变量，优先级高于配置文件
+	BindEnv(viper.GetViper())
+
 	if err := viper.ReadInConfig(); err != nil {
 		var pathError *fs.PathError
 		if errors.As(err, &pathError) {
@@ -241,7 +454,7 @@ func Init(cfgFilePath string) {
 			).Fatal("配置文件解析错误")
 		}
 	}
-	
+
 	// 只显示配置文件名，不显示完整路径
 	configFile := viper.ConfigFileUsed()
 	if configFile != "" {
@@ -256,6 +469,11 @@ func Init(cfgFilePath string) {
 		log.Info("使用默认配置")
 	}
 
+	// 只记录被覆盖的配置键，不输出值
+	if overrides := EnvOverrides(); len(overrides) > 0 {
+		log.WithField("keys", overrides).Info("环境变量覆盖配置")
+	}
+
 	// 验证配置
 	if _, err := ValidateConfig(); err != nil {
 		log.WithFields(
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// EnvPrefix 环境变量前缀
+// 配置键中的 . 替换为 _ 并转为大写，如 NETWORKDEV_SERVER_PORT 覆盖 server.port，
+// NETWORKDEV_SECURITY_JWT_SECRET 覆盖 security.jwt_secret
+const EnvPrefix = "NETWORKDEV"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// configField 配置项（叶子节点）
+type configField struct {
+	key  string
+	kind reflect.Kind
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// EnvName 获取配置键对应的环境变量名
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// EnvOverrides 获取当前被环境变量覆盖的配置键（按字母排序）
+// 仅返回键名，不包含值，避免在日志中泄露密钥
+func EnvOverrides() []string {
+	var keys []string
+	for _, field := range configFields(reflect.TypeOf(AppConfig{}), "") {
+		if _, ok := os.LookupEnv(EnvName(field.key)); ok {
+			keys = append(keys, field.key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// BindEnv 为viper实例绑定 NETWORKDEV_* 环境变量
+// - 环境变量优先级高于配置文件，且不会写回配置文件
+// - 逐个绑定 AppConfig 中的所有配置键，配置文件中缺少的键同样可以被覆盖
+// - 列表类型支持 JSON 数组（如 encryption_keys）或逗号分隔的字符串（如 allow_ips）
+func BindEnv(v *viper.Viper) {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	for _, field := range configFields(reflect.TypeOf(AppConfig{}), "") {
+		_ = v.BindEnv(field.key)
+		if field.kind != reflect.Slice {
+			continue
+		}
+		raw, ok := os.LookupEnv(EnvName(field.key))
+		if !ok {
+			continue
+		}
+		v.Set(field.key, parseEnvList(raw))
+	}
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// parseEnvList 解析列表类型的环境变量值
+func parseEnvList(raw string) interface{} {
+	trimmed := strings.TrimSpace(raw)
+	if strings.HasPrefix(trimmed, "[") {
+		var list []interface{}
+		if err := json.Unmarshal([]byte(trimmed), &list); err == nil {
+			return list
+		}
+	}
+
+	items := []string{}
+	for _, item := range strings.Split(trimmed, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// configFields 按 mapstructure 标签递归列出配置结构体的所有叶子配置项
+func configFields(t reflect.Type, prefix string) []configField {
+	var fields []configField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		if field.Type.Kind() == reflect.Struct {
+			fields = append(fields, configFields(field.Type, key)...)
+			continue
+		}
+		fields = append(fields, configField{key: key, kind: field.Type.Kind()})
+	}
+	return fields
+}
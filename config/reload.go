@@ -0,0 +1,174 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// reloadDebounce 配置文件变更事件的合并等待时间
+const reloadDebounce = 300 * time.Millisecond
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// liveReloadKeys 修改后无需重启即可生效的配置项
+// 其余配置项（监听地址、数据库、Redis、密钥等）在启动时使用，修改后需要重启服务
+var liveReloadKeys = map[string]bool{
+	"log.level":              true,
+	"log.format":             true,
+	"server.dev_mode":        true,
+	"server.trusted_proxies": true,
+	"server.list_format":     true,
+}
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// ReloadFunc 配置热重载回调
+// config 为验证通过的新配置，changed 为本次变化且可在运行时生效的配置键
+type ReloadFunc func(config *AppConfig, changed []string)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Watch 监听配置文件变化并热重载
+// - 变化后的配置在独立的viper实例中读取并通过 ValidateConfig 同样的规则验证，未通过时继续使用当前配置
+// - 可运行时生效的配置项写回全局配置后交给 apply 应用，其余变化只提示需要重启，全局配置中保持原值
+// - 环境变量覆盖的配置项始终以环境变量为准
+func Watch(apply ReloadFunc) {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return
+	}
+
+	current := snapshot(viper.GetViper())
+	var reloadMu sync.Mutex
+	reload := func() {
+		reloadMu.Lock()
+		defer reloadMu.Unlock()
+
+		config, v, err := loadConfigFile(file)
+		if err != nil {
+			log.WithError(err).Error("配置文件变更未通过验证，继续使用当前配置")
+			return
+		}
+
+		next := snapshot(v)
+		live, restart := diffSettings(current, next)
+		current = next
+
+		if len(restart) > 0 {
+			log.WithField("keys", restart).Warn("以下配置修改需要重启服务后生效")
+		}
+		if len(live) > 0 {
+			// 写回全局配置，直接读取 viper 的代码（开发模式、列表格式等）同样使用新值
+			for _, key := range live {
+				viper.Set(key, liveValue(v, key))
+			}
+			apply(config, live)
+			log.WithField("keys", live).Info("配置已热重载")
+		}
+	}
+
+	watcher := viper.New()
+	watcher.SetConfigFile(file)
+	watcher.SetConfigType("json")
+	if err := watcher.ReadInConfig(); err != nil {
+		log.WithError(err).Error("配置文件监听启动失败")
+		return
+	}
+
+	// 编辑器保存文件时通常触发多次写入事件，合并短时间内的事件后再重新加载
+	var mu sync.Mutex
+	var timer *time.Timer
+	watcher.OnConfigChange(func(event fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDebounce, reload)
+	})
+	watcher.WatchConfig()
+
+	log.Info("已启用配置文件热重载")
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// loadConfigFile 在独立的viper实例中读取并验证配置文件
+func loadConfigFile(file string) (*AppConfig, *viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(file)
+	v.SetConfigType("json")
+	BindEnv(v)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	config, err := validateViper(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, v, nil
+}
+
+// liveValue 获取热重载配置项的新值
+// 配置文件中删除的配置项按空值写回，避免全局配置继续使用删除前的值
+func liveValue(v *viper.Viper, key string) interface{} {
+	if value := v.Get(key); value != nil {
+		return value
+	}
+	return ""
+}
+
+// snapshot 记录viper实例中所有配置项的当前值
+func snapshot(v *viper.Viper) map[string]string {
+	settings := make(map[string]string)
+	for _, key := range v.AllKeys() {
+		settings[key] = fmt.Sprint(v.Get(key))
+	}
+	return settings
+}
+
+// diffSettings 比较两次配置，按是否可热重载拆分变化的配置键
+func diffSettings(previous, next map[string]string) (live, restart []string) {
+	seen := make(map[string]bool)
+	for key, value := range next {
+		seen[key] = true
+		if previous[key] != value {
+			live, restart = classifyKey(key, live, restart)
+		}
+	}
+	for key := range previous {
+		if !seen[key] {
+			live, restart = classifyKey(key, live, restart)
+		}
+	}
+	sort.Strings(live)
+	sort.Strings(restart)
+	return live, restart
+}
+
+// classifyKey 将配置键归入可热重载或需要重启的列表
+func classifyKey(key string, live, restart []string) ([]string, []string) {
+	if liveReloadKeys[key] {
+		return append(live, key), restart
+	}
+	return live, append(restart, key)
+}
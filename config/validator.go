@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -13,16 +15,42 @@ import (
 	"github.com/spf13/viper"
 )
 
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// postgresIdentifierPattern PostgreSQL schema 名称格式（不含引号的普通标识符）
+var postgresIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// encryptionKeyIDPattern 加密密钥ID格式：字母、数字、下划线、短横线，最长32位
+// 密钥ID写入密文前缀，冒号等分隔字符不能出现在ID中
+var encryptionKeyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
 // ============================================================================
 // 公共函数
 // ============================================================================
 
 // ValidateConfig 验证配置
 func ValidateConfig() (*AppConfig, error) {
+	return validateViper(viper.GetViper())
+}
+
+// IsValidEncryptionKeyID 检查加密密钥ID格式是否有效
+func IsValidEncryptionKeyID(id string) bool {
+	return encryptionKeyIDPattern.MatchString(id)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// validateViper 解析并验证指定viper实例中的配置
+// 配置热重载时使用独立实例，验证通过前不影响正在运行的配置
+func validateViper(v *viper.Viper) (*AppConfig, error) {
 	var config AppConfig
 
 	// 解析配置到结构体
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("解析配置失败: %w", err)
 	}
 
@@ -35,10 +63,6 @@ func ValidateConfig() (*AppConfig, error) {
 	return &config, nil
 }
 
-// ============================================================================
-// 私有函数
-// ============================================================================
-
 // validateConfig 验证配置
 func validateConfig(config *AppConfig) error {
 	// 验证服务器配置
@@ -66,6 +90,40 @@ func validateConfig(config *AppConfig) error {
 		return fmt.Errorf("安全配置错误: %w", err)
 	}
 
+	// 验证备份配置
+	if err := validateBackupConfig(&config.Backup); err != nil {
+		return fmt.Errorf("备份配置错误: %w", err)
+	}
+
+	// 验证指标配置
+	if err := validateMetricsConfig(&config.Metrics); err != nil {
+		return fmt.Errorf("指标配置错误: %w", err)
+	}
+
+	// 验证链路追踪配置
+	if err := validateTracingConfig(&config.Tracing); err != nil {
+		return fmt.Errorf("链路追踪配置错误: %w", err)
+	}
+
+	// 验证缓存配置
+	if err := validateCacheConfig(&config.Cache); err != nil {
+		return fmt.Errorf("缓存配置错误: %w", err)
+	}
+
+	// 验证定时任务配置
+	if err := validateSchedulerConfig(&config.Scheduler); err != nil {
+		return fmt.Errorf("定时任务配置错误: %w", err)
+	}
+
+	// 验证Webhook配置
+	if err := validateWebhookConfig(&config.Webhook); err != nil {
+		return fmt.Errorf("Webhook配置错误: %w", err)
+	}
+
+	if err := validateMailConfig(&config.Mail); err != nil {
+		return fmt.Errorf("邮件配置错误: %w", err)
+	}
+
 	return nil
 }
 
@@ -83,13 +141,129 @@ func validateServerConfig(config *ServerConfig) error {
 		return fmt.Errorf("无效的端口号: %d，端口号必须在1-65535之间", config.Port)
 	}
 
+	// 验证关闭等待时间
+	if config.ShutdownDelay < 0 || config.ShutdownDelay > 60 {
+		return fmt.Errorf("无效的关闭等待时间: %d，必须在0-60秒之间", config.ShutdownDelay)
+	}
+
+	// 验证可信代理
+	if err := validateIPList(config.TrustedProxies); err != nil {
+		return fmt.Errorf("可信代理配置错误: %w", err)
+	}
+
+	// 验证列表响应格式
+	if config.ListFormat != "" && config.ListFormat != "layui" && config.ListFormat != "standard" {
+		return fmt.Errorf("无效的列表响应格式: %s，必须是 layui 或 standard", config.ListFormat)
+	}
+
+	// 验证HTTPS配置
+	if err := validateTLSConfig(&config.TLS, config.Port); err != nil {
+		return fmt.Errorf("HTTPS配置错误: %w", err)
+	}
+
+	// 验证管理后台配置
+	if err := validateAdminConfig(&config.Admin); err != nil {
+		return fmt.Errorf("管理后台配置错误: %w", err)
+	}
+
+	return nil
+}
+
+// validateTLSConfig 验证HTTPS配置
+func validateTLSConfig(config *TLSConfig, port int) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	if config.CertFile == "" || config.KeyFile == "" {
+		return errors.New("启用HTTPS时必须配置证书与私钥文件")
+	}
+	for _, file := range []string{config.CertFile, config.KeyFile, config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("无法访问文件 %s: %w", file, err)
+		}
+	}
+
+	if !contains([]string{"", "1.0", "1.1", "1.2", "1.3"}, config.MinVersion) {
+		return fmt.Errorf("无效的TLS最低版本: %s", config.MinVersion)
+	}
+	if config.MinVersion == "1.0" || config.MinVersion == "1.1" {
+		log.Warn("TLS 1.0/1.1 已不安全，建议最低版本设置为 1.2")
+	}
+
+	clientAuth := strings.ToLower(config.ClientAuth)
+	if !contains([]string{"", "none", "request", "require"}, clientAuth) {
+		return fmt.Errorf("无效的客户端证书校验方式: %s", config.ClientAuth)
+	}
+	if clientAuth != "" && clientAuth != "none" && config.ClientCAFile == "" {
+		return errors.New("启用客户端证书校验时必须配置客户端CA文件")
+	}
+
+	if config.RedirectHTTP {
+		if config.HTTPPort < 1 || config.HTTPPort > 65535 {
+			return fmt.Errorf("无效的HTTP跳转端口: %d", config.HTTPPort)
+		}
+		if config.HTTPPort == port {
+			return errors.New("HTTP跳转端口不能与服务端口相同")
+		}
+	}
+
+	if config.ReloadInterval < 0 {
+		return errors.New("证书检查间隔不能为负数")
+	}
+	return nil
+}
+
+// validateAdminConfig 验证管理后台配置
+// 前缀不能与客户端API、静态资源等公共路径冲突
+func validateAdminConfig(config *AdminConfig) error {
+	if config.Prefix != "" {
+		prefix := strings.TrimRight(config.Prefix, "/")
+		if !strings.HasPrefix(config.Prefix, "/") || prefix == "" {
+			return fmt.Errorf("无效的后台路径前缀: %s，必须以 / 开头且不能为根路径", config.Prefix)
+		}
+		if strings.ContainsAny(prefix, ":*?#") {
+			return fmt.Errorf("后台路径前缀不能包含特殊字符: %s", config.Prefix)
+		}
+		for _, reserved := range []string{"/api", "/static", "/assets", "/healthz", "/readyz"} {
+			if prefix == reserved || strings.HasPrefix(prefix, reserved+"/") {
+				return fmt.Errorf("后台路径前缀 %s 与系统路径 %s 冲突", config.Prefix, reserved)
+			}
+		}
+	}
+
+	if config.Listen != "" {
+		if _, _, err := net.SplitHostPort(config.Listen); err != nil {
+			return fmt.Errorf("后台监听地址格式错误: %w", err)
+		}
+	}
+
+	if err := validateIPList(config.AllowIPs); err != nil {
+		return fmt.Errorf("后台访问白名单错误: %w", err)
+	}
+	return nil
+}
+
+// validateIPList 验证IP或CIDR列表
+func validateIPList(entries []string) error {
+	for _, entry := range entries {
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+		if net.ParseIP(entry) == nil {
+			return fmt.Errorf("无效的IP或CIDR: %s", entry)
+		}
+	}
 	return nil
 }
 
 // validateDatabaseConfig 验证数据库配置
 func validateDatabaseConfig(config *DatabaseConfig) error {
 	// 验证数据库类型
-	validTypes := []string{"mysql", "sqlite"}
+	validTypes := []string{"mysql", "sqlite", "postgres", "postgresql"}
 	if !contains(validTypes, config.Type) {
 		return fmt.Errorf("不支持的数据库类型: %s，支持的类型: %s", config.Type, strings.Join(validTypes, ", "))
 	}
@@ -100,6 +274,8 @@ func validateDatabaseConfig(config *DatabaseConfig) error {
 		return validateMySQLConfig(&config.MySQL)
 	case "sqlite":
 		return validateSQLiteConfig(&config.SQLite)
+	case "postgres", "postgresql":
+		return validatePostgresConfig(&config.Postgres)
 	}
 
 	return nil
@@ -128,6 +304,36 @@ func validateMySQLConfig(config *MySQLConfig) error {
 	return nil
 }
 
+// validatePostgresConfig 验证PostgreSQL配置
+func validatePostgresConfig(config *PostgresConfig) error {
+	if config.Host == "" {
+		return errors.New("PostgreSQL主机地址不能为空")
+	}
+	if config.Port < 1 || config.Port > 65535 {
+		return fmt.Errorf("无效的PostgreSQL端口号: %d", config.Port)
+	}
+	if config.Username == "" {
+		return errors.New("PostgreSQL用户名不能为空")
+	}
+	if config.Database == "" {
+		return errors.New("PostgreSQL数据库名不能为空")
+	}
+	validSSLModes := []string{"", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	if !contains(validSSLModes, config.SSLMode) {
+		return fmt.Errorf("无效的PostgreSQL SSL模式: %s", config.SSLMode)
+	}
+	if config.Schema != "" && !postgresIdentifierPattern.MatchString(config.Schema) {
+		return fmt.Errorf("无效的PostgreSQL schema名称: %s", config.Schema)
+	}
+	if config.MaxIdleConns < 0 {
+		return errors.New("PostgreSQL最大空闲连接数不能为负数")
+	}
+	if config.MaxOpenConns < 0 {
+		return errors.New("PostgreSQL最大打开连接数不能为负数")
+	}
+	return nil
+}
+
 // validateSQLiteConfig 验证SQLite配置
 func validateSQLiteConfig(config *SQLiteConfig) error {
 	if config.Path == "" {
@@ -167,6 +373,11 @@ func validateLogConfig(config *LogConfig) error {
 		return fmt.Errorf("无效的日志级别: %s，支持的级别: %s", config.Level, strings.Join(validLevels, ", "))
 	}
 
+	// 验证日志格式
+	if !contains([]string{"", "text", "json"}, config.Format) {
+		return fmt.Errorf("无效的日志格式: %s，支持的格式: text, json", config.Format)
+	}
+
 	// 检查日志文件目录（仅当日志文件路径不为空时）
 	if config.File != "" {
 		dir := filepath.Dir(config.File)
@@ -198,14 +409,18 @@ func validateSecurityConfig(config *SecurityConfig) error {
 		return errors.New("JWT密钥长度不能少于16个字符")
 	}
 
-	if len(config.EncryptionKey) < 16 {
-		return errors.New("加密密钥长度不能少于16个字符")
+	if err := validateEncryptionKeys(config); err != nil {
+		return err
 	}
 
 	if config.JWTRefresh < 1 || config.JWTRefresh > 23 {
 		return errors.New("JWT令牌刷新阈值必须在1-23小时之间")
 	}
 
+	if config.AuditHistoryDays < 0 {
+		return errors.New("审计日志保留天数不能为负数")
+	}
+
 	// 检查是否使用默认值（生产环境警告）
 	if strings.Contains(config.JWTSecret, "default") {
 		log.Warn("检测到使用默认JWT密钥，生产环境请更换为安全的密钥")
@@ -218,6 +433,154 @@ func validateSecurityConfig(config *SecurityConfig) error {
 	return nil
 }
 
+// validateBackupConfig 验证定时备份配置
+func validateBackupConfig(config *BackupConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.Dir == "" {
+		return errors.New("启用定时备份时备份目录不能为空")
+	}
+	if config.Interval < 1 {
+		return errors.New("备份间隔不能小于1小时")
+	}
+	if config.Retention < 0 {
+		return errors.New("备份保留数量不能为负数")
+	}
+	return nil
+}
+
+// validateMetricsConfig 验证 Prometheus 指标配置
+func validateMetricsConfig(config *MetricsConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.Path != "" && !strings.HasPrefix(config.Path, "/") {
+		return errors.New("指标接口路径必须以 / 开头")
+	}
+	if config.Token != "" && len(config.Token) < 16 {
+		return errors.New("指标访问令牌长度不能少于16个字符")
+	}
+	if config.Listen != "" {
+		if _, _, err := net.SplitHostPort(config.Listen); err != nil {
+			return fmt.Errorf("指标监听地址格式错误: %w", err)
+		}
+	}
+	if config.Token == "" && config.Listen == "" {
+		log.Warn("指标接口未配置访问令牌或独立监听地址，仅允许本机访问")
+	}
+	return nil
+}
+
+// validateTracingConfig 验证链路追踪配置
+func validateTracingConfig(config *TracingConfig) error {
+	if !config.Enabled {
+		return nil
+	}
+	switch config.Exporter {
+	case "otlp":
+		if config.Endpoint == "" {
+			return errors.New("otlp 导出方式必须配置 endpoint")
+		}
+		if strings.Contains(config.Endpoint, "://") {
+			return errors.New("endpoint 只需填写主机与端口（如 localhost:4318），是否使用HTTPS由 insecure 决定")
+		}
+		if _, _, err := net.SplitHostPort(config.Endpoint); err != nil {
+			return fmt.Errorf("endpoint 格式错误: %w", err)
+		}
+	case "stdout":
+	case "file":
+		if config.File == "" {
+			return errors.New("file 导出方式必须配置 file")
+		}
+	default:
+		return fmt.Errorf("不支持的导出方式: %s，可选 otlp/stdout/file", config.Exporter)
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return fmt.Errorf("采样比例必须在0-1之间: %v", config.SampleRatio)
+	}
+	return nil
+}
+
+// validateCacheConfig 验证缓存配置
+func validateCacheConfig(config *CacheConfig) error {
+	if config.LocalSize < 0 {
+		return fmt.Errorf("进程内缓存条目上限不能为负数: %d", config.LocalSize)
+	}
+	if config.LocalTTL < 0 {
+		return fmt.Errorf("进程内缓存有效期不能为负数: %d", config.LocalTTL)
+	}
+	if config.RedisTTL < 0 {
+		return fmt.Errorf("Redis缓存有效期不能为负数: %d", config.RedisTTL)
+	}
+	return nil
+}
+
+// validateSchedulerConfig 验证定时任务配置
+func validateSchedulerConfig(config *SchedulerConfig) error {
+	if config.HistoryDays < 0 {
+		return fmt.Errorf("执行记录保留天数不能为负数: %d", config.HistoryDays)
+	}
+	return nil
+}
+
+// validateWebhookConfig 验证Webhook配置
+func validateWebhookConfig(config *WebhookConfig) error {
+	if config.Timeout < 0 || config.Timeout > 60 {
+		return fmt.Errorf("无效的推送超时时间: %d，必须在0-60秒之间", config.Timeout)
+	}
+	if config.MaxAttempts < 0 || config.MaxAttempts > 20 {
+		return fmt.Errorf("无效的最大尝试次数: %d，必须在0-20之间", config.MaxAttempts)
+	}
+	if config.HistoryDays < 0 {
+		return fmt.Errorf("投递记录保留天数不能为负数: %d", config.HistoryDays)
+	}
+	return nil
+}
+
+// validateEncryptionKeys 验证加密密钥配置
+// - 至少配置 encryption_key 或 encryption_keys 其中之一
+// - 每个密钥长度不少于16个字符，密钥ID格式有效且不能重复
+// - active_key_id 必须指向已配置的密钥
+func validateEncryptionKeys(config *SecurityConfig) error {
+	if config.EncryptionKey == "" && len(config.EncryptionKeys) == 0 {
+		return errors.New("必须配置加密密钥 encryption_key 或 encryption_keys")
+	}
+
+	ids := make([]string, 0, len(config.EncryptionKeys)+1)
+	if config.EncryptionKey != "" {
+		if len(config.EncryptionKey) < 16 {
+			return errors.New("加密密钥长度不能少于16个字符")
+		}
+		ids = append(ids, "default")
+	}
+
+	for _, key := range config.EncryptionKeys {
+		if key.ID == "" {
+			return errors.New("加密密钥ID不能为空")
+		}
+		if !IsValidEncryptionKeyID(key.ID) {
+			return fmt.Errorf("加密密钥ID格式错误: %q（只能包含字母、数字、下划线、短横线，最长32位）", key.ID)
+		}
+		if contains(ids, key.ID) {
+			return fmt.Errorf("加密密钥ID重复: %s", key.ID)
+		}
+		if len(key.Key) < 16 {
+			return fmt.Errorf("加密密钥 %s 长度不能少于16个字符", key.ID)
+		}
+		ids = append(ids, key.ID)
+	}
+
+	if config.ActiveKeyID != "" && !contains(ids, config.ActiveKeyID) {
+		return fmt.Errorf("活动加密密钥 %s 不存在", config.ActiveKeyID)
+	}
+	if config.ActiveKeyID == "" && config.EncryptionKey == "" && len(config.EncryptionKeys) > 1 {
+		return errors.New("配置了多个加密密钥时必须指定 active_key_id")
+	}
+
+	return nil
+}
+
 // contains 检查切片是否包含指定元素
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -267,3 +630,34 @@ func convertValue[T any](value interface{}) (T, error) {
 
 	return zero, fmt.Errorf("无法转换类型")
 }
+
+// validateMailConfig 验证邮件配置
+func validateMailConfig(config *MailConfig) error {
+	switch config.Encryption {
+	case "", "starttls", "tls", "none":
+	default:
+		return fmt.Errorf("无效的加密方式: %s，支持 starttls、tls、none", config.Encryption)
+	}
+	if config.Port < 0 || config.Port > 65535 {
+		return fmt.Errorf("无效的SMTP端口: %d，必须在0-65535之间", config.Port)
+	}
+	if config.Timeout < 0 || config.Timeout > 120 {
+		return fmt.Errorf("无效的发送超时时间: %d，必须在0-120秒之间", config.Timeout)
+	}
+	if config.MaxAttempts < 0 || config.MaxAttempts > 20 {
+		return fmt.Errorf("无效的最大尝试次数: %d，必须在0-20之间", config.MaxAttempts)
+	}
+	if config.HistoryDays < 0 {
+		return fmt.Errorf("发送记录保留天数不能为负数: %d", config.HistoryDays)
+	}
+	if !config.Enabled {
+		return nil
+	}
+	if config.Host == "" {
+		return fmt.Errorf("启用邮件发送时必须配置SMTP服务器地址")
+	}
+	if _, err := mail.ParseAddress(config.From); err != nil {
+		return fmt.Errorf("无效的发件人地址: %s", config.From)
+	}
+	return nil
+}
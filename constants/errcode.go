@@ -0,0 +1,172 @@
+package constants
+
+import (
+	"net/http"
+	"sort"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// ErrorCode 错误码
+// 所有接口响应的 code 字段取自本目录，数字与名称一经发布不再修改，客户端可直接按数字判断
+// 号段划分：
+// - 0：成功；1：通用失败（操作未完成，如测试发送失败）
+// - 1xxx：请求错误（参数、资源）
+// - 2xxx：认证与权限错误
+// - 3xxx：客户端验证业务错误（应用、卡密、账号、绑定等），统一返回 HTTP 200，客户端以 code 区分
+// - 5xxx：服务端错误
+type ErrorCode struct {
+	Code    int    `json:"code"`    // 数字错误码
+	Name    string `json:"name"`    // 字符串错误码
+	Status  int    `json:"status"`  // HTTP状态码
+	Message string `json:"message"` // 默认提示信息
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 通用
+var (
+	CodeOK     = ErrorCode{0, "OK", http.StatusOK, "success"}
+	CodeFailed = ErrorCode{1, "FAILED", http.StatusOK, "操作失败"}
+)
+
+// 请求错误（1xxx）
+var (
+	CodeInvalidRequest   = ErrorCode{1001, "INVALID_REQUEST", http.StatusBadRequest, "无效请求"}
+	CodeValidationError  = ErrorCode{1002, "VALIDATION_ERROR", http.StatusBadRequest, "参数校验失败"}
+	CodeNotFound         = ErrorCode{1003, "NOT_FOUND", http.StatusNotFound, "资源不存在"}
+	CodeConflict         = ErrorCode{1004, "CONFLICT", http.StatusConflict, "资源冲突"}
+	CodeMethodNotAllowed = ErrorCode{1005, "METHOD_NOT_ALLOWED", http.StatusMethodNotAllowed, "不支持的请求方法"}
+	CodeTooManyRequests  = ErrorCode{1006, "TOO_MANY_REQUESTS", http.StatusTooManyRequests, "请求过于频繁"}
+)
+
+// 认证与权限错误（2xxx）
+var (
+	CodeUnauthorized      = ErrorCode{2001, "UNAUTHORIZED", http.StatusUnauthorized, "未登录或会话已过期"}
+	CodeTokenExpired      = ErrorCode{2002, "TOKEN_EXPIRED", http.StatusUnauthorized, "令牌已过期"}
+	CodeForbidden         = ErrorCode{2003, "FORBIDDEN", http.StatusForbidden, "没有访问权限"}
+	CodeCSRFInvalid       = ErrorCode{2004, "CSRF_INVALID", http.StatusForbidden, "CSRF令牌验证失败"}
+	CodeIPForbidden       = ErrorCode{2005, "IP_FORBIDDEN", http.StatusForbidden, "当前IP不允许访问"}
+	CodeAPIKeyInvalid     = ErrorCode{2006, "API_KEY_INVALID", http.StatusUnauthorized, "API密钥无效"}
+	CodeAPIKeyDisabled    = ErrorCode{2007, "API_KEY_DISABLED", http.StatusUnauthorized, "API密钥已停用"}
+	CodeAPIKeyExpired     = ErrorCode{2008, "API_KEY_EXPIRED", http.StatusUnauthorized, "API密钥已过期"}
+	CodeAPIKeyScopeDenied = ErrorCode{2009, "API_KEY_SCOPE_DENIED", http.StatusForbidden, "API密钥缺少权限"}
+	CodeAPIKeyAppDenied   = ErrorCode{2010, "API_KEY_APP_DENIED", http.StatusForbidden, "API密钥无权操作该应用"}
+	CodeCaptchaInvalid    = ErrorCode{2011, "CAPTCHA_INVALID", http.StatusBadRequest, "验证码错误"}
+	CodeLoginFailed       = ErrorCode{2012, "LOGIN_FAILED", http.StatusBadRequest, "用户名或密码错误"}
+)
+
+// 客户端验证业务错误（3xxx）
+var (
+	CodeAppNotFound       = ErrorCode{3001, "APP_NOT_FOUND", http.StatusOK, "应用不存在"}
+	CodeAppDisabled       = ErrorCode{3002, "APP_DISABLED", http.StatusOK, "应用已停用"}
+	CodeAPIDisabled       = ErrorCode{3003, "API_DISABLED", http.StatusOK, "接口已停用"}
+	CodeSignatureInvalid  = ErrorCode{3004, "SIGNATURE_INVALID", http.StatusOK, "签名校验失败"}
+	CodeTimestampInvalid  = ErrorCode{3005, "TIMESTAMP_INVALID", http.StatusOK, "请求时间戳无效或已过期"}
+	CodeDecryptFailed     = ErrorCode{3006, "DECRYPT_FAILED", http.StatusOK, "请求数据解密失败"}
+	CodeVersionOutdated   = ErrorCode{3007, "VERSION_OUTDATED", http.StatusOK, "客户端版本过低，请更新"}
+	CodeRequestReplayed   = ErrorCode{3008, "REQUEST_REPLAYED", http.StatusOK, "请求已被处理，请勿重放"}
+	CodeCardNotFound      = ErrorCode{3101, "CARD_NOT_FOUND", http.StatusOK, "卡密不存在"}
+	CodeCardUsed          = ErrorCode{3102, "CARD_USED", http.StatusOK, "卡密已被使用"}
+	CodeCardDisabled      = ErrorCode{3103, "CARD_DISABLED", http.StatusOK, "卡密已被禁用"}
+	CodeCardExpired       = ErrorCode{3104, "CARD_EXPIRED", http.StatusOK, "卡密已过期"}
+	CodeAccountNotFound   = ErrorCode{3201, "ACCOUNT_NOT_FOUND", http.StatusOK, "账号不存在"}
+	CodePasswordIncorrect = ErrorCode{3202, "PASSWORD_INCORRECT", http.StatusOK, "账号或密码错误"}
+	CodeAccountDisabled   = ErrorCode{3203, "ACCOUNT_DISABLED", http.StatusOK, "账号已被禁用"}
+	CodeAccountExpired    = ErrorCode{3204, "ACCOUNT_EXPIRED", http.StatusOK, "账号已到期"}
+	CodeAccountExists     = ErrorCode{3205, "ACCOUNT_EXISTS", http.StatusOK, "账号已存在"}
+	CodeRegisterDisabled  = ErrorCode{3206, "REGISTER_DISABLED", http.StatusOK, "应用未开放注册"}
+	CodeRegisterLimited   = ErrorCode{3207, "REGISTER_LIMITED", http.StatusOK, "注册次数已达上限"}
+	CodeTrialUsed         = ErrorCode{3208, "TRIAL_USED", http.StatusOK, "试用次数已用完"}
+	CodeResetCodeInvalid  = ErrorCode{3209, "RESET_CODE_INVALID", http.StatusOK, "验证码错误或已过期"}
+	CodeMachineMismatch   = ErrorCode{3301, "MACHINE_MISMATCH", http.StatusOK, "机器码与绑定不一致"}
+	CodeIPMismatch        = ErrorCode{3302, "IP_MISMATCH", http.StatusOK, "IP与绑定不一致"}
+	CodeRebindDisabled    = ErrorCode{3303, "REBIND_DISABLED", http.StatusOK, "应用未开启换绑"}
+	CodeRebindExhausted   = ErrorCode{3304, "REBIND_EXHAUSTED", http.StatusOK, "换绑次数已用完"}
+	CodeMultiOpenLimit    = ErrorCode{3305, "MULTI_OPEN_LIMIT", http.StatusOK, "超过多开数量限制"}
+	CodeSessionInvalid    = ErrorCode{3306, "SESSION_INVALID", http.StatusOK, "登录状态已失效，请重新登录"}
+	CodeSessionKicked     = ErrorCode{3307, "SESSION_KICKED", http.StatusOK, "已在其他设备登录或被管理员下线"}
+	CodeDeviceBlacklisted = ErrorCode{3308, "DEVICE_BLACKLISTED", http.StatusOK, "设备或IP已被列入黑名单"}
+	CodeVariableNotFound  = ErrorCode{3401, "VARIABLE_NOT_FOUND", http.StatusOK, "变量不存在"}
+	CodeFunctionNotFound  = ErrorCode{3402, "FUNCTION_NOT_FOUND", http.StatusOK, "函数不存在"}
+	CodeFunctionFailed    = ErrorCode{3403, "FUNCTION_FAILED", http.StatusOK, "函数执行失败"}
+)
+
+// 服务端错误（5xxx）
+var (
+	CodeInternalError      = ErrorCode{5001, "INTERNAL_ERROR", http.StatusInternalServerError, "服务器内部错误"}
+	CodeDatabaseError      = ErrorCode{5002, "DATABASE_ERROR", http.StatusInternalServerError, "数据库操作失败"}
+	CodeServiceUnavailable = ErrorCode{5003, "SERVICE_UNAVAILABLE", http.StatusServiceUnavailable, "服务暂不可用"}
+	CodeMaintenance        = ErrorCode{5004, "MAINTENANCE", http.StatusServiceUnavailable, "系统维护中"}
+)
+
+// errorCodes 错误码目录（按数字排序）
+var errorCodes = sortedErrorCodes(
+	CodeOK, CodeFailed,
+	CodeInvalidRequest, CodeValidationError, CodeNotFound, CodeConflict, CodeMethodNotAllowed, CodeTooManyRequests,
+	CodeUnauthorized, CodeTokenExpired, CodeForbidden, CodeCSRFInvalid, CodeIPForbidden,
+	CodeAPIKeyInvalid, CodeAPIKeyDisabled, CodeAPIKeyExpired, CodeAPIKeyScopeDenied, CodeAPIKeyAppDenied,
+	CodeCaptchaInvalid, CodeLoginFailed,
+	CodeAppNotFound, CodeAppDisabled, CodeAPIDisabled, CodeSignatureInvalid, CodeTimestampInvalid,
+	CodeDecryptFailed, CodeVersionOutdated, CodeRequestReplayed,
+	CodeCardNotFound, CodeCardUsed, CodeCardDisabled, CodeCardExpired,
+	CodeAccountNotFound, CodePasswordIncorrect, CodeAccountDisabled, CodeAccountExpired, CodeAccountExists,
+	CodeRegisterDisabled, CodeRegisterLimited, CodeTrialUsed, CodeResetCodeInvalid,
+	CodeMachineMismatch, CodeIPMismatch, CodeRebindDisabled, CodeRebindExhausted, CodeMultiOpenLimit,
+	CodeSessionInvalid, CodeSessionKicked, CodeDeviceBlacklisted,
+	CodeVariableNotFound, CodeFunctionNotFound, CodeFunctionFailed,
+	CodeInternalError, CodeDatabaseError, CodeServiceUnavailable, CodeMaintenance,
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// ErrorCodes 返回完整的错误码目录（按数字排序），用于生成文档
+func ErrorCodes() []ErrorCode {
+	list := make([]ErrorCode, len(errorCodes))
+	copy(list, errorCodes)
+	return list
+}
+
+// LookupErrorCode 按数字错误码查找目录项
+func LookupErrorCode(code int) (ErrorCode, bool) {
+	for _, ec := range errorCodes {
+		if ec.Code == code {
+			return ec, true
+		}
+	}
+	return ErrorCode{}, false
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Error 实现 error 接口，便于在服务层直接返回错误码
+func (ec ErrorCode) Error() string {
+	return ec.Message
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// sortedErrorCodes 按数字排序错误码，重复的数字或名称视为编码错误直接panic
+func sortedErrorCodes(codes ...ErrorCode) []ErrorCode {
+	seenCode := map[int]bool{}
+	seenName := map[string]bool{}
+	for _, ec := range codes {
+		if seenCode[ec.Code] || seenName[ec.Name] {
+			panic("重复的错误码: " + ec.Name)
+		}
+		seenCode[ec.Code] = true
+		seenName[ec.Name] = true
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
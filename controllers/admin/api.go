@@ -5,12 +5,13 @@ import (
 	"net/http"
 	"networkDev/controllers"
 	"networkDev/models"
+	"networkDev/services"
 	"networkDev/utils/encrypt"
+	"networkDev/utils/logger"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
 // ============================================================================
@@ -79,7 +80,7 @@ func APIListHandler(c *gin.Context) {
 	// 获取总数
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		logrus.WithError(err).Error("Failed to count APIs")
+		logger.FromContext(c).WithError(err).Error("Failed to count APIs")
 		apiBaseController.HandleInternalError(c, "获取接口总数失败", err)
 		return
 	}
@@ -88,7 +89,7 @@ func APIListHandler(c *gin.Context) {
 	var apis []models.API
 	offset := (page - 1) * limit
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&apis).Error; err != nil {
-		logrus.WithError(err).Error("Failed to fetch APIs")
+		logger.FromContext(c).WithError(err).Error("Failed to fetch APIs")
 		apiBaseController.HandleInternalError(c, "获取接口列表失败", err)
 		return
 	}
@@ -102,7 +103,7 @@ func APIListHandler(c *gin.Context) {
 	var apps []models.App
 	if len(appUUIDs) > 0 {
 		if err := db.Where("uuid IN ?", appUUIDs).Find(&apps).Error; err != nil {
-			logrus.WithError(err).Error("Failed to fetch related apps")
+			logger.FromContext(c).WithError(err).Error("Failed to fetch related apps")
 		}
 	}
 
@@ -124,8 +125,17 @@ func APIListHandler(c *gin.Context) {
 		} `json:"algorithm_names"`
 	}
 
+	// 默认对接口私钥脱敏，仅在管理员会话显式请求 reveal=1 时返回明文
+	reveal := c.Query("reveal") == "1"
+	if reveal && !allowSecretAccess(c) {
+		return
+	}
+
 	var responseAPIs []APIResponse
 	for _, api := range apis {
+		if !reveal {
+			api.Redact()
+		}
 		responseAPI := APIResponse{
 			API:         api,
 			AppName:     appMap[api.AppUUID],
@@ -137,21 +147,7 @@ func APIListHandler(c *gin.Context) {
 		responseAPIs = append(responseAPIs, responseAPI)
 	}
 
-	// 计算分页信息
-	totalPages := (total + int64(limit) - 1) / int64(limit)
-
-	response := gin.H{
-		"success": true,
-		"data": gin.H{
-			"apis":        responseAPIs,
-			"total":       total,
-			"page":        page,
-			"limit":       limit,
-			"total_pages": totalPages,
-		},
-	}
-
-	c.JSON(http.StatusOK, response)
+	apiBaseController.HandleList(c, responseAPIs, total, page, limit)
 }
 
 // ============================================================================
@@ -221,6 +217,14 @@ func APIUpdateHandler(c *gin.Context) {
 	api.SubmitAlgorithm = req.SubmitAlgorithm
 	api.ReturnAlgorithm = req.ReturnAlgorithm
 
+	// 脱敏占位值表示未修改，保留原有私钥
+	if req.SubmitPrivateKey == models.RedactedPlaceholder {
+		req.SubmitPrivateKey = api.SubmitPrivateKey
+	}
+	if req.ReturnPrivateKey == models.RedactedPlaceholder {
+		req.ReturnPrivateKey = api.ReturnPrivateKey
+	}
+
 	// 可选更新密钥/证书（当提供时）
 	if req.SubmitPublicKey != "" || req.SubmitPrivateKey != "" {
 		api.SubmitPublicKey = req.SubmitPublicKey
@@ -232,14 +236,47 @@ func APIUpdateHandler(c *gin.Context) {
 	}
 
 	if err := db.Save(&api).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update API")
+		logger.FromContext(c).WithError(err).Error("Failed to update API")
 		apiBaseController.HandleInternalError(c, "更新接口失败", err)
 		return
 	}
 
+	// 使缓存的接口配置（含已解析的密钥）失效
+	services.InvalidateAPI(c.Request.Context(), api.AppUUID, api.APIType)
+
+	if currentAPIKey(c) != nil {
+		api.Redact()
+	}
 	apiBaseController.HandleSuccess(c, "接口更新成功", api)
 }
 
+// APIGetHandler 获取单个接口详情API处理器
+// 用于编辑接口时获取完整的密钥信息（列表接口默认对私钥脱敏），使用API密钥时同样脱敏
+func APIGetHandler(c *gin.Context) {
+	apiUUID := strings.TrimSpace(c.Query("uuid"))
+	if apiUUID == "" {
+		apiBaseController.HandleValidationError(c, "接口UUID不能为空")
+		return
+	}
+
+	// 获取数据库连接
+	db, ok := apiBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	var api models.API
+	if err := db.Where("uuid = ?", apiUUID).First(&api).Error; err != nil {
+		apiBaseController.HandleNotFoundError(c, "接口")
+		return
+	}
+
+	if currentAPIKey(c) != nil {
+		api.Redact()
+	}
+	apiBaseController.HandleSuccess(c, "获取成功", api)
+}
+
 // APIGetTypesHandler 获取接口类型列表API处理器
 func APIGetTypesHandler(c *gin.Context) {
 	// 构建接口类型列表
@@ -257,7 +294,7 @@ func APIGetTypesHandler(c *gin.Context) {
 		models.APITypeUserLogin, models.APITypeUserRegin, models.APITypeUserRecharge,
 		models.APITypeLogOut,
 		models.APITypeGetExpired, models.APITypeCheckUserStatus, models.APITypeGetAppData, models.APITypeGetVariable,
-		models.APITypeUpdatePwd, models.APITypeMacChangeBind, models.APITypeIPChangeBind,
+		models.APITypeUpdatePwd, models.APITypeMacChangeBind, models.APITypeIPChangeBind, models.APITypeSendResetCode, models.APITypeResetPwd,
 		models.APITypeDisableUser, models.APITypeBlackUser, models.APITypeUserDeductedTime,
 	}
 
@@ -307,10 +344,11 @@ func APIUpdateStatusHandler(c *gin.Context) {
 
 	// 更新状态
 	if err := db.Model(&api).Update("status", req.Status).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update API status")
+		logger.FromContext(c).WithError(err).Error("Failed to update API status")
 		apiBaseController.HandleInternalError(c, "更新状态失败", err)
 		return
 	}
+	services.InvalidateAPI(c.Request.Context(), api.AppUUID, api.APIType)
 
 	statusText := "禁用"
 	if req.Status == 1 {
@@ -351,7 +389,7 @@ func APIGenerateKeysHandler(c *gin.Context) {
 		// 生成16字节随机密钥并返回16位十六进制（大写）
 		key, err := encrypt.GenerateRC4Key(8) // 生成8字节密钥
 		if err != nil {
-			logrus.WithError(err).Error("Failed to generate RC4 key")
+			logger.FromContext(c).WithError(err).Error("Failed to generate RC4 key")
 			apiBaseController.HandleInternalError(c, "生成RC4密钥失败", err)
 			return
 		}
@@ -361,7 +399,7 @@ func APIGenerateKeysHandler(c *gin.Context) {
 		// 生成标准RSA 2048密钥对，返回PEM明文字符串
 		publicKey, privateKey, err := encrypt.GenerateRSAKeyPair(2048)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to generate RSA key pair")
+			logger.FromContext(c).WithError(err).Error("Failed to generate RSA key pair")
 			apiBaseController.HandleInternalError(c, "生成RSA密钥失败", err)
 			return
 		}
@@ -369,14 +407,14 @@ func APIGenerateKeysHandler(c *gin.Context) {
 		// 转换为PEM格式
 		publicKeyPEM, err := encrypt.PublicKeyToPEM(publicKey)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to convert public key to PEM")
+			logger.FromContext(c).WithError(err).Error("Failed to convert public key to PEM")
 			apiBaseController.HandleInternalError(c, "转换公钥格式失败", err)
 			return
 		}
 
 		privateKeyPEM, err := encrypt.PrivateKeyToPEM(privateKey)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to convert private key to PEM")
+			logger.FromContext(c).WithError(err).Error("Failed to convert private key to PEM")
 			apiBaseController.HandleInternalError(c, "转换私钥格式失败", err)
 			return
 		}
@@ -387,7 +425,7 @@ func APIGenerateKeysHandler(c *gin.Context) {
 		// 生成RSA动态加密密钥对，返回PEM明文字符串
 		publicKeyPEM, privateKeyPEM, err := encrypt.GenerateRSADynamicKeyPair(2048)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to generate RSA dynamic key pair")
+			logger.FromContext(c).WithError(err).Error("Failed to generate RSA dynamic key pair")
 			apiBaseController.HandleInternalError(c, "生成RSA动态密钥失败", err)
 			return
 		}
@@ -398,7 +436,7 @@ func APIGenerateKeysHandler(c *gin.Context) {
 		// 生成易加密密钥对，返回逗号分隔的整数数组字符串
 		encryptKey, _, err := encrypt.GenerateEasyKey()
 		if err != nil {
-			logrus.WithError(err).Error("Failed to generate Easy encryption key")
+			logger.FromContext(c).WithError(err).Error("Failed to generate Easy encryption key")
 			apiBaseController.HandleInternalError(c, "生成易加密密钥失败", err)
 			return
 		}
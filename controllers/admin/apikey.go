@@ -0,0 +1,398 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// apiKeyContextKey 当前请求使用的API密钥在gin上下文中的键
+const apiKeyContextKey = "admin_api_key"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// apiKeyRequest 新增/编辑API密钥请求参数
+type apiKeyRequest struct {
+	ID        uint     `json:"id"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	AppUUIDs  []string `json:"app_uuids"`
+	ExpiresAt string   `json:"expires_at"` // 过期时间，支持 2006-01-02、2006-01-02 15:04:05 与 RFC3339，留空表示长期有效
+	Status    int      `json:"status"`
+	Remark    string   `json:"remark"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var apiKeyBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// 页面处理器
+// ============================================================================
+
+// APIKeysFragmentHandler API密钥页面片段处理器
+func APIKeysFragmentHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "apikeys.html", gin.H{
+		"Title":     "API密钥",
+		"Resources": models.APIKeyResources,
+	})
+}
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// APIKeyListHandler API密钥列表API处理器
+// 只返回密钥前缀，不返回密钥摘要
+func APIKeyListHandler(c *gin.Context) {
+	page, pageSize := apiKeyBaseController.GetPaginationParams(c)
+
+	db, ok := apiKeyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.AdminAPIKey{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apiKeyBaseController.HandleInternalError(c, "查询API密钥总数失败", err)
+		return
+	}
+
+	var keys []models.AdminAPIKey
+	if err := query.Order("id DESC").Offset(apiKeyBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&keys).Error; err != nil {
+		apiKeyBaseController.HandleInternalError(c, "查询API密钥列表失败", err)
+		return
+	}
+
+	apiKeyBaseController.HandleList(c, keys, total, page, pageSize)
+}
+
+// APIKeyCreateHandler 新增API密钥API处理器
+// 密钥明文只在本次响应中返回
+func APIKeyCreateHandler(c *gin.Context) {
+	var req apiKeyRequest
+	if !apiKeyBaseController.BindJSON(c, &req) {
+		return
+	}
+	expiresAt, ok := validateAPIKeyRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	key := models.AdminAPIKey{
+		Name:      req.Name,
+		Scopes:    strings.Join(req.Scopes, ","),
+		AppUUIDs:  strings.Join(req.AppUUIDs, ","),
+		ExpiresAt: expiresAt,
+		Status:    req.Status,
+		Remark:    req.Remark,
+	}
+	plain, err := services.CreateAPIKey(c.Request.Context(), &key)
+	if err != nil {
+		apiKeyBaseController.HandleInternalError(c, "创建API密钥失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"api_key_id": key.ID,
+		"scopes":     key.Scopes,
+	}).Info("创建API密钥")
+	apiKeyBaseController.HandleSuccess(c, "创建成功，请立即复制保存密钥，关闭后无法再次查看", gin.H{
+		"key":     plain,
+		"api_key": key,
+	})
+}
+
+// APIKeyUpdateHandler 编辑API密钥API处理器
+// 只修改名称、权限、应用范围、过期时间与状态，密钥本身不变
+func APIKeyUpdateHandler(c *gin.Context) {
+	var req apiKeyRequest
+	if !apiKeyBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		apiKeyBaseController.HandleValidationError(c, "API密钥ID不能为空")
+		return
+	}
+	expiresAt, ok := validateAPIKeyRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	db, ok := apiKeyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var key models.AdminAPIKey
+	if err := db.First(&key, req.ID).Error; err != nil {
+		apiKeyBaseController.HandleNotFoundError(c, "API密钥")
+		return
+	}
+
+	key.Name = req.Name
+	key.Scopes = strings.Join(req.Scopes, ",")
+	key.AppUUIDs = strings.Join(req.AppUUIDs, ",")
+	key.ExpiresAt = expiresAt
+	key.Status = req.Status
+	key.Remark = req.Remark
+	if err := db.Save(&key).Error; err != nil {
+		apiKeyBaseController.HandleInternalError(c, "更新API密钥失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithField("api_key_id", key.ID).Info("更新API密钥")
+	apiKeyBaseController.HandleSuccess(c, "保存成功", key)
+}
+
+// APIKeyDeleteHandler 删除API密钥API处理器
+// 审计日志保留，仍可按密钥ID查询
+func APIKeyDeleteHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !apiKeyBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		apiKeyBaseController.HandleValidationError(c, "API密钥ID不能为空")
+		return
+	}
+
+	db, ok := apiKeyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	result := db.Delete(&models.AdminAPIKey{}, req.ID)
+	if result.Error != nil {
+		apiKeyBaseController.HandleInternalError(c, "删除API密钥失败", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		apiKeyBaseController.HandleNotFoundError(c, "API密钥")
+		return
+	}
+
+	logger.FromContext(c).WithField("api_key_id", req.ID).Info("删除API密钥")
+	apiKeyBaseController.HandleSuccess(c, "删除成功", nil)
+}
+
+// AuditLogListHandler 审计日志列表API处理器
+func AuditLogListHandler(c *gin.Context) {
+	page, pageSize := apiKeyBaseController.GetPaginationParams(c)
+
+	db, ok := apiKeyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.AuditLog{})
+	if actorType := strings.TrimSpace(c.Query("actor_type")); actorType != "" {
+		query = query.Where("actor_type = ?", actorType)
+	}
+	if keyID, err := strconv.ParseUint(c.Query("api_key_id"), 10, 64); err == nil && keyID > 0 {
+		query = query.Where("api_key_id = ?", keyID)
+	}
+	if path := strings.TrimSpace(c.Query("path")); path != "" {
+		query = query.Where("path LIKE ?", "%"+path+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		apiKeyBaseController.HandleInternalError(c, "查询审计日志总数失败", err)
+		return
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order("id DESC").Offset(apiKeyBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&logs).Error; err != nil {
+		apiKeyBaseController.HandleInternalError(c, "查询审计日志失败", err)
+		return
+	}
+
+	apiKeyBaseController.HandleList(c, logs, total, page, pageSize)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// authenticateAPIKeyRequest 使用API密钥认证后台接口请求
+// - 密钥无效、停用或过期时返回 401
+// - 按请求方法与路径确定所需权限（见 models.APIKeyResources），未授权时返回 403
+// - 认证通过的请求（包括因权限不足被拒绝的请求）全部记录审计日志
+func authenticateAPIKeyRequest(c *gin.Context, token string) {
+	start := time.Now()
+	key, err := services.AuthenticateAPIKey(c.Request.Context(), token, utils.ClientIP(c))
+	if err != nil {
+		if !errors.Is(err, services.ErrAPIKeyInvalid) && !errors.Is(err, services.ErrAPIKeyDisabled) && !errors.Is(err, services.ErrAPIKeyExpired) {
+			apiKeyBaseController.HandleInternalError(c, "校验API密钥失败", err)
+			c.Abort()
+			return
+		}
+		logger.FromContext(c).WithError(err).WithField("ip", utils.ClientIP(c)).Warn("API密钥认证失败")
+		utils.AbortWithError(c, apiKeyErrorCode(err), err.Error())
+		return
+	}
+
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	scope, ok := models.APIKeyScopeForRequest(c.Request.Method, strings.TrimPrefix(route, utils.AdminPrefix()))
+	if !ok || !key.HasScope(scope) {
+		msg := "API密钥不能访问该接口"
+		if ok {
+			msg = "API密钥缺少权限: " + scope
+		}
+		utils.AbortWithError(c, constants.CodeAPIKeyScopeDenied, msg)
+		recordAdminAudit(c, models.AuditActorAPIKey, key.Name, key.ID, start)
+		return
+	}
+
+	c.Set(apiKeyContextKey, key)
+	c.Next()
+	recordAdminAudit(c, models.AuditActorAPIKey, key.Name, key.ID, start)
+}
+
+// apiKeyErrorCode 将API密钥认证错误映射为错误码
+func apiKeyErrorCode(err error) constants.ErrorCode {
+	switch {
+	case errors.Is(err, services.ErrAPIKeyDisabled):
+		return constants.CodeAPIKeyDisabled
+	case errors.Is(err, services.ErrAPIKeyExpired):
+		return constants.CodeAPIKeyExpired
+	default:
+		return constants.CodeAPIKeyInvalid
+	}
+}
+
+// recordAdminAudit 记录一条后台接口调用的审计日志
+func recordAdminAudit(c *gin.Context, actorType, actor string, apiKeyID uint, start time.Time) {
+	services.RecordAudit(c.Request.Context(), &models.AuditLog{
+		ActorType:  actorType,
+		Actor:      actor,
+		APIKeyID:   apiKeyID,
+		Method:     c.Request.Method,
+		Path:       c.Request.URL.Path,
+		Status:     c.Writer.Status(),
+		IP:         utils.ClientIP(c),
+		RequestID:  logger.RequestID(c),
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+}
+
+// currentAPIKey 获取当前请求使用的API密钥，管理员会话请求返回 nil
+func currentAPIKey(c *gin.Context) *models.AdminAPIKey {
+	if value, ok := c.Get(apiKeyContextKey); ok {
+		if key, ok := value.(*models.AdminAPIKey); ok {
+			return key
+		}
+	}
+	return nil
+}
+
+// allowSecretAccess 判断当前请求能否读取应用密钥、接口私钥等明文
+// 使用API密钥的请求一律拒绝并返回 403，管理员会话不受限制
+func allowSecretAccess(c *gin.Context) bool {
+	if currentAPIKey(c) == nil {
+		return true
+	}
+	utils.AbortWithError(c, constants.CodeAPIKeyScopeDenied, "API密钥不能读取密钥明文")
+	return false
+}
+
+// apiKeyAllowsApp 判断当前请求是否允许操作指定应用，管理员会话不受限制
+func apiKeyAllowsApp(c *gin.Context, appUUID string) bool {
+	key := currentAPIKey(c)
+	return key == nil || key.AllowsApp(appUUID)
+}
+
+// validateAPIKeyRequest 校验并规范化API密钥请求参数，返回解析后的过期时间
+func validateAPIKeyRequest(c *gin.Context, req *apiKeyRequest) (*time.Time, bool) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		apiKeyBaseController.HandleValidationError(c, "名称不能为空")
+		return nil, false
+	}
+	if len(req.Scopes) == 0 {
+		apiKeyBaseController.HandleValidationError(c, "请选择授权的权限")
+		return nil, false
+	}
+	for _, scope := range req.Scopes {
+		if !models.IsAPIKeyScope(scope) {
+			apiKeyBaseController.HandleValidationError(c, "无效的权限: "+scope)
+			return nil, false
+		}
+	}
+	if req.Status != models.APIKeyEnabled {
+		req.Status = models.APIKeyDisabled
+	}
+
+	apps := make([]string, 0, len(req.AppUUIDs))
+	for _, uuid := range req.AppUUIDs {
+		if uuid = strings.ToUpper(strings.TrimSpace(uuid)); uuid != "" {
+			apps = append(apps, uuid)
+		}
+	}
+	req.AppUUIDs = apps
+	if len(apps) > 0 {
+		// 只有校验应用范围的接口才能授权给限定应用的密钥，避免通过其他接口越权
+		for _, scope := range req.Scopes {
+			if !models.IsAppScopedAPIKeyScope(scope) {
+				apiKeyBaseController.HandleValidationError(c, "限定应用的密钥只能授权卡密权限，"+scope+" 不支持按应用限制")
+				return nil, false
+			}
+		}
+		db, ok := apiKeyBaseController.GetDB(c)
+		if !ok {
+			return nil, false
+		}
+		var count int64
+		if err := db.Model(&models.App{}).Where("uuid IN ?", apps).Count(&count).Error; err != nil {
+			apiKeyBaseController.HandleInternalError(c, "查询应用失败", err)
+			return nil, false
+		}
+		if int(count) != len(apps) {
+			apiKeyBaseController.HandleValidationError(c, "应用不存在")
+			return nil, false
+		}
+	}
+
+	text := strings.TrimSpace(req.ExpiresAt)
+	if text == "" {
+		return nil, true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			// 编辑时允许保留已过期的时间，便于停用/启用已过期的密钥
+			if req.ID == 0 && !t.After(time.Now()) {
+				apiKeyBaseController.HandleValidationError(c, "过期时间必须晚于当前时间")
+				return nil, false
+			}
+			return &t, true
+		}
+	}
+	apiKeyBaseController.HandleValidationError(c, "过期时间格式错误")
+	return nil, false
+}
@@ -1,12 +1,17 @@
 package admin
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"net/http"
+	"networkDev/constants"
 	"networkDev/controllers"
 	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
 	"strconv"
 	"strings"
 
@@ -69,7 +74,7 @@ func AppsListHandler(c *gin.Context) {
 
 	// 获取总数
 	if err := query.Count(&total).Error; err != nil {
-		logrus.WithError(err).Error("Failed to count apps")
+		logger.FromContext(c).WithError(err).Error("Failed to count apps")
 		appBaseController.HandleInternalError(c, "获取应用总数失败", err)
 		return
 	}
@@ -77,20 +82,23 @@ func AppsListHandler(c *gin.Context) {
 	// 分页查询
 	offset := (page - 1) * limit
 	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&apps).Error; err != nil {
-		logrus.WithError(err).Error("Failed to query apps")
+		logger.FromContext(c).WithError(err).Error("Failed to query apps")
 		appBaseController.HandleInternalError(c, "查询应用列表失败", err)
 		return
 	}
 
-	// 返回结果
-	response := gin.H{
-		"code":  0,
-		"msg":   "success",
-		"count": total,
-		"data":  apps,
+	// 默认对应用密钥脱敏，仅在管理员会话显式请求 reveal=1 时返回明文
+	reveal := c.Query("reveal") == "1"
+	if reveal && !allowSecretAccess(c) {
+		return
+	}
+	if !reveal {
+		for i := range apps {
+			apps[i].Redact()
+		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	appBaseController.HandleList(c, apps, total, page, limit)
 }
 
 // AppGetAppDataHandler 获取应用数据处理器
@@ -98,10 +106,7 @@ func AppGetAppDataHandler(c *gin.Context) {
 	// 获取UUID参数
 	uuid := c.Query("uuid")
 	if uuid == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
@@ -114,11 +119,8 @@ func AppGetAppDataHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", uuid).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -127,7 +129,7 @@ func AppGetAppDataHandler(c *gin.Context) {
 	if app.AppData != "" {
 		decodedBytes, err := base64.StdEncoding.DecodeString(app.AppData)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to decode app data")
+			logger.FromContext(c).WithError(err).Error("Failed to decode app data")
 			// 如果解码失败，返回空字符串
 			appData = ""
 		} else {
@@ -135,12 +137,8 @@ func AppGetAppDataHandler(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "获取成功",
-		"data": gin.H{
-			"app_data": appData,
-		},
+	appBaseController.HandleSuccess(c, "获取成功", gin.H{
+		"app_data": appData,
 	})
 }
 
@@ -149,10 +147,7 @@ func AppGetAnnouncementHandler(c *gin.Context) {
 	// 获取UUID参数
 	uuid := c.Query("uuid")
 	if uuid == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
@@ -165,11 +160,8 @@ func AppGetAnnouncementHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", uuid).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -178,7 +170,7 @@ func AppGetAnnouncementHandler(c *gin.Context) {
 	if app.Announcement != "" {
 		decodedBytes, err := base64.StdEncoding.DecodeString(app.Announcement)
 		if err != nil {
-			logrus.WithError(err).Error("Failed to decode announcement")
+			logger.FromContext(c).WithError(err).Error("Failed to decode announcement")
 			// 如果解码失败，返回空字符串
 			announcement = ""
 		} else {
@@ -186,12 +178,8 @@ func AppGetAnnouncementHandler(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "获取成功",
-		"data": gin.H{
-			"announcement": announcement,
-		},
+	appBaseController.HandleSuccess(c, "获取成功", gin.H{
+		"announcement": announcement,
 	})
 }
 
@@ -207,10 +195,12 @@ func AppResetSecretHandler(c *gin.Context) {
 
 	// 验证必填字段
 	if strings.TrimSpace(req.UUID) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
+		return
+	}
+
+	// 新密钥只在响应中返回，API密钥不能读取
+	if !allowSecretAccess(c) {
 		return
 	}
 
@@ -223,11 +213,8 @@ func AppResetSecretHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -237,23 +224,21 @@ func AppResetSecretHandler(c *gin.Context) {
 	newSecret := strings.ToUpper(hex.EncodeToString(bytes))
 
 	// 更新密钥
-	if err := db.Model(&app).Update("secret", newSecret).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app secret")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "重置密钥失败",
-		})
+	// 通过结构体更新以经过加密序列化器，避免明文写入数据库
+	app.Secret = newSecret
+	if err := db.Model(&app).Select("secret").Updates(&app).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to update app secret")
+		appBaseController.HandleError(c, constants.CodeInternalError, "重置密钥失败")
 		return
 	}
 
-	logrus.WithField("app_uuid", app.UUID).Info("Successfully reset app secret")
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithField("app_uuid", app.UUID).Info("Successfully reset app secret")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "重置成功",
-		"data": gin.H{
-			"secret": newSecret,
-		},
+	appBaseController.HandleSuccess(c, "重置成功", gin.H{
+		"secret": newSecret,
 	})
 }
 
@@ -274,11 +259,8 @@ func AppCreateHandler(c *gin.Context) {
 
 	// 验证必填字段
 	if strings.TrimSpace(req.Name) == "" {
-		logrus.Error("App name is empty")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用名称不能为空",
-		})
+		logger.FromContext(c).Error("App name is empty")
+		appBaseController.HandleValidationError(c, "应用名称不能为空")
 		return
 	}
 
@@ -287,7 +269,7 @@ func AppCreateHandler(c *gin.Context) {
 		req.Version = "1.0.0"
 	}
 
-	logrus.WithFields(logrus.Fields{
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"name":          req.Name,
 		"version":       req.Version,
 		"status":        req.Status,
@@ -325,11 +307,8 @@ func AppCreateHandler(c *gin.Context) {
 	// 开始事务
 	tx := db.Begin()
 	if tx.Error != nil {
-		logrus.WithError(tx.Error).Error("Failed to begin transaction")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "开始事务失败",
-		})
+		logger.FromContext(c).WithError(tx.Error).Error("Failed to begin transaction")
+		appBaseController.HandleError(c, constants.CodeInternalError, "开始事务失败")
 		return
 	}
 	defer func() {
@@ -341,11 +320,8 @@ func AppCreateHandler(c *gin.Context) {
 	// 创建应用
 	if err := tx.Create(&app).Error; err != nil {
 		tx.Rollback()
-		logrus.WithError(err).Error("Failed to create app")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "创建应用失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to create app")
+		appBaseController.HandleError(c, constants.CodeInternalError, "创建应用失败")
 		return
 	}
 
@@ -364,32 +340,25 @@ func AppCreateHandler(c *gin.Context) {
 
 		if err := tx.Create(&api).Error; err != nil {
 			tx.Rollback()
-			logrus.WithError(err).WithField("api_type", apiType).Error("Failed to create default API")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"code": 1,
-				"msg":  "创建默认接口失败",
-			})
+			logger.FromContext(c).WithError(err).WithField("api_type", apiType).Error("Failed to create default API")
+			appBaseController.HandleError(c, constants.CodeInternalError, "创建默认接口失败")
 			return
 		}
 	}
 
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
-		logrus.WithError(err).Error("Failed to commit transaction")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "提交事务失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to commit transaction")
+		appBaseController.HandleError(c, constants.CodeInternalError, "提交事务失败")
 		return
 	}
 
-	logrus.WithField("app_uuid", app.UUID).Info("Successfully created app with default APIs")
+	logger.FromContext(c).WithField("app_uuid", app.UUID).Info("Successfully created app with default APIs")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "创建成功",
-		"data": app,
-	})
+	if currentAPIKey(c) != nil {
+		app.Redact()
+	}
+	appBaseController.HandleSuccess(c, "创建成功", app)
 }
 
 // AppUpdateHandler 更新应用API处理器
@@ -410,18 +379,12 @@ func AppUpdateHandler(c *gin.Context) {
 
 	// 验证必填字段
 	if req.ID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用ID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用ID不能为空")
 		return
 	}
 
 	if strings.TrimSpace(req.Name) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用名称不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用名称不能为空")
 		return
 	}
 
@@ -434,15 +397,13 @@ func AppUpdateHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.First(&app, req.ID).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
 	// 更新应用信息
+	oldStatus := app.Status
 	app.Name = strings.TrimSpace(req.Name)
 	app.Version = req.Version
 	app.Status = req.Status
@@ -451,21 +412,24 @@ func AppUpdateHandler(c *gin.Context) {
 	app.ForceUpdate = req.ForceUpdate
 
 	if err := db.Save(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新应用失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新应用失败")
 		return
 	}
 
-	logrus.WithField("app_id", app.ID).Info("Successfully updated app")
+	// 使缓存的应用信息失效，后台修改立即对客户端接口生效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "更新成功",
-		"data": app,
-	})
+	if app.Status != oldStatus {
+		emitAppStatusChanged(c, app, oldStatus)
+	}
+
+	logger.FromContext(c).WithField("app_id", app.ID).Info("Successfully updated app")
+
+	if currentAPIKey(c) != nil {
+		app.Redact()
+	}
+	appBaseController.HandleSuccess(c, "更新成功", app)
 }
 
 // AppDeleteHandler 删除应用处理器
@@ -480,10 +444,7 @@ func AppDeleteHandler(c *gin.Context) {
 
 	// 验证必填字段
 	if req.ID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用ID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用ID不能为空")
 		return
 	}
 
@@ -496,22 +457,16 @@ func AppDeleteHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.First(&app, req.ID).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
 	// 开始事务
 	tx := db.Begin()
 	if tx.Error != nil {
-		logrus.WithError(tx.Error).Error("Failed to begin transaction")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "开始事务失败",
-		})
+		logger.FromContext(c).WithError(tx.Error).Error("Failed to begin transaction")
+		appBaseController.HandleError(c, constants.CodeInternalError, "开始事务失败")
 		return
 	}
 
@@ -524,44 +479,36 @@ func AppDeleteHandler(c *gin.Context) {
 	// 删除相关的API记录
 	if err := tx.Where("app_uuid = ?", app.UUID).Delete(&models.API{}).Error; err != nil {
 		tx.Rollback()
-		logrus.WithError(err).Error("Failed to delete related APIs")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "删除相关接口失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to delete related APIs")
+		appBaseController.HandleError(c, constants.CodeInternalError, "删除相关接口失败")
 		return
 	}
 
 	// 删除应用
 	if err := tx.Delete(&app).Error; err != nil {
 		tx.Rollback()
-		logrus.WithError(err).Error("Failed to delete app")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "删除应用失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to delete app")
+		appBaseController.HandleError(c, constants.CodeInternalError, "删除应用失败")
 		return
 	}
 
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
-		logrus.WithError(err).Error("Failed to commit transaction")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "提交事务失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to commit transaction")
+		appBaseController.HandleError(c, constants.CodeInternalError, "提交事务失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使缓存的应用与接口配置失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+	services.InvalidateAppAPIs(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_id":   app.ID,
 		"app_uuid": app.UUID,
 	}).Info("Successfully deleted app and related APIs")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "删除成功",
-	})
+	appBaseController.HandleSuccess(c, "删除成功", nil)
 }
 
 // AppUpdateAppDataHandler 更新应用数据处理器
@@ -578,20 +525,14 @@ func AppUpdateAppDataHandler(c *gin.Context) {
 
 	// 验证UUID
 	if req.UUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(req.UUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -604,11 +545,8 @@ func AppUpdateAppDataHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -617,23 +555,20 @@ func AppUpdateAppDataHandler(c *gin.Context) {
 
 	// 更新应用的数据内容
 	if err := db.Model(&app).Update("app_data", encodedAppData).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app data")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新应用数据失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app data")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新应用数据失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_uuid": req.UUID,
 		"app_name": app.Name,
 	}).Info("App data updated successfully")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "应用数据更新成功",
-	})
+	appBaseController.HandleSuccess(c, "应用数据更新成功", nil)
 }
 
 // AppUpdateAnnouncementHandler 更新应用程序公告处理器
@@ -650,20 +585,14 @@ func AppUpdateAnnouncementHandler(c *gin.Context) {
 
 	// 验证UUID
 	if req.UUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(req.UUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -676,11 +605,8 @@ func AppUpdateAnnouncementHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -689,43 +615,34 @@ func AppUpdateAnnouncementHandler(c *gin.Context) {
 
 	// 更新应用的公告内容
 	if err := db.Model(&app).Update("announcement", encodedAnnouncement).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app announcement")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新程序公告失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app announcement")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新程序公告失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_uuid": req.UUID,
 		"app_name": app.Name,
 	}).Info("App announcement updated successfully")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "程序公告更新成功",
-	})
+	appBaseController.HandleSuccess(c, "程序公告更新成功", nil)
 }
 
 // AppGetMultiConfigHandler 获取应用多开配置处理器
 func AppGetMultiConfigHandler(c *gin.Context) {
 	appUUID := c.Query("uuid")
 	if appUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(appUUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -738,24 +655,17 @@ func AppGetMultiConfigHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", appUUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "获取多开配置成功",
-		"data": gin.H{
-			"login_type":       app.LoginType,
-			"multi_open_scope": app.MultiOpenScope,
-			"clean_interval":   app.CleanInterval,
-			"check_interval":   app.CheckInterval,
-			"multi_open_count": app.MultiOpenCount,
-		},
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
+		return
+	}
+
+	appBaseController.HandleSuccess(c, "获取多开配置成功", gin.H{
+		"login_type":       app.LoginType,
+		"multi_open_scope": app.MultiOpenScope,
+		"clean_interval":   app.CleanInterval,
+		"check_interval":   app.CheckInterval,
+		"multi_open_count": app.MultiOpenCount,
 	})
 }
 
@@ -777,57 +687,36 @@ func AppUpdateMultiConfigHandler(c *gin.Context) {
 
 	// 验证UUID
 	if req.UUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(req.UUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
 	// 验证参数范围
 	if req.LoginType < 0 || req.LoginType > 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "登录方式参数无效",
-		})
+		appBaseController.HandleValidationError(c, "登录方式参数无效")
 		return
 	}
 	if req.MultiOpenScope < 0 || req.MultiOpenScope > 2 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "多开范围参数无效",
-		})
+		appBaseController.HandleValidationError(c, "多开范围参数无效")
 		return
 	}
 	if req.CleanInterval < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "清理间隔必须大于0",
-		})
+		appBaseController.HandleValidationError(c, "清理间隔必须大于0")
 		return
 	}
 	if req.CheckInterval < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "校验间隔必须大于0",
-		})
+		appBaseController.HandleValidationError(c, "校验间隔必须大于0")
 		return
 	}
 	if req.MultiOpenCount < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "多开数量必须大于0",
-		})
+		appBaseController.HandleValidationError(c, "多开数量必须大于0")
 		return
 	}
 
@@ -840,11 +729,8 @@ func AppUpdateMultiConfigHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -858,43 +744,34 @@ func AppUpdateMultiConfigHandler(c *gin.Context) {
 	}
 
 	if err := db.Model(&app).Updates(updates).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app multi config")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新多开配置失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app multi config")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新多开配置失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_uuid": req.UUID,
 		"app_name": app.Name,
 	}).Info("App multi config updated successfully")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "多开配置更新成功",
-	})
+	appBaseController.HandleSuccess(c, "多开配置更新成功", nil)
 }
 
 // AppGetBindConfigHandler 获取应用绑定配置处理器
 func AppGetBindConfigHandler(c *gin.Context) {
 	appUUID := c.Query("uuid")
 	if appUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(appUUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -907,31 +784,24 @@ func AppGetBindConfigHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", appUUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "获取绑定配置成功",
-		"data": gin.H{
-			"machine_verify":         app.MachineVerify,
-			"machine_rebind_enabled": app.MachineRebindEnabled,
-			"machine_rebind_limit":   app.MachineRebindLimit,
-			"machine_free_count":     app.MachineFreeCount,
-			"machine_rebind_count":   app.MachineRebindCount,
-			"machine_rebind_deduct":  app.MachineRebindDeduct,
-			"ip_verify":              app.IPVerify,
-			"ip_rebind_enabled":      app.IPRebindEnabled,
-			"ip_rebind_limit":        app.IPRebindLimit,
-			"ip_free_count":          app.IPFreeCount,
-			"ip_rebind_count":        app.IPRebindCount,
-			"ip_rebind_deduct":       app.IPRebindDeduct,
-		},
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
+		return
+	}
+
+	appBaseController.HandleSuccess(c, "获取绑定配置成功", gin.H{
+		"machine_verify":         app.MachineVerify,
+		"machine_rebind_enabled": app.MachineRebindEnabled,
+		"machine_rebind_limit":   app.MachineRebindLimit,
+		"machine_free_count":     app.MachineFreeCount,
+		"machine_rebind_count":   app.MachineRebindCount,
+		"machine_rebind_deduct":  app.MachineRebindDeduct,
+		"ip_verify":              app.IPVerify,
+		"ip_rebind_enabled":      app.IPRebindEnabled,
+		"ip_rebind_limit":        app.IPRebindLimit,
+		"ip_free_count":          app.IPFreeCount,
+		"ip_rebind_count":        app.IPRebindCount,
+		"ip_rebind_deduct":       app.IPRebindDeduct,
 	})
 }
 
@@ -960,20 +830,14 @@ func AppUpdateBindConfigHandler(c *gin.Context) {
 
 	// 验证UUID
 	if req.UUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(req.UUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -986,11 +850,8 @@ func AppUpdateBindConfigHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -1011,43 +872,34 @@ func AppUpdateBindConfigHandler(c *gin.Context) {
 	}
 
 	if err := db.Model(&app).Updates(updates).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app bind config")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新绑定配置失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app bind config")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新绑定配置失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_uuid": req.UUID,
 		"app_name": app.Name,
 	}).Info("App bind config updated successfully")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "绑定配置更新成功",
-	})
+	appBaseController.HandleSuccess(c, "绑定配置更新成功", nil)
 }
 
 // AppGetRegisterConfigHandler 获取应用注册配置处理器
 func AppGetRegisterConfigHandler(c *gin.Context) {
 	appUUID := c.Query("uuid")
 	if appUUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(appUUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -1060,26 +912,19 @@ func AppGetRegisterConfigHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", appUUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "获取注册配置成功",
-		"data": gin.H{
-			"register_enabled":       app.RegisterEnabled,
-			"register_limit_enabled": app.RegisterLimitEnabled,
-			"register_limit_time":    app.RegisterLimitTime,
-			"register_count":         app.RegisterCount,
-			"trial_enabled":          app.TrialEnabled,
-			"trial_limit_time":       app.TrialLimitTime,
-			"trial_duration":         app.TrialDuration,
-		},
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
+		return
+	}
+
+	appBaseController.HandleSuccess(c, "获取注册配置成功", gin.H{
+		"register_enabled":       app.RegisterEnabled,
+		"register_limit_enabled": app.RegisterLimitEnabled,
+		"register_limit_time":    app.RegisterLimitTime,
+		"register_count":         app.RegisterCount,
+		"trial_enabled":          app.TrialEnabled,
+		"trial_limit_time":       app.TrialLimitTime,
+		"trial_duration":         app.TrialDuration,
 	})
 }
 
@@ -1103,20 +948,14 @@ func AppUpdateRegisterConfigHandler(c *gin.Context) {
 
 	// 验证UUID
 	if req.UUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用UUID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
 		return
 	}
 
 	// 验证UUID格式
 	if _, err := uuid.Parse(req.UUID); err != nil {
-		logrus.WithError(err).Error("Invalid UUID format")
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "无效的UUID格式",
-		})
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
 		return
 	}
 
@@ -1129,11 +968,8 @@ func AppUpdateRegisterConfigHandler(c *gin.Context) {
 	// 查找应用
 	var app models.App
 	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
-		logrus.WithError(err).Error("Failed to find app")
-		c.JSON(http.StatusNotFound, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
@@ -1149,25 +985,131 @@ func AppUpdateRegisterConfigHandler(c *gin.Context) {
 	}
 
 	if err := db.Model(&app).Updates(updates).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app register config")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新注册配置失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app register config")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新注册配置失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_uuid": req.UUID,
 		"app_name": app.Name,
 	}).Info("App register config updated successfully")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "注册配置更新成功",
+	appBaseController.HandleSuccess(c, "注册配置更新成功", nil)
+}
+
+// AppGetMaintenanceConfigHandler 获取应用维护配置处理器
+func AppGetMaintenanceConfigHandler(c *gin.Context) {
+	appUUID := c.Query("uuid")
+	if appUUID == "" {
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
+		return
+	}
+
+	// 验证UUID格式
+	if _, err := uuid.Parse(appUUID); err != nil {
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
+		return
+	}
+
+	// 获取数据库连接
+	db, ok := appBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	// 查找应用
+	var app models.App
+	if err := db.Where("uuid = ?", appUUID).First(&app).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
+		return
+	}
+
+	appBaseController.HandleSuccess(c, "获取维护配置成功", gin.H{
+		"maintenance_mode":    app.MaintenanceMode,
+		"maintenance_message": app.MaintenanceMessage,
 	})
 }
 
+// AppUpdateMaintenanceConfigHandler 更新应用维护配置处理器
+func AppUpdateMaintenanceConfigHandler(c *gin.Context) {
+	// 解析请求体
+	var req struct {
+		UUID               string `json:"uuid"`
+		MaintenanceMode    int    `json:"maintenance_mode"`
+		MaintenanceMessage string `json:"maintenance_message"`
+	}
+
+	if !appBaseController.BindJSON(c, &req) {
+		return
+	}
+
+	// 验证UUID
+	if req.UUID == "" {
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
+		return
+	}
+
+	// 验证UUID格式
+	if _, err := uuid.Parse(req.UUID); err != nil {
+		logger.FromContext(c).WithError(err).Error("Invalid UUID format")
+		appBaseController.HandleValidationError(c, "无效的UUID格式")
+		return
+	}
+
+	// 验证参数范围
+	if req.MaintenanceMode < 0 || req.MaintenanceMode > 1 {
+		appBaseController.HandleValidationError(c, "维护开关参数无效")
+		return
+	}
+	if len([]rune(req.MaintenanceMessage)) > 500 {
+		appBaseController.HandleValidationError(c, "维护说明不能超过500个字符")
+		return
+	}
+
+	// 获取数据库连接
+	db, ok := appBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	// 查找应用
+	var app models.App
+	if err := db.Where("uuid = ?", req.UUID).First(&app).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
+		return
+	}
+
+	// 更新维护配置
+	updates := map[string]interface{}{
+		"maintenance_mode":    req.MaintenanceMode,
+		"maintenance_message": strings.TrimSpace(req.MaintenanceMessage),
+	}
+
+	if err := db.Model(&app).Updates(updates).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to update app maintenance config")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新维护配置失败")
+		return
+	}
+
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"app_uuid":         req.UUID,
+		"app_name":         app.Name,
+		"maintenance_mode": req.MaintenanceMode,
+	}).Info("App maintenance config updated successfully")
+
+	appBaseController.HandleSuccess(c, "维护配置更新成功", nil)
+}
+
 // AppsBatchDeleteHandler 批量删除应用处理器
 func AppsBatchDeleteHandler(c *gin.Context) {
 	var req struct {
@@ -1179,10 +1121,7 @@ func AppsBatchDeleteHandler(c *gin.Context) {
 	}
 
 	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "请选择要删除的应用",
-		})
+		appBaseController.HandleValidationError(c, "请选择要删除的应用")
 		return
 	}
 
@@ -1195,11 +1134,8 @@ func AppsBatchDeleteHandler(c *gin.Context) {
 	// 开始事务
 	tx := db.Begin()
 	if tx.Error != nil {
-		logrus.WithError(tx.Error).Error("Failed to begin transaction")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "开始事务失败",
-		})
+		logger.FromContext(c).WithError(tx.Error).Error("Failed to begin transaction")
+		appBaseController.HandleError(c, constants.CodeInternalError, "开始事务失败")
 		return
 	}
 
@@ -1213,11 +1149,8 @@ func AppsBatchDeleteHandler(c *gin.Context) {
 	var apps []models.App
 	if err := tx.Where("id IN ?", req.IDs).Find(&apps).Error; err != nil {
 		tx.Rollback()
-		logrus.WithError(err).Error("Failed to find apps")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "查找应用失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to find apps")
+		appBaseController.HandleError(c, constants.CodeInternalError, "查找应用失败")
 		return
 	}
 
@@ -1231,11 +1164,8 @@ func AppsBatchDeleteHandler(c *gin.Context) {
 	if len(appUUIDs) > 0 {
 		if err := tx.Where("app_uuid IN ?", appUUIDs).Delete(&models.API{}).Error; err != nil {
 			tx.Rollback()
-			logrus.WithError(err).Error("Failed to delete related APIs")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"code": 1,
-				"msg":  "删除相关接口失败",
-			})
+			logger.FromContext(c).WithError(err).Error("Failed to delete related APIs")
+			appBaseController.HandleError(c, constants.CodeInternalError, "删除相关接口失败")
 			return
 		}
 	}
@@ -1243,33 +1173,28 @@ func AppsBatchDeleteHandler(c *gin.Context) {
 	// 批量删除应用
 	if err := tx.Delete(&models.App{}, req.IDs).Error; err != nil {
 		tx.Rollback()
-		logrus.WithError(err).Error("Failed to batch delete apps")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "批量删除失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to batch delete apps")
+		appBaseController.HandleError(c, constants.CodeInternalError, "批量删除失败")
 		return
 	}
 
 	// 提交事务
 	if err := tx.Commit().Error; err != nil {
-		logrus.WithError(err).Error("Failed to commit transaction")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "提交事务失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to commit transaction")
+		appBaseController.HandleError(c, constants.CodeInternalError, "提交事务失败")
 		return
 	}
 
-	logrus.WithFields(logrus.Fields{
+	// 使缓存的应用与接口配置失效
+	services.InvalidateApps(c.Request.Context(), appUUIDs...)
+	services.InvalidateAppAPIs(c.Request.Context(), appUUIDs...)
+
+	logger.FromContext(c).WithFields(logrus.Fields{
 		"app_ids":   req.IDs,
 		"app_uuids": appUUIDs,
 	}).Info("Successfully batch deleted apps and related APIs")
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "批量删除成功",
-	})
+	appBaseController.HandleSuccess(c, "批量删除成功", nil)
 }
 
 // AppsBatchUpdateStatusHandler 批量更新应用状态处理器
@@ -1284,18 +1209,12 @@ func AppsBatchUpdateStatusHandler(c *gin.Context) {
 	}
 
 	if len(req.IDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "请选择要更新的应用",
-		})
+		appBaseController.HandleValidationError(c, "请选择要更新的应用")
 		return
 	}
 
 	if req.Status != 0 && req.Status != 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "状态值无效",
-		})
+		appBaseController.HandleValidationError(c, "状态值无效")
 		return
 	}
 
@@ -1305,25 +1224,41 @@ func AppsBatchUpdateStatusHandler(c *gin.Context) {
 		return
 	}
 
+	// 查询更新前的应用状态，用于推送状态变更事件与缓存失效
+	var apps []models.App
+	if err := db.Select("id", "uuid", "name", "status").Where("id IN ?", req.IDs).Find(&apps).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to load apps for batch status update")
+		appBaseController.HandleError(c, constants.CodeInternalError, "批量更新状态失败")
+		return
+	}
+
 	// 批量更新状态
 	if err := db.Model(&models.App{}).Where("id IN ?", req.IDs).Update("status", req.Status).Error; err != nil {
-		logrus.WithError(err).Error("Failed to batch update app status")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "批量更新状态失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to batch update app status")
+		appBaseController.HandleError(c, constants.CodeInternalError, "批量更新状态失败")
 		return
 	}
 
+	// 使缓存的应用信息失效
+	appUUIDs := make([]string, 0, len(apps))
+	for _, app := range apps {
+		appUUIDs = append(appUUIDs, app.UUID)
+	}
+	services.InvalidateApps(c.Request.Context(), appUUIDs...)
+
+	for _, app := range apps {
+		if oldStatus := app.Status; oldStatus != req.Status {
+			app.Status = req.Status
+			emitAppStatusChanged(c, app, oldStatus)
+		}
+	}
+
 	statusText := "禁用"
 	if req.Status == 1 {
 		statusText = "启用"
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "批量" + statusText + "成功",
-	})
+	appBaseController.HandleSuccess(c, "批量"+statusText+"成功", nil)
 }
 
 // AppUpdateStatusHandler 更新单个应用状态处理器
@@ -1338,18 +1273,12 @@ func AppUpdateStatusHandler(c *gin.Context) {
 	}
 
 	if req.ID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用ID不能为空",
-		})
+		appBaseController.HandleValidationError(c, "应用ID不能为空")
 		return
 	}
 
 	if req.Status != 0 && req.Status != 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "状态值无效",
-		})
+		appBaseController.HandleValidationError(c, "状态值无效")
 		return
 	}
 
@@ -1362,32 +1291,32 @@ func AppUpdateStatusHandler(c *gin.Context) {
 	// 检查应用是否存在
 	var app models.App
 	if err := db.Where("id = ?", req.ID).First(&app).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code": 1,
-			"msg":  "应用不存在",
-		})
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
 		return
 	}
 
 	// 更新状态
+	oldStatus := app.Status
 	if err := db.Model(&app).Update("status", req.Status).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update app status")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "更新状态失败",
-		})
+		logger.FromContext(c).WithError(err).Error("Failed to update app status")
+		appBaseController.HandleError(c, constants.CodeInternalError, "更新状态失败")
 		return
 	}
 
+	// 使应用缓存失效
+	services.InvalidateApps(c.Request.Context(), app.UUID)
+
+	if oldStatus != req.Status {
+		app.Status = req.Status
+		emitAppStatusChanged(c, app, oldStatus)
+	}
+
 	statusText := "禁用"
 	if req.Status == 1 {
 		statusText = "启用"
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "应用" + statusText + "成功",
-	})
+	appBaseController.HandleSuccess(c, "应用"+statusText+"成功", nil)
 }
 
 // AppsSimpleListHandler 简化应用列表API处理器（用于下拉框选择等场景）
@@ -1410,17 +1339,70 @@ func AppsSimpleListHandler(c *gin.Context) {
 		Where("status = ?", 1). // 只获取启用的应用
 		Order("name ASC").
 		Find(&apps).Error; err != nil {
-		logrus.WithError(err).Error("Failed to query simple apps list")
+		logger.FromContext(c).WithError(err).Error("Failed to query simple apps list")
 		appBaseController.HandleInternalError(c, "获取应用列表失败", err)
 		return
 	}
 
-	// 返回结果
-	response := gin.H{
-		"code": 0,
-		"msg":  "success",
-		"data": apps,
+	appBaseController.HandleSuccess(c, "success", apps)
+}
+
+// AppIntegrationBundleHandler 导出应用的客户端接入包
+// 返回 zip 压缩包：manifest.json（接口、算法与公钥）及易语言、C#、Python、C++ 源码
+// 接入包只包含公开信息，返回私钥与 RC4/易加密密钥由接入方另行设置
+// 接入包属于应用配置导出，不能使用API密钥导出
+func AppIntegrationBundleHandler(c *gin.Context) {
+	uuid := c.Query("uuid")
+	if uuid == "" {
+		appBaseController.HandleValidationError(c, "应用UUID不能为空")
+		return
+	}
+	if !allowSecretAccess(c) {
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	db, ok := appBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	var app models.App
+	if err := db.Where("uuid = ?", uuid).First(&app).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to find app")
+		appBaseController.HandleError(c, constants.CodeNotFound, "应用不存在")
+		return
+	}
+
+	var apis []models.API
+	if err := db.Where("app_uuid = ?", app.UUID).Order("api_type ASC").Find(&apis).Error; err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to query app APIs")
+		appBaseController.HandleInternalError(c, "获取接口列表失败", err)
+		return
+	}
+
+	// 先完整生成再写出，生成失败时仍可返回JSON错误
+	var buf bytes.Buffer
+	if err := services.WriteIntegrationBundle(&buf, services.BuildIntegrationManifest(&app, apis)); err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to build integration bundle")
+		appBaseController.HandleInternalError(c, "生成接入包失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"app_uuid":  app.UUID,
+		"api_count": len(apis),
+	}).Info("Exported integration bundle")
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="integration_%s.zip"`, app.UUID))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// emitAppStatusChanged 推送应用状态变更事件
+func emitAppStatusChanged(c *gin.Context, app models.App, oldStatus int) {
+	services.EmitWebhookEvent(c.Request.Context(), models.WebhookEventAppStatusChanged, app.UUID, services.AppStatusChangedData(&app, oldStatus))
 }
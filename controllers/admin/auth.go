@@ -6,13 +6,18 @@ import (
 	"strings"
 	"time"
 
+	"networkDev/constants"
 	"networkDev/controllers"
 	"networkDev/database"
 	"networkDev/models"
+	"networkDev/services"
 	"networkDev/utils"
+	"networkDev/utils/logger"
+	"networkDev/utils/metrics"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
@@ -34,7 +39,7 @@ var authBaseController = controllers.NewBaseController()
 func LoginPageHandler(c *gin.Context) {
 	// 使用带清理功能的JWT校验，避免失效Cookie在登录页面造成问题
 	if IsAdminAuthenticatedWithCleanup(c) {
-		c.Redirect(http.StatusFound, "/admin")
+		c.Redirect(http.StatusFound, utils.AdminPrefix())
 		return
 	}
 
@@ -62,6 +67,7 @@ func LoginPageHandler(c *gin.Context) {
 	}
 	data := authBaseController.GetDefaultTemplateData()
 	data["CSRFToken"] = token
+	data["AdminPrefix"] = utils.AdminPrefix()
 
 	// 合并额外数据
 	for key, value := range extraData {
@@ -101,7 +107,7 @@ func LoginHandler(c *gin.Context) {
 
 	// 验证验证码
 	if !VerifyCaptcha(c, body.Captcha) {
-		authBaseController.HandleValidationError(c, "验证码错误")
+		authBaseController.HandleError(c, constants.CodeCaptchaInvalid, "")
 		return
 	}
 
@@ -114,7 +120,7 @@ func LoginHandler(c *gin.Context) {
 	// 通过前缀匹配一次性获取所有管理员相关设置
 	var adminSettings []models.Settings
 	if err := db.Where("name LIKE ?", "admin_%").Find(&adminSettings).Error; err != nil {
-		authBaseController.HandleValidationError(c, "用户不存在或密码错误")
+		authBaseController.HandleError(c, constants.CodeLoginFailed, "用户不存在或密码错误")
 		return
 	}
 
@@ -130,13 +136,14 @@ func LoginHandler(c *gin.Context) {
 	adminPasswordSalt, hasSalt := settingsMap["admin_password_salt"]
 
 	if !hasUsername || !hasPassword || !hasSalt {
-		authBaseController.HandleValidationError(c, "用户不存在或密码错误")
+		authBaseController.HandleError(c, constants.CodeLoginFailed, "用户不存在或密码错误")
 		return
 	}
 
 	// 验证用户名
 	if body.Username != adminUsername {
-		authBaseController.HandleValidationError(c, "用户不存在或密码错误")
+		metrics.RecordAdminLogin(false)
+		authBaseController.HandleError(c, constants.CodeLoginFailed, "用户不存在或密码错误")
 		return
 	}
 
@@ -148,7 +155,8 @@ func LoginHandler(c *gin.Context) {
 
 	// 使用盐值验证密码
 	if !utils.VerifyPasswordWithSalt(body.Password, adminPasswordSalt, adminPassword) {
-		authBaseController.HandleValidationError(c, "用户不存在或密码错误")
+		metrics.RecordAdminLogin(false)
+		authBaseController.HandleError(c, constants.CodeLoginFailed, "用户不存在或密码错误")
 		return
 	}
 
@@ -169,9 +177,16 @@ func LoginHandler(c *gin.Context) {
 	// 设置JWT Cookie（使用安全配置）
 	cookie := utils.CreateSecureCookie("admin_session", token, utils.GetDefaultCookieMaxAge())
 	c.SetCookie(cookie.Name, cookie.Value, cookie.MaxAge, cookie.Path, cookie.Domain, cookie.Secure, cookie.HttpOnly)
+	metrics.RecordAdminLogin(true)
+	services.EmitWebhookEvent(c.Request.Context(), models.WebhookEventAdminLogin, "", gin.H{
+		"username":   adminUsername,
+		"client_ip":  utils.ClientIP(c),
+		"user_agent": c.Request.UserAgent(),
+	})
+	services.NotifyAdminLogin(c.Request.Context(), adminUsername, utils.ClientIP(c))
 
 	authBaseController.HandleSuccess(c, "登录成功", gin.H{
-		"redirect": "/admin",
+		"redirect": utils.AdminPrefix(),
 	})
 }
 
@@ -186,7 +201,7 @@ func LogoutHandler(c *gin.Context) {
 	// 这里可以实现JWT黑名单机制
 
 	authBaseController.HandleSuccess(c, "已退出登录", gin.H{
-		"redirect": "/admin/login",
+		"redirect": utils.AdminPath("/login"),
 	})
 }
 
@@ -277,16 +292,20 @@ func validateAdminPasswordHash(claims *JWTClaims, c *gin.Context) bool {
 	// 这确保了密码修改后，旧的JWT令牌会失效
 	db, err := database.GetDB()
 	if err != nil {
-		fmt.Printf("[SECURITY WARNING] Database connection failed during auth - Username=%s, IP=%s\n",
-			claims.Username, c.ClientIP())
+		logger.FromContext(c).WithFields(logrus.Fields{
+			"username":  claims.Username,
+			"client_ip": utils.ClientIP(c),
+		}).Warn("[安全警告] 认证时数据库连接失败")
 		return false
 	}
 
 	// 获取当前数据库中的管理员密码
 	var adminPassword models.Settings
-	if err := db.Where("name = ?", "admin_password").First(&adminPassword).Error; err != nil {
-		fmt.Printf("[SECURITY WARNING] Admin password not found in database - Username=%s, IP=%s\n",
-			claims.Username, c.ClientIP())
+	if err := db.WithContext(c.Request.Context()).Where("name = ?", "admin_password").First(&adminPassword).Error; err != nil {
+		logger.FromContext(c).WithFields(logrus.Fields{
+			"username":  claims.Username,
+			"client_ip": utils.ClientIP(c),
+		}).Warn("[安全警告] 数据库中未找到管理员密码")
 		return false
 	}
 
@@ -295,8 +314,10 @@ func validateAdminPasswordHash(claims *JWTClaims, c *gin.Context) bool {
 
 	// 验证JWT中的密码哈希是否与当前数据库中的密码哈希一致
 	if claims.PasswordHash != currentPasswordHash {
-		fmt.Printf("[SECURITY WARNING] Password hash mismatch - JWT token invalidated - Username=%s, IP=%s\n",
-			claims.Username, c.ClientIP())
+		logger.FromContext(c).WithFields(logrus.Fields{
+			"username":  claims.Username,
+			"client_ip": utils.ClientIP(c),
+		}).Warn("[安全警告] 密码哈希不匹配，JWT令牌已失效")
 		return false
 	}
 
@@ -413,10 +434,16 @@ func GetCurrentAdminUserWithRefresh(c *gin.Context) (*JWTClaims, bool, error) {
 }
 
 // AdminAuthRequired 管理员认证拦截中间件
+// - 携带 Authorization: Bearer <API密钥> 时按API密钥认证（见 authenticateAPIKeyRequest），不读取会话Cookie
 // - 未登录：重定向到 /admin/login
-// - 已登录：自动刷新接近过期的令牌，然后放行到后续处理器
+// - 已登录：自动刷新接近过期的令牌，然后放行到后续处理器；非GET请求记录审计日志
 func AdminAuthRequired() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if token, ok := utils.BearerToken(c); ok {
+			authenticateAPIKeyRequest(c, token)
+			return
+		}
+
 		// 尝试获取用户信息并自动刷新令牌
 		claims, refreshed, err := GetCurrentAdminUserWithRefresh(c)
 		if err != nil {
@@ -429,15 +456,10 @@ func AdminAuthRequired() gin.HandlerFunc {
 			accept := c.GetHeader("Accept")
 			xrw := strings.ToLower(strings.TrimSpace(c.GetHeader("X-Requested-With")))
 			if strings.Contains(accept, "application/json") || xrw == "xmlhttprequest" {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"success": false,
-					"message": "未登录或会话已过期",
-					"data":    nil,
-				})
-				c.Abort()
+				utils.AbortWithError(c, constants.CodeUnauthorized, "")
 				return
 			}
-			c.Redirect(http.StatusFound, "/admin/login")
+			c.Redirect(http.StatusFound, utils.AdminPath("/login"))
 			c.Abort()
 			return
 		}
@@ -448,6 +470,11 @@ func AdminAuthRequired() gin.HandlerFunc {
 			_ = claims // 避免未使用变量警告
 		}
 
+		start := time.Now()
 		c.Next()
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			recordAdminAudit(c, models.AuditActorAdmin, claims.Username, 0, start)
+		}
 	}
 }
@@ -8,8 +8,10 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"networkDev/constants"
 	"networkDev/controllers"
 	"networkDev/middleware"
+	"networkDev/services"
 	"networkDev/utils"
 
 	"github.com/mojocn/base64Captcha"
@@ -22,9 +24,6 @@ import (
 // 创建基础控制器实例
 var captchaBaseController = controllers.NewBaseController()
 
-// 全局验证码存储器
-var store = base64Captcha.DefaultMemStore
-
 // ============================================================================
 // 辅助函数
 // ============================================================================
@@ -65,8 +64,8 @@ func CaptchaHandler(c *gin.Context) {
 		Fonts:           []string{"wqy-microhei.ttc"},
 	}
 
-	// 生成验证码
-	captcha := base64Captcha.NewCaptcha(&driver, store)
+	// 生成验证码（多实例部署时存储在Redis中，任一实例都能校验）
+	captcha := base64Captcha.NewCaptcha(&driver, services.GetCaptchaStore())
 	id, b64s, _, err := captcha.Generate()
 	if err != nil {
 		captchaBaseController.HandleInternalError(c, "生成验证码失败", err)
@@ -118,6 +117,8 @@ func VerifyCaptcha(c *gin.Context, captchaValue string) bool {
 		return false
 	}
 
+	store := services.GetCaptchaStore()
+
 	// 先尝试原始值验证
 	if store.Verify(captchaId, captchaValue, false) {
 		// 验证成功后删除验证码
@@ -155,6 +156,6 @@ func CaptchaAPIHandler(c *gin.Context) {
 	if isValid {
 		captchaBaseController.HandleSuccess(c, "验证码正确", nil)
 	} else {
-		captchaBaseController.HandleValidationError(c, "验证码错误")
+		captchaBaseController.HandleError(c, constants.CodeCaptchaInvalid, "")
 	}
 }
@@ -0,0 +1,204 @@
+package admin
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// cardGenerateRequest 批量生成卡密请求参数
+type cardGenerateRequest struct {
+	AppUUID  string `json:"app_uuid"`
+	Count    int    `json:"count"`
+	Duration string `json:"duration"` // 卡密时长，例如 30d、12h、90m，不带后缀按分钟计算
+	Valid    string `json:"valid"`    // 有效期，格式与 duration 相同，留空表示长期有效
+	Remark   string `json:"remark"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var cardBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// CardsListHandler 卡密列表API处理器
+// 支持按应用、状态、批次号与卡密筛选；限定应用的API密钥只能查询授权应用的卡密
+func CardsListHandler(c *gin.Context) {
+	page, pageSize := cardBaseController.GetPaginationParams(c)
+
+	db, ok := cardBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.Card{})
+	if appUUID := strings.ToUpper(strings.TrimSpace(c.Query("app_uuid"))); appUUID != "" {
+		if !apiKeyAllowsApp(c, appUUID) {
+			cardBaseController.HandleError(c, constants.CodeAPIKeyAppDenied, "")
+			return
+		}
+		query = query.Where("app_uuid = ?", appUUID)
+	} else if key := currentAPIKey(c); key != nil && len(key.AppList()) > 0 {
+		query = query.Where("app_uuid IN ?", key.AppList())
+	}
+	if status, err := strconv.Atoi(c.Query("status")); err == nil {
+		query = query.Where("status = ?", status)
+	}
+	if batchNo := strings.TrimSpace(c.Query("batch_no")); batchNo != "" {
+		query = query.Where("batch_no = ?", batchNo)
+	}
+	if cardKey := strings.TrimSpace(c.Query("card_key")); cardKey != "" {
+		query = query.Where("card_key = ?", strings.ToUpper(cardKey))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		cardBaseController.HandleInternalError(c, "查询卡密总数失败", err)
+		return
+	}
+
+	var cards []models.Card
+	if err := query.Order("id DESC").Offset(cardBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&cards).Error; err != nil {
+		cardBaseController.HandleInternalError(c, "查询卡密列表失败", err)
+		return
+	}
+
+	cardBaseController.HandleList(c, cards, total, page, pageSize)
+}
+
+// CardsGenerateHandler 批量生成卡密API处理器
+// 参数与 card generate 命令一致，返回生成的卡密
+func CardsGenerateHandler(c *gin.Context) {
+	var req cardGenerateRequest
+	if !cardBaseController.BindJSON(c, &req) {
+		return
+	}
+	req.AppUUID = strings.ToUpper(strings.TrimSpace(req.AppUUID))
+	if req.AppUUID == "" {
+		cardBaseController.HandleValidationError(c, "应用UUID不能为空")
+		return
+	}
+	if !apiKeyAllowsApp(c, req.AppUUID) {
+		cardBaseController.HandleError(c, constants.CodeAPIKeyAppDenied, "")
+		return
+	}
+	if req.Count < 1 || req.Count > services.CardMaxGenerateCount {
+		cardBaseController.HandleValidationError(c, "生成数量必须在 1~"+strconv.Itoa(services.CardMaxGenerateCount)+" 之间")
+		return
+	}
+	duration, err := services.ParseCardDuration(req.Duration)
+	if err != nil {
+		cardBaseController.HandleValidationError(c, err.Error())
+		return
+	}
+	var expiresAt *time.Time
+	if strings.TrimSpace(req.Valid) != "" {
+		valid, err := services.ParseCardDuration(req.Valid)
+		if err != nil {
+			cardBaseController.HandleValidationError(c, "有效期格式错误")
+			return
+		}
+		t := time.Now().Add(time.Duration(valid) * time.Minute)
+		expiresAt = &t
+	}
+
+	db, ok := cardBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var app models.App
+	if err := db.Where("uuid = ?", req.AppUUID).First(&app).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cardBaseController.HandleNotFoundError(c, "应用")
+			return
+		}
+		cardBaseController.HandleInternalError(c, "查询应用失败", err)
+		return
+	}
+
+	cards, err := services.GenerateCards(c.Request.Context(), services.CardGenerateOptions{
+		AppUUID:   app.UUID,
+		Count:     req.Count,
+		Duration:  duration,
+		ExpiresAt: expiresAt,
+		Remark:    req.Remark,
+	})
+	if err != nil {
+		cardBaseController.HandleInternalError(c, "生成卡密失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"app_uuid": app.UUID,
+		"count":    len(cards),
+		"batch_no": cards[0].BatchNo,
+	}).Info("生成卡密")
+	cardBaseController.HandleSuccess(c, "生成成功", cards)
+}
+
+// CardsUpdateStatusHandler 批量启用/禁用卡密API处理器
+// 只能在未使用与已禁用之间切换，已使用与已过期的卡密不受影响
+func CardsUpdateStatusHandler(c *gin.Context) {
+	var req struct {
+		IDs    []uint `json:"ids"`
+		Status int    `json:"status"`
+	}
+	if !cardBaseController.BindJSON(c, &req) {
+		return
+	}
+	if len(req.IDs) == 0 {
+		cardBaseController.HandleValidationError(c, "请选择卡密")
+		return
+	}
+	from := models.CardStatusDisabled
+	switch req.Status {
+	case models.CardStatusUnused:
+	case models.CardStatusDisabled:
+		from = models.CardStatusUnused
+	default:
+		cardBaseController.HandleValidationError(c, "状态只能为 0（启用）或 2（禁用）")
+		return
+	}
+
+	db, ok := cardBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	query := db.Model(&models.Card{}).Where("id IN ? AND status = ?", req.IDs, from)
+	if key := currentAPIKey(c); key != nil && len(key.AppList()) > 0 {
+		query = query.Where("app_uuid IN ?", key.AppList())
+	}
+	result := query.Update("status", req.Status)
+	if result.Error != nil {
+		cardBaseController.HandleInternalError(c, "更新卡密状态失败", result.Error)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"status":  req.Status,
+		"updated": result.RowsAffected,
+	}).Info("更新卡密状态")
+	cardBaseController.HandleSuccess(c, "已更新 "+strconv.FormatInt(result.RowsAffected, 10)+" 张卡密", gin.H{
+		"updated": result.RowsAffected,
+	})
+}
@@ -4,12 +4,13 @@ import (
 	"net/http"
 	"networkDev/controllers"
 	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
 // ============================================================================
@@ -84,7 +85,7 @@ func FunctionListHandler(c *gin.Context) {
 	// 获取总数
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		logrus.WithError(err).Error("Failed to count functions")
+		logger.FromContext(c).WithError(err).Error("Failed to count functions")
 		functionBaseController.HandleInternalError(c, "查询函数总数失败", err)
 		return
 	}
@@ -93,7 +94,7 @@ func FunctionListHandler(c *gin.Context) {
 	var functions []models.Function
 	offset := (page - 1) * limit
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&functions).Error; err != nil {
-		logrus.WithError(err).Error("Failed to fetch functions")
+		logger.FromContext(c).WithError(err).Error("Failed to fetch functions")
 		functionBaseController.HandleInternalError(c, "查询函数列表失败", err)
 		return
 	}
@@ -126,14 +127,7 @@ func FunctionListHandler(c *gin.Context) {
 		})
 	}
 
-	response := gin.H{
-		"code":  0,
-		"msg":   "success",
-		"count": total,
-		"data":  responseData,
-	}
-
-	c.JSON(http.StatusOK, response)
+	functionBaseController.HandleList(c, responseData, total, page, limit)
 }
 
 // FunctionCreateHandler 新增函数API处理器
@@ -178,7 +172,7 @@ func FunctionCreateHandler(c *gin.Context) {
 	if appUUID != "0" {
 		var appCount int64
 		if err := db.Model(&models.App{}).Where("uuid = ?", appUUID).Count(&appCount).Error; err != nil {
-			logrus.WithError(err).Error("Failed to check app existence")
+			logger.FromContext(c).WithError(err).Error("Failed to check app existence")
 			functionBaseController.HandleInternalError(c, "验证应用失败", err)
 			return
 		}
@@ -197,7 +191,7 @@ func FunctionCreateHandler(c *gin.Context) {
 	}
 
 	if err := db.Create(&function).Error; err != nil {
-		logrus.WithError(err).Error("Failed to create function")
+		logger.FromContext(c).WithError(err).Error("Failed to create function")
 		functionBaseController.HandleInternalError(c, "创建函数失败", err)
 		return
 	}
@@ -240,7 +234,7 @@ func FunctionUpdateHandler(c *gin.Context) {
 	if updateAppUUID != "0" {
 		var appCount int64
 		if err := db.Model(&models.App{}).Where("uuid = ?", updateAppUUID).Count(&appCount).Error; err != nil {
-			logrus.WithError(err).Error("Failed to check app existence")
+			logger.FromContext(c).WithError(err).Error("Failed to check app existence")
 			functionBaseController.HandleInternalError(c, "验证应用失败", err)
 			return
 		}
@@ -263,11 +257,14 @@ func FunctionUpdateHandler(c *gin.Context) {
 	function.Remark = strings.TrimSpace(req.Remark)
 
 	if err := db.Save(&function).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update function")
+		logger.FromContext(c).WithError(err).Error("Failed to update function")
 		functionBaseController.HandleInternalError(c, "更新函数失败", err)
 		return
 	}
 
+	// 使缓存的函数失效
+	services.InvalidateFunctions(c.Request.Context(), function.Alias)
+
 	functionBaseController.HandleSuccess(c, "更新成功", function)
 }
 
@@ -291,14 +288,22 @@ func FunctionDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	// 删除前记录别名，用于缓存失效
+	var aliases []string
+	if err := db.Model(&models.Function{}).Where("id = ?", req.ID).Pluck("alias", &aliases).Error; err != nil {
+		logger.FromContext(c).WithError(err).Warn("Failed to load function alias for cache invalidation")
+	}
+
 	// 删除函数
 	if err := db.Delete(&models.Function{}, req.ID).Error; err != nil {
-		logrus.WithError(err).Error("Failed to delete function")
+		logger.FromContext(c).WithError(err).Error("Failed to delete function")
 		functionBaseController.HandleInternalError(c, "删除函数失败", err)
 		return
 	}
 
-	logrus.WithField("function_id", req.ID).Info("Successfully deleted function")
+	services.InvalidateFunctions(c.Request.Context(), aliases...)
+
+	logger.FromContext(c).WithField("function_id", req.ID).Info("Successfully deleted function")
 
 	functionBaseController.HandleSuccess(c, "删除成功", nil)
 }
@@ -323,14 +328,22 @@ func FunctionsBatchDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	// 删除前记录别名，用于缓存失效
+	var aliases []string
+	if err := db.Model(&models.Function{}).Where("id IN ?", req.IDs).Pluck("alias", &aliases).Error; err != nil {
+		logger.FromContext(c).WithError(err).Warn("Failed to load function aliases for cache invalidation")
+	}
+
 	// 批量删除函数
 	if err := db.Delete(&models.Function{}, req.IDs).Error; err != nil {
-		logrus.WithError(err).Error("Failed to batch delete functions")
+		logger.FromContext(c).WithError(err).Error("Failed to batch delete functions")
 		functionBaseController.HandleInternalError(c, "批量删除失败", err)
 		return
 	}
 
-	logrus.WithField("function_ids", req.IDs).Info("Successfully batch deleted functions")
+	services.InvalidateFunctions(c.Request.Context(), aliases...)
+
+	logger.FromContext(c).WithField("function_ids", req.IDs).Info("Successfully batch deleted functions")
 
 	functionBaseController.HandleSuccess(c, "批量删除成功", nil)
 }
@@ -56,7 +56,7 @@ func AdminIndexHandler(c *gin.Context) {
 		AdminLayoutHandler(c)
 		return
 	}
-	c.Redirect(http.StatusFound, "/admin/login")
+	c.Redirect(http.StatusFound, utils.AdminPath("/login"))
 }
 
 // AdminLayoutHandler 后台布局页渲染
@@ -81,6 +81,7 @@ func AdminLayoutHandler(c *gin.Context) {
 	// 准备模板数据
 	data := handlersBaseController.GetDefaultTemplateData()
 	data["CSRFToken"] = token
+	data["AdminPrefix"] = utils.AdminPrefix()
 
 	// 从数据库读取站点标题，如果失败则使用默认值
 	if db, ok := handlersBaseController.GetDB(c); ok {
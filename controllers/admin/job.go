@@ -0,0 +1,171 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/cron"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var jobBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// 页面处理器
+// ============================================================================
+
+// JobsFragmentHandler 定时任务页面片段处理器
+func JobsFragmentHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "jobs.html", gin.H{
+		"Title": "定时任务",
+	})
+}
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// JobListHandler 定时任务列表API处理器
+// 返回所有内置任务的执行计划、启用状态、下次执行时间与最近一次执行记录
+func JobListHandler(c *gin.Context) {
+	jobBaseController.HandleSuccess(c, "ok", services.GetScheduler().Jobs(c.Request.Context()))
+}
+
+// JobUpdateHandler 修改定时任务API处理器
+// cron 为空时恢复默认执行计划
+func JobUpdateHandler(c *gin.Context) {
+	var req struct {
+		Name    string `json:"name"`
+		Enabled bool   `json:"enabled"`
+		Cron    string `json:"cron"`
+	}
+	if !jobBaseController.BindJSON(c, &req) {
+		return
+	}
+	if !jobBaseController.ValidateRequired(c, map[string]interface{}{
+		"任务名称": req.Name,
+	}) {
+		return
+	}
+
+	spec := strings.TrimSpace(req.Cron)
+	if spec != "" {
+		if err := cron.Validate(spec); err != nil {
+			jobBaseController.HandleValidationError(c, "执行计划格式错误: "+err.Error())
+			return
+		}
+	}
+
+	if err := services.GetScheduler().UpdateJob(c.Request.Context(), req.Name, req.Enabled, spec); err != nil {
+		if errors.Is(err, services.ErrJobNotFound) {
+			jobBaseController.HandleError(c, constants.CodeNotFound, err.Error())
+			return
+		}
+		jobBaseController.HandleInternalError(c, "保存任务设置失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"job":     req.Name,
+		"enabled": req.Enabled,
+		"cron":    spec,
+	}).Info("修改定时任务设置")
+	jobBaseController.HandleSuccess(c, "保存成功", nil)
+}
+
+// JobRunHandler 立即执行定时任务API处理器
+// 任务在后台执行，执行结果在执行记录中查看
+func JobRunHandler(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !jobBaseController.BindJSON(c, &req) {
+		return
+	}
+	if !jobBaseController.ValidateRequired(c, map[string]interface{}{
+		"任务名称": req.Name,
+	}) {
+		return
+	}
+
+	if err := services.GetScheduler().RunNow(req.Name); err != nil {
+		jobBaseController.HandleError(c, jobErrorCode(err), err.Error())
+		return
+	}
+
+	logger.FromContext(c).WithField("job", req.Name).Info("手动执行定时任务")
+	jobBaseController.HandleSuccess(c, "任务已开始执行", nil)
+}
+
+// JobRunsListHandler 任务执行记录列表API处理器
+func JobRunsListHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page <= 0 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("page_size"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	db, ok := jobBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.JobRun{})
+	if name := strings.TrimSpace(c.Query("job_name")); name != "" {
+		query = query.Where("job_name = ?", name)
+	}
+	if status := strings.TrimSpace(c.Query("status")); status != "" {
+		if value, err := strconv.Atoi(status); err == nil {
+			query = query.Where("status = ?", value)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		jobBaseController.HandleInternalError(c, "查询执行记录总数失败", err)
+		return
+	}
+
+	var runs []models.JobRun
+	if err := query.Order("started_at DESC").Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&runs).Error; err != nil {
+		jobBaseController.HandleInternalError(c, "查询执行记录失败", err)
+		return
+	}
+
+	jobBaseController.HandleList(c, runs, total, page, limit)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// jobErrorCode 将调度器错误映射为错误码
+func jobErrorCode(err error) constants.ErrorCode {
+	switch {
+	case errors.Is(err, services.ErrJobNotFound):
+		return constants.CodeNotFound
+	case errors.Is(err, services.ErrJobRunning):
+		return constants.CodeConflict
+	case errors.Is(err, services.ErrSchedulerStopped):
+		return constants.CodeServiceUnavailable
+	default:
+		return constants.CodeInvalidRequest
+	}
+}
@@ -0,0 +1,220 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// mailTemplateRequest 保存/预览邮件模板请求参数
+type mailTemplateRequest struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var mailBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// 页面处理器
+// ============================================================================
+
+// MailFragmentHandler 邮件页面片段处理器
+func MailFragmentHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "mail.html", gin.H{
+		"Title":     "邮件",
+		"Templates": models.MailTemplateOptions,
+		"Enabled":   services.MailEnabled(),
+		"Host":      viper.GetString("mail.host"),
+		"From":      viper.GetString("mail.from"),
+	})
+}
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// MailTemplateGetHandler 获取邮件模板API处理器
+func MailTemplateGetHandler(c *gin.Context) {
+	name := c.Query("name")
+	if !models.IsMailTemplate(name) {
+		mailBaseController.HandleValidationError(c, "邮件模板不存在")
+		return
+	}
+	mailBaseController.HandleSuccess(c, "success", services.LoadMailTemplate(name))
+}
+
+// MailTemplateUpdateHandler 保存邮件模板API处理器
+// 保存前使用示例数据渲染，模板语法错误时拒绝保存
+func MailTemplateUpdateHandler(c *gin.Context) {
+	var req mailTemplateRequest
+	if !mailBaseController.BindJSON(c, &req) {
+		return
+	}
+	if !models.IsMailTemplate(req.Name) {
+		mailBaseController.HandleValidationError(c, "邮件模板不存在")
+		return
+	}
+
+	tpl := database.MailTemplate{Subject: req.Subject, HTML: req.HTML, Text: req.Text}
+	if err := services.SaveMailTemplate(c.Request.Context(), req.Name, tpl); err != nil {
+		mailBaseController.HandleValidationError(c, err.Error())
+		return
+	}
+
+	logger.FromContext(c).WithField("template", req.Name).Info("更新邮件模板")
+	mailBaseController.HandleSuccess(c, "保存成功", nil)
+}
+
+// MailTemplateResetHandler 恢复邮件模板默认内容API处理器
+func MailTemplateResetHandler(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if !mailBaseController.BindJSON(c, &req) {
+		return
+	}
+	tpl, ok := database.DefaultMailTemplate(req.Name)
+	if !ok {
+		mailBaseController.HandleValidationError(c, "邮件模板不存在")
+		return
+	}
+	if err := services.SaveMailTemplate(c.Request.Context(), req.Name, tpl); err != nil {
+		mailBaseController.HandleInternalError(c, "恢复默认模板失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithField("template", req.Name).Info("恢复邮件模板默认内容")
+	mailBaseController.HandleSuccess(c, "已恢复默认模板", tpl)
+}
+
+// MailTemplatePreviewHandler 使用示例数据预览邮件模板API处理器
+func MailTemplatePreviewHandler(c *gin.Context) {
+	var req mailTemplateRequest
+	if !mailBaseController.BindJSON(c, &req) {
+		return
+	}
+	if !models.IsMailTemplate(req.Name) {
+		mailBaseController.HandleValidationError(c, "邮件模板不存在")
+		return
+	}
+
+	rendered, err := services.RenderMail(database.MailTemplate{Subject: req.Subject, HTML: req.HTML, Text: req.Text}, services.MailTemplateSampleData(req.Name))
+	if err != nil {
+		mailBaseController.HandleValidationError(c, err.Error())
+		return
+	}
+	mailBaseController.HandleSuccess(c, "success", rendered)
+}
+
+// MailTestHandler 发送测试邮件API处理器
+// 同步发送，返回SMTP服务器的错误信息便于排查配置
+func MailTestHandler(c *gin.Context) {
+	var req struct {
+		To string `json:"to"`
+	}
+	if !mailBaseController.BindJSON(c, &req) {
+		return
+	}
+	if _, err := mail.ParseAddress(strings.TrimSpace(req.To)); err != nil {
+		mailBaseController.HandleValidationError(c, "收件人地址格式错误")
+		return
+	}
+
+	message, err := services.SendTestMail(c.Request.Context(), strings.TrimSpace(req.To))
+	if errors.Is(err, services.ErrMailDisabled) {
+		mailBaseController.HandleValidationError(c, err.Error())
+		return
+	}
+	if err != nil {
+		mailBaseController.HandleInternalError(c, "发送测试邮件失败", err)
+		return
+	}
+	if message.Status != models.MailStatusSent {
+		mailBaseController.HandleErrorData(c, constants.CodeFailed, "发送失败: "+message.Error, message)
+		return
+	}
+	mailBaseController.HandleSuccess(c, "发送成功", message)
+}
+
+// MailQueueListHandler 邮件发送记录列表API处理器
+func MailQueueListHandler(c *gin.Context) {
+	page, pageSize := mailBaseController.GetPaginationParams(c)
+
+	db, ok := mailBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.MailMessage{})
+	if template := strings.TrimSpace(c.Query("template")); template != "" {
+		query = query.Where("template = ?", template)
+	}
+	if status, err := strconv.Atoi(c.Query("status")); err == nil {
+		query = query.Where("status = ?", status)
+	}
+	if to := strings.TrimSpace(c.Query("to")); to != "" {
+		query = query.Where("`to` LIKE ?", "%"+to+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		mailBaseController.HandleInternalError(c, "查询邮件记录总数失败", err)
+		return
+	}
+
+	var messages []models.MailMessage
+	if err := query.Order("id DESC").Offset(mailBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&messages).Error; err != nil {
+		mailBaseController.HandleInternalError(c, "查询邮件记录失败", err)
+		return
+	}
+
+	mailBaseController.HandleList(c, messages, total, page, pageSize)
+}
+
+// MailResendHandler 重新发送失败邮件API处理器
+func MailResendHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !mailBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		mailBaseController.HandleValidationError(c, "邮件ID不能为空")
+		return
+	}
+
+	message, err := services.ResendMail(c.Request.Context(), req.ID)
+	if err != nil {
+		mailBaseController.HandleValidationError(c, err.Error())
+		return
+	}
+	if message.Status != models.MailStatusSent {
+		mailBaseController.HandleErrorData(c, constants.CodeFailed, "发送失败，稍后自动重试: "+message.Error, message)
+		return
+	}
+	mailBaseController.HandleSuccess(c, "发送成功", message)
+}
@@ -0,0 +1,34 @@
+package admin_test
+
+import (
+	"os"
+	"testing"
+
+	"networkDev/database"
+	"networkDev/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// TestMain 使用内存 SQLite 初始化数据库与数据加密密钥
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("database.type", "sqlite")
+	viper.Set("database.sqlite.path", ":memory:")
+	viper.Set("security.encryption_key", "admin-test-key")
+
+	if err := utils.InitCrypto(); err != nil {
+		panic(err)
+	}
+	if _, err := database.Init(); err != nil {
+		panic(err)
+	}
+	if err := database.AutoMigrate(); err != nil {
+		panic(err)
+	}
+	if err := database.SeedDefaultSettings(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
@@ -0,0 +1,328 @@
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// notifyChannelRequest 新增/编辑通知渠道请求参数
+type notifyChannelRequest struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`
+	ChatID      string   `json:"chat_id"`
+	Events      []string `json:"events"`
+	MinSeverity int      `json:"min_severity"`
+	RateLimit   int      `json:"rate_limit"`
+	Status      int      `json:"status"`
+	Remark      string   `json:"remark"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var notifyBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// 页面处理器
+// ============================================================================
+
+// NotifyFragmentHandler 告警通知页面片段处理器
+func NotifyFragmentHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "notify.html", gin.H{
+		"Title":  "告警通知",
+		"Types":  models.NotifyChannelTypes,
+		"Events": models.NotifyEventOptions,
+	})
+}
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// NotifyChannelListHandler 通知渠道列表API处理器
+// 密钥字段不返回明文，只返回是否已设置
+func NotifyChannelListHandler(c *gin.Context) {
+	page, pageSize := notifyBaseController.GetPaginationParams(c)
+
+	db, ok := notifyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.NotifyChannel{})
+	if channelType := strings.TrimSpace(c.Query("type")); channelType != "" {
+		query = query.Where("type = ?", channelType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		notifyBaseController.HandleInternalError(c, "查询通知渠道总数失败", err)
+		return
+	}
+
+	var channels []models.NotifyChannel
+	if err := query.Order("id DESC").Offset(notifyBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&channels).Error; err != nil {
+		notifyBaseController.HandleInternalError(c, "查询通知渠道列表失败", err)
+		return
+	}
+
+	data := make([]gin.H, 0, len(channels))
+	for _, channel := range channels {
+		data = append(data, notifyChannelView(&channel))
+	}
+	notifyBaseController.HandleList(c, data, total, page, pageSize)
+}
+
+// NotifyChannelCreateHandler 新增通知渠道API处理器
+func NotifyChannelCreateHandler(c *gin.Context) {
+	var req notifyChannelRequest
+	if !notifyBaseController.BindJSON(c, &req) {
+		return
+	}
+	if !validateNotifyChannelRequest(c, &req, true) {
+		return
+	}
+
+	channel := models.NotifyChannel{
+		Name:        req.Name,
+		Type:        req.Type,
+		URL:         req.URL,
+		Secret:      req.Secret,
+		ChatID:      req.ChatID,
+		Events:      strings.Join(req.Events, ","),
+		MinSeverity: req.MinSeverity,
+		RateLimit:   req.RateLimit,
+		Status:      req.Status,
+		Remark:      req.Remark,
+	}
+	db, ok := notifyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	if err := db.Create(&channel).Error; err != nil {
+		notifyBaseController.HandleInternalError(c, "创建通知渠道失败", err)
+		return
+	}
+	services.ReloadNotifyChannels(c.Request.Context())
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"channel_id": channel.ID,
+		"type":       channel.Type,
+	}).Info("创建通知渠道")
+	notifyBaseController.HandleSuccess(c, "创建成功", notifyChannelView(&channel))
+}
+
+// NotifyChannelUpdateHandler 编辑通知渠道API处理器
+// 密钥为空时保持不变
+func NotifyChannelUpdateHandler(c *gin.Context) {
+	var req notifyChannelRequest
+	if !notifyBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		notifyBaseController.HandleValidationError(c, "通知渠道ID不能为空")
+		return
+	}
+
+	db, ok := notifyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var channel models.NotifyChannel
+	if err := db.First(&channel, req.ID).Error; err != nil {
+		notifyBaseController.HandleNotFoundError(c, "通知渠道")
+		return
+	}
+	if !validateNotifyChannelRequest(c, &req, channel.Secret == "" || channel.Type != req.Type) {
+		return
+	}
+
+	channel.Name = req.Name
+	channel.Type = req.Type
+	channel.URL = req.URL
+	if req.Secret != "" || req.Type == models.NotifyChannelWeCom {
+		channel.Secret = req.Secret
+	}
+	channel.ChatID = req.ChatID
+	channel.Events = strings.Join(req.Events, ",")
+	channel.MinSeverity = req.MinSeverity
+	channel.RateLimit = req.RateLimit
+	channel.Status = req.Status
+	channel.Remark = req.Remark
+	if err := db.Save(&channel).Error; err != nil {
+		notifyBaseController.HandleInternalError(c, "更新通知渠道失败", err)
+		return
+	}
+	services.ReloadNotifyChannels(c.Request.Context())
+
+	logger.FromContext(c).WithField("channel_id", channel.ID).Info("更新通知渠道")
+	notifyBaseController.HandleSuccess(c, "保存成功", notifyChannelView(&channel))
+}
+
+// NotifyChannelDeleteHandler 删除通知渠道API处理器
+func NotifyChannelDeleteHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !notifyBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		notifyBaseController.HandleValidationError(c, "通知渠道ID不能为空")
+		return
+	}
+
+	db, ok := notifyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	result := db.Delete(&models.NotifyChannel{}, req.ID)
+	if result.Error != nil {
+		notifyBaseController.HandleInternalError(c, "删除通知渠道失败", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		notifyBaseController.HandleNotFoundError(c, "通知渠道")
+		return
+	}
+	services.ReloadNotifyChannels(c.Request.Context())
+
+	logger.FromContext(c).WithField("channel_id", req.ID).Info("删除通知渠道")
+	notifyBaseController.HandleSuccess(c, "删除成功", nil)
+}
+
+// NotifyChannelTestHandler 发送测试通知API处理器
+// 同步发送，返回机器人的错误信息便于排查配置
+func NotifyChannelTestHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !notifyBaseController.BindJSON(c, &req) {
+		return
+	}
+
+	db, ok := notifyBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var channel models.NotifyChannel
+	if err := db.First(&channel, req.ID).Error; err != nil {
+		notifyBaseController.HandleNotFoundError(c, "通知渠道")
+		return
+	}
+
+	if err := services.SendTestNotification(c.Request.Context(), &channel); err != nil {
+		notifyBaseController.HandleError(c, constants.CodeFailed, "发送失败: "+err.Error())
+		return
+	}
+	notifyBaseController.HandleSuccess(c, "发送成功，请在群聊中查看", nil)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// notifyChannelView 通知渠道列表与详情的返回内容，不包含密钥明文
+func notifyChannelView(channel *models.NotifyChannel) gin.H {
+	return gin.H{
+		"id":           channel.ID,
+		"name":         channel.Name,
+		"type":         channel.Type,
+		"url":          channel.URL,
+		"has_secret":   channel.Secret != "",
+		"chat_id":      channel.ChatID,
+		"events":       channel.Events,
+		"min_severity": channel.MinSeverity,
+		"rate_limit":   channel.RateLimit,
+		"status":       channel.Status,
+		"remark":       channel.Remark,
+		"created_at":   channel.CreatedAt,
+		"updated_at":   channel.UpdatedAt,
+	}
+}
+
+// validateNotifyChannelRequest 规范化并验证新增/编辑通知渠道的请求参数
+// requireSecret 为 true 时 Telegram 渠道必须填写 Bot Token（新增或原渠道未设置时）
+func validateNotifyChannelRequest(c *gin.Context, req *notifyChannelRequest, requireSecret bool) bool {
+	req.Name = strings.TrimSpace(req.Name)
+	req.URL = strings.TrimSpace(req.URL)
+	req.Secret = strings.TrimSpace(req.Secret)
+	req.ChatID = strings.TrimSpace(req.ChatID)
+
+	if !notifyBaseController.ValidateRequired(c, map[string]interface{}{
+		"名称": req.Name,
+	}) {
+		return false
+	}
+	if !models.IsNotifyChannelType(req.Type) {
+		notifyBaseController.HandleValidationError(c, "不支持的渠道类型")
+		return false
+	}
+
+	if req.Type == models.NotifyChannelTelegram {
+		if req.ChatID == "" {
+			notifyBaseController.HandleValidationError(c, "Chat ID不能为空")
+			return false
+		}
+		if requireSecret && req.Secret == "" {
+			notifyBaseController.HandleValidationError(c, "Bot Token不能为空")
+			return false
+		}
+	} else if req.URL == "" {
+		notifyBaseController.HandleValidationError(c, "Webhook地址不能为空")
+		return false
+	}
+	if req.Type == models.NotifyChannelWeCom {
+		// 企业微信机器人不支持加签，密钥包含在 Webhook 地址中
+		req.Secret = ""
+	}
+	if req.URL != "" {
+		if parsed, err := url.Parse(req.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			notifyBaseController.HandleValidationError(c, "Webhook地址必须是 http:// 或 https:// 开头的URL")
+			return false
+		}
+	}
+
+	if len(req.Events) == 0 {
+		notifyBaseController.HandleValidationError(c, "请选择订阅的事件")
+		return false
+	}
+	for _, event := range req.Events {
+		if !models.IsNotifyEvent(event) {
+			notifyBaseController.HandleValidationError(c, "不支持的事件: "+event)
+			return false
+		}
+	}
+	if req.MinSeverity < models.NotifySeverityInfo || req.MinSeverity > models.NotifySeverityCritical {
+		notifyBaseController.HandleValidationError(c, "最低通知级别无效")
+		return false
+	}
+	if req.RateLimit < 1 || req.RateLimit > 600 {
+		notifyBaseController.HandleValidationError(c, "发送频率必须在1-600条/分钟之间")
+		return false
+	}
+	if req.Status != models.NotifyChannelEnabled && req.Status != models.NotifyChannelDisabled {
+		notifyBaseController.HandleValidationError(c, "状态值无效")
+		return false
+	}
+	return true
+}
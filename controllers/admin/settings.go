@@ -1,15 +1,16 @@
 package admin
 
 import (
-	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 	"net/http"
+	"networkDev/constants"
 	"networkDev/controllers"
 	"networkDev/models"
 	"networkDev/services"
 	"networkDev/utils"
+	"networkDev/utils/logger"
+	"strings"
 )
 
 // ============================================================================
@@ -35,6 +36,7 @@ func SettingsFragmentHandler(c *gin.Context) {
 
 // SettingsQueryHandler 设置查询API
 // - 返回所有设置项的 name:value 映射
+// - 使用API密钥的请求不返回管理员密码哈希与盐值
 func SettingsQueryHandler(c *gin.Context) {
 	db, ok := settingsBaseController.GetDB(c)
 	if !ok {
@@ -45,8 +47,12 @@ func SettingsQueryHandler(c *gin.Context) {
 		settingsBaseController.HandleInternalError(c, "查询失败", err)
 		return
 	}
+	hideCredentials := currentAPIKey(c) != nil
 	res := map[string]string{}
 	for _, s := range list {
+		if hideCredentials && isCredentialSetting(s.Name) {
+			continue
+		}
 		res[s.Name] = s.Value
 	}
 	settingsBaseController.HandleSuccess(c, "ok", res)
@@ -57,6 +63,7 @@ func SettingsQueryHandler(c *gin.Context) {
 //  1. 直接字段格式: {"site_title": "值", "site_keywords": "值"}
 //  2. 嵌套格式: {"settings": {"site_title": "值", "site_keywords": "值"}}
 //
+// - 使用API密钥的请求不能修改管理员密码哈希与盐值，返回 403
 // - 自动创建不存在的设置项
 // - 更新已存在的设置项
 // - 更新完成后：
@@ -103,6 +110,15 @@ func SettingsUpdateHandler(c *gin.Context) {
 		return
 	}
 
+	if currentAPIKey(c) != nil {
+		for k := range settingsData {
+			if isCredentialSetting(k) {
+				utils.AbortWithError(c, constants.CodeAPIKeyScopeDenied, "API密钥不能修改管理员密码")
+				return
+			}
+		}
+	}
+
 	db, ok := settingsBaseController.GetDB(c)
 	if !ok {
 		return
@@ -118,7 +134,7 @@ func SettingsUpdateHandler(c *gin.Context) {
 			// 不存在则创建
 			s = models.Settings{Name: k, Value: v}
 			if err := db.Create(&s).Error; err != nil {
-				logrus.WithError(err).WithField("setting_name", k).Error("创建设置失败")
+				logger.FromContext(c).WithError(err).WithField("setting_name", k).Error("创建设置失败")
 				settingsBaseController.HandleInternalError(c, fmt.Sprintf("保存设置 %s 失败", k), err)
 				return
 			}
@@ -126,7 +142,7 @@ func SettingsUpdateHandler(c *gin.Context) {
 		} else {
 			// 存在则更新
 			if err := db.Model(&models.Settings{}).Where("id = ?", s.ID).Update("value", v).Error; err != nil {
-				logrus.WithError(err).WithField("setting_name", k).Error("更新设置失败")
+				logger.FromContext(c).WithError(err).WithField("setting_name", k).Error("更新设置失败")
 				settingsBaseController.HandleInternalError(c, fmt.Sprintf("更新设置 %s 失败", k), err)
 				return
 			}
@@ -137,10 +153,19 @@ func SettingsUpdateHandler(c *gin.Context) {
 	}
 
 	// 删除Redis缓存键（如果Redis不可用则静默跳过）
-	_ = utils.RedisDel(context.Background(), keysToDel...)
+	_ = utils.RedisDel(c.Request.Context(), keysToDel...)
 
-	// 刷新内存中的设置缓存，保证后续读取一致
-	services.GetSettingsService().RefreshCache()
+	// 刷新内存中的设置缓存，并通知其他实例重新加载，保证后续读取一致
+	services.GetSettingsService().RefreshCache(c.Request.Context())
 
 	settingsBaseController.HandleSuccess(c, "保存成功", nil)
 }
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// isCredentialSetting 判断设置项是否为管理员登录凭据（admin_password、admin_password_salt）
+func isCredentialSetting(name string) bool {
+	return strings.HasPrefix(name, "admin_password")
+}
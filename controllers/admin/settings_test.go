@@ -0,0 +1,108 @@
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/server"
+	"networkDev/services"
+	"networkDev/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// callWithAPIKey 使用API密钥调用后台接口，返回状态码与响应
+func callWithAPIKey(t *testing.T, router *gin.Engine, key, method, path string, body interface{}) (int, utils.Response) {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, utils.AdminPath(path), bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp utils.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s %s 响应不是JSON: %s", method, path, w.Body.String())
+	}
+	return w.Code, resp
+}
+
+// settingValue 查询设置项的当前值
+func settingValue(t *testing.T, name string) string {
+	t.Helper()
+	db, err := database.GetDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var setting models.Settings
+	if err := db.Where("name = ?", name).First(&setting).Error; err != nil {
+		t.Fatalf("查询设置 %s: %v", name, err)
+	}
+	return setting.Value
+}
+
+// TestSettingsHideCredentialsFromAPIKey system 权限的API密钥不能读取或修改管理员密码哈希与盐值
+func TestSettingsHideCredentialsFromAPIKey(t *testing.T) {
+	router := gin.New()
+	server.RegisterAdminRoutes(router)
+
+	key, err := services.CreateAPIKey(context.Background(), &models.AdminAPIKey{
+		Name:   "settings-test",
+		Scopes: models.APIKeyScopeSystemWrite,
+		Status: 1,
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	password := settingValue(t, "admin_password")
+	salt := settingValue(t, "admin_password_salt")
+
+	code, resp := callWithAPIKey(t, router, key, http.MethodGet, "/api/settings", nil)
+	if code != http.StatusOK {
+		t.Fatalf("查询设置 = %d %+v", code, resp)
+	}
+	settings, _ := resp.Data.(map[string]interface{})
+	if _, ok := settings["site_title"]; !ok {
+		t.Fatalf("查询结果缺少普通设置项: %v", settings)
+	}
+	for _, name := range []string{"admin_password", "admin_password_salt"} {
+		if _, ok := settings[name]; ok {
+			t.Errorf("API密钥不应读取到 %s", name)
+		}
+	}
+
+	for _, body := range []map[string]string{
+		{"admin_password": "forged-hash"},
+		{"site_title": "被修改", "admin_password_salt": "forged-salt"},
+	} {
+		if code, resp := callWithAPIKey(t, router, key, http.MethodPost, "/api/settings/update", body); code != http.StatusForbidden {
+			t.Errorf("修改 %v = %d %+v，期望 403", body, code, resp)
+		}
+	}
+	if settingValue(t, "admin_password") != password || settingValue(t, "admin_password_salt") != salt {
+		t.Fatal("管理员密码哈希与盐值不应被修改")
+	}
+	if settingValue(t, "site_title") == "被修改" {
+		t.Fatal("被拒绝的请求不应修改其他设置项")
+	}
+
+	if code, resp := callWithAPIKey(t, router, key, http.MethodPost, "/api/settings/update", map[string]string{"site_title": "API密钥修改"}); code != http.StatusOK {
+		t.Fatalf("修改普通设置项 = %d %+v", code, resp)
+	}
+	if got := settingValue(t, "site_title"); got != "API密钥修改" {
+		t.Fatalf("site_title = %q", got)
+	}
+}
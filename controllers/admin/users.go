@@ -0,0 +1,185 @@
+package admin
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"networkDev/controllers"
+	"networkDev/models"
+	"networkDev/utils"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 普通用户账号限制
+const (
+	memberUsernameMinLength = 3
+	memberUsernameMaxLength = 64
+	memberPasswordMinLength = 6
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// memberView 普通用户的接口返回字段，不包含密码哈希与盐值
+type memberView struct {
+	ID        uint      `json:"id"`
+	UUID      string    `json:"uuid"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var usersBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// UsersListHandler 普通用户列表API处理器
+func UsersListHandler(c *gin.Context) {
+	page, pageSize := usersBaseController.GetPaginationParams(c)
+
+	db, ok := usersBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.User{})
+	if keyword := strings.TrimSpace(c.Query("keyword")); keyword != "" {
+		query = query.Where("username LIKE ?", "%"+keyword+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		usersBaseController.HandleInternalError(c, "查询用户总数失败", err)
+		return
+	}
+
+	var users []models.User
+	if err := query.Order("id DESC").Offset(usersBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&users).Error; err != nil {
+		usersBaseController.HandleInternalError(c, "查询用户列表失败", err)
+		return
+	}
+
+	views := make([]memberView, 0, len(users))
+	for _, user := range users {
+		views = append(views, newMemberView(user))
+	}
+	usersBaseController.HandleList(c, views, total, page, pageSize)
+}
+
+// UsersCreateHandler 新增普通用户API处理器
+func UsersCreateHandler(c *gin.Context) {
+	var req struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if !usersBaseController.BindJSON(c, &req) {
+		return
+	}
+	req.Username = strings.TrimSpace(req.Username)
+	if len(req.Username) < memberUsernameMinLength || len(req.Username) > memberUsernameMaxLength || strings.IndexFunc(req.Username, unicode.IsSpace) >= 0 {
+		usersBaseController.HandleValidationError(c, "用户名长度必须在3~64个字符之间，且不能包含空白字符")
+		return
+	}
+	if len(req.Password) < memberPasswordMinLength {
+		usersBaseController.HandleValidationError(c, "密码长度不能少于6位")
+		return
+	}
+
+	db, ok := usersBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var count int64
+	if err := db.Model(&models.User{}).Where("username = ?", req.Username).Count(&count).Error; err != nil {
+		usersBaseController.HandleInternalError(c, "查询用户失败", err)
+		return
+	}
+	if count > 0 {
+		usersBaseController.HandleValidationError(c, "用户名已存在")
+		return
+	}
+
+	salt, err := utils.GenerateRandomSalt()
+	if err != nil {
+		usersBaseController.HandleInternalError(c, "生成密码盐失败", err)
+		return
+	}
+	hash, err := utils.HashPasswordWithSalt(req.Password, salt)
+	if err != nil {
+		usersBaseController.HandleInternalError(c, "生成密码哈希失败", err)
+		return
+	}
+	user := models.User{Username: req.Username, Password: hash, PasswordSalt: salt}
+	if err := db.Create(&user).Error; err != nil {
+		usersBaseController.HandleInternalError(c, "创建用户失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"user_id":  user.ID,
+		"username": user.Username,
+	}).Info("创建用户")
+	usersBaseController.HandleSuccess(c, "创建成功", newMemberView(user))
+}
+
+// UsersDeleteHandler 删除普通用户API处理器
+func UsersDeleteHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !usersBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		usersBaseController.HandleValidationError(c, "用户ID不能为空")
+		return
+	}
+
+	db, ok := usersBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	result := db.Delete(&models.User{}, req.ID)
+	if result.Error != nil {
+		usersBaseController.HandleInternalError(c, "删除用户失败", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		usersBaseController.HandleNotFoundError(c, "用户")
+		return
+	}
+
+	logger.FromContext(c).WithField("user_id", req.ID).Info("删除用户")
+	usersBaseController.HandleSuccess(c, "删除成功", nil)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// newMemberView 转换为接口返回字段
+func newMemberView(user models.User) memberView {
+	return memberView{
+		ID:        user.ID,
+		UUID:      user.UUID,
+		Username:  user.Username,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
@@ -4,12 +4,13 @@ import (
 	"net/http"
 	"networkDev/controllers"
 	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
 // ============================================================================
@@ -84,7 +85,7 @@ func VariableListHandler(c *gin.Context) {
 	// 获取总数
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		logrus.WithError(err).Error("Failed to count variables")
+		logger.FromContext(c).WithError(err).Error("Failed to count variables")
 		variableBaseController.HandleInternalError(c, "查询变量总数失败", err)
 		return
 	}
@@ -93,7 +94,7 @@ func VariableListHandler(c *gin.Context) {
 	var variables []models.Variable
 	offset := (page - 1) * limit
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&variables).Error; err != nil {
-		logrus.WithError(err).Error("Failed to fetch variables")
+		logger.FromContext(c).WithError(err).Error("Failed to fetch variables")
 		variableBaseController.HandleInternalError(c, "查询变量列表失败", err)
 		return
 	}
@@ -126,14 +127,7 @@ func VariableListHandler(c *gin.Context) {
 		})
 	}
 
-	response := gin.H{
-		"code":  0,
-		"msg":   "success",
-		"count": total,
-		"data":  responseData,
-	}
-
-	c.JSON(http.StatusOK, response)
+	variableBaseController.HandleList(c, responseData, total, page, limit)
 }
 
 // VariableCreateHandler 新增变量API处理器
@@ -178,7 +172,7 @@ func VariableCreateHandler(c *gin.Context) {
 	if updateAppUUID != "0" {
 		var appCount int64
 		if err := db.Model(&models.App{}).Where("uuid = ?", updateAppUUID).Count(&appCount).Error; err != nil {
-			logrus.WithError(err).Error("Failed to check app existence")
+			logger.FromContext(c).WithError(err).Error("Failed to check app existence")
 			variableBaseController.HandleInternalError(c, "验证应用失败", err)
 			return
 		}
@@ -198,7 +192,7 @@ func VariableCreateHandler(c *gin.Context) {
 	if appUUID != "0" {
 		var appCount int64
 		if err := db.Model(&models.App{}).Where("uuid = ?", appUUID).Count(&appCount).Error; err != nil {
-			logrus.WithError(err).Error("Failed to check app existence")
+			logger.FromContext(c).WithError(err).Error("Failed to check app existence")
 			variableBaseController.HandleInternalError(c, "验证应用失败", err)
 			return
 		}
@@ -217,7 +211,7 @@ func VariableCreateHandler(c *gin.Context) {
 	}
 
 	if err := db.Create(&variable).Error; err != nil {
-		logrus.WithError(err).Error("Failed to create variable")
+		logger.FromContext(c).WithError(err).Error("Failed to create variable")
 		variableBaseController.HandleInternalError(c, "创建变量失败", err)
 		return
 	}
@@ -260,7 +254,7 @@ func VariableUpdateHandler(c *gin.Context) {
 	if updateAppUUID != "0" {
 		var appCount int64
 		if err := db.Model(&models.App{}).Where("uuid = ?", updateAppUUID).Count(&appCount).Error; err != nil {
-			logrus.WithError(err).Error("Failed to check app existence")
+			logger.FromContext(c).WithError(err).Error("Failed to check app existence")
 			variableBaseController.HandleInternalError(c, "验证应用失败", err)
 			return
 		}
@@ -283,11 +277,14 @@ func VariableUpdateHandler(c *gin.Context) {
 	variable.Remark = strings.TrimSpace(req.Remark)
 
 	if err := db.Save(&variable).Error; err != nil {
-		logrus.WithError(err).Error("Failed to update variable")
+		logger.FromContext(c).WithError(err).Error("Failed to update variable")
 		variableBaseController.HandleInternalError(c, "更新变量失败", err)
 		return
 	}
 
+	// 使缓存的变量失效
+	services.InvalidateVariables(c.Request.Context(), variable.Alias)
+
 	variableBaseController.HandleSuccess(c, "更新成功", variable)
 }
 
@@ -311,14 +308,22 @@ func VariableDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	// 删除前记录别名，用于缓存失效
+	var aliases []string
+	if err := db.Model(&models.Variable{}).Where("id = ?", req.ID).Pluck("alias", &aliases).Error; err != nil {
+		logger.FromContext(c).WithError(err).Warn("Failed to load variable alias for cache invalidation")
+	}
+
 	// 删除变量
 	if err := db.Delete(&models.Variable{}, req.ID).Error; err != nil {
-		logrus.WithError(err).Error("Failed to delete variable")
+		logger.FromContext(c).WithError(err).Error("Failed to delete variable")
 		variableBaseController.HandleInternalError(c, "删除变量失败", err)
 		return
 	}
 
-	logrus.WithField("variable_id", req.ID).Info("Successfully deleted variable")
+	services.InvalidateVariables(c.Request.Context(), aliases...)
+
+	logger.FromContext(c).WithField("variable_id", req.ID).Info("Successfully deleted variable")
 
 	variableBaseController.HandleSuccess(c, "删除成功", nil)
 }
@@ -343,14 +348,22 @@ func VariablesBatchDeleteHandler(c *gin.Context) {
 		return
 	}
 
+	// 删除前记录别名，用于缓存失效
+	var aliases []string
+	if err := db.Model(&models.Variable{}).Where("id IN ?", req.IDs).Pluck("alias", &aliases).Error; err != nil {
+		logger.FromContext(c).WithError(err).Warn("Failed to load variable aliases for cache invalidation")
+	}
+
 	// 批量删除变量
 	if err := db.Delete(&models.Variable{}, req.IDs).Error; err != nil {
-		logrus.WithError(err).Error("Failed to batch delete variables")
+		logger.FromContext(c).WithError(err).Error("Failed to batch delete variables")
 		variableBaseController.HandleInternalError(c, "批量删除失败", err)
 		return
 	}
 
-	logrus.WithField("variable_ids", req.IDs).Info("Successfully batch deleted variables")
+	services.InvalidateVariables(c.Request.Context(), aliases...)
+
+	logger.FromContext(c).WithField("variable_ids", req.IDs).Info("Successfully batch deleted variables")
 
 	variableBaseController.HandleSuccess(c, "批量删除成功", nil)
 }
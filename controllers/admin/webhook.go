@@ -0,0 +1,345 @@
+package admin
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// webhookRequest 新增/编辑Webhook请求参数
+type webhookRequest struct {
+	ID      uint     `json:"id"`
+	AppUUID string   `json:"app_uuid"`
+	Name    string   `json:"name"`
+	URL     string   `json:"url"`
+	Secret  string   `json:"secret"`
+	Events  []string `json:"events"`
+	Status  int      `json:"status"`
+	Remark  string   `json:"remark"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// 创建基础控制器实例
+var webhookBaseController = controllers.NewBaseController()
+
+// ============================================================================
+// 页面处理器
+// ============================================================================
+
+// WebhooksFragmentHandler Webhook页面片段处理器
+func WebhooksFragmentHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "webhooks.html", gin.H{
+		"Title":  "Webhook",
+		"Events": models.WebhookEventOptions,
+	})
+}
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// WebhookListHandler Webhook列表API处理器
+func WebhookListHandler(c *gin.Context) {
+	page, pageSize := webhookBaseController.GetPaginationParams(c)
+
+	db, ok := webhookBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.Webhook{})
+	if appUUID := strings.TrimSpace(c.Query("app_uuid")); appUUID != "" {
+		query = query.Where("app_uuid = ?", appUUID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "查询Webhook总数失败", err)
+		return
+	}
+
+	var webhooks []models.Webhook
+	if err := query.Order("id DESC").Offset(webhookBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&webhooks).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "查询Webhook列表失败", err)
+		return
+	}
+
+	// 默认对签名密钥脱敏，仅在管理员会话显式请求 reveal=1 时返回明文
+	reveal := c.Query("reveal") == "1"
+	if reveal && !allowSecretAccess(c) {
+		return
+	}
+	if !reveal {
+		for i := range webhooks {
+			webhooks[i].Redact()
+		}
+	}
+
+	webhookBaseController.HandleList(c, webhooks, total, page, pageSize)
+}
+
+// WebhookCreateHandler 新增Webhook API处理器
+// 签名密钥为空时自动生成
+func WebhookCreateHandler(c *gin.Context) {
+	var req webhookRequest
+	if !webhookBaseController.BindJSON(c, &req) {
+		return
+	}
+	if !validateWebhookRequest(c, &req) {
+		return
+	}
+
+	webhook := models.Webhook{
+		AppUUID: req.AppUUID,
+		Name:    req.Name,
+		URL:     req.URL,
+		Secret:  req.Secret,
+		Events:  strings.Join(req.Events, ","),
+		Status:  req.Status,
+		Remark:  req.Remark,
+	}
+	db, ok := webhookBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	if err := db.Create(&webhook).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "创建Webhook失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithFields(logrus.Fields{
+		"webhook_id": webhook.ID,
+		"url":        webhook.URL,
+	}).Info("创建Webhook")
+	if currentAPIKey(c) != nil {
+		webhook.Redact()
+	}
+	webhookBaseController.HandleSuccess(c, "创建成功", webhook)
+}
+
+// WebhookUpdateHandler 编辑Webhook API处理器
+// 签名密钥为空时保持不变
+func WebhookUpdateHandler(c *gin.Context) {
+	var req webhookRequest
+	if !webhookBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		webhookBaseController.HandleValidationError(c, "Webhook ID不能为空")
+		return
+	}
+	if !validateWebhookRequest(c, &req) {
+		return
+	}
+
+	db, ok := webhookBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var webhook models.Webhook
+	if err := db.First(&webhook, req.ID).Error; err != nil {
+		webhookBaseController.HandleNotFoundError(c, "Webhook")
+		return
+	}
+
+	webhook.AppUUID = req.AppUUID
+	webhook.Name = req.Name
+	webhook.URL = req.URL
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	webhook.Events = strings.Join(req.Events, ",")
+	webhook.Status = req.Status
+	webhook.Remark = req.Remark
+	if err := db.Save(&webhook).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "更新Webhook失败", err)
+		return
+	}
+
+	logger.FromContext(c).WithField("webhook_id", webhook.ID).Info("更新Webhook")
+	if currentAPIKey(c) != nil {
+		webhook.Redact()
+	}
+	webhookBaseController.HandleSuccess(c, "保存成功", webhook)
+}
+
+// WebhookDeleteHandler 删除Webhook API处理器
+// 同时删除该Webhook的投递记录
+func WebhookDeleteHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !webhookBaseController.BindJSON(c, &req) {
+		return
+	}
+	if req.ID == 0 {
+		webhookBaseController.HandleValidationError(c, "Webhook ID不能为空")
+		return
+	}
+
+	db, ok := webhookBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	if err := db.Where("webhook_id = ?", req.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "删除投递记录失败", err)
+		return
+	}
+	result := db.Delete(&models.Webhook{}, req.ID)
+	if result.Error != nil {
+		webhookBaseController.HandleInternalError(c, "删除Webhook失败", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		webhookBaseController.HandleNotFoundError(c, "Webhook")
+		return
+	}
+
+	logger.FromContext(c).WithField("webhook_id", req.ID).Info("删除Webhook")
+	webhookBaseController.HandleSuccess(c, "删除成功", nil)
+}
+
+// WebhookTestHandler 发送测试事件API处理器
+// 同步推送一条 webhook.test 事件，返回接收方的响应状态码与内容
+func WebhookTestHandler(c *gin.Context) {
+	var req struct {
+		ID uint `json:"id"`
+	}
+	if !webhookBaseController.BindJSON(c, &req) {
+		return
+	}
+
+	db, ok := webhookBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+	var webhook models.Webhook
+	if err := db.First(&webhook, req.ID).Error; err != nil {
+		webhookBaseController.HandleNotFoundError(c, "Webhook")
+		return
+	}
+
+	delivery, err := services.SendTestWebhook(c.Request.Context(), &webhook)
+	if err != nil {
+		webhookBaseController.HandleInternalError(c, "发送测试事件失败", err)
+		return
+	}
+	if delivery.Status != models.WebhookDeliverySuccess {
+		webhookBaseController.HandleErrorData(c, constants.CodeFailed, "推送失败: "+delivery.Error, delivery)
+		return
+	}
+	webhookBaseController.HandleSuccess(c, "推送成功，接收方返回 "+strconv.Itoa(delivery.ResponseCode), delivery)
+}
+
+// WebhookDeliveriesListHandler 投递记录列表API处理器
+func WebhookDeliveriesListHandler(c *gin.Context) {
+	page, pageSize := webhookBaseController.GetPaginationParams(c)
+
+	db, ok := webhookBaseController.GetDB(c)
+	if !ok {
+		return
+	}
+
+	query := db.Model(&models.WebhookDelivery{})
+	if webhookID, err := strconv.Atoi(c.Query("webhook_id")); err == nil && webhookID > 0 {
+		query = query.Where("webhook_id = ?", webhookID)
+	}
+	if event := strings.TrimSpace(c.Query("event")); event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if status, err := strconv.Atoi(c.Query("status")); err == nil {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "查询投递记录总数失败", err)
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("id DESC").Offset(webhookBaseController.CalculateOffset(page, pageSize)).Limit(pageSize).Find(&deliveries).Error; err != nil {
+		webhookBaseController.HandleInternalError(c, "查询投递记录失败", err)
+		return
+	}
+
+	webhookBaseController.HandleList(c, deliveries, total, page, pageSize)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// validateWebhookRequest 规范化并验证新增/编辑Webhook的请求参数
+func validateWebhookRequest(c *gin.Context, req *webhookRequest) bool {
+	req.Name = strings.TrimSpace(req.Name)
+	req.URL = strings.TrimSpace(req.URL)
+	req.Secret = strings.TrimSpace(req.Secret)
+	req.AppUUID = strings.TrimSpace(req.AppUUID)
+	if req.AppUUID == "" {
+		req.AppUUID = "0"
+	}
+
+	if !webhookBaseController.ValidateRequired(c, map[string]interface{}{
+		"名称":   req.Name,
+		"推送地址": req.URL,
+	}) {
+		return false
+	}
+	if parsed, err := url.Parse(req.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		webhookBaseController.HandleValidationError(c, "推送地址必须是 http:// 或 https:// 开头的URL")
+		return false
+	}
+	if req.Secret != "" && len(req.Secret) < 16 {
+		webhookBaseController.HandleValidationError(c, "签名密钥长度不能少于16个字符")
+		return false
+	}
+	if len(req.Events) == 0 {
+		webhookBaseController.HandleValidationError(c, "请选择订阅的事件")
+		return false
+	}
+	for _, event := range req.Events {
+		if !models.IsWebhookEvent(event) {
+			webhookBaseController.HandleValidationError(c, "不支持的事件: "+event)
+			return false
+		}
+	}
+	if req.Status != models.WebhookStatusEnabled && req.Status != models.WebhookStatusDisabled {
+		webhookBaseController.HandleValidationError(c, "状态值无效")
+		return false
+	}
+
+	if req.AppUUID != "0" {
+		db, ok := webhookBaseController.GetDB(c)
+		if !ok {
+			return false
+		}
+		var count int64
+		if err := db.Model(&models.App{}).Where("uuid = ?", req.AppUUID).Count(&count).Error; err != nil {
+			webhookBaseController.HandleInternalError(c, "验证应用失败", err)
+			return false
+		}
+		if count == 0 {
+			webhookBaseController.HandleValidationError(c, "指定的应用不存在")
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,12 @@
 package controllers
 
 import (
-	"net/http"
 	"strconv"
 
+	"networkDev/constants"
 	"networkDev/database"
+	"networkDev/utils"
+	"networkDev/utils/logger"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -31,13 +33,14 @@ func NewBaseController() *BaseController {
 // ============================================================================
 
 // GetDB 获取数据库连接，统一错误处理
+// 返回的连接绑定请求上下文，启用链路追踪时数据库操作记录为请求span的子span
 func (bc *BaseController) GetDB(c *gin.Context) (*gorm.DB, bool) {
 	db, err := database.GetDB()
 	if err != nil {
 		bc.HandleDatabaseError(c, err)
 		return nil, false
 	}
-	return db, true
+	return db.WithContext(c.Request.Context()), true
 }
 
 // ============================================================================
@@ -46,38 +49,36 @@ func (bc *BaseController) GetDB(c *gin.Context) (*gorm.DB, bool) {
 
 // HandleDatabaseError 统一处理数据库连接错误
 func (bc *BaseController) HandleDatabaseError(c *gin.Context, err error) {
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"code": 1,
-		"msg":  "数据库连接失败",
-		"data": nil,
-	})
+	logger.FromContext(c).WithError(err).Error("数据库连接失败")
+	utils.WriteErrorResponse(c, constants.CodeDatabaseError, "数据库连接失败", nil)
 }
 
 // HandleValidationError 统一处理验证错误
 func (bc *BaseController) HandleValidationError(c *gin.Context, message string) {
-	c.JSON(http.StatusBadRequest, gin.H{
-		"code": 1,
-		"msg":  message,
-		"data": nil,
-	})
+	utils.WriteErrorResponse(c, constants.CodeValidationError, message, nil)
 }
 
 // HandleNotFoundError 统一处理资源未找到错误
 func (bc *BaseController) HandleNotFoundError(c *gin.Context, resource string) {
-	c.JSON(http.StatusNotFound, gin.H{
-		"code": 1,
-		"msg":  resource + "不存在",
-		"data": nil,
-	})
+	utils.WriteErrorResponse(c, constants.CodeNotFound, resource+"不存在", nil)
 }
 
 // HandleInternalError 统一处理内部服务器错误
+// 错误详情只写入日志（附带请求ID），不返回给客户端
 func (bc *BaseController) HandleInternalError(c *gin.Context, message string, err error) {
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"code": 1,
-		"msg":  message,
-		"data": nil,
-	})
+	logger.FromContext(c).WithError(err).Error(message)
+	utils.WriteErrorResponse(c, constants.CodeInternalError, message, nil)
+}
+
+// HandleError 按错误码返回错误响应，HTTP状态码取自错误码目录
+// message 为空时使用错误码的默认提示
+func (bc *BaseController) HandleError(c *gin.Context, ec constants.ErrorCode, message string) {
+	utils.WriteErrorResponse(c, ec, message, nil)
+}
+
+// HandleErrorData 按错误码返回携带数据的错误响应（如测试发送失败时返回发送记录）
+func (bc *BaseController) HandleErrorData(c *gin.Context, ec constants.ErrorCode, message string, data interface{}) {
+	utils.WriteErrorResponse(c, ec, message, data)
 }
 
 // ============================================================================
@@ -86,20 +87,18 @@ func (bc *BaseController) HandleInternalError(c *gin.Context, message string, er
 
 // HandleSuccess 统一处理成功响应
 func (bc *BaseController) HandleSuccess(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  message,
-		"data": data,
-	})
+	utils.WriteSuccessResponse(c, message, data)
 }
 
 // HandleCreated 统一处理创建成功响应
 func (bc *BaseController) HandleCreated(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusCreated, gin.H{
-		"code": 0,
-		"msg":  message,
-		"data": data,
-	})
+	utils.WriteCreatedResponse(c, message, data)
+}
+
+// HandleList 统一处理分页列表响应
+// 默认返回LayUI表格格式，可通过配置 server.list_format 或请求参数 format 切换为标准格式
+func (bc *BaseController) HandleList(c *gin.Context, items interface{}, total int64, page, pageSize int) {
+	utils.WriteListResponse(c, items, total, page, pageSize)
 }
 
 // ============================================================================
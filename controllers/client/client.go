@@ -0,0 +1,255 @@
+package client
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/controllers"
+	"networkDev/middleware"
+	"networkDev/services"
+	"networkDev/utils"
+	"networkDev/utils/encrypt"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// timestampWindow 请求时间戳与服务器时间的最大偏差（秒）
+const timestampWindow = 300
+
+// nonceKeyPrefix 请求随机串判重标记的名称前缀
+const nonceKeyPrefix = "client_nonce:"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// clientRequest 客户端请求
+// data 为业务参数 JSON 使用接口提交算法加密后的内容，sign 由 encrypt.ClientRequestSign 计算
+type clientRequest struct {
+	AppUUID   string `json:"app_uuid"`
+	APIType   int    `json:"api_type"`
+	Timestamp int64  `json:"timestamp"`
+	Nonce     string `json:"nonce"`
+	Data      string `json:"data"`
+	Sign      string `json:"sign"`
+}
+
+// clientResponse 成功响应的 data
+// payload 为返回数据 JSON 使用接口返回算法加密后的内容，sign 由 encrypt.ClientResponseSign 计算
+type clientResponse struct {
+	Payload   string `json:"payload"`
+	Timestamp int64  `json:"timestamp"`
+	Nonce     string `json:"nonce"`
+	Sign      string `json:"sign"`
+}
+
+// clientPayload 解密后的业务参数，各接口按需读取
+type clientPayload struct {
+	Card        string        `json:"card"`
+	Username    string        `json:"username"`
+	Password    string        `json:"password"`
+	NewPassword string        `json:"new_password"`
+	Email       string        `json:"email"`
+	Code        string        `json:"code"`
+	MachineCode string        `json:"machine_code"`
+	Token       string        `json:"token"`
+	Version     string        `json:"version"`
+	Name        string        `json:"name"`
+	Args        []interface{} `json:"args"`
+	Minutes     int           `json:"minutes"`
+	Reason      string        `json:"reason"`
+}
+
+// clientCall 单次接口调用
+type clientCall struct {
+	caller  services.ClientCaller
+	payload clientPayload
+}
+
+// clientError 携带自定义提示信息的错误码
+type clientError struct {
+	code    constants.ErrorCode
+	message string
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var clientBaseController = controllers.NewBaseController()
+
+// noncePattern 请求随机串格式
+// 签名内容以换行符拼接各字段，随机串限定为字母、数字、下划线与连字符，避免移动字段边界后签名不变
+var noncePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+// ============================================================================
+// API处理器
+// ============================================================================
+
+// ClientHandler 客户端接口统一入口
+// 依次校验时间戳、应用、签名、随机串、应用状态与维护开关、接口状态，解密业务参数后按接口类型分发；
+// 成功时返回使用接口返回算法加密并签名的数据，失败时返回未加密的错误码
+func ClientHandler(c *gin.Context) {
+	var req clientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		clientBaseController.HandleError(c, constants.CodeInvalidRequest, "请求格式错误")
+		return
+	}
+	if req.AppUUID == "" || req.Sign == "" {
+		clientBaseController.HandleValidationError(c, "app_uuid 与 sign 不能为空")
+		return
+	}
+	if !noncePattern.MatchString(req.Nonce) {
+		clientBaseController.HandleValidationError(c, "nonce 必须为 8~64 位字母、数字、下划线或连字符")
+		return
+	}
+	now := time.Now()
+	if delta := now.Unix() - req.Timestamp; delta > timestampWindow || delta < -timestampWindow {
+		clientBaseController.HandleError(c, constants.CodeTimestampInvalid, "")
+		return
+	}
+	handler, ok := clientHandlers[req.APIType]
+	if !ok {
+		clientBaseController.HandleValidationError(c, "不支持的接口类型")
+		return
+	}
+
+	ctx := c.Request.Context()
+	app, err := services.GetApp(ctx, req.AppUUID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			clientBaseController.HandleError(c, constants.CodeAppNotFound, "")
+			return
+		}
+		clientBaseController.HandleInternalError(c, "获取应用失败", err)
+		return
+	}
+	expected := encrypt.ClientRequestSign(app.Secret, req.AppUUID, req.APIType, req.Timestamp, req.Nonce, req.Data)
+	if !hmac.Equal([]byte(expected), []byte(req.Sign)) {
+		clientBaseController.HandleError(c, constants.CodeSignatureInvalid, "")
+		return
+	}
+	// 判重标记保留两个时间戳窗口，窗口内的请求无法重放，窗口外的请求已被时间戳校验拒绝
+	first, err := utils.MarkOnce(ctx, nonceKeyPrefix+app.UUID+":"+req.Nonce, 2*timestampWindow*time.Second)
+	if err != nil {
+		clientBaseController.HandleInternalError(c, "校验请求随机串失败", err)
+		return
+	}
+	if !first {
+		clientBaseController.HandleError(c, constants.CodeRequestReplayed, "")
+		return
+	}
+	if app.Status != 1 {
+		clientBaseController.HandleError(c, constants.CodeAppDisabled, "")
+		return
+	}
+	// 全站维护已由 MaintenanceMiddleware 按 ClientAPIPrefix 处理，这里只检查应用维护开关
+	if middleware.CheckAppMaintenance(c, app.MaintenanceMode, app.MaintenanceMessage) {
+		return
+	}
+
+	config, err := services.GetAPIConfig(ctx, app.UUID, req.APIType)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		clientBaseController.HandleInternalError(c, "获取接口配置失败", err)
+		return
+	}
+	if config == nil || config.API.Status != 1 {
+		clientBaseController.HandleError(c, constants.CodeAPIDisabled, "")
+		return
+	}
+
+	call := &clientCall{caller: services.ClientCaller{App: app, IP: utils.ClientIP(c)}}
+	if req.Data != "" {
+		plaintext, err := config.Submit.Decrypt(req.Data)
+		if err != nil || json.Unmarshal([]byte(plaintext), &call.payload) != nil {
+			clientBaseController.HandleError(c, constants.CodeDecryptFailed, "")
+			return
+		}
+	}
+	call.caller.MachineCode = call.payload.MachineCode
+
+	result, err := handler(c, call)
+	if err != nil {
+		writeClientError(c, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		clientBaseController.HandleInternalError(c, "序列化返回数据失败", err)
+		return
+	}
+	payload, err := config.Return.Encrypt(string(data))
+	if err != nil {
+		clientBaseController.HandleInternalError(c, "加密返回数据失败", err)
+		return
+	}
+	timestamp := time.Now().Unix()
+	clientBaseController.HandleSuccess(c, "", clientResponse{
+		Payload:   payload,
+		Timestamp: timestamp,
+		Nonce:     req.Nonce,
+		Sign:      encrypt.ClientResponseSign(app.Secret, payload, timestamp, req.Nonce),
+	})
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Error 实现 error 接口
+func (e *clientError) Error() string {
+	return e.message
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// validationError 参数校验失败
+func validationError(message string) error {
+	return &clientError{code: constants.CodeValidationError, message: message}
+}
+
+// writeClientError 按错误类型写入错误响应
+// 业务错误码原样返回，其他错误记录日志后返回内部错误
+func writeClientError(c *gin.Context, err error) {
+	var custom *clientError
+	if errors.As(err, &custom) {
+		clientBaseController.HandleError(c, custom.code, custom.message)
+		return
+	}
+	var ec constants.ErrorCode
+	if errors.As(err, &ec) {
+		clientBaseController.HandleError(c, ec, "")
+		return
+	}
+	logger.FromContext(c).WithError(err).Error("Failed to handle client request")
+	clientBaseController.HandleError(c, constants.CodeInternalError, "")
+}
+
+// requireSession 校验业务参数中的会话令牌
+func requireSession(c *gin.Context, call *clientCall) (*services.ClientSession, error) {
+	return services.ClientCheckSession(c.Request.Context(), call.caller, call.payload.Token)
+}
+
+// decodeBase64Text 解码 base64 存储的公告、应用数据，解码失败时返回空字符串
+func decodeBase64Text(c *gin.Context, text string) string {
+	decoded, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		logger.FromContext(c).WithError(err).Error("Failed to decode app text")
+		return ""
+	}
+	return string(decoded)
+}
@@ -0,0 +1,435 @@
+package client
+
+import (
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"networkDev/constants"
+	"networkDev/models"
+	"networkDev/services"
+	"networkDev/utils/logger"
+	"networkDev/utils/metrics"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// clientHandlerFunc 接口类型处理函数，返回值序列化后加密返回
+type clientHandlerFunc func(c *gin.Context, call *clientCall) (interface{}, error)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// clientHandlers 接口类型与处理函数的对应关系
+var clientHandlers = map[int]clientHandlerFunc{
+	models.APITypeGetBulletin:      bulletinHandler,
+	models.APITypeGetUpdateUrl:     updateURLHandler,
+	models.APITypeCheckAppVersion:  checkVersionHandler,
+	models.APITypeGetCardInfo:      cardInfoHandler,
+	models.APITypeSingleLogin:      cardLoginHandler,
+	models.APITypeUserLogin:        accountLoginHandler,
+	models.APITypeUserRegin:        registerHandler,
+	models.APITypeUserRecharge:     rechargeHandler,
+	models.APITypeLogOut:           logoutHandler,
+	models.APITypeGetExpired:       expiredHandler,
+	models.APITypeCheckUserStatus:  heartbeatHandler,
+	models.APITypeGetAppData:       appDataHandler,
+	models.APITypeGetVariable:      variableHandler,
+	models.APITypeExecuteFunction:  functionHandler,
+	models.APITypeUpdatePwd:        changePasswordHandler,
+	models.APITypeMacChangeBind:    rebindMachineHandler,
+	models.APITypeIPChangeBind:     rebindIPHandler,
+	models.APITypeSendResetCode:    sendResetCodeHandler,
+	models.APITypeResetPwd:         resetPasswordHandler,
+	models.APITypeDisableUser:      banHandler,
+	models.APITypeBlackUser:        blacklistHandler,
+	models.APITypeUserDeductedTime: deductTimeHandler,
+}
+
+// ============================================================================
+// 基础信息
+// ============================================================================
+
+// bulletinHandler 获取程序公告
+func bulletinHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	return gin.H{"announcement": decodeBase64Text(c, call.caller.App.Announcement)}, nil
+}
+
+// updateURLHandler 获取更新地址
+func updateURLHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	app := call.caller.App
+	return gin.H{
+		"version":       app.Version,
+		"force_update":  app.ForceUpdate == 1,
+		"download_type": app.DownloadType,
+		"download_url":  app.DownloadURL,
+	}, nil
+}
+
+// checkVersionHandler 检测最新版本
+func checkVersionHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if call.payload.Version == "" {
+		return nil, validationError("version 不能为空")
+	}
+	app := call.caller.App
+	return gin.H{
+		"version":       app.Version,
+		"has_update":    compareVersions(call.payload.Version, app.Version) < 0,
+		"force_update":  app.ForceUpdate == 1,
+		"download_type": app.DownloadType,
+		"download_url":  app.DownloadURL,
+	}, nil
+}
+
+// cardInfoHandler 获取卡密信息
+func cardInfoHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if call.payload.Card == "" {
+		return nil, validationError("card 不能为空")
+	}
+	card, err := services.FindClientCard(c.Request.Context(), call.caller.App.UUID, call.payload.Card)
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"card":        card.CardKey,
+		"status":      card.Status,
+		"status_text": models.CardStatusText(card.Status),
+		"duration":    card.Duration,
+		"used_at":     card.UsedAt,
+		"expires_at":  card.ExpiresAt,
+		"end_at":      card.EndAt,
+	}, nil
+}
+
+// ============================================================================
+// 登录与账号
+// ============================================================================
+
+// cardLoginHandler 卡密登录
+func cardLoginHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if call.payload.Card == "" {
+		return nil, validationError("card 不能为空")
+	}
+	if err := checkLoginRequest(call); err != nil {
+		return nil, err
+	}
+	result, err := services.ClientLoginCard(c.Request.Context(), call.caller, call.payload.Card)
+	return loginResult(c, call, models.APITypeSingleLogin, result, err)
+}
+
+// accountLoginHandler 账号登录
+func accountLoginHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if call.payload.Username == "" || call.payload.Password == "" {
+		return nil, validationError("username 与 password 不能为空")
+	}
+	if err := checkLoginRequest(call); err != nil {
+		return nil, err
+	}
+	result, err := services.ClientLoginAccount(c.Request.Context(), call.caller, call.payload.Username, call.payload.Password)
+	return loginResult(c, call, models.APITypeUserLogin, result, err)
+}
+
+// registerHandler 注册账号
+func registerHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	payload := call.payload
+	if err := validateUsername(payload.Username); err != nil {
+		return nil, err
+	}
+	if err := validatePassword(payload.Password); err != nil {
+		return nil, err
+	}
+	email := strings.TrimSpace(payload.Email)
+	if email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, validationError("邮箱格式错误")
+		}
+	}
+	account, err := services.ClientRegister(c.Request.Context(), call.caller, services.ClientRegisterInput{
+		Username: payload.Username,
+		Password: payload.Password,
+		Email:    email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"username": account.Username,
+		"end_at":   account.EndAt,
+		"trial":    account.TrialGrantedAt != nil,
+	}, nil
+}
+
+// rechargeHandler 使用卡密为账号充值
+func rechargeHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if call.payload.Username == "" || call.payload.Card == "" {
+		return nil, validationError("username 与 card 不能为空")
+	}
+	account, card, err := services.ClientRecharge(c.Request.Context(), call.caller, call.payload.Username, call.payload.Card)
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"username": account.Username,
+		"duration": card.Duration,
+		"end_at":   account.EndAt,
+	}, nil
+}
+
+// logoutHandler 退出登录
+func logoutHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	return nil, services.ClientLogout(c.Request.Context(), call.caller, call.payload.Token)
+}
+
+// changePasswordHandler 修改账号密码
+func changePasswordHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	payload := call.payload
+	if payload.Username == "" || payload.Password == "" {
+		return nil, validationError("username 与 password 不能为空")
+	}
+	if err := validatePassword(payload.NewPassword); err != nil {
+		return nil, err
+	}
+	return nil, services.ClientChangePassword(c.Request.Context(), call.caller, payload.Username, payload.Password, payload.NewPassword)
+}
+
+// sendResetCodeHandler 向账号的邮箱发送找回密码验证码
+func sendResetCodeHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	payload := call.payload
+	if payload.Username == "" || payload.Email == "" {
+		return nil, validationError("username 与 email 不能为空")
+	}
+	err := services.ClientSendResetCode(c.Request.Context(), call.caller, payload.Username, payload.Email)
+	switch {
+	case errors.Is(err, services.ErrMailDisabled):
+		return nil, &clientError{code: constants.CodeServiceUnavailable, message: "未启用邮件发送，暂不能找回密码"}
+	case errors.Is(err, services.ErrMailTooFrequent):
+		return nil, &clientError{code: constants.CodeTooManyRequests, message: err.Error()}
+	}
+	return nil, err
+}
+
+// resetPasswordHandler 使用邮箱验证码重置账号密码
+func resetPasswordHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	payload := call.payload
+	if payload.Username == "" || payload.Email == "" || payload.Code == "" {
+		return nil, validationError("username、email 与 code 不能为空")
+	}
+	if err := validatePassword(payload.NewPassword); err != nil {
+		return nil, err
+	}
+	return nil, services.ClientResetPassword(c.Request.Context(), call.caller, payload.Username, payload.Email, payload.Code, payload.NewPassword)
+}
+
+// ============================================================================
+// 状态查询
+// ============================================================================
+
+// expiredHandler 获取到期时间
+func expiredHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	session, err := requireSession(c, call)
+	if err != nil {
+		return nil, err
+	}
+	return services.ClientSubjectStatus(c.Request.Context(), call.caller, session)
+}
+
+// heartbeatHandler 检测账号状态（心跳）
+func heartbeatHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	return services.ClientHeartbeat(c.Request.Context(), call.caller, call.payload.Token)
+}
+
+// appDataHandler 获取程序数据
+func appDataHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if _, err := requireSession(c, call); err != nil {
+		return nil, err
+	}
+	return gin.H{"app_data": decodeBase64Text(c, call.caller.App.AppData)}, nil
+}
+
+// variableHandler 获取变量数据，只能读取本应用与全局变量
+func variableHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if _, err := requireSession(c, call); err != nil {
+		return nil, err
+	}
+	if call.payload.Name == "" {
+		return nil, validationError("name 不能为空")
+	}
+	variable, err := services.GetVariable(c.Request.Context(), call.payload.Name)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !visibleToApp(variable.AppUUID, call.caller.App)) {
+		return nil, constants.CodeVariableNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{"name": variable.Alias, "data": variable.Data}, nil
+}
+
+// functionHandler 执行远程函数，只能执行本应用与全局函数
+func functionHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if _, err := requireSession(c, call); err != nil {
+		return nil, err
+	}
+	if call.payload.Name == "" {
+		return nil, validationError("name 不能为空")
+	}
+	function, err := services.GetFunction(c.Request.Context(), call.payload.Name)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !visibleToApp(function.AppUUID, call.caller.App)) {
+		return nil, constants.CodeFunctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := services.RunFunction(c.Request.Context(), function.Code, call.payload.Args)
+	if err != nil {
+		// 脚本错误可能包含函数源码片段，只记录日志
+		logger.FromContext(c).WithError(err).WithField("function", function.Alias).Warn("Client function failed")
+		return nil, constants.CodeFunctionFailed
+	}
+	return gin.H{"name": function.Alias, "result": result}, nil
+}
+
+// ============================================================================
+// 换绑与风控
+// ============================================================================
+
+// rebindMachineHandler 机器码转绑，新机器码取请求中的 machine_code
+func rebindMachineHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	if call.payload.MachineCode == "" {
+		return nil, validationError("machine_code 不能为空")
+	}
+	return rebind(c, call, true)
+}
+
+// rebindIPHandler IP转绑，新IP取请求来源IP
+func rebindIPHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	return rebind(c, call, false)
+}
+
+// banHandler 封停当前登录的卡密或账号
+func banHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	session, err := requireSession(c, call)
+	if err != nil {
+		return nil, err
+	}
+	return nil, services.ClientBan(c.Request.Context(), call.caller, session, call.payload.Reason)
+}
+
+// blacklistHandler 将当前会话的机器码与IP加入黑名单
+func blacklistHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	session, err := requireSession(c, call)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := services.ClientBlacklist(c.Request.Context(), call.caller, session, call.payload.Reason); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// deductTimeHandler 扣除当前登录的卡密或账号的剩余时长
+func deductTimeHandler(c *gin.Context, call *clientCall) (interface{}, error) {
+	session, err := requireSession(c, call)
+	if err != nil {
+		return nil, err
+	}
+	if call.payload.Minutes <= 0 {
+		return nil, validationError("minutes 必须大于0")
+	}
+	return services.ClientDeductTime(c.Request.Context(), call.caller, session, call.payload.Minutes)
+}
+
+// ============================================================================
+// 辅助函数
+// ============================================================================
+
+// checkLoginRequest 登录前检查机器码与强制更新
+func checkLoginRequest(call *clientCall) error {
+	app := call.caller.App
+	if app.MachineVerify == 1 && call.payload.MachineCode == "" {
+		return validationError("machine_code 不能为空")
+	}
+	if app.ForceUpdate == 1 && compareVersions(call.payload.Version, app.Version) < 0 {
+		return constants.CodeVersionOutdated
+	}
+	return nil
+}
+
+// loginResult 记录登录指标后返回登录结果
+// 登录被拒绝（业务错误码）时计入登录失败次数，用于失败次数激增告警；内部错误不计入
+func loginResult(c *gin.Context, call *clientCall, apiType int, result *services.ClientLoginResult, err error) (interface{}, error) {
+	if err != nil {
+		var ec constants.ErrorCode
+		if errors.As(err, &ec) {
+			services.RecordClientLoginFailure(c.Request.Context(), call.caller.App.UUID)
+		}
+		return nil, err
+	}
+	metrics.RecordClientLogin(call.caller.App.UUID, apiType)
+	return result, nil
+}
+
+// rebind 换绑机器码或IP，卡密登录传入 card，账号登录传入 username 与 password
+func rebind(c *gin.Context, call *clientCall, machine bool) (interface{}, error) {
+	payload := call.payload
+	if payload.Card == "" && (payload.Username == "" || payload.Password == "") {
+		return nil, validationError("card 或 username 与 password 不能为空")
+	}
+	return services.ClientRebind(c.Request.Context(), call.caller, services.ClientIdentity{
+		CardKey:  payload.Card,
+		Username: payload.Username,
+		Password: payload.Password,
+	}, machine)
+}
+
+// validateUsername 校验用户名长度
+func validateUsername(username string) error {
+	if n := utf8.RuneCountInString(username); n < services.ClientUsernameMinLength || n > services.ClientUsernameMaxLength || strings.TrimSpace(username) != username {
+		return validationError("用户名长度必须在 " + strconv.Itoa(services.ClientUsernameMinLength) + "~" + strconv.Itoa(services.ClientUsernameMaxLength) + " 之间且不能以空白开头或结尾")
+	}
+	return nil
+}
+
+// validatePassword 校验密码长度
+func validatePassword(password string) error {
+	if n := utf8.RuneCountInString(password); n < services.ClientPasswordMinLength || n > services.ClientPasswordMaxLength {
+		return validationError("密码长度必须在 " + strconv.Itoa(services.ClientPasswordMinLength) + "~" + strconv.Itoa(services.ClientPasswordMaxLength) + " 之间")
+	}
+	return nil
+}
+
+// visibleToApp 变量、函数是否对应用可见（本应用或全局）
+func visibleToApp(appUUID string, app *models.App) bool {
+	return appUUID == "0" || appUUID == app.UUID
+}
+
+// compareVersions 按点分隔的数字比较版本号，a<b 返回 -1，相等返回 0，a>b 返回 1
+// 非数字部分按 0 处理，空版本号小于任何非空版本号
+func compareVersions(a, b string) int {
+	partsA := strings.Split(strings.TrimPrefix(strings.TrimSpace(a), "v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(strings.TrimSpace(b), "v"), ".")
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -42,6 +42,7 @@ func RootHandler(c *gin.Context) {
 		})
 		return
 	}
+	db = db.WithContext(c.Request.Context())
 
 	// 获取默认模板数据
 	data := homeBaseController.GetDefaultTemplateData()
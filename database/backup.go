@@ -0,0 +1,814 @@
+package database
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// BackupFormat 备份归档格式标识
+	BackupFormat = "networkdev-backup"
+	// BackupFormatVersion 备份归档格式版本，格式不兼容调整时递增
+	BackupFormatVersion = 1
+	// BackupFilePrefix 备份文件名前缀
+	BackupFilePrefix = "networkdev-backup-"
+	// BackupFileExt 备份文件扩展名
+	BackupFileExt = ".tar.gz"
+
+	// backupManifestName 归档中的清单文件名，始终为第一个条目
+	backupManifestName = "manifest.json"
+	// backupDataDir 归档中的数据目录，每张表一个 JSON Lines 文件
+	backupDataDir = "data"
+	// backupInsertBatchSize 恢复时的批量插入大小
+	backupInsertBatchSize = 200
+)
+
+// 恢复模式
+const (
+	// RestoreModeReplace 清空归档中包含的表后导入全部数据
+	RestoreModeReplace = "replace"
+	// RestoreModeMerge 仅导入不冲突的数据，冲突行跳过
+	RestoreModeMerge = "merge"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// BackupManifest 备份归档清单
+type BackupManifest struct {
+	Format        string             `json:"format"`
+	FormatVersion int                `json:"format_version"`
+	CreatedAt     time.Time          `json:"created_at"`
+	SourceDialect string             `json:"source_dialect"`
+	SchemaVersion int                `json:"schema_version"`
+	Tables        []BackupTableEntry `json:"tables"`
+}
+
+// BackupTableEntry 归档中的单表信息
+type BackupTableEntry struct {
+	Name    string   `json:"name"`
+	Rows    int      `json:"rows"`
+	Columns []string `json:"columns"`
+}
+
+// RestoreOptions 恢复选项
+type RestoreOptions struct {
+	Mode   string // 恢复模式：replace 或 merge
+	DryRun bool   // 仅统计，不写入数据库
+}
+
+// RestoreReport 恢复结果报告
+type RestoreReport struct {
+	Manifest BackupManifest       `json:"manifest"`
+	Mode     string               `json:"mode"`
+	DryRun   bool                 `json:"dry_run"`
+	Tables   []RestoreTableReport `json:"tables"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+// RestoreTableReport 单表恢复统计
+// - Existing：恢复前目标库中的行数
+// - Conflicts：归档中主键或唯一键与目标库已有数据冲突的行数
+// - Imported：实际（或预计）导入的行数
+type RestoreTableReport struct {
+	Name      string `json:"name"`
+	Rows      int    `json:"rows"`
+	Existing  int64  `json:"existing"`
+	Conflicts int    `json:"conflicts"`
+	Imported  int    `json:"imported"`
+}
+
+// backupTable 参与备份的表
+type backupTable struct {
+	model  interface{}
+	schema *schema.Schema
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// backupModels 参与备份与恢复的模型（按恢复顺序排列）
+// 新增数据表时需要在此登记，否则不会包含在备份中
+// 以下表不参与备份：
+// - mail_messages：邮件正文包含明文的找回密码验证码，不应写入可移植的归档，恢复后重新发送历史邮件也没有意义
+// - schema_migrations：由迁移记录维护，恢复时按归档中的 schema_version 校验
+var backupModels = []interface{}{
+	&models.User{},
+	&models.Settings{},
+	&models.App{},
+	&models.API{},
+	&models.Variable{},
+	&models.Function{},
+	&models.Card{},
+	&models.Account{},
+	&models.Blacklist{},
+	&models.Job{},
+	&models.JobRun{},
+	&models.Webhook{},
+	&models.WebhookDelivery{},
+	&models.NotifyChannel{},
+	&models.AdminAPIKey{},
+	&models.AuditLog{},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// CreateBackup 将所有数据表写入压缩归档
+// - 归档为 tar.gz，包含 manifest.json 与 data/<表名>.jsonl
+// - 所有表在同一个只读事务中读取（MySQL/PostgreSQL 使用 REPEATABLE READ），保证数据一致
+// - 数据逐行读取并先写入临时文件，内存占用与数据量无关
+// - 数据按列名保存为 JSON，时间统一为 RFC3339 格式，与数据库方言无关
+// - 加密字段保持密文原样导出，恢复到其他实例时需要配置相同的加密密钥
+func CreateBackup(w io.Writer) (*BackupManifest, error) {
+	db, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := parseBackupTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &BackupManifest{
+		Format:        BackupFormat,
+		FormatVersion: BackupFormatVersion,
+		CreatedAt:     time.Now(),
+		SourceDialect: db.Dialector.Name(),
+	}
+	if manifest.SchemaVersion, err = currentSchemaVersion(db); err != nil {
+		return nil, err
+	}
+
+	// 清单需要位于归档开头且包含各表行数，因此先将各表数据依次写入临时文件并记录长度
+	spool, err := os.CreateTemp("", ".backup-*.jsonl")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}()
+
+	sizes := make([]int64, 0, len(tables))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		buffered := bufio.NewWriter(spool)
+		var offset int64
+		for _, table := range tables {
+			count, err := dumpTable(tx, table, buffered)
+			if err != nil {
+				return fmt.Errorf("导出表 %s 失败: %w", table.schema.Table, err)
+			}
+			if err := buffered.Flush(); err != nil {
+				return fmt.Errorf("写入临时文件失败: %w", err)
+			}
+			end, err := spool.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			sizes = append(sizes, end-offset)
+			offset = end
+			manifest.Tables = append(manifest.Tables, BackupTableEntry{
+				Name:    table.schema.Table,
+				Rows:    count,
+				Columns: table.schema.DBNames,
+			})
+		}
+		return nil
+	}, backupTxOptions(db))
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeTarEntry(tw, backupManifestName, bytes.NewReader(manifestBytes), int64(len(manifestBytes)), manifest.CreatedAt); err != nil {
+		return nil, err
+	}
+	var offset int64
+	for i, entry := range manifest.Tables {
+		name := path.Join(backupDataDir, entry.Name+".jsonl")
+		if err := writeTarEntry(tw, name, io.NewSectionReader(spool, offset, sizes[i]), sizes[i], manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+		offset += sizes[i]
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// CreateBackupFile 在指定目录下生成备份文件
+// 先写入临时文件再重命名，避免中断时留下不完整的归档
+func CreateBackupFile(dir string) (string, *BackupManifest, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("创建备份目录失败: %w", err)
+	}
+
+	name := BackupFilePrefix + time.Now().Format("20060102-150405") + BackupFileExt
+	target := filepath.Join(dir, name)
+	tmp, err := os.CreateTemp(dir, ".backup-*.tmp")
+	if err != nil {
+		return "", nil, fmt.Errorf("创建备份文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	manifest, err := CreateBackup(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", nil, fmt.Errorf("保存备份文件失败: %w", err)
+	}
+	return target, manifest, nil
+}
+
+// PruneBackups 按文件名时间顺序清理旧备份，仅保留最近 keep 个
+// keep <= 0 时不清理；返回被删除的文件
+func PruneBackups(dir string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, BackupFilePrefix) && strings.HasSuffix(name, BackupFileExt) {
+			files = append(files, name)
+		}
+	}
+	if len(files) <= keep {
+		return nil, nil
+	}
+
+	sort.Strings(files)
+	var removed []string
+	for _, name := range files[:len(files)-keep] {
+		file := filepath.Join(dir, name)
+		if err := os.Remove(file); err != nil {
+			return removed, err
+		}
+		removed = append(removed, file)
+	}
+	return removed, nil
+}
+
+// RestoreBackup 从归档恢复数据
+// - 在单个事务中执行，任一失败则全部回滚
+// - replace 模式清空归档中包含的表后导入；merge 模式跳过主键或唯一键冲突的行
+// - DryRun 时只统计行数与冲突，不写入数据库，目标库可以尚未执行迁移
+// - 归档来自更新版本（表结构版本高于当前程序）时拒绝恢复
+func RestoreBackup(r io.Reader, opts RestoreOptions) (*RestoreReport, error) {
+	if opts.Mode == "" {
+		opts.Mode = RestoreModeReplace
+	}
+	if opts.Mode != RestoreModeReplace && opts.Mode != RestoreModeMerge {
+		return nil, fmt.Errorf("不支持的恢复模式: %s", opts.Mode)
+	}
+
+	db, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := parseBackupTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, rows, err := readBackupArchive(r)
+	if err != nil {
+		return nil, err
+	}
+	if latest := latestMigrationVersion(); manifest.SchemaVersion > latest {
+		return nil, fmt.Errorf("备份的表结构版本 %d 高于当前程序支持的版本 %d，请先升级程序", manifest.SchemaVersion, latest)
+	}
+
+	report := &RestoreReport{Manifest: *manifest, Mode: opts.Mode, DryRun: opts.DryRun}
+	known := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		known[table.schema.Table] = true
+	}
+	for _, entry := range manifest.Tables {
+		if !known[entry.Name] {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("表 %s 不在当前程序的备份范围内，已跳过", entry.Name))
+		}
+	}
+
+	errDryRun := errors.New("dry run")
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			tableRows, ok := rows[table.schema.Table]
+			if !ok {
+				continue
+			}
+			tableReport, warnings, err := restoreTable(tx, table, tableRows, opts)
+			if err != nil {
+				return fmt.Errorf("恢复表 %s 失败: %w", table.schema.Table, err)
+			}
+			report.Tables = append(report.Tables, *tableReport)
+			report.Warnings = append(report.Warnings, warnings...)
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	if !opts.DryRun {
+		logrus.WithFields(logrus.Fields{
+			"mode":   opts.Mode,
+			"tables": len(report.Tables),
+		}).Info("备份数据恢复完成")
+	}
+	return report, nil
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// parseBackupTables 解析参与备份的模型结构
+func parseBackupTables(db *gorm.DB) ([]backupTable, error) {
+	tables := make([]backupTable, 0, len(backupModels))
+	for _, model := range backupModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("解析模型 %T 失败: %w", model, err)
+		}
+		tables = append(tables, backupTable{model: model, schema: stmt.Schema})
+	}
+	return tables, nil
+}
+
+// currentSchemaVersion 获取数据库当前已执行的最高迁移版本
+func currentSchemaVersion(db *gorm.DB) (int, error) {
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return 0, err
+	}
+	var version int
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("查询表结构版本失败: %w", err)
+	}
+	return version, nil
+}
+
+// backupTxOptions 备份使用的只读事务选项
+// MySQL 与 PostgreSQL 指定 REPEATABLE READ，所有表读取同一快照；SQLite 的读事务本身即读取同一快照
+func backupTxOptions(db *gorm.DB) *sql.TxOptions {
+	opts := &sql.TxOptions{ReadOnly: true}
+	switch db.Dialector.Name() {
+	case "mysql", "postgres":
+		opts.Isolation = sql.LevelRepeatableRead
+	}
+	return opts
+}
+
+// latestMigrationVersion 获取当前程序注册的最高迁移版本
+func latestMigrationVersion() int {
+	sorted := sortedMigrations()
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[len(sorted)-1].Version
+}
+
+// dumpTable 按主键顺序导出单表数据为 JSON Lines
+// 直接读取原始列值，不经过模型序列化器，加密字段保持密文
+func dumpTable(tx *gorm.DB, table backupTable, w io.Writer) (int, error) {
+	query := tx.Table(table.schema.Table).Select(table.schema.DBNames)
+	if pk := table.schema.PrioritizedPrimaryField; pk != nil {
+		query = query.Order(pk.DBName)
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	encoder := json.NewEncoder(w)
+	count := 0
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return count, err
+		}
+
+		record := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			value, err := exportValue(table.schema.LookUpField(column), values[i])
+			if err != nil {
+				return count, fmt.Errorf("列 %s: %w", column, err)
+			}
+			record[column] = value
+		}
+		if err := encoder.Encode(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// exportValue 将数据库驱动返回的值转换为与方言无关的 JSON 值
+func exportValue(field *schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if b, ok := value.([]byte); ok {
+		value = string(b)
+	}
+	if field == nil {
+		return value, nil
+	}
+
+	switch field.DataType {
+	case schema.Time:
+		t, err := toTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return t.UTC().Format(time.RFC3339Nano), nil
+	case schema.Bool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case schema.Int, schema.Uint:
+		if s, ok := value.(string); ok {
+			return strconv.ParseInt(s, 10, 64)
+		}
+	case schema.Float:
+		if s, ok := value.(string); ok {
+			return strconv.ParseFloat(s, 64)
+		}
+	}
+	return value, nil
+}
+
+// importValue 将归档中的 JSON 值转换为写入数据库的值
+func importValue(field *schema.Field, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	number, isNumber := value.(json.Number)
+
+	switch field.DataType {
+	case schema.Time:
+		return toTime(value)
+	case schema.Int:
+		if isNumber {
+			return number.Int64()
+		}
+	case schema.Uint:
+		if isNumber {
+			return strconv.ParseUint(number.String(), 10, 64)
+		}
+	case schema.Float:
+		if isNumber {
+			return number.Float64()
+		}
+	case schema.String:
+		if isNumber {
+			return number.String(), nil
+		}
+	}
+	return value, nil
+}
+
+// toTime 解析不同驱动返回的时间值
+func toTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		layouts := []string{
+			time.RFC3339Nano,
+			"2006-01-02 15:04:05.999999999-07:00",
+			"2006-01-02 15:04:05.999999999",
+			"2006-01-02T15:04:05.999999999",
+			"2006-01-02",
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("无法解析时间值: %s", v)
+	default:
+		return time.Time{}, fmt.Errorf("不支持的时间类型: %T", value)
+	}
+}
+
+// writeTarEntry 写入单个归档条目，size 为 r 中数据的长度
+func writeTarEntry(tw *tar.Writer, name string, r io.Reader, size int64, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    size,
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// readBackupArchive 读取并校验归档，返回清单与各表数据
+func readBackupArchive(r io.Reader) (*BackupManifest, map[string][]map[string]interface{}, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取备份归档失败: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	var manifest *BackupManifest
+	rows := make(map[string][]map[string]interface{})
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("读取备份归档失败: %w", err)
+		}
+
+		if header.Name == backupManifestName {
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, nil, fmt.Errorf("解析备份清单失败: %w", err)
+			}
+			if manifest.Format != BackupFormat {
+				return nil, nil, errors.New("不是有效的备份归档")
+			}
+			if manifest.FormatVersion > BackupFormatVersion {
+				return nil, nil, fmt.Errorf("备份格式版本 %d 高于当前程序支持的版本 %d", manifest.FormatVersion, BackupFormatVersion)
+			}
+			continue
+		}
+
+		if manifest == nil {
+			return nil, nil, errors.New("备份归档缺少清单文件")
+		}
+		table := strings.TrimSuffix(path.Base(header.Name), ".jsonl")
+		if path.Dir(header.Name) != backupDataDir || table == path.Base(header.Name) {
+			continue
+		}
+
+		tableRows, err := readJSONLines(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("解析表 %s 数据失败: %w", table, err)
+		}
+		rows[table] = tableRows
+	}
+
+	if manifest == nil {
+		return nil, nil, errors.New("备份归档缺少清单文件")
+	}
+	for _, entry := range manifest.Tables {
+		if len(rows[entry.Name]) != entry.Rows {
+			return nil, nil, fmt.Errorf("表 %s 数据不完整：清单记录 %d 行，实际 %d 行", entry.Name, entry.Rows, len(rows[entry.Name]))
+		}
+	}
+	return manifest, rows, nil
+}
+
+// readJSONLines 读取 JSON Lines 数据，数字保留为 json.Number 避免精度丢失
+func readJSONLines(r io.Reader) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// restoreTable 恢复单表数据
+func restoreTable(tx *gorm.DB, table backupTable, archived []map[string]interface{}, opts RestoreOptions) (*RestoreTableReport, []string, error) {
+	name := table.schema.Table
+	report := &RestoreTableReport{Name: name, Rows: len(archived)}
+	var warnings []string
+
+	// 试运行不执行迁移，目标库中尚不存在的表按空表统计
+	exists := tx.Migrator().HasTable(name)
+	if !exists {
+		if !opts.DryRun {
+			return nil, nil, fmt.Errorf("表 %s 不存在，请先执行数据库迁移", name)
+		}
+		warnings = append(warnings, fmt.Sprintf("表 %s 在目标库中不存在，实际恢复时会先执行迁移创建", name))
+	} else if err := tx.Table(name).Count(&report.Existing).Error; err != nil {
+		return nil, nil, err
+	}
+
+	// 转换列值，忽略当前表结构中不存在的列
+	records := make([]map[string]interface{}, 0, len(archived))
+	dropped := make(map[string]bool)
+	for _, row := range archived {
+		record := make(map[string]interface{}, len(row))
+		for column, value := range row {
+			field := table.schema.LookUpField(column)
+			if field == nil || field.DBName == "" {
+				dropped[column] = true
+				continue
+			}
+			converted, err := importValue(field, value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("列 %s: %w", column, err)
+			}
+			record[field.DBName] = converted
+		}
+		records = append(records, record)
+	}
+	for column := range dropped {
+		warnings = append(warnings, fmt.Sprintf("表 %s 的列 %s 在当前表结构中不存在，已忽略", name, column))
+	}
+
+	// 检查加密字段能否使用当前密钥解密
+	if columns, ok := sensitiveColumns[name]; ok {
+		undecryptable := 0
+		for _, record := range records {
+			for _, column := range columns {
+				if value, ok := record[column].(string); ok {
+					if _, err := models.DecryptField(value); err != nil {
+						undecryptable++
+					}
+				}
+			}
+		}
+		if undecryptable > 0 {
+			warnings = append(warnings, fmt.Sprintf("表 %s 有 %d 个加密字段无法使用当前密钥解密，请确认已配置备份来源实例的加密密钥", name, undecryptable))
+		}
+	}
+
+	// 统计与目标库已有数据冲突的行
+	conflicting := make(map[int]bool)
+	if exists {
+		var err error
+		if conflicting, err = findConflicts(tx, table, records); err != nil {
+			return nil, nil, err
+		}
+	}
+	report.Conflicts = len(conflicting)
+
+	toInsert := records
+	if opts.Mode == RestoreModeMerge {
+		toInsert = make([]map[string]interface{}, 0, len(records))
+		for i, record := range records {
+			if !conflicting[i] {
+				toInsert = append(toInsert, record)
+			}
+		}
+	}
+	report.Imported = len(toInsert)
+
+	if opts.DryRun {
+		return report, warnings, nil
+	}
+
+	if opts.Mode == RestoreModeReplace {
+		if err := tx.Exec("DELETE FROM " + tx.Statement.Quote(name)).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(toInsert) > 0 {
+		if err := tx.Table(name).CreateInBatches(toInsert, backupInsertBatchSize).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := resetSequence(tx, table); err != nil {
+		return nil, nil, err
+	}
+	return report, warnings, nil
+}
+
+// findConflicts 查找主键或单列唯一索引与目标库已有数据冲突的行（返回行下标集合）
+func findConflicts(tx *gorm.DB, table backupTable, records []map[string]interface{}) (map[int]bool, error) {
+	var keyColumns []string
+	for _, field := range table.schema.PrimaryFields {
+		keyColumns = append(keyColumns, field.DBName)
+	}
+	for _, index := range table.schema.ParseIndexes() {
+		if index.Class == "UNIQUE" && len(index.Fields) == 1 {
+			keyColumns = append(keyColumns, index.Fields[0].DBName)
+		}
+	}
+
+	conflicting := make(map[int]bool)
+	for _, column := range keyColumns {
+		var existing []interface{}
+		if err := tx.Table(table.schema.Table).Pluck(column, &existing).Error; err != nil {
+			return nil, err
+		}
+		if len(existing) == 0 {
+			continue
+		}
+		values := make(map[string]bool, len(existing))
+		for _, value := range existing {
+			values[conflictKey(value)] = true
+		}
+		for i, record := range records {
+			if value, ok := record[column]; ok && values[conflictKey(value)] {
+				conflicting[i] = true
+			}
+		}
+	}
+	return conflicting, nil
+}
+
+// conflictKey 将列值转换为可比较的字符串
+func conflictKey(value interface{}) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// resetSequence 导入指定主键后同步 PostgreSQL 自增序列
+// SQLite 与 MySQL 会根据已有最大值自动调整自增计数
+func resetSequence(tx *gorm.DB, table backupTable) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	pk := table.schema.PrioritizedPrimaryField
+	if pk == nil || !pk.AutoIncrement {
+		return nil
+	}
+	sql := fmt.Sprintf(
+		"SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+		table.schema.Table, pk.DBName, tx.Statement.Quote(pk.DBName), tx.Statement.Quote(table.schema.Table),
+	)
+	return tx.Exec(sql).Error
+}
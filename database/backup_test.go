@@ -0,0 +1,378 @@
+package database_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+
+	"gorm.io/gorm"
+)
+
+// backupFixture 备份测试数据
+type backupFixture struct {
+	app       models.App
+	card      models.Card
+	variable  models.Variable
+	account   models.Account
+	blacklist models.Blacklist
+}
+
+// seedBackupData 写入应用、卡密、变量、账号与黑名单，名称带时间戳以支持重复运行
+func seedBackupData(t *testing.T) *backupFixture {
+	t.Helper()
+	db := getDB(t)
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	usedAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	endAt := time.Now().Add(72 * time.Hour).Truncate(time.Millisecond)
+
+	f := &backupFixture{
+		app: models.App{UUID: "app-" + suffix[len(suffix)-12:], Name: "备份测试应用", Secret: "secret-" + suffix, Status: 1},
+	}
+	if err := db.Create(&f.app).Error; err != nil {
+		t.Fatalf("创建应用: %v", err)
+	}
+	f.card = models.Card{UUID: "card-" + suffix[len(suffix)-12:], AppUUID: f.app.UUID, CardKey: "KEY" + suffix, Duration: 60, Remark: "原始备注", UsedAt: &usedAt}
+	if err := db.Create(&f.card).Error; err != nil {
+		t.Fatalf("创建卡密: %v", err)
+	}
+	f.variable = models.Variable{
+		UUID:    "var-" + suffix[len(suffix)-12:],
+		Number:  suffix[len(suffix)-13:],
+		AppUUID: f.app.UUID,
+		Alias:   "var" + suffix,
+		Data:    "变量数据",
+	}
+	if err := db.Create(&f.variable).Error; err != nil {
+		t.Fatalf("创建变量: %v", err)
+	}
+	f.account = models.Account{
+		UUID:         "acct-" + suffix[len(suffix)-12:],
+		AppUUID:      f.app.UUID,
+		Username:     "user" + suffix,
+		Password:     "hash",
+		PasswordSalt: "salt",
+		Email:        "user" + suffix + "@example.com",
+		Status:       1,
+	}
+	f.account.EndAt = &endAt
+	f.account.MachineCode = "MACHINE-" + suffix
+	if err := db.Create(&f.account).Error; err != nil {
+		t.Fatalf("创建账号: %v", err)
+	}
+	f.blacklist = models.Blacklist{AppUUID: f.app.UUID, Type: 1, Value: "BLOCKED-" + suffix, Reason: "测试"}
+	if err := db.Create(&f.blacklist).Error; err != nil {
+		t.Fatalf("创建黑名单: %v", err)
+	}
+	return f
+}
+
+// getDB 获取数据库连接
+func getDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.GetDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createBackup 生成内存中的备份归档
+func createBackup(t *testing.T) ([]byte, *database.BackupManifest) {
+	t.Helper()
+	var buf bytes.Buffer
+	manifest, err := database.CreateBackup(&buf)
+	if err != nil {
+		t.Fatalf("CreateBackup: %v", err)
+	}
+	return buf.Bytes(), manifest
+}
+
+// tableReport 查找单表恢复统计
+func tableReport(t *testing.T, report *database.RestoreReport, name string) database.RestoreTableReport {
+	t.Helper()
+	for _, table := range report.Tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	t.Fatalf("恢复报告缺少表 %s", name)
+	return database.RestoreTableReport{}
+}
+
+// TestBackupRoundTrip 备份后修改数据，以 replace 模式恢复得到与备份时一致的数据
+func TestBackupRoundTrip(t *testing.T) {
+	db := getDB(t)
+	f := seedBackupData(t)
+	archive, manifest := createBackup(t)
+
+	if manifest.Format != database.BackupFormat || manifest.SchemaVersion == 0 {
+		t.Fatalf("备份清单 = %+v", manifest)
+	}
+	tables := make(map[string]int)
+	for _, entry := range manifest.Tables {
+		tables[entry.Name] = entry.Rows
+	}
+	for _, name := range []string{"apps", "cards", "variables", "accounts", "blacklists", "settings"} {
+		if tables[name] == 0 {
+			t.Errorf("备份中表 %s 没有数据", name)
+		}
+	}
+	if _, ok := tables["mail_messages"]; ok {
+		t.Error("mail_messages 不应包含在备份中")
+	}
+
+	// 备份之后的修改
+	db.Model(&models.App{}).Where("id = ?", f.app.ID).Update("name", "已修改")
+	db.Delete(&models.Variable{}, f.variable.ID)
+	db.Delete(&models.Account{}, f.account.ID)
+	db.Delete(&models.Blacklist{}, f.blacklist.ID)
+	extra := models.Card{UUID: "extra-" + f.card.UUID, AppUUID: f.app.UUID, CardKey: "EXTRA" + f.card.CardKey, Duration: 1}
+	if err := db.Create(&extra).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := database.RestoreBackup(bytes.NewReader(archive), database.RestoreOptions{})
+	if err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if report.Mode != database.RestoreModeReplace || len(report.Warnings) != 0 {
+		t.Fatalf("恢复报告 = %+v", report)
+	}
+	if r := tableReport(t, report, "variables"); r.Rows != tables["variables"] || r.Imported != r.Rows {
+		t.Fatalf("variables 恢复统计 = %+v", r)
+	}
+
+	var app models.App
+	if err := db.First(&app, f.app.ID).Error; err != nil {
+		t.Fatalf("查询应用: %v", err)
+	}
+	if app.Name != f.app.Name || app.Secret != f.app.Secret || app.UUID != f.app.UUID {
+		t.Fatalf("恢复后应用 = %s/%s/%s", app.UUID, app.Name, app.Secret)
+	}
+
+	var variable models.Variable
+	if err := db.First(&variable, f.variable.ID).Error; err != nil {
+		t.Fatalf("变量未恢复: %v", err)
+	}
+	if variable.Alias != f.variable.Alias || variable.Data != f.variable.Data || variable.Number != f.variable.Number {
+		t.Fatalf("恢复后变量 = %+v", variable)
+	}
+	if !variable.CreatedAt.Equal(f.variable.CreatedAt) {
+		t.Fatalf("恢复后创建时间 = %v，期望 %v", variable.CreatedAt, f.variable.CreatedAt)
+	}
+
+	var account models.Account
+	if err := db.First(&account, f.account.ID).Error; err != nil {
+		t.Fatalf("账号未恢复: %v", err)
+	}
+	if account.Username != f.account.Username || account.Email != f.account.Email || account.MachineCode != f.account.MachineCode {
+		t.Fatalf("恢复后账号 = %+v", account)
+	}
+	if account.EndAt == nil || !account.EndAt.Equal(*f.account.EndAt) {
+		t.Fatalf("恢复后到期时间 = %v，期望 %v", account.EndAt, f.account.EndAt)
+	}
+	var blacklist models.Blacklist
+	if err := db.First(&blacklist, f.blacklist.ID).Error; err != nil || blacklist.Value != f.blacklist.Value {
+		t.Fatalf("黑名单未恢复: %v", err)
+	}
+
+	var card models.Card
+	if err := db.First(&card, f.card.ID).Error; err != nil {
+		t.Fatalf("查询卡密: %v", err)
+	}
+	if card.UsedAt == nil || !card.UsedAt.Equal(*f.card.UsedAt) {
+		t.Fatalf("恢复后使用时间 = %v，期望 %v", card.UsedAt, f.card.UsedAt)
+	}
+	var count int64
+	db.Model(&models.Card{}).Where("id = ?", extra.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("replace 模式应清除备份之后新增的卡密")
+	}
+
+	// 恢复后自增主键继续可用
+	next := models.Card{UUID: "next-" + f.card.UUID, AppUUID: f.app.UUID, CardKey: "NEXT" + f.card.CardKey, Duration: 1}
+	if err := db.Create(&next).Error; err != nil {
+		t.Fatalf("恢复后写入卡密: %v", err)
+	}
+}
+
+// TestBackupSpoolRemoved 备份导出使用的临时文件在完成后删除
+func TestBackupSpoolRemoved(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	seedBackupData(t)
+	createBackup(t)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("临时目录应为空，实际有 %d 个文件", len(entries))
+	}
+}
+
+// TestBackupRestoreMerge merge 模式只导入不冲突的行，保留目标库已有数据
+func TestBackupRestoreMerge(t *testing.T) {
+	db := getDB(t)
+	f := seedBackupData(t)
+	archive, _ := createBackup(t)
+
+	db.Delete(&models.Variable{}, f.variable.ID)
+	db.Model(&models.Card{}).Where("id = ?", f.card.ID).Update("remark", "恢复前修改")
+	extra := models.Card{UUID: "merge-" + f.card.UUID, AppUUID: f.app.UUID, CardKey: "MERGE" + f.card.CardKey, Duration: 1}
+	if err := db.Create(&extra).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := database.RestoreBackup(bytes.NewReader(archive), database.RestoreOptions{Mode: database.RestoreModeMerge})
+	if err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if r := tableReport(t, report, "variables"); r.Imported != 1 || r.Conflicts != r.Rows-1 {
+		t.Fatalf("variables 恢复统计 = %+v", r)
+	}
+	if r := tableReport(t, report, "cards"); r.Imported != 0 || r.Conflicts != r.Rows {
+		t.Fatalf("cards 恢复统计 = %+v", r)
+	}
+
+	if err := db.First(&models.Variable{}, f.variable.ID).Error; err != nil {
+		t.Fatalf("变量未恢复: %v", err)
+	}
+	var card models.Card
+	db.First(&card, f.card.ID)
+	if card.Remark != "恢复前修改" {
+		t.Fatalf("冲突的卡密不应被覆盖，备注 = %q", card.Remark)
+	}
+	if err := db.First(&models.Card{}, extra.ID).Error; err != nil {
+		t.Fatalf("merge 模式不应删除已有卡密: %v", err)
+	}
+}
+
+// TestBackupRestoreDryRun 试运行只统计，不修改数据
+func TestBackupRestoreDryRun(t *testing.T) {
+	db := getDB(t)
+	f := seedBackupData(t)
+	archive, _ := createBackup(t)
+	db.Delete(&models.Variable{}, f.variable.ID)
+
+	report, err := database.RestoreBackup(bytes.NewReader(archive), database.RestoreOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if !report.DryRun {
+		t.Fatal("报告应标记为试运行")
+	}
+	if r := tableReport(t, report, "variables"); r.Imported != r.Rows || r.Existing != int64(r.Rows-1) {
+		t.Fatalf("variables 试运行统计 = %+v", r)
+	}
+	var count int64
+	db.Model(&models.Variable{}).Where("id = ?", f.variable.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("试运行不应写入数据")
+	}
+}
+
+// TestBackupRestoreDryRunMissingTable 试运行不要求目标库已执行迁移，缺少的表按空表统计
+func TestBackupRestoreDryRunMissingTable(t *testing.T) {
+	db := getDB(t)
+	archive, _ := createBackup(t)
+	if err := db.Migrator().DropTable(&models.NotifyChannel{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := db.Migrator().CreateTable(&models.NotifyChannel{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	report, err := database.RestoreBackup(bytes.NewReader(archive), database.RestoreOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if r := tableReport(t, report, "notify_channels"); r.Existing != 0 || r.Conflicts != 0 {
+		t.Fatalf("notify_channels 试运行统计 = %+v", r)
+	}
+	if len(report.Warnings) == 0 {
+		t.Error("缺少的表应给出警告")
+	}
+	if db.Migrator().HasTable(&models.NotifyChannel{}) {
+		t.Error("试运行不应创建数据表")
+	}
+
+	if _, err := database.RestoreBackup(bytes.NewReader(archive), database.RestoreOptions{}); err == nil {
+		t.Error("实际恢复时缺少的表应返回错误")
+	}
+}
+
+// TestBackupRestoreRejected 无效的归档、恢复模式与更新版本的表结构被拒绝
+func TestBackupRestoreRejected(t *testing.T) {
+	archive, manifest := createBackup(t)
+
+	if _, err := database.RestoreBackup(bytes.NewReader(archive), database.RestoreOptions{Mode: "append"}); err == nil {
+		t.Error("不支持的恢复模式应返回错误")
+	}
+	if _, err := database.RestoreBackup(bytes.NewReader([]byte("not a backup")), database.RestoreOptions{}); err == nil {
+		t.Error("无效的归档应返回错误")
+	}
+
+	newer := *manifest
+	newer.Tables = nil
+	newer.SchemaVersion = manifest.SchemaVersion + 1
+	if _, err := database.RestoreBackup(bytes.NewReader(writeArchive(t, newer, nil)), database.RestoreOptions{}); err == nil {
+		t.Error("表结构版本更高的归档应被拒绝")
+	}
+
+	other := *manifest
+	other.Format = "other"
+	other.Tables = nil
+	if _, err := database.RestoreBackup(bytes.NewReader(writeArchive(t, other, nil)), database.RestoreOptions{}); err == nil {
+		t.Error("格式标识不符的归档应被拒绝")
+	}
+
+	incomplete := *manifest
+	incomplete.Tables = []database.BackupTableEntry{{Name: "apps", Rows: 2}}
+	data := map[string]string{"data/apps.jsonl": `{"id": 1}` + "\n"}
+	if _, err := database.RestoreBackup(bytes.NewReader(writeArchive(t, incomplete, data)), database.RestoreOptions{}); err == nil {
+		t.Error("行数与清单不符的归档应被拒绝")
+	}
+}
+
+// writeArchive 按备份格式手工构造归档
+func writeArchive(t *testing.T, manifest database.BackupManifest, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("manifest.json", manifestBytes)
+	for name, data := range files {
+		write(name, []byte(data))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -3,12 +3,15 @@ package database
 import (
 	"fmt"
 	"networkDev/utils"
+	"networkDev/utils/tracing"
+	"strings"
 	"sync"
 
 	"github.com/glebarez/sqlite"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -29,27 +32,26 @@ var (
 
 // Init 初始化数据库连接（根据配置自动选择驱动）
 // - 默认使用 SQLite（github.com/glebarez/sqlite）
-// - 生产环境支持 MySQL（gorm.io/driver/mysql）
+// - 生产环境支持 MySQL（gorm.io/driver/mysql）与 PostgreSQL（gorm.io/driver/postgres）
 func Init() (*gorm.DB, error) {
 	var initErr error
 	once.Do(func() {
 		dbType := viper.GetString("database.type")
+		var configPrefix string
 		switch dbType {
 		case "mysql":
 			initErr = initMySQL()
+			configPrefix = "database.mysql"
+		case "postgres", "postgresql":
+			initErr = initPostgres()
+			configPrefix = "database.postgres"
 		default:
 			initErr = initSQLite()
+			configPrefix = "database.sqlite"
 		}
 
 		// 如果数据库初始化成功，配置连接池和启动健康检查
 		if initErr == nil && dbInstance != nil {
-			// 加载数据库配置
-			var configPrefix string
-			if dbType == "mysql" {
-				configPrefix = "database.mysql"
-			} else {
-				configPrefix = "database.sqlite"
-			}
 
 			dbConfig := utils.LoadDatabaseConfig(configPrefix)
 
@@ -66,6 +68,13 @@ func Init() (*gorm.DB, error) {
 
 			// 启动健康检查
 			utils.StartHealthCheck(dbInstance, dbConfig)
+
+			// 启用链路追踪时为数据库操作创建span
+			if tracing.Enabled() {
+				if err := tracing.RegisterGormCallbacks(dbInstance); err != nil {
+					logrus.WithError(err).Warn("注册数据库链路追踪回调失败")
+				}
+			}
 		}
 	})
 	return dbInstance, initErr
@@ -134,3 +143,56 @@ func initMySQL() error {
 	logrus.WithField("host", host).WithField("database", dbname).Info("MySQL 连接已建立")
 	return nil
 }
+
+// initPostgres 初始化 PostgreSQL 数据库
+// 从 viper 读取 database.postgres.* 配置构建 DSN
+// 非 public schema 会在连接后自动创建，并通过 search_path 作为默认 schema
+func initPostgres() error {
+	host := viper.GetString("database.postgres.host")
+	port := viper.GetInt("database.postgres.port")
+	user := viper.GetString("database.postgres.username")
+	pass := viper.GetString("database.postgres.password")
+	dbname := viper.GetString("database.postgres.database")
+	sslmode := viper.GetString("database.postgres.sslmode")
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+	schema := viper.GetString("database.postgres.schema")
+	if schema == "" {
+		schema = "public"
+	}
+
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s search_path=%s",
+		host, port, quotePostgresDSNValue(user), quotePostgresDSNValue(pass), quotePostgresDSNValue(dbname), sslmode, schema)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		logrus.WithError(err).Error("PostgreSQL 初始化失败")
+		return err
+	}
+
+	if schema != "public" {
+		if err := db.Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS "%s"`, schema)).Error; err != nil {
+			logrus.WithError(err).WithField("schema", schema).Error("创建 PostgreSQL schema 失败")
+			return err
+		}
+	}
+
+	dbInstance = db
+	logrus.WithFields(logrus.Fields{
+		"host":     host,
+		"database": dbname,
+		"schema":   schema,
+	}).Info("PostgreSQL 连接已建立")
+	return nil
+}
+
+// quotePostgresDSNValue 按 libpq 连接字符串规则转义取值
+// 取值包含空格、引号或反斜杠时需要使用单引号包裹
+func quotePostgresDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
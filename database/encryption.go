@@ -0,0 +1,152 @@
+package database
+
+import (
+	"fmt"
+	"networkDev/models"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// sensitiveColumns 需要加密存储的敏感字段（表名 -> 字段列表）
+// 由后续迁移创建的表在其创建之前会被跳过
+var sensitiveColumns = map[string][]string{
+	"apps":            {"secret"},
+	"apis":            {"submit_private_key", "return_private_key"},
+	"webhooks":        {"secret"},
+	"notify_channels": {"secret"},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// RotateEncryptionKey 使用当前活动密钥重新加密所有敏感字段
+// - 用于密钥轮换：配置新的活动密钥后执行，旧密钥确认不再使用后即可从配置中移除
+// - dryRun=true 时只返回需要重新加密的数量
+func RotateEncryptionKey(dryRun bool) (int, error) {
+	db, err := GetDB()
+	if err != nil {
+		return 0, err
+	}
+	return reencryptSensitiveColumns(db, true, dryRun)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// encryptSensitiveColumns 将历史明文的敏感字段加密
+// - 仅处理没有 enc: 前缀的非空值，已加密的数据会被跳过，可重复执行
+func encryptSensitiveColumns(db *gorm.DB) error {
+	total, err := reencryptSensitiveColumns(db, false, false)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		logrus.WithField("count", total).Info("已加密历史明文敏感字段")
+	}
+	return nil
+}
+
+// reencryptSensitiveColumns 使用活动密钥重新加密敏感字段
+// - rotate=false 时只处理历史明文；rotate=true 时同时处理使用旧密钥加密的值
+// - dryRun=true 时只统计需要处理的行数，不写入数据库
+// - 直接按表操作而不经过模型，避免序列化器再次处理
+// - 在事务中执行，任一失败则全部回滚
+func reencryptSensitiveColumns(db *gorm.DB, rotate, dryRun bool) (int, error) {
+	total := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for table, columns := range sensitiveColumns {
+			if !tx.Migrator().HasTable(table) {
+				continue
+			}
+			for _, column := range columns {
+				query := tx.Table(table).
+					Select(fmt.Sprintf("id, %s AS value", column)).
+					Where(fmt.Sprintf("%s <> ''", column))
+				if !rotate {
+					query = query.Where(fmt.Sprintf("%s NOT LIKE ?", column), models.EncryptedPrefix+"%")
+				}
+
+				var rows []struct {
+					ID    uint
+					Value string
+				}
+				if err := query.Scan(&rows).Error; err != nil {
+					return fmt.Errorf("查询 %s.%s 失败: %w", table, column, err)
+				}
+
+				for _, row := range rows {
+					needed, err := models.NeedsReencrypt(row.Value)
+					if err != nil {
+						return err
+					}
+					if !needed {
+						continue
+					}
+					total++
+					if dryRun {
+						continue
+					}
+
+					plain, err := models.DecryptField(row.Value)
+					if err != nil {
+						return fmt.Errorf("解密 %s.%s (id=%d) 失败: %w", table, column, row.ID, err)
+					}
+					enc, err := models.EncryptField(plain)
+					if err != nil {
+						return fmt.Errorf("加密 %s.%s (id=%d) 失败: %w", table, column, row.ID, err)
+					}
+					if err := tx.Table(table).Where("id = ?", row.ID).UpdateColumn(column, enc).Error; err != nil {
+						return fmt.Errorf("更新 %s.%s (id=%d) 失败: %w", table, column, row.ID, err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// decryptSensitiveColumns 将已加密的敏感字段还原为明文
+// 仅用于回滚加密迁移，正常运行时不应调用
+func decryptSensitiveColumns(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for table, columns := range sensitiveColumns {
+			if !tx.Migrator().HasTable(table) {
+				continue
+			}
+			for _, column := range columns {
+				var rows []struct {
+					ID    uint
+					Value string
+				}
+				if err := tx.Table(table).
+					Select(fmt.Sprintf("id, %s AS value", column)).
+					Where(fmt.Sprintf("%s LIKE ?", column), models.EncryptedPrefix+"%").
+					Scan(&rows).Error; err != nil {
+					return fmt.Errorf("查询 %s.%s 失败: %w", table, column, err)
+				}
+
+				for _, row := range rows {
+					plain, err := models.DecryptField(row.Value)
+					if err != nil {
+						return fmt.Errorf("解密 %s.%s (id=%d) 失败: %w", table, column, row.ID, err)
+					}
+					if err := tx.Table(table).Where("id = ?", row.ID).UpdateColumn(column, plain).Error; err != nil {
+						return fmt.Errorf("更新 %s.%s (id=%d) 失败: %w", table, column, row.ID, err)
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
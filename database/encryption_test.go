@@ -0,0 +1,45 @@
+package database_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+)
+
+// TestEncryptedPrefixPlaintext 以 enc: 开头的明文同样加密存储，读取时还原为原值
+func TestEncryptedPrefixPlaintext(t *testing.T) {
+	db := getDB(t)
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	secret := models.EncryptedPrefix + "looks-encrypted-" + suffix
+	app := models.App{UUID: "enc-" + suffix[len(suffix)-12:], Name: "加密前缀应用", Secret: secret, Status: 1}
+	if err := db.Create(&app).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var stored string
+	if err := db.Table("apps").Where("id = ?", app.ID).Pluck("secret", &stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored == secret {
+		t.Fatal("以 enc: 开头的明文不应原样写入数据库")
+	}
+	if plain, err := models.DecryptField(stored); err != nil || plain != secret {
+		t.Fatalf("DecryptField = %q, %v", plain, err)
+	}
+
+	var loaded models.App
+	if err := db.First(&loaded, app.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Secret != secret {
+		t.Fatalf("Secret = %q，期望 %q", loaded.Secret, secret)
+	}
+
+	// 已加密的值不会被加密迁移或密钥轮换再次处理
+	if count, err := database.RotateEncryptionKey(true); err != nil || count != 0 {
+		t.Fatalf("RotateEncryptionKey = %d, %v", count, err)
+	}
+}
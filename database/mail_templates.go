@@ -0,0 +1,105 @@
+package database
+
+import "networkDev/models"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// MailTemplate 邮件模板内容
+// 保存在 settings 表的 mail_template_<名称>_subject/_html/_text 三项中，
+// 主题与纯文本正文使用 text/template 渲染，HTML 正文使用 html/template 渲染
+type MailTemplate struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// defaultMailTemplates 内置邮件模板的默认内容
+var defaultMailTemplates = map[string]MailTemplate{
+	models.MailTemplateTest: {
+		Subject: "[{{.SiteTitle}}] 测试邮件",
+		HTML: `<p>这是一封来自 <b>{{.SiteTitle}}</b> 的测试邮件。</p>
+<p>收到这封邮件说明 SMTP 配置正确。</p>
+<p style="color:#999">发送时间：{{.Now}}</p>`,
+		Text: `这是一封来自 {{.SiteTitle}} 的测试邮件。
+收到这封邮件说明 SMTP 配置正确。
+
+发送时间：{{.Now}}`,
+	},
+	models.MailTemplateAdminAlert: {
+		Subject: "[{{.SiteTitle}}] 【{{.Severity}}】{{.Title}}",
+		HTML: `<h3>【{{.Severity}}】{{.Title}}</h3>
+<ul>{{range .Lines}}<li>{{.}}</li>{{end}}</ul>
+<p style="color:#999">时间：{{.Now}}</p>`,
+		Text: `【{{.Severity}}】{{.Title}}
+{{range .Lines}}
+- {{.}}{{end}}
+
+时间：{{.Now}}`,
+	},
+	models.MailTemplatePasswordReset: {
+		Subject: "[{{.SiteTitle}}] 找回密码验证码",
+		HTML: `<p>{{.Username}}，您好：</p>
+<p>您正在找回密码，验证码为：</p>
+<p style="font-size:24px;font-weight:bold;letter-spacing:4px">{{.Code}}</p>
+<p>验证码 {{.Minutes}} 分钟内有效。如果不是您本人操作，请忽略这封邮件。</p>`,
+		Text: `{{.Username}}，您好：
+
+您正在找回密码，验证码为：{{.Code}}
+验证码 {{.Minutes}} 分钟内有效。如果不是您本人操作，请忽略这封邮件。`,
+	},
+	models.MailTemplateExpiryReminder: {
+		Subject: "[{{.SiteTitle}}] {{.Total}} 张卡密将在 {{.Days}} 天内到期",
+		HTML: `<p>以下未使用的卡密将在 {{.Days}} 天内到期：</p>
+<table border="1" cellpadding="6" cellspacing="0" style="border-collapse:collapse">
+<tr><th>应用</th><th>数量</th><th>最早到期时间</th></tr>
+{{range .Apps}}<tr><td>{{.Name}}</td><td>{{.Count}}</td><td>{{.Earliest}}</td></tr>
+{{end}}</table>
+<p style="color:#999">时间：{{.Now}}</p>`,
+		Text: `以下未使用的卡密将在 {{.Days}} 天内到期：
+{{range .Apps}}
+- {{.Name}}：{{.Count}} 张，最早 {{.Earliest}} 到期{{end}}
+
+时间：{{.Now}}`,
+	},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// DefaultMailTemplate 获取内置邮件模板的默认内容
+func DefaultMailTemplate(name string) (MailTemplate, bool) {
+	template, ok := defaultMailTemplates[name]
+	return template, ok
+}
+
+// MailTemplateSettingNames 获取邮件模板在 settings 表中的三个设置项名称
+func MailTemplateSettingNames(name string) (subject, html, text string) {
+	prefix := "mail_template_" + name
+	return prefix + "_subject", prefix + "_html", prefix + "_text"
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// defaultMailTemplateSettings 内置邮件模板对应的默认设置项
+func defaultMailTemplateSettings() []models.Settings {
+	var settings []models.Settings
+	for _, option := range models.MailTemplateOptions {
+		template := defaultMailTemplates[option.Name]
+		subject, html, text := MailTemplateSettingNames(option.Name)
+		settings = append(settings,
+			models.Settings{Name: subject, Value: template.Subject, Description: "邮件模板「" + option.Title + "」的主题"},
+			models.Settings{Name: html, Value: template.HTML, Description: "邮件模板「" + option.Title + "」的HTML正文"},
+			models.Settings{Name: text, Value: template.Text, Description: "邮件模板「" + option.Title + "」的纯文本正文"},
+		)
+	}
+	return settings
+}
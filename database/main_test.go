@@ -0,0 +1,32 @@
+package database_test
+
+import (
+	"os"
+	"testing"
+
+	"networkDev/database"
+	"networkDev/utils"
+
+	"github.com/spf13/viper"
+)
+
+// TestMain 使用内存 SQLite 初始化数据库与数据加密密钥
+func TestMain(m *testing.M) {
+	viper.Set("database.type", "sqlite")
+	viper.Set("database.sqlite.path", ":memory:")
+	viper.Set("security.encryption_key", "database-test-key")
+
+	if err := utils.InitCrypto(); err != nil {
+		panic(err)
+	}
+	if _, err := database.Init(); err != nil {
+		panic(err)
+	}
+	if err := database.AutoMigrate(); err != nil {
+		panic(err)
+	}
+	if err := database.SeedDefaultSettings(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
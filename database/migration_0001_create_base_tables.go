@@ -0,0 +1,167 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0001 初始表结构
+// ============================================================================
+//
+// 迁移中的结构体是当时模型的快照，后续修改 models 包不会影响已发布的迁移。
+// 对于引入版本化迁移之前通过 AutoMigrate 创建的数据库，本迁移会补齐缺失的
+// 表与列（包括将 users.password_salt 扩展到 64 位），不会删除已有数据。
+
+type migration0001User struct {
+	ID           uint      `gorm:"primaryKey;comment:用户ID，自增主键"`
+	UUID         string    `gorm:"uniqueIndex;size:36;not null;comment:用户的唯一标识符"`
+	Username     string    `gorm:"uniqueIndex;size:64;not null;comment:用户名，唯一索引"`
+	Password     string    `gorm:"size:255;not null;comment:密码哈希值"`
+	PasswordSalt string    `gorm:"size:64;not null;comment:密码加密盐值"`
+	CreatedAt    time.Time `gorm:"comment:创建时间"`
+	UpdatedAt    time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0001User) TableName() string {
+	return "users"
+}
+
+type migration0001Settings struct {
+	ID          uint      `gorm:"primaryKey;comment:设置ID，自增主键"`
+	Name        string    `gorm:"uniqueIndex;size:64;not null;comment:配置项名称，唯一索引"`
+	Value       string    `gorm:"type:text;comment:配置项的值"`
+	Description string    `gorm:"size:255;comment:配置项描述说明"`
+	CreatedAt   time.Time `gorm:"comment:创建时间"`
+	UpdatedAt   time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0001Settings) TableName() string {
+	return "settings"
+}
+
+type migration0001App struct {
+	ID                   uint      `gorm:"primaryKey;comment:应用ID，自增主键"`
+	UUID                 string    `gorm:"uniqueIndex;size:36;not null;comment:应用UUID，唯一标识符"`
+	Status               int       `gorm:"default:0;not null;comment:应用状态，1=启用，0=禁用"`
+	Name                 string    `gorm:"size:100;not null;comment:应用名称"`
+	Secret               string    `gorm:"size:255;not null;comment:应用密钥，用于API认证，加密存储"`
+	Version              string    `gorm:"size:50;default:'1.0.0';comment:应用版本号"`
+	ForceUpdate          int       `gorm:"default:0;not null;comment:强制更新，0=不开启，1=开启"`
+	DownloadType         int       `gorm:"default:0;not null;comment:更新方式，0=不启用更新，1=自动更新，2=手动下载"`
+	DownloadURL          string    `gorm:"size:500;comment:下载地址"`
+	AppData              string    `gorm:"type:text;comment:应用数据，base64编码存储"`
+	Announcement         string    `gorm:"type:text;comment:程序公告内容，base64编码存储"`
+	LoginType            int       `gorm:"default:0;not null;comment:登陆方式，0=顶号登录，1=非顶号登录"`
+	MultiOpenScope       int       `gorm:"default:2;not null;comment:多开范围，0=单电脑，1=单IP，2=全部电脑"`
+	CleanInterval        int       `gorm:"default:1;not null;comment:清理间隔，单位小时"`
+	CheckInterval        int       `gorm:"default:10;not null;comment:校验间隔，单位分钟"`
+	MultiOpenCount       int       `gorm:"default:1;not null;comment:多开数量"`
+	MachineVerify        int       `gorm:"default:0;not null;comment:机器验证，0=关闭，1=开启"`
+	MachineRebindEnabled int       `gorm:"default:0;not null;comment:机器重绑开关，0=关闭，1=开启"`
+	MachineRebindLimit   int       `gorm:"default:0;not null;comment:机器重绑限制，0=每天，1=永久"`
+	MachineFreeCount     int       `gorm:"default:0;not null;comment:机器免费次数"`
+	MachineRebindCount   int       `gorm:"default:0;not null;comment:机器重绑次数"`
+	MachineRebindDeduct  int       `gorm:"default:0;not null;comment:机器重绑扣除，单位分钟"`
+	IPVerify             int       `gorm:"default:0;not null;comment:IP地址验证，0=关闭，1=开启，2=开启(市)，3=开启(省)"`
+	IPRebindEnabled      int       `gorm:"default:0;not null;comment:IP地址重绑开关，0=关闭，1=开启"`
+	IPRebindLimit        int       `gorm:"default:0;not null;comment:IP地址重绑限制，0=每天，1=永久"`
+	IPFreeCount          int       `gorm:"default:0;not null;comment:IP地址免费次数"`
+	IPRebindCount        int       `gorm:"default:0;not null;comment:IP地址重绑次数"`
+	IPRebindDeduct       int       `gorm:"default:0;not null;comment:IP地址重绑扣除，单位分钟"`
+	RegisterEnabled      int       `gorm:"default:1;not null;comment:账号注册开关，0=关闭，1=开启"`
+	RegisterLimitEnabled int       `gorm:"default:0;not null;comment:注册限制开关，0=关闭，1=开启"`
+	RegisterLimitTime    int       `gorm:"default:1;not null;comment:注册限制时间，0=每天，1=永久"`
+	RegisterCount        int       `gorm:"default:1;not null;comment:注册次数"`
+	TrialEnabled         int       `gorm:"default:0;not null;comment:领取试用开关，0=关闭，1=开启"`
+	TrialLimitTime       int       `gorm:"default:1;not null;comment:试用限制时间，0=每天，1=永久"`
+	TrialDuration        int       `gorm:"default:0;not null;comment:试用时间，单位分钟"`
+	MaintenanceMode      int       `gorm:"default:0;not null;comment:应用维护开关，0=关闭，1=开启"`
+	MaintenanceMessage   string    `gorm:"size:500;comment:应用维护说明"`
+	CreatedAt            time.Time `gorm:"comment:创建时间"`
+	UpdatedAt            time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0001App) TableName() string {
+	return "apps"
+}
+
+type migration0001API struct {
+	ID               uint      `gorm:"primaryKey;comment:API接口ID，自增主键"`
+	UUID             string    `gorm:"uniqueIndex;size:36;not null;comment:API接口UUID，唯一标识符"`
+	APIType          int       `gorm:"not null;comment:API类型"`
+	AppUUID          string    `gorm:"size:36;not null;index;comment:关联的应用UUID"`
+	Status           int       `gorm:"default:0;not null;comment:接口状态，1=启用，0=禁用"`
+	SubmitAlgorithm  int       `gorm:"default:0;not null;comment:提交算法，0=不加密，1=RC4，2=RSA，3=RSA动态，4=易加密"`
+	ReturnAlgorithm  int       `gorm:"default:0;not null;comment:返回算法，0=不加密，1=RC4，2=RSA，3=RSA动态，4=易加密"`
+	SubmitPublicKey  string    `gorm:"type:text;comment:提交算法公钥，明文PEM"`
+	SubmitPrivateKey string    `gorm:"type:text;comment:提交算法私钥，加密存储"`
+	ReturnPublicKey  string    `gorm:"type:text;comment:返回算法公钥，明文PEM"`
+	ReturnPrivateKey string    `gorm:"type:text;comment:返回算法私钥，加密存储"`
+	CreatedAt        time.Time `gorm:"comment:创建时间"`
+	UpdatedAt        time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0001API) TableName() string {
+	return "apis"
+}
+
+type migration0001Variable struct {
+	ID        uint      `gorm:"primaryKey;comment:变量ID，自增主键"`
+	UUID      string    `gorm:"uniqueIndex;size:36;not null;comment:变量的唯一标识符"`
+	Number    string    `gorm:"uniqueIndex;size:13;not null;comment:变量编号，13位Unix时间戳"`
+	AppUUID   string    `gorm:"size:36;not null;default:'0';comment:应用绑定标识符"`
+	Alias     string    `gorm:"uniqueIndex;size:100;not null;comment:变量别名"`
+	Data      string    `gorm:"type:text;comment:变量数据"`
+	Remark    string    `gorm:"type:text;comment:备注信息"`
+	CreatedAt time.Time `gorm:"comment:创建时间"`
+	UpdatedAt time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0001Variable) TableName() string {
+	return "variables"
+}
+
+type migration0001Function struct {
+	ID        uint      `gorm:"primaryKey;comment:函数ID，自增主键"`
+	UUID      string    `gorm:"uniqueIndex;size:36;not null;comment:函数的唯一标识符"`
+	Number    string    `gorm:"uniqueIndex;size:13;not null;comment:函数编号，13位Unix时间戳"`
+	AppUUID   string    `gorm:"size:36;not null;default:'0';comment:应用绑定标识符"`
+	Alias     string    `gorm:"uniqueIndex;size:100;not null;comment:函数别名"`
+	Code      string    `gorm:"type:text;comment:函数代码"`
+	Remark    string    `gorm:"type:text;comment:备注信息"`
+	CreatedAt time.Time `gorm:"comment:创建时间"`
+	UpdatedAt time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0001Function) TableName() string {
+	return "functions"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 1,
+		Name:    "create_base_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&migration0001User{},
+				&migration0001Settings{},
+				&migration0001App{},
+				&migration0001API{},
+				&migration0001Variable{},
+				&migration0001Function{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&migration0001Function{},
+				&migration0001Variable{},
+				&migration0001API{},
+				&migration0001App{},
+				&migration0001Settings{},
+				&migration0001User{},
+			)
+		},
+	})
+}
@@ -0,0 +1,16 @@
+package database
+
+// ============================================================================
+// 0002 加密敏感字段
+// ============================================================================
+//
+// 将历史明文存储的应用密钥与接口私钥加密，回滚时还原为明文。
+
+func init() {
+	registerMigration(Migration{
+		Version: 2,
+		Name:    "encrypt_sensitive_columns",
+		Up:      encryptSensitiveColumns,
+		Down:    decryptSensitiveColumns,
+	})
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0003 卡密表
+// ============================================================================
+
+type migration0003Card struct {
+	ID        uint       `gorm:"primaryKey;comment:卡密ID，自增主键"`
+	UUID      string     `gorm:"uniqueIndex;size:36;not null;comment:卡密UUID，唯一标识符"`
+	AppUUID   string     `gorm:"index;size:36;not null;comment:所属应用UUID"`
+	CardKey   string     `gorm:"uniqueIndex;size:64;not null;comment:卡密内容"`
+	Duration  int        `gorm:"default:0;not null;comment:卡密时长，单位分钟"`
+	Status    int        `gorm:"default:0;not null;index;comment:卡密状态，0=未使用，1=已使用，2=已禁用"`
+	BatchNo   string     `gorm:"index;size:32;comment:生成批次号"`
+	Remark    string     `gorm:"size:255;comment:备注信息"`
+	UsedAt    *time.Time `gorm:"comment:使用时间"`
+	CreatedAt time.Time  `gorm:"comment:创建时间"`
+	UpdatedAt time.Time  `gorm:"comment:更新时间"`
+}
+
+func (migration0003Card) TableName() string {
+	return "cards"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 3,
+		Name:    "create_cards",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&migration0003Card{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&migration0003Card{})
+		},
+	})
+}
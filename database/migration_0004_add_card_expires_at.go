@@ -0,0 +1,40 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0004 卡密有效期
+// ============================================================================
+//
+// 为卡密增加有效期截止时间，超过有效期仍未使用的卡密由定时任务标记为已过期。
+
+type migration0004Card struct {
+	ExpiresAt *time.Time `gorm:"index;comment:有效期截止时间，为空表示长期有效"`
+}
+
+func (migration0004Card) TableName() string {
+	return "cards"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 4,
+		Name:    "add_card_expires_at",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&migration0004Card{}, "ExpiresAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&migration0004Card{}, "ExpiresAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&migration0004Card{}, "ExpiresAt"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&migration0004Card{}, "ExpiresAt")
+		},
+	})
+}
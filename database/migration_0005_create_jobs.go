@@ -0,0 +1,54 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0005 定时任务设置与执行记录表
+// ============================================================================
+
+type migration0005Job struct {
+	ID        uint      `gorm:"primaryKey;comment:任务设置ID，自增主键"`
+	Name      string    `gorm:"uniqueIndex;size:64;not null;comment:任务名称"`
+	Enabled   bool      `gorm:"not null;comment:是否启用"`
+	Cron      string    `gorm:"size:64;comment:执行计划，为空时使用默认执行计划"`
+	CreatedAt time.Time `gorm:"comment:创建时间"`
+	UpdatedAt time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0005Job) TableName() string {
+	return "jobs"
+}
+
+type migration0005JobRun struct {
+	ID         uint       `gorm:"primaryKey;comment:执行记录ID，自增主键"`
+	JobName    string     `gorm:"index;size:64;not null;comment:任务名称"`
+	Trigger    string     `gorm:"size:16;not null;comment:触发方式，schedule=按计划，manual=手动"`
+	Instance   string     `gorm:"size:36;comment:执行任务的实例ID"`
+	Status     int        `gorm:"default:0;not null;index;comment:执行状态，0=执行中，1=成功，2=失败"`
+	Result     string     `gorm:"size:255;comment:执行结果摘要"`
+	Error      string     `gorm:"type:text;comment:失败原因"`
+	StartedAt  time.Time  `gorm:"index;not null;comment:开始时间"`
+	FinishedAt *time.Time `gorm:"comment:结束时间"`
+	DurationMs int64      `gorm:"default:0;not null;comment:耗时，单位毫秒"`
+}
+
+func (migration0005JobRun) TableName() string {
+	return "job_runs"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 5,
+		Name:    "create_jobs",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&migration0005Job{}, &migration0005JobRun{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&migration0005JobRun{}, &migration0005Job{})
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0006 Webhook 推送地址与投递记录表
+// ============================================================================
+
+type migration0006Webhook struct {
+	ID        uint      `gorm:"primaryKey;comment:Webhook ID，自增主键"`
+	UUID      string    `gorm:"uniqueIndex;size:36;not null;comment:Webhook UUID，唯一标识符"`
+	AppUUID   string    `gorm:"index;size:36;not null;default:'0';comment:订阅的应用UUID，0表示全部应用"`
+	Name      string    `gorm:"size:100;not null;comment:名称"`
+	URL       string    `gorm:"size:500;not null;comment:推送地址"`
+	Secret    string    `gorm:"size:255;not null;comment:签名密钥，加密存储"`
+	Events    string    `gorm:"type:text;comment:订阅的事件，逗号分隔"`
+	Status    int       `gorm:"default:0;not null;comment:状态，1=启用，0=停用"`
+	Remark    string    `gorm:"type:text;comment:备注"`
+	CreatedAt time.Time `gorm:"comment:创建时间"`
+	UpdatedAt time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0006Webhook) TableName() string {
+	return "webhooks"
+}
+
+type migration0006WebhookDelivery struct {
+	ID           uint       `gorm:"primaryKey;comment:投递记录ID，自增主键"`
+	UUID         string     `gorm:"uniqueIndex;size:36;not null;comment:投递UUID，唯一标识符"`
+	WebhookID    uint       `gorm:"index;not null;comment:所属Webhook ID"`
+	Event        string     `gorm:"size:64;not null;comment:事件类型"`
+	Payload      string     `gorm:"type:text;comment:推送的JSON内容"`
+	Status       int        `gorm:"default:0;not null;index;comment:投递状态，0=等待投递，1=成功，2=失败"`
+	Attempts     int        `gorm:"default:0;not null;comment:已尝试次数"`
+	ResponseCode int        `gorm:"default:0;not null;comment:最近一次尝试的HTTP状态码"`
+	ResponseBody string     `gorm:"type:text;comment:最近一次尝试的响应内容"`
+	Error        string     `gorm:"type:text;comment:最近一次尝试的失败原因"`
+	DurationMs   int64      `gorm:"default:0;not null;comment:最近一次尝试的耗时，单位毫秒"`
+	NextRetryAt  *time.Time `gorm:"index;comment:下次投递时间"`
+	CreatedAt    time.Time  `gorm:"index;comment:创建时间"`
+	UpdatedAt    time.Time  `gorm:"comment:更新时间"`
+}
+
+func (migration0006WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 6,
+		Name:    "create_webhooks",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&migration0006Webhook{}, &migration0006WebhookDelivery{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&migration0006WebhookDelivery{}, &migration0006Webhook{})
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0007 通知渠道表
+// ============================================================================
+
+type migration0007NotifyChannel struct {
+	ID          uint      `gorm:"primaryKey;comment:通知渠道ID，自增主键"`
+	Name        string    `gorm:"size:100;not null;comment:名称"`
+	Type        string    `gorm:"size:16;not null;comment:渠道类型，dingtalk/wecom/feishu/telegram"`
+	URL         string    `gorm:"size:500;comment:机器人Webhook地址，Telegram为Bot API地址"`
+	Secret      string    `gorm:"size:255;comment:签名密钥或Bot Token，加密存储"`
+	ChatID      string    `gorm:"size:64;comment:Telegram会话ID"`
+	Events      string    `gorm:"type:text;comment:订阅的事件，逗号分隔"`
+	MinSeverity int       `gorm:"default:1;not null;comment:最低通知级别，1=提示，2=警告，3=严重"`
+	RateLimit   int       `gorm:"default:20;not null;comment:每分钟最多发送条数"`
+	Status      int       `gorm:"default:0;not null;comment:状态，1=启用，0=停用"`
+	Remark      string    `gorm:"type:text;comment:备注"`
+	CreatedAt   time.Time `gorm:"comment:创建时间"`
+	UpdatedAt   time.Time `gorm:"comment:更新时间"`
+}
+
+func (migration0007NotifyChannel) TableName() string {
+	return "notify_channels"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 7,
+		Name:    "create_notify_channels",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&migration0007NotifyChannel{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&migration0007NotifyChannel{})
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0008 邮件发送队列表
+// ============================================================================
+
+type migration0008MailMessage struct {
+	ID          uint       `gorm:"primaryKey;comment:邮件ID，自增主键"`
+	Template    string     `gorm:"size:64;index;comment:模板名称"`
+	To          string     `gorm:"size:255;not null;comment:收件人地址"`
+	Subject     string     `gorm:"size:255;comment:邮件主题"`
+	HTMLBody    string     `gorm:"type:text;comment:HTML正文"`
+	TextBody    string     `gorm:"type:text;comment:纯文本正文"`
+	Status      int        `gorm:"default:0;not null;index;comment:发送状态，0=等待发送，1=已发送，2=失败"`
+	Attempts    int        `gorm:"default:0;not null;comment:已尝试次数"`
+	Error       string     `gorm:"type:text;comment:最近一次失败原因"`
+	NextRetryAt *time.Time `gorm:"index;comment:下次发送时间"`
+	SentAt      *time.Time `gorm:"comment:发送成功时间"`
+	CreatedAt   time.Time  `gorm:"index;comment:创建时间"`
+	UpdatedAt   time.Time  `gorm:"comment:更新时间"`
+}
+
+func (migration0008MailMessage) TableName() string {
+	return "mail_queue"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 8,
+		Name:    "create_mail_queue",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&migration0008MailMessage{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&migration0008MailMessage{})
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0009 API密钥与审计日志表
+// ============================================================================
+
+type migration0009AdminAPIKey struct {
+	ID         uint       `gorm:"primaryKey;comment:API密钥ID，自增主键"`
+	Name       string     `gorm:"size:100;not null;comment:名称"`
+	Prefix     string     `gorm:"size:16;not null;comment:密钥前缀"`
+	KeyHash    string     `gorm:"size:64;not null;uniqueIndex;comment:密钥SHA-256摘要"`
+	Scopes     string     `gorm:"type:text;comment:权限范围，逗号分隔"`
+	AppUUIDs   string     `gorm:"type:text;comment:允许操作的应用UUID，逗号分隔，为空表示不限制"`
+	ExpiresAt  *time.Time `gorm:"comment:过期时间，为空表示长期有效"`
+	LastUsedAt *time.Time `gorm:"comment:最近使用时间"`
+	LastUsedIP string     `gorm:"size:64;comment:最近使用的IP"`
+	Status     int        `gorm:"default:0;not null;comment:状态，1=启用，0=停用"`
+	Remark     string     `gorm:"type:text;comment:备注"`
+	CreatedAt  time.Time  `gorm:"comment:创建时间"`
+	UpdatedAt  time.Time  `gorm:"comment:更新时间"`
+}
+
+func (migration0009AdminAPIKey) TableName() string {
+	return "admin_api_keys"
+}
+
+type migration0009AuditLog struct {
+	ID         uint      `gorm:"primaryKey;comment:审计日志ID，自增主键"`
+	ActorType  string    `gorm:"size:16;not null;index;comment:操作者类型，admin=管理员，api_key=API密钥"`
+	Actor      string    `gorm:"size:100;comment:操作者名称"`
+	APIKeyID   uint      `gorm:"default:0;not null;index;comment:API密钥ID，管理员操作为0"`
+	Method     string    `gorm:"size:8;not null;comment:请求方法"`
+	Path       string    `gorm:"size:255;not null;comment:请求路径"`
+	Status     int       `gorm:"default:0;not null;comment:响应状态码"`
+	IP         string    `gorm:"size:64;comment:客户端IP"`
+	RequestID  string    `gorm:"size:64;comment:请求ID"`
+	DurationMs int64     `gorm:"default:0;not null;comment:处理耗时，单位毫秒"`
+	CreatedAt  time.Time `gorm:"index;comment:创建时间"`
+}
+
+func (migration0009AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 9,
+		Name:    "create_api_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&migration0009AdminAPIKey{}, &migration0009AuditLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&migration0009AuditLog{}, &migration0009AdminAPIKey{})
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0010 客户端账号、黑名单与卡密登录绑定
+// ============================================================================
+//
+// 创建客户端账号表与黑名单表，并为卡密增加卡密登录使用的到期时间、设备绑定与充值账号字段。
+
+type migration0010ClientBinding struct {
+	EndAt             *time.Time `gorm:"index;comment:到期时间"`
+	MachineCode       string     `gorm:"size:128;comment:绑定的机器码"`
+	BindIP            string     `gorm:"size:64;comment:绑定的IP"`
+	MachineRebinds    int        `gorm:"default:0;not null;comment:机器码换绑次数"`
+	MachineRebindDate string     `gorm:"size:10;comment:机器码换绑次数所属日期"`
+	IPRebinds         int        `gorm:"default:0;not null;comment:IP换绑次数"`
+	IPRebindDate      string     `gorm:"size:10;comment:IP换绑次数所属日期"`
+	LastLoginAt       *time.Time `gorm:"comment:最近登录时间"`
+	LastLoginIP       string     `gorm:"size:64;comment:最近登录IP"`
+}
+
+// migration0010CardColumns 卡密新增的字段，按声明顺序添加与回滚
+var migration0010CardColumns = []string{
+	"AccountUUID", "EndAt", "MachineCode", "BindIP", "MachineRebinds", "MachineRebindDate",
+	"IPRebinds", "IPRebindDate", "LastLoginAt", "LastLoginIP",
+}
+
+type migration0010Card struct {
+	AccountUUID string                     `gorm:"index;size:36;comment:充值到的账号UUID"`
+	Binding     migration0010ClientBinding `gorm:"embedded"`
+}
+
+func (migration0010Card) TableName() string {
+	return "cards"
+}
+
+type migration0010Account struct {
+	ID              uint                       `gorm:"primaryKey;comment:账号ID，自增主键"`
+	UUID            string                     `gorm:"uniqueIndex;size:36;not null;comment:账号UUID，唯一标识符"`
+	AppUUID         string                     `gorm:"uniqueIndex:idx_accounts_app_username;size:36;not null;comment:所属应用UUID"`
+	Username        string                     `gorm:"uniqueIndex:idx_accounts_app_username;size:64;not null;comment:用户名，应用内唯一"`
+	Password        string                     `gorm:"size:255;not null;comment:密码哈希值"`
+	PasswordSalt    string                     `gorm:"size:64;not null;comment:密码加密盐值"`
+	Email           string                     `gorm:"size:255;index;comment:邮箱，用于找回密码"`
+	Status          int                        `gorm:"default:1;not null;comment:账号状态，1=正常，2=已封禁"`
+	BanReason       string                     `gorm:"size:255;comment:封禁原因"`
+	RegisterMachine string                     `gorm:"size:128;index;comment:注册机器码"`
+	RegisterIP      string                     `gorm:"size:64;comment:注册IP"`
+	TrialGrantedAt  *time.Time                 `gorm:"comment:领取试用时间"`
+	Binding         migration0010ClientBinding `gorm:"embedded"`
+	CreatedAt       time.Time                  `gorm:"comment:创建时间"`
+	UpdatedAt       time.Time                  `gorm:"comment:更新时间"`
+}
+
+func (migration0010Account) TableName() string {
+	return "accounts"
+}
+
+type migration0010Blacklist struct {
+	ID        uint      `gorm:"primaryKey;comment:黑名单ID，自增主键"`
+	AppUUID   string    `gorm:"uniqueIndex:idx_blacklists_entry;size:36;not null;comment:所属应用UUID"`
+	Type      int       `gorm:"uniqueIndex:idx_blacklists_entry;not null;comment:类型，1=机器码，2=IP"`
+	Value     string    `gorm:"uniqueIndex:idx_blacklists_entry;size:128;not null;comment:机器码或IP"`
+	Reason    string    `gorm:"size:255;comment:加入原因"`
+	CreatedAt time.Time `gorm:"comment:加入时间"`
+}
+
+func (migration0010Blacklist) TableName() string {
+	return "blacklists"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 10,
+		Name:    "create_client_accounts",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range migration0010CardColumns {
+				if err := tx.Migrator().AddColumn(&migration0010Card{}, column); err != nil {
+					return err
+				}
+			}
+			for _, index := range []string{"AccountUUID", "EndAt"} {
+				if err := tx.Migrator().CreateIndex(&migration0010Card{}, index); err != nil {
+					return err
+				}
+			}
+			return tx.AutoMigrate(&migration0010Account{}, &migration0010Blacklist{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&migration0010Blacklist{}, &migration0010Account{}); err != nil {
+				return err
+			}
+			for _, index := range []string{"AccountUUID", "EndAt"} {
+				if err := tx.Migrator().DropIndex(&migration0010Card{}, index); err != nil {
+					return err
+				}
+			}
+			for i := len(migration0010CardColumns) - 1; i >= 0; i-- {
+				if err := tx.Migrator().DropColumn(&migration0010Card{}, migration0010CardColumns[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0011 找回密码接口
+// ============================================================================
+//
+// 新增「发送找回密码验证码」「找回账号密码」两种接口类型，为已有应用补建对应的接口记录
+// （与创建应用时一致：默认禁用、不加密）。
+
+// migration0011APITypes 新增的接口类型
+var migration0011APITypes = []int{53, 54}
+
+type migration0011API struct {
+	ID              uint `gorm:"primaryKey"`
+	UUID            string
+	APIType         int
+	AppUUID         string
+	Status          int
+	SubmitAlgorithm int
+	ReturnAlgorithm int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (migration0011API) TableName() string {
+	return "apis"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 11,
+		Name:    "add_password_reset_apis",
+		Up: func(tx *gorm.DB) error {
+			var appUUIDs []string
+			if err := tx.Table("apps").Pluck("uuid", &appUUIDs).Error; err != nil {
+				return err
+			}
+			for _, appUUID := range appUUIDs {
+				for _, apiType := range migration0011APITypes {
+					var count int64
+					if err := tx.Model(&migration0011API{}).Where("app_uuid = ? AND api_type = ?", appUUID, apiType).Count(&count).Error; err != nil {
+						return err
+					}
+					if count > 0 {
+						continue
+					}
+					api := migration0011API{UUID: strings.ToUpper(uuid.New().String()), APIType: apiType, AppUUID: appUUID}
+					if err := tx.Create(&api).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Where("api_type IN ?", migration0011APITypes).Delete(&migration0011API{}).Error
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 0012 加密密钥列改为 text
+// ============================================================================
+//
+// 应用密钥、Webhook 与通知渠道的签名密钥加密后带有前缀、密钥ID与 base64 编码，
+// 长度超过明文的 1.3 倍，varchar(255) 无法容纳较长的密钥，统一改为 text。
+// SQLite 的字符串列本身即为 TEXT 且不限制长度，无需调整。
+
+type migration0012App struct {
+	Secret string `gorm:"type:text;not null;comment:应用密钥，用于API认证，加密存储"`
+}
+
+func (migration0012App) TableName() string {
+	return "apps"
+}
+
+type migration0012Webhook struct {
+	Secret string `gorm:"type:text;not null;comment:签名密钥，加密存储"`
+}
+
+func (migration0012Webhook) TableName() string {
+	return "webhooks"
+}
+
+type migration0012NotifyChannel struct {
+	Secret string `gorm:"type:text;comment:签名密钥或Bot Token，加密存储"`
+}
+
+func (migration0012NotifyChannel) TableName() string {
+	return "notify_channels"
+}
+
+// migration0012Legacy* 调整前的列定义，回滚时使用
+type migration0012LegacyApp struct {
+	Secret string `gorm:"size:255;not null;comment:应用密钥，用于API认证，加密存储"`
+}
+
+func (migration0012LegacyApp) TableName() string {
+	return "apps"
+}
+
+type migration0012LegacyWebhook struct {
+	Secret string `gorm:"size:255;not null;comment:签名密钥，加密存储"`
+}
+
+func (migration0012LegacyWebhook) TableName() string {
+	return "webhooks"
+}
+
+type migration0012LegacyNotifyChannel struct {
+	Secret string `gorm:"size:255;comment:签名密钥或Bot Token，加密存储"`
+}
+
+func (migration0012LegacyNotifyChannel) TableName() string {
+	return "notify_channels"
+}
+
+func init() {
+	registerMigration(Migration{
+		Version: 12,
+		Name:    "widen_secret_columns",
+		Up: func(tx *gorm.DB) error {
+			return alterSecretColumns(tx, &migration0012App{}, &migration0012Webhook{}, &migration0012NotifyChannel{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return alterSecretColumns(tx, &migration0012LegacyApp{}, &migration0012LegacyWebhook{}, &migration0012LegacyNotifyChannel{})
+		},
+	})
+}
+
+// alterSecretColumns 按给定模型修改 Secret 列的类型
+func alterSecretColumns(tx *gorm.DB, tables ...interface{}) error {
+	if tx.Dialector.Name() == "sqlite" {
+		return nil
+	}
+	for _, table := range tables {
+		if err := tx.Migrator().AlterColumn(table, "Secret"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
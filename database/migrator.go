@@ -0,0 +1,273 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Migration 版本化迁移定义
+// - Version：迁移版本号，全局唯一且递增，已发布的迁移不得修改
+// - Name：迁移名称，用于状态展示
+// - Up/Down：升级与回滚操作，需自行处理不同数据库方言的差异
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration 已执行的迁移记录
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false;comment:迁移版本号"`
+	Name      string    `gorm:"size:128;not null;comment:迁移名称"`
+	AppliedAt time.Time `gorm:"not null;comment:执行时间"`
+}
+
+// MigrationState 迁移状态
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// migrations 已注册的迁移列表（按版本号排序后执行）
+var migrations []Migration
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// MigrateUp 执行未应用的迁移
+// target 为目标版本号，0 表示升级到最新版本
+// 返回本次执行的迁移列表
+func MigrateUp(target int) ([]Migration, error) {
+	db, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range sortedMigrations() {
+		if target > 0 && m.Version > target {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		logrus.WithFields(logrus.Fields{"version": m.Version, "name": m.Name}).Info("执行数据库迁移")
+		if err := runMigration(db, m, true); err != nil {
+			return done, fmt.Errorf("迁移 %04d_%s 执行失败: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrateDown 回滚最近执行的迁移
+// steps 为回滚的迁移数量，至少为 1
+// 返回本次回滚的迁移列表
+func MigrateDown(steps int) ([]Migration, error) {
+	if steps < 1 {
+		steps = 1
+	}
+
+	db, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := sortedMigrations()
+	var done []Migration
+	for i := len(sorted) - 1; i >= 0 && len(done) < steps; i-- {
+		m := sorted[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == nil {
+			return done, fmt.Errorf("迁移 %04d_%s 不支持回滚", m.Version, m.Name)
+		}
+
+		logrus.WithFields(logrus.Fields{"version": m.Version, "name": m.Name}).Info("回滚数据库迁移")
+		if err := runMigration(db, m, false); err != nil {
+			return done, fmt.Errorf("迁移 %04d_%s 回滚失败: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// MigrationStatus 获取所有迁移的执行状态
+func MigrationStatus() ([]MigrationState, error) {
+	db, err := GetDB()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSchemaMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []MigrationState
+	for _, m := range sortedMigrations() {
+		state := MigrationState{Version: m.Version, Name: m.Name}
+		if record, ok := applied[m.Version]; ok {
+			appliedAt := record.AppliedAt
+			state.Applied = true
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// PendingMigrationCount 获取尚未执行的迁移数量
+// 供就绪检查频繁调用，只执行只读查询：迁移记录表不存在时所有迁移都视为未执行
+func PendingMigrationCount() (int, error) {
+	db, err := GetDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var versions []int
+	if err := db.Model(&SchemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		if !db.Migrator().HasTable(&SchemaMigration{}) {
+			return len(migrations), nil
+		}
+		return 0, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+
+	applied := make(map[int]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+	count := 0
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// registerMigration 注册迁移
+// 在各迁移文件的 init 中调用，版本号重复时直接 panic，便于开发阶段发现问题
+func registerMigration(m Migration) {
+	for _, existing := range migrations {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("数据库迁移版本号重复: %d", m.Version))
+		}
+	}
+	migrations = append(migrations, m)
+}
+
+// sortedMigrations 返回按版本号升序排列的迁移列表
+func sortedMigrations() []Migration {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// ensureSchemaMigrationsTable 确保迁移记录表存在
+func ensureSchemaMigrationsTable(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("创建 schema_migrations 表失败: %w", err)
+	}
+	return nil
+}
+
+// appliedVersions 获取已执行的迁移记录（版本号 -> 记录）
+func appliedVersions(db *gorm.DB) (map[int]SchemaMigration, error) {
+	var records []SchemaMigration
+	if err := db.Order("version ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// runMigration 执行单个迁移并更新迁移记录
+// - SQLite、PostgreSQL 支持事务性 DDL，在事务中执行，失败时整体回滚
+// - MySQL 的 DDL 会隐式提交事务，只能逐条执行，失败后需根据日志人工处理
+func runMigration(db *gorm.DB, m Migration, up bool) error {
+	apply := func(tx *gorm.DB) error {
+		if up {
+			if m.Up == nil {
+				return errors.New("未定义升级操作")
+			}
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}
+
+		if err := m.Down(tx); err != nil {
+			return err
+		}
+		return tx.Where("version = ?", m.Version).Delete(&SchemaMigration{}).Error
+	}
+
+	if supportsTransactionalDDL(db) {
+		return db.Transaction(apply)
+	}
+	return apply(db)
+}
+
+// supportsTransactionalDDL 判断当前数据库是否支持事务性DDL
+func supportsTransactionalDDL(db *gorm.DB) bool {
+	return db.Dialector.Name() != "mysql"
+}
@@ -68,6 +68,16 @@ func SeedDefaultSettings() error {
 			Value:       "0",
 			Description: "维护模式，0=关闭维护模式，1=开启维护模式",
 		},
+		{
+			Name:        "maintenance_message",
+			Value:       "系统维护中，请稍后再试",
+			Description: "维护说明，维护模式下展示给访客和客户端",
+		},
+		{
+			Name:        "maintenance_end_time",
+			Value:       "",
+			Description: "预计维护结束时间，格式 2006-01-02 15:04:05，留空则不显示",
+		},
 		// ===== 管理员账号相关默认项 =====
 		{
 			Name:        "admin_username",
@@ -84,6 +94,42 @@ func SeedDefaultSettings() error {
 			Value:       "",
 			Description: "管理员密码加密盐值",
 		},
+		// ===== 告警通知相关默认项 =====
+		{
+			Name:        "notify_login_new_ip",
+			Value:       "1",
+			Description: "管理员从新IP登录时发送告警，0=关闭，1=开启",
+		},
+		{
+			Name:        "admin_login_ips",
+			Value:       "",
+			Description: "管理员最近登录过的IP，逗号分隔，清空后下次登录只记录不告警",
+		},
+		{
+			Name:        "notify_login_failure_threshold",
+			Value:       "20",
+			Description: "同一应用在统计窗口内客户端登录失败达到该次数时发送告警，0=关闭",
+		},
+		{
+			Name:        "notify_login_failure_window",
+			Value:       "5",
+			Description: "客户端登录失败次数的统计窗口（分钟）",
+		},
+		{
+			Name:        "notify_mail_to",
+			Value:       "",
+			Description: "接收告警邮件的地址，逗号分隔，留空则不发送告警邮件",
+		},
+		{
+			Name:        "notify_mail_min_severity",
+			Value:       "2",
+			Description: "发送告警邮件的最低级别，1=提示，2=警告，3=严重",
+		},
+		{
+			Name:        "mail_expiry_remind_days",
+			Value:       "3",
+			Description: "卡密到期提醒天数，未使用的卡密在该天数内到期时邮件提醒，0=关闭",
+		},
 		// ===== 页脚与备案相关默认项 =====
 		{
 			Name:        "footer_text",
@@ -112,6 +158,8 @@ func SeedDefaultSettings() error {
 		},
 	}
 
+	defaultSettings = append(defaultSettings, defaultMailTemplateSettings()...)
+
 	// 逐个检查并创建不存在的设置项
 	for _, setting := range defaultSettings {
 		var count int64
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"networkDev/constants"
+	"networkDev/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 中间件函数
+// ============================================================================
+
+// IPAllowlist IP白名单中间件
+// - entries 为IP或CIDR（如 127.0.0.1、10.0.0.0/8），为空时不做限制
+// - 存在无法解析的条目时拒绝所有访问（配置校验阶段已拦截）
+// - 客户端地址由 utils.ClientIP 获取，仅信任 server.trusted_proxies 中的代理转发的头部
+func IPAllowlist(entries []string) gin.HandlerFunc {
+	if len(entries) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+	networks, err := utils.ParseIPNets(entries)
+	if err != nil {
+		logrus.WithError(err).Error("IP白名单配置无效，拒绝所有访问")
+	}
+
+	return func(c *gin.Context) {
+		if ip := utils.ClientIP(c); !utils.ContainsIP(networks, ip) {
+			logrus.WithFields(logrus.Fields{
+				"client_ip": ip,
+				"path":      c.Request.URL.Path,
+			}).Warn("拒绝白名单外的后台访问")
+			utils.AbortWithError(c, constants.CodeIPForbidden, "")
+			return
+		}
+		c.Next()
+	}
+}
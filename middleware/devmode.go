@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"sync/atomic"
+
 	"networkDev/web"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +25,13 @@ type DevModeConfig struct {
 	EnableDebugLog bool
 }
 
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// devModeOverride 运行时设置的开发模式开关（配置热重载），为 nil 时读取配置
+var devModeOverride atomic.Pointer[bool]
+
 // ============================================================================
 // 中间件函数
 // ============================================================================
@@ -59,9 +68,17 @@ func DevModeMiddleware(engine *gin.Engine) gin.HandlerFunc {
 
 // IsDevMode 检查是否为开发模式
 func IsDevMode() bool {
+	if enabled := devModeOverride.Load(); enabled != nil {
+		return *enabled
+	}
 	return viper.GetBool("server.dev_mode")
 }
 
+// SetDevMode 运行时切换开发模式，供配置热重载调用
+func SetDevMode(enabled bool) {
+	devModeOverride.Store(&enabled)
+}
+
 // GetDevModeConfig 获取开发模式配置
 func GetDevModeConfig() DevModeConfig {
 	if !IsDevMode() {
@@ -1,11 +1,12 @@
 package middleware
 
 import (
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"networkDev/utils"
 	"networkDev/utils/logger"
+	"networkDev/utils/metrics"
 )
 
 // ============================================================================
@@ -34,74 +35,36 @@ func NewLoggingMiddleware(logger *logger.Logger) *LoggingMiddleware {
 // ============================================================================
 
 // Handler 返回Gin中间件函数，用于记录HTTP请求日志
-// 记录格式遵循Apache Common Log Format
+// 记录格式遵循Apache Common Log Format（或 log.format=json 时的JSON格式）
+// 请求开始时获取或生成请求ID（X-Request-ID），写入上下文与响应头，供后续日志与响应使用
 func (lm *LoggingMiddleware) Handler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 记录开始时间
 		start := time.Now()
 
+		// 获取或生成请求ID
+		requestID := logger.EnsureRequestID(c)
+
 		// 处理请求
 		c.Next()
 
 		// 计算处理时间
 		duration := time.Since(start)
 
-		// 获取客户端IP
-		clientIP := getClientIP(c)
-
-		// 记录日志 - Apache Common Log Format
-		// 使用专门的HTTP日志方法避免User-Agent中的反斜杠被转义
-		lm.logger.LogRequestWithHeaders(
-			c.Request.Method,
-			c.Request.RequestURI,
-			clientIP,
-			c.Writer.Status(),
-			duration,
-			"-", // referer (已废弃)
-			c.Request.UserAgent(),
-		)
-	}
-}
-
-// ============================================================================
-// 私有函数
-// ============================================================================
-
-// getClientIP 获取客户端真实IP地址
-// 优先从X-Forwarded-For、X-Real-IP等头部获取，最后使用RemoteAddr
-func getClientIP(c *gin.Context) string {
-	// 检查X-Forwarded-For头部
-	xForwardedFor := c.GetHeader("X-Forwarded-For")
-	if xForwardedFor != "" {
-		// X-Forwarded-For可能包含多个IP，取第一个
-		ips := strings.Split(xForwardedFor, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
+		// 记录请求指标（使用路由模板作为标签，避免路径参数导致标签膨胀）
+		metrics.ObserveHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), duration)
+
+		// 记录日志，使用专门的HTTP日志方法避免User-Agent中的反斜杠被转义
+		lm.logger.LogHTTPRequest(logger.RequestLog{
+			Method:    c.Request.Method,
+			Path:      c.Request.RequestURI,
+			ClientIP:  utils.ClientIP(c),
+			Status:    c.Writer.Status(),
+			Duration:  duration,
+			UserAgent: c.Request.UserAgent(),
+			RequestID: requestID,
+		})
 	}
-
-	// 检查X-Real-IP头部
-	xRealIP := c.GetHeader("X-Real-IP")
-	if xRealIP != "" {
-		return xRealIP
-	}
-
-	// 检查X-Forwarded头部
-	xForwarded := c.GetHeader("X-Forwarded")
-	if xForwarded != "" {
-		return xForwarded
-	}
-
-	// 使用RemoteAddr
-	remoteAddr := c.Request.RemoteAddr
-	if strings.Contains(remoteAddr, ":") {
-		// 移除端口号
-		if idx := strings.LastIndex(remoteAddr, ":"); idx != -1 {
-			return remoteAddr[:idx]
-		}
-	}
-
-	return remoteAddr
 }
 
 // ============================================================================
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/services"
+	"networkDev/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// ClientAPIPrefix 客户端API路由前缀
+// 维护模式下该前缀下的所有请求统一返回结构化的维护响应
+const ClientAPIPrefix = "/api/"
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// maintenanceExemptPaths 额外登记的不受维护模式影响的路径（精确匹配）
+// 用于监控、探活等基础设施接口
+var maintenanceExemptPaths = map[string]bool{}
+
+// ============================================================================
+// 中间件函数
+// ============================================================================
+
+// MaintenanceMiddleware 维护模式中间件
+// - 未开启维护模式：直接放行
+// - 后台路径与静态资源：始终放行，保证管理员可以登录并关闭维护模式
+// - 已登录管理员：放行，便于维护期间预览站点
+// - 客户端API：返回结构化的维护响应（包含维护说明与预计结束时间）
+// - 其他页面：渲染维护页面
+// isAdmin 用于判断当前请求是否来自已登录的管理员，由调用方注入以避免包循环依赖
+func MaintenanceMiddleware(isAdmin func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		settings := services.GetSettingsService()
+		if !settings.IsMaintenanceMode() {
+			c.Next()
+			return
+		}
+
+		path := c.Request.URL.Path
+		if isMaintenanceExemptPath(path) {
+			c.Next()
+			return
+		}
+
+		if isAdmin != nil && isAdmin(c) {
+			c.Header("X-Maintenance-Mode", "true")
+			c.Next()
+			return
+		}
+
+		message := settings.GetMaintenanceMessage()
+		endTime := settings.GetMaintenanceEndTime()
+
+		if strings.HasPrefix(path, ClientAPIPrefix) {
+			AbortWithMaintenance(c, message, endTime)
+			return
+		}
+
+		c.HTML(http.StatusServiceUnavailable, "maintenance.html", gin.H{
+			"SystemName":    settings.GetString("site_title", "网络验证系统"),
+			"Message":       message,
+			"EndTime":       endTime,
+			"FooterText":    settings.GetString("footer_text", ""),
+			"ICPRecord":     settings.GetString("icp_record", ""),
+			"ICPRecordLink": settings.GetString("icp_record_link", ""),
+			"PSBRecord":     settings.GetString("psb_record", ""),
+			"PSBRecordLink": settings.GetString("psb_record_link", ""),
+		})
+		c.Abort()
+	}
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// AbortWithMaintenance 终止请求并返回结构化的维护响应
+// 供全站维护与单个应用维护共用，保证客户端收到的格式一致
+// message: 维护说明
+// endTime: 预计结束时间（格式 2006-01-02 15:04:05，可为空）
+func AbortWithMaintenance(c *gin.Context, message, endTime string) {
+	if endTime != "" {
+		if end, err := time.ParseInLocation("2006-01-02 15:04:05", endTime, time.Local); err == nil {
+			if seconds := int(time.Until(end).Seconds()); seconds > 0 {
+				c.Header("Retry-After", strconv.Itoa(seconds))
+			}
+		}
+	}
+	c.AbortWithStatusJSON(constants.CodeMaintenance.Status, utils.NewResponse(c, constants.CodeMaintenance, message, gin.H{
+		"maintenance": true,
+		"message":     message,
+		"end_time":    endTime,
+	}))
+}
+
+// ExemptFromMaintenance 登记不受维护模式影响的路径
+// 需要在服务启动、注册路由时调用
+func ExemptFromMaintenance(path string) {
+	maintenanceExemptPaths[path] = true
+}
+
+// CheckAppMaintenance 检查单个应用是否处于维护状态
+// - 全站维护由 MaintenanceMiddleware 统一处理，这里只处理应用级维护开关
+// - 应用处于维护中时写入维护响应并返回 true，调用方应立即返回
+func CheckAppMaintenance(c *gin.Context, appMaintenance int, appMessage string) bool {
+	if appMaintenance != 1 {
+		return false
+	}
+	message := appMessage
+	if message == "" {
+		message = services.GetSettingsService().GetMaintenanceMessage()
+	}
+	AbortWithMaintenance(c, message, "")
+	return true
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// isMaintenanceExemptPath 判断路径是否不受维护模式影响
+func isMaintenanceExemptPath(path string) bool {
+	if maintenanceExemptPaths[path] || utils.IsAdminPath(path) {
+		return true
+	}
+	exemptPrefixes := []string{"/static/", "/assets/", "/favicon.ico"}
+	for _, prefix := range exemptPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"networkDev/utils"
+	"networkDev/utils/logger"
+	"networkDev/utils/tracing"
+)
+
+// ============================================================================
+// 中间件函数
+// ============================================================================
+
+// Tracing 为每个HTTP请求创建span
+// - 沿用请求头中的 traceparent，使网关或客户端的链路可以延续到本服务
+// - span 以路由模板命名（如 GET /admin/api/apps），避免路径参数导致名称膨胀
+// - 请求上下文替换为带有span的上下文，数据库与Redis操作通过 c.Request.Context() 成为子span
+// - 需要挂载在日志中间件之后，才能获取到请求ID
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracing.Enabled() {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracing.Tracer().Start(ctx, fmt.Sprintf("%s %s", c.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", utils.ClientIP(c)),
+				attribute.String("user_agent.original", c.Request.UserAgent()),
+				attribute.String(logger.RequestIDKey, logger.RequestID(c)),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+	}
+}
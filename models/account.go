@@ -0,0 +1,112 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 账号状态
+const (
+	AccountStatusNormal = 1 // 正常
+	AccountStatusBanned = 2 // 已封禁
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// ClientBinding 客户端登录主体（卡密、账号）共用的到期时间与设备绑定信息
+// 换绑次数按应用的换绑限制统计：每天限制时 RebindDate 记录计数所属日期，跨天后重新计数
+type ClientBinding struct {
+	// EndAt：到期时间，卡密在首次登录时按面值时长计算，为空表示未激活或未充值
+	EndAt *time.Time `gorm:"index;comment:到期时间" json:"end_at"`
+	// MachineCode：绑定的机器码，开启机器验证后首次登录时绑定
+	MachineCode string `gorm:"size:128;comment:绑定的机器码" json:"machine_code"`
+	// BindIP：绑定的IP，开启IP验证后首次登录时绑定
+	BindIP string `gorm:"size:64;comment:绑定的IP" json:"bind_ip"`
+	// MachineRebinds：机器码换绑次数
+	MachineRebinds int `gorm:"default:0;not null;comment:机器码换绑次数" json:"machine_rebinds"`
+	// MachineRebindDate：机器码换绑次数所属日期（2006-01-02）
+	MachineRebindDate string `gorm:"size:10;comment:机器码换绑次数所属日期" json:"machine_rebind_date"`
+	// IPRebinds：IP换绑次数
+	IPRebinds int `gorm:"default:0;not null;comment:IP换绑次数" json:"ip_rebinds"`
+	// IPRebindDate：IP换绑次数所属日期（2006-01-02）
+	IPRebindDate string `gorm:"size:10;comment:IP换绑次数所属日期" json:"ip_rebind_date"`
+	// LastLoginAt：最近登录时间
+	LastLoginAt *time.Time `gorm:"comment:最近登录时间" json:"last_login_at"`
+	// LastLoginIP：最近登录IP
+	LastLoginIP string `gorm:"size:64;comment:最近登录IP" json:"last_login_ip"`
+}
+
+// Account 客户端账号表模型
+// 账号属于单个应用，通过客户端接口注册、登录与充值；用户名在应用内唯一
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type Account struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:账号ID，自增主键" json:"id"`
+
+	// UUID：账号唯一标识符，自动生成
+	UUID string `gorm:"uniqueIndex;size:36;not null;comment:账号UUID，唯一标识符" json:"uuid"`
+
+	// AppUUID：所属应用UUID
+	AppUUID string `gorm:"uniqueIndex:idx_accounts_app_username;size:36;not null;comment:所属应用UUID" json:"app_uuid"`
+
+	// Username：用户名，应用内唯一
+	Username string `gorm:"uniqueIndex:idx_accounts_app_username;size:64;not null;comment:用户名，应用内唯一" json:"username"`
+
+	// Password/PasswordSalt：密码哈希与盐值，不返回给前端
+	Password     string `gorm:"size:255;not null;comment:密码哈希值" json:"-"`
+	PasswordSalt string `gorm:"size:64;not null;comment:密码加密盐值" json:"-"`
+
+	// Email：邮箱，用于找回密码
+	Email string `gorm:"size:255;index;comment:邮箱，用于找回密码" json:"email"`
+
+	// Status：状态（1=正常，2=已封禁）
+	Status int `gorm:"default:1;not null;comment:账号状态，1=正常，2=已封禁" json:"status"`
+
+	// BanReason：封禁原因
+	BanReason string `gorm:"size:255;comment:封禁原因" json:"ban_reason"`
+
+	// RegisterMachine/RegisterIP：注册时的机器码与IP，用于注册次数限制
+	RegisterMachine string `gorm:"size:128;index;comment:注册机器码" json:"register_machine"`
+	RegisterIP      string `gorm:"size:64;comment:注册IP" json:"register_ip"`
+
+	// TrialGrantedAt：领取试用时间，未领取为空
+	TrialGrantedAt *time.Time `gorm:"comment:领取试用时间" json:"trial_granted_at"`
+
+	// 到期时间与设备绑定
+	ClientBinding `gorm:"embedded"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// BeforeCreate 在创建记录前自动生成UUID
+func (account *Account) BeforeCreate(tx *gorm.DB) error {
+	if account.UUID == "" {
+		account.UUID = strings.ToUpper(uuid.New().String())
+	}
+	return nil
+}
+
+// TableName 指定表名
+func (Account) TableName() string {
+	return "accounts"
+}
+
+// Expired 判断到期时间是否已过，未设置到期时间视为已到期
+func (binding *ClientBinding) Expired(now time.Time) bool {
+	return binding.EndAt == nil || !now.Before(*binding.EndAt)
+}
@@ -42,6 +42,8 @@ const (
 	APITypeUpdatePwd     = 50 // 修改账号密码
 	APITypeMacChangeBind = 51 // 机器码转绑
 	APITypeIPChangeBind  = 52 // IP转绑
+	APITypeSendResetCode = 53 // 发送找回密码验证码
+	APITypeResetPwd      = 54 // 找回账号密码
 
 	// 风控操作
 	APITypeDisableUser      = 60 // 封停用户
@@ -92,14 +94,14 @@ type API struct {
 	// 提交算法公钥（明文PEM存储）
 	SubmitPublicKey string `gorm:"type:text;comment:提交算法公钥，明文PEM" json:"submit_public_key"`
 
-	// 提交算法私钥（明文PEM存储）
-	SubmitPrivateKey string `gorm:"type:text;comment:提交算法私钥，明文PEM" json:"submit_private_key"`
+	// 提交算法私钥（加密存储，读取时自动解密为明文）
+	SubmitPrivateKey string `gorm:"type:text;serializer:encrypted;comment:提交算法私钥，加密存储" json:"submit_private_key"`
 
 	// 返回算法公钥（明文PEM存储）
 	ReturnPublicKey string `gorm:"type:text;comment:返回算法公钥，明文PEM" json:"return_public_key"`
 
-	// 返回算法私钥（明文PEM存储）
-	ReturnPrivateKey string `gorm:"type:text;comment:返回算法私钥，明文PEM" json:"return_private_key"`
+	// 返回算法私钥（加密存储，读取时自动解密为明文）
+	ReturnPrivateKey string `gorm:"type:text;serializer:encrypted;comment:返回算法私钥，加密存储" json:"return_private_key"`
 
 	// 时间字段
 	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
@@ -118,6 +120,13 @@ func (api *API) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// Redact 对敏感字段进行脱敏，用于列表响应
+// 公钥可以公开分发，不做处理
+func (api *API) Redact() {
+	api.SubmitPrivateKey = RedactField(api.SubmitPrivateKey)
+	api.ReturnPrivateKey = RedactField(api.ReturnPrivateKey)
+}
+
 // TableName 指定表名
 func (API) TableName() string {
 	return "apis"
@@ -215,6 +224,8 @@ func GetAPITypes(categorized bool) interface{} {
 				{Type: APITypeUpdatePwd, Name: "修改账号密码"},
 				{Type: APITypeMacChangeBind, Name: "机器码转绑"},
 				{Type: APITypeIPChangeBind, Name: "IP转绑"},
+				{Type: APITypeSendResetCode, Name: "发送找回密码验证码"},
+				{Type: APITypeResetPwd, Name: "找回账号密码"},
 			},
 		},
 		{
@@ -0,0 +1,246 @@
+package models
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// APIKeyPrefix API密钥的固定前缀，便于在日志与代码仓库中识别泄露的密钥
+const APIKeyPrefix = "ndk_"
+
+// API密钥状态
+const (
+	APIKeyDisabled = 0 // 停用
+	APIKeyEnabled  = 1 // 启用
+)
+
+// API密钥权限范围
+// 读权限允许调用对应接口的 GET 请求，写权限同时包含读权限
+const (
+	APIKeyScopeAppsRead       = "apps:read"
+	APIKeyScopeAppsWrite      = "apps:write"
+	APIKeyScopeCardsRead      = "cards:read"
+	APIKeyScopeCardsWrite     = "cards:write"
+	APIKeyScopeUsersRead      = "users:read"
+	APIKeyScopeUsersWrite     = "users:write"
+	APIKeyScopeVariablesRead  = "variables:read"
+	APIKeyScopeVariablesWrite = "variables:write"
+	APIKeyScopeSystemRead     = "system:read"
+	APIKeyScopeSystemWrite    = "system:write"
+)
+
+// 审计日志操作者类型
+const (
+	AuditActorAdmin  = "admin"   // 管理员登录会话
+	AuditActorAPIKey = "api_key" // API密钥
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// APIKeyResource API密钥可授权的接口分组（管理后台展示）
+// Paths 为相对后台路径前缀的接口路径前缀，AppScoped 表示接口会校验密钥的应用范围
+type APIKeyResource struct {
+	Name      string   `json:"name"`
+	Title     string   `json:"title"`
+	Paths     []string `json:"paths"`
+	AppScoped bool     `json:"app_scoped"`
+}
+
+// AdminAPIKey 管理后台API密钥表模型
+// 供外部系统通过 Authorization: Bearer <密钥> 调用 /admin/api/* 接口，
+// 只保存密钥的 SHA-256 摘要，明文只在创建时返回一次
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type AdminAPIKey struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:API密钥ID，自增主键" json:"id"`
+	// Name：名称
+	Name string `gorm:"size:100;not null;comment:名称" json:"name"`
+	// Prefix：密钥开头的若干字符，用于在列表中辨认密钥
+	Prefix string `gorm:"size:16;not null;comment:密钥前缀" json:"prefix"`
+	// KeyHash：密钥的 SHA-256 摘要（十六进制）
+	KeyHash string `gorm:"size:64;not null;uniqueIndex;comment:密钥SHA-256摘要" json:"-"`
+	// Scopes：授权的权限范围，逗号分隔
+	Scopes string `gorm:"type:text;comment:权限范围，逗号分隔" json:"scopes"`
+	// AppUUIDs：允许操作的应用UUID，逗号分隔，为空表示不限制
+	AppUUIDs string `gorm:"type:text;comment:允许操作的应用UUID，逗号分隔，为空表示不限制" json:"app_uuids"`
+	// ExpiresAt：过期时间，为空表示长期有效
+	ExpiresAt *time.Time `gorm:"comment:过期时间，为空表示长期有效" json:"expires_at"`
+	// LastUsedAt：最近使用时间
+	LastUsedAt *time.Time `gorm:"comment:最近使用时间" json:"last_used_at"`
+	// LastUsedIP：最近使用的IP
+	LastUsedIP string `gorm:"size:64;comment:最近使用的IP" json:"last_used_ip"`
+	// Status：状态（1=启用，0=停用）
+	Status int `gorm:"default:0;not null;comment:状态，1=启用，0=停用" json:"status"`
+	// Remark：备注
+	Remark string `gorm:"type:text;comment:备注" json:"remark"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// AuditLog 审计日志表模型
+// 记录管理员会话的写操作与API密钥的全部调用
+type AuditLog struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:审计日志ID，自增主键" json:"id"`
+	// ActorType：操作者类型（admin/api_key）
+	ActorType string `gorm:"size:16;not null;index;comment:操作者类型，admin=管理员，api_key=API密钥" json:"actor_type"`
+	// Actor：操作者名称，管理员为用户名，API密钥为密钥名称
+	Actor string `gorm:"size:100;comment:操作者名称" json:"actor"`
+	// APIKeyID：API密钥ID，管理员操作为0
+	APIKeyID uint `gorm:"default:0;not null;index;comment:API密钥ID，管理员操作为0" json:"api_key_id"`
+	// Method：请求方法
+	Method string `gorm:"size:8;not null;comment:请求方法" json:"method"`
+	// Path：请求路径
+	Path string `gorm:"size:255;not null;comment:请求路径" json:"path"`
+	// Status：响应状态码
+	Status int `gorm:"default:0;not null;comment:响应状态码" json:"status"`
+	// IP：客户端IP
+	IP string `gorm:"size:64;comment:客户端IP" json:"ip"`
+	// RequestID：请求ID，可用于关联服务端日志
+	RequestID string `gorm:"size:64;comment:请求ID" json:"request_id"`
+	// DurationMs：处理耗时（毫秒）
+	DurationMs int64 `gorm:"default:0;not null;comment:处理耗时，单位毫秒" json:"duration_ms"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"index;comment:创建时间" json:"created_at"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// APIKeyResources API密钥可授权的接口分组
+// 未列出的接口（API密钥管理、审计日志、管理员资料与页面）不允许使用API密钥访问
+var APIKeyResources = []APIKeyResource{
+	{Name: "apps", Title: "应用与接口", Paths: []string{"/api/apps", "/api/apis"}},
+	{Name: "cards", Title: "卡密", Paths: []string{"/api/cards"}, AppScoped: true},
+	{Name: "users", Title: "用户", Paths: []string{"/api/users"}},
+	{Name: "variables", Title: "变量与函数", Paths: []string{"/variable", "/function"}},
+	{Name: "system", Title: "系统管理", Paths: []string{"/api/settings", "/api/jobs", "/api/webhooks", "/api/notify", "/api/mail", "/api/system", "/api/dashboard"}},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// IsAPIKeyScope 判断是否为有效的权限范围
+func IsAPIKeyScope(scope string) bool {
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || (access != "read" && access != "write") {
+		return false
+	}
+	_, found := findAPIKeyResource(resource)
+	return found
+}
+
+// IsAppScopedAPIKeyScope 判断权限范围对应的接口是否校验应用范围
+func IsAppScopedAPIKeyScope(scope string) bool {
+	name, _, _ := strings.Cut(scope, ":")
+	resource, found := findAPIKeyResource(name)
+	return found && resource.AppScoped
+}
+
+// APIKeyScopeForRequest 获取调用接口所需的权限范围
+// path 为相对后台路径前缀的请求路径；GET/HEAD 请求需要读权限，其余请求需要写权限
+func APIKeyScopeForRequest(method, path string) (string, bool) {
+	access := "write"
+	if method == http.MethodGet || method == http.MethodHead {
+		access = "read"
+	}
+	for _, resource := range APIKeyResources {
+		for _, prefix := range resource.Paths {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return resource.Name + ":" + access, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (AdminAPIKey) TableName() string {
+	return "admin_api_keys"
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// ScopeList 获取授权的权限范围列表
+func (key *AdminAPIKey) ScopeList() []string {
+	return splitList(key.Scopes)
+}
+
+// AppList 获取允许操作的应用UUID列表，为空表示不限制
+func (key *AdminAPIKey) AppList() []string {
+	return splitList(key.AppUUIDs)
+}
+
+// HasScope 判断是否拥有指定权限范围，写权限包含读权限
+func (key *AdminAPIKey) HasScope(scope string) bool {
+	resource, access, _ := strings.Cut(scope, ":")
+	for _, granted := range key.ScopeList() {
+		if granted == scope || (access == "read" && granted == resource+":write") {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsApp 判断是否允许操作指定应用
+func (key *AdminAPIKey) AllowsApp(appUUID string) bool {
+	apps := key.AppList()
+	if len(apps) == 0 {
+		return true
+	}
+	for _, uuid := range apps {
+		if strings.EqualFold(uuid, appUUID) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired 判断密钥是否已过期
+func (key *AdminAPIKey) Expired(now time.Time) bool {
+	return key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// findAPIKeyResource 按名称查找接口分组
+func findAPIKeyResource(name string) (APIKeyResource, bool) {
+	for _, resource := range APIKeyResources {
+		if resource.Name == name {
+			return resource, true
+		}
+	}
+	return APIKeyResource{}, false
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,131 @@
+package models_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"networkDev/models"
+)
+
+// TestAPIKeyScopeForRequest 请求路径映射到接口分组，GET/HEAD 需要读权限，其余需要写权限
+func TestAPIKeyScopeForRequest(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/api/apps", models.APIKeyScopeAppsRead},
+		{http.MethodHead, "/api/apps/list", models.APIKeyScopeAppsRead},
+		{http.MethodPost, "/api/apps/create", models.APIKeyScopeAppsWrite},
+		{http.MethodPost, "/api/apis/update", models.APIKeyScopeAppsWrite},
+		{http.MethodGet, "/api/cards/list", models.APIKeyScopeCardsRead},
+		{http.MethodDelete, "/api/cards/:id", models.APIKeyScopeCardsWrite},
+		{http.MethodGet, "/api/users/list", models.APIKeyScopeUsersRead},
+		{http.MethodPut, "/variable/update", models.APIKeyScopeVariablesWrite},
+		{http.MethodGet, "/function/list", models.APIKeyScopeVariablesRead},
+		{http.MethodPost, "/api/jobs/run", models.APIKeyScopeSystemWrite},
+		{http.MethodGet, "/api/dashboard/stats", models.APIKeyScopeSystemRead},
+	}
+	for _, tc := range cases {
+		scope, ok := models.APIKeyScopeForRequest(tc.method, tc.path)
+		if !ok || scope != tc.want {
+			t.Errorf("%s %s = %q, %v，期望 %q", tc.method, tc.path, scope, ok, tc.want)
+		}
+	}
+}
+
+// TestAPIKeyScopeForRequestDenied 未列出的接口与仅前缀相似的路径不映射到任何权限范围
+func TestAPIKeyScopeForRequestDenied(t *testing.T) {
+	paths := []string{
+		"/api/apikeys/list",
+		"/api/audit/list",
+		"/api/appsx",
+		"/variables",
+		"/functions",
+		"/dashboard",
+		"/",
+		"",
+	}
+	for _, path := range paths {
+		if scope, ok := models.APIKeyScopeForRequest(http.MethodGet, path); ok {
+			t.Errorf("GET %s = %q，期望不允许访问", path, scope)
+		}
+	}
+}
+
+// TestIsAPIKeyScope 只接受已知接口分组的 read/write 权限
+func TestIsAPIKeyScope(t *testing.T) {
+	for _, resource := range models.APIKeyResources {
+		for _, scope := range []string{resource.Name + ":read", resource.Name + ":write"} {
+			if !models.IsAPIKeyScope(scope) {
+				t.Errorf("IsAPIKeyScope(%q) = false", scope)
+			}
+		}
+	}
+	for _, scope := range []string{"", "apps", "apps:", "apps:admin", "apikeys:read", ":read", "Apps:read"} {
+		if models.IsAPIKeyScope(scope) {
+			t.Errorf("IsAPIKeyScope(%q) = true", scope)
+		}
+	}
+
+	if !models.IsAppScopedAPIKeyScope(models.APIKeyScopeCardsWrite) {
+		t.Error("卡密权限应校验应用范围")
+	}
+	if models.IsAppScopedAPIKeyScope(models.APIKeyScopeAppsWrite) || models.IsAppScopedAPIKeyScope("unknown:read") {
+		t.Error("只有卡密权限校验应用范围")
+	}
+}
+
+// TestAdminAPIKeyHasScope 写权限包含读权限，读权限不包含写权限
+func TestAdminAPIKeyHasScope(t *testing.T) {
+	key := &models.AdminAPIKey{Scopes: " cards:write, apps:read ,,"}
+
+	granted := []string{models.APIKeyScopeCardsWrite, models.APIKeyScopeCardsRead, models.APIKeyScopeAppsRead}
+	for _, scope := range granted {
+		if !key.HasScope(scope) {
+			t.Errorf("HasScope(%q) = false", scope)
+		}
+	}
+	denied := []string{models.APIKeyScopeAppsWrite, models.APIKeyScopeUsersRead, models.APIKeyScopeSystemWrite, ""}
+	for _, scope := range denied {
+		if key.HasScope(scope) {
+			t.Errorf("HasScope(%q) = true", scope)
+		}
+	}
+	if got := key.ScopeList(); len(got) != 2 {
+		t.Errorf("ScopeList = %q，期望 2 项", got)
+	}
+}
+
+// TestAdminAPIKeyAllowsApp 应用范围为空时不限制，否则按UUID匹配（忽略大小写）
+func TestAdminAPIKeyAllowsApp(t *testing.T) {
+	unrestricted := &models.AdminAPIKey{}
+	if !unrestricted.AllowsApp("any-app") {
+		t.Error("未限制应用范围时应允许任意应用")
+	}
+
+	key := &models.AdminAPIKey{AppUUIDs: "AAAA-1111, bbbb-2222"}
+	if !key.AllowsApp("aaaa-1111") || !key.AllowsApp("BBBB-2222") {
+		t.Error("应允许范围内的应用")
+	}
+	if key.AllowsApp("cccc-3333") || key.AllowsApp("") {
+		t.Error("不应允许范围外的应用")
+	}
+}
+
+// TestAdminAPIKeyExpired 到达过期时间即视为过期，未设置过期时间长期有效
+func TestAdminAPIKeyExpired(t *testing.T) {
+	now := time.Now()
+	if (&models.AdminAPIKey{}).Expired(now) {
+		t.Error("未设置过期时间不应过期")
+	}
+	expiresAt := now.Add(time.Minute)
+	key := &models.AdminAPIKey{ExpiresAt: &expiresAt}
+	if key.Expired(now) {
+		t.Error("过期时间之前不应过期")
+	}
+	if !key.Expired(expiresAt) {
+		t.Error("到达过期时间应视为过期")
+	}
+}
@@ -26,8 +26,8 @@ type App struct {
 	Status int `gorm:"default:0;not null;comment:应用状态，1=启用，0=禁用" json:"status"`
 	// Name：应用名称；json 名称与前端一致
 	Name string `gorm:"size:100;not null;comment:应用名称" json:"name"`
-	// Secret：应用密钥，用于API认证
-	Secret string `gorm:"size:255;not null;comment:应用密钥，用于API认证" json:"secret"`
+	// Secret：应用密钥，用于API认证（加密存储，读取时自动解密为明文）
+	Secret string `gorm:"type:text;not null;serializer:encrypted;comment:应用密钥，用于API认证，加密存储" json:"secret"`
 	// Version：应用版本号
 	Version string `gorm:"size:50;default:'1.0.0';comment:应用版本号" json:"version"`
 	// ForceUpdate：强制更新（0=不开启，1=开启）
@@ -100,6 +100,12 @@ type App struct {
 	// TrialDuration：试用时间（单位：分钟）
 	TrialDuration int `gorm:"default:0;not null;comment:试用时间，单位分钟" json:"trial_duration"`
 
+	// 应用维护相关字段
+	// MaintenanceMode：应用维护开关（0=关闭，1=开启），开启后仅暂停该应用的客户端接口
+	MaintenanceMode int `gorm:"default:0;not null;comment:应用维护开关，0=关闭，1=开启" json:"maintenance_mode"`
+	// MaintenanceMessage：应用维护说明，留空则使用系统维护说明
+	MaintenanceMessage string `gorm:"size:500;comment:应用维护说明" json:"maintenance_message"`
+
 	// CreatedAt/UpdatedAt：时间字段，返回为 created_at/updated_at，便于前端展示
 	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
 	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
@@ -123,6 +129,11 @@ func (app *App) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// Redact 对敏感字段进行脱敏，用于列表响应
+func (app *App) Redact() {
+	app.Secret = RedactField(app.Secret)
+}
+
 // TableName 指定表名
 func (App) TableName() string {
 	return "apps"
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 黑名单类型
+const (
+	BlacklistTypeMachine = 1 // 机器码
+	BlacklistTypeIP      = 2 // IP
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Blacklist 黑名单表模型
+// 由客户端「添加黑名单」接口写入，列入黑名单的机器码或IP不能再登录、注册该应用
+type Blacklist struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:黑名单ID，自增主键" json:"id"`
+
+	// AppUUID：所属应用UUID
+	AppUUID string `gorm:"uniqueIndex:idx_blacklists_entry;size:36;not null;comment:所属应用UUID" json:"app_uuid"`
+
+	// Type：类型（1=机器码，2=IP）
+	Type int `gorm:"uniqueIndex:idx_blacklists_entry;not null;comment:类型，1=机器码，2=IP" json:"type"`
+
+	// Value：机器码或IP
+	Value string `gorm:"uniqueIndex:idx_blacklists_entry;size:128;not null;comment:机器码或IP" json:"value"`
+
+	// Reason：加入原因
+	Reason string `gorm:"size:255;comment:加入原因" json:"reason"`
+
+	// CreatedAt：加入时间
+	CreatedAt time.Time `gorm:"comment:加入时间" json:"created_at"`
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (Blacklist) TableName() string {
+	return "blacklists"
+}
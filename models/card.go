@@ -0,0 +1,125 @@
+package models
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 卡密状态
+const (
+	CardStatusUnused   = 0 // 未使用
+	CardStatusUsed     = 1 // 已使用
+	CardStatusDisabled = 2 // 已禁用
+	CardStatusExpired  = 3 // 已过期（超过有效期未使用）
+)
+
+// cardKeyCharset 卡密字符集（去除易混淆的 0/O、1/I/L）
+const cardKeyCharset = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// CardKeyLength 卡密长度
+const CardKeyLength = 24
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Card 卡密表模型
+// 用于管理应用的充值卡密，Duration 为卡密面值时长
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type Card struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:卡密ID，自增主键" json:"id"`
+	// UUID：卡密唯一标识符，自动生成
+	UUID string `gorm:"uniqueIndex;size:36;not null;comment:卡密UUID，唯一标识符" json:"uuid"`
+	// AppUUID：所属应用UUID
+	AppUUID string `gorm:"index;size:36;not null;comment:所属应用UUID" json:"app_uuid"`
+	// CardKey：卡密内容，全局唯一
+	CardKey string `gorm:"uniqueIndex;size:64;not null;comment:卡密内容" json:"card_key"`
+	// Duration：卡密时长（单位：分钟）
+	Duration int `gorm:"default:0;not null;comment:卡密时长，单位分钟" json:"duration"`
+	// Status：状态（0=未使用，1=已使用，2=已禁用，3=已过期）
+	Status int `gorm:"default:0;not null;index;comment:卡密状态，0=未使用，1=已使用，2=已禁用，3=已过期" json:"status"`
+	// BatchNo：生成批次号，便于按批次导出
+	BatchNo string `gorm:"index;size:32;comment:生成批次号" json:"batch_no"`
+	// Remark：备注信息
+	Remark string `gorm:"size:255;comment:备注信息" json:"remark"`
+	// UsedAt：使用时间
+	UsedAt *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	// ExpiresAt：有效期截止时间，超过后未使用的卡密由定时任务标记为已过期，为空表示长期有效
+	ExpiresAt *time.Time `gorm:"index;comment:有效期截止时间，为空表示长期有效" json:"expires_at"`
+	// AccountUUID：充值到的账号UUID，卡密登录使用时为空
+	AccountUUID string `gorm:"index;size:36;comment:充值到的账号UUID" json:"account_uuid"`
+	// 卡密登录的到期时间与设备绑定
+	ClientBinding `gorm:"embedded"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// BeforeCreate 在创建记录前自动生成UUID和卡密
+func (card *Card) BeforeCreate(tx *gorm.DB) error {
+	if card.UUID == "" {
+		card.UUID = strings.ToUpper(uuid.New().String())
+	}
+	if card.CardKey == "" {
+		key, err := GenerateCardKey()
+		if err != nil {
+			return err
+		}
+		card.CardKey = key
+	}
+	return nil
+}
+
+// TableName 指定表名
+func (Card) TableName() string {
+	return "cards"
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// GenerateCardKey 生成随机卡密
+func GenerateCardKey() (string, error) {
+	var sb strings.Builder
+	max := big.NewInt(int64(len(cardKeyCharset)))
+	for i := 0; i < CardKeyLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(cardKeyCharset[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// CardStatusText 获取卡密状态说明
+func CardStatusText(status int) string {
+	switch status {
+	case CardStatusUnused:
+		return "未使用"
+	case CardStatusUsed:
+		return "已使用"
+	case CardStatusDisabled:
+		return "已禁用"
+	case CardStatusExpired:
+		return "已过期"
+	default:
+		return "未知"
+	}
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"networkDev/utils"
+	"networkDev/utils/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm/schema"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// EncryptedPrefix 加密字段的存储前缀
+// 用于区分已加密的密文与历史遗留的明文数据
+const EncryptedPrefix = "enc:"
+
+// RedactedPlaceholder 敏感字段脱敏后的占位值
+const RedactedPlaceholder = "******"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// EncryptedSerializer 敏感字段加密序列化器
+// - 写入数据库时使用 AES-256-GCM 加密，并添加 enc: 前缀
+// - 模型中的值一律视为明文，以 enc: 开头的用户输入同样加密
+// - 读取时自动解密，处理器始终使用明文
+// - 没有前缀的历史明文数据原样返回，由迁移任务统一加密
+// 使用方式：在字段标签中添加 serializer:encrypted
+type EncryptedSerializer struct{}
+
+// ============================================================================
+// 初始化
+// ============================================================================
+
+func init() {
+	schema.RegisterSerializer("encrypted", EncryptedSerializer{})
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Scan 从数据库读取并解密字段值
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+		stored = ""
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("加密字段 %s 的数据类型不受支持: %T", field.Name, dbValue)
+	}
+
+	plain, err := decryptFieldTraced(ctx, field, stored)
+	if err != nil {
+		return fmt.Errorf("解密字段 %s 失败: %w", field.Name, err)
+	}
+	return field.Set(ctx, dst, plain)
+}
+
+// Value 加密字段值后写入数据库
+func (EncryptedSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plain, _ := fieldValue.(string)
+	if plain == "" {
+		return plain, nil
+	}
+
+	_, span := tracing.Start(ctx, "crypto.encrypt", fieldAttributes(field)...)
+	enc, err := EncryptField(plain)
+	tracing.End(span, err)
+	return enc, err
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// EncryptField 加密敏感字段值
+// 空字符串原样返回；其余值一律加密，调用方需自行跳过已加密的数据库值
+func EncryptField(plain string) (string, error) {
+	if plain == "" {
+		return plain, nil
+	}
+	enc, err := utils.EncryptString(plain)
+	if err != nil {
+		return "", err
+	}
+	return EncryptedPrefix + enc, nil
+}
+
+// DecryptField 解密敏感字段值
+// 没有加密前缀的值视为历史明文，原样返回
+func DecryptField(stored string) (string, error) {
+	if !IsEncryptedField(stored) {
+		return stored, nil
+	}
+	return utils.DecryptString(strings.TrimPrefix(stored, EncryptedPrefix))
+}
+
+// IsEncryptedField 判断数据库中的值是否已加密
+func IsEncryptedField(stored string) bool {
+	return strings.HasPrefix(stored, EncryptedPrefix)
+}
+
+// NeedsReencrypt 判断数据库中的值是否需要使用活动密钥重新加密
+// 历史明文与使用非活动密钥加密的值都需要重新加密
+func NeedsReencrypt(stored string) (bool, error) {
+	if stored == "" {
+		return false, nil
+	}
+	if !IsEncryptedField(stored) {
+		return true, nil
+	}
+	activeID, err := utils.ActiveKeyID()
+	if err != nil {
+		return false, err
+	}
+	return utils.CiphertextKeyID(strings.TrimPrefix(stored, EncryptedPrefix)) != activeID, nil
+}
+
+// RedactField 对敏感字段进行脱敏
+// 空值保持为空，便于前端区分“未设置”与“已隐藏”
+func RedactField(value string) string {
+	if value == "" {
+		return ""
+	}
+	return RedactedPlaceholder
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// decryptFieldTraced 解密字段值，已加密的值记录 crypto.decrypt span
+// 历史明文与空值不涉及解密，不创建span
+func decryptFieldTraced(ctx context.Context, field *schema.Field, stored string) (string, error) {
+	if !IsEncryptedField(stored) {
+		return stored, nil
+	}
+	_, span := tracing.Start(ctx, "crypto.decrypt", fieldAttributes(field)...)
+	plain, err := DecryptField(stored)
+	tracing.End(span, err)
+	return plain, err
+}
+
+// fieldAttributes 加解密span中记录的字段信息（表名与字段名，不包含字段值）
+func fieldAttributes(field *schema.Field) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("db.sql.table", field.Schema.Table),
+		attribute.String("crypto.field", field.DBName),
+	}
+}
@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 任务执行状态
+const (
+	JobRunStatusRunning = 0 // 执行中
+	JobRunStatusSuccess = 1 // 成功
+	JobRunStatusFailed  = 2 // 失败
+)
+
+// 任务触发方式
+const (
+	JobTriggerSchedule = "schedule" // 按执行计划触发
+	JobTriggerManual   = "manual"   // 后台手动触发
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Job 定时任务设置表模型
+// 保存后台对内置任务的修改（启用状态、执行计划），没有记录的任务使用默认设置
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type Job struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:任务设置ID，自增主键" json:"id"`
+	// Name：任务名称，对应内置任务
+	Name string `gorm:"uniqueIndex;size:64;not null;comment:任务名称" json:"name"`
+	// Enabled：是否启用
+	Enabled bool `gorm:"not null;comment:是否启用" json:"enabled"`
+	// Cron：执行计划，为空时使用任务默认执行计划
+	Cron string `gorm:"size:64;comment:执行计划，为空时使用默认执行计划" json:"cron"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// JobRun 任务执行记录表模型
+// 每次执行开始时写入一条执行中的记录，结束后更新状态、耗时与错误信息
+type JobRun struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:执行记录ID，自增主键" json:"id"`
+	// JobName：任务名称
+	JobName string `gorm:"index;size:64;not null;comment:任务名称" json:"job_name"`
+	// Trigger：触发方式（schedule=按计划，manual=手动）
+	Trigger string `gorm:"size:16;not null;comment:触发方式，schedule=按计划，manual=手动" json:"trigger"`
+	// Instance：执行任务的实例ID
+	Instance string `gorm:"size:36;comment:执行任务的实例ID" json:"instance"`
+	// Status：执行状态（0=执行中，1=成功，2=失败）
+	Status int `gorm:"default:0;not null;index;comment:执行状态，0=执行中，1=成功，2=失败" json:"status"`
+	// Result：执行结果摘要
+	Result string `gorm:"size:255;comment:执行结果摘要" json:"result"`
+	// Error：失败原因
+	Error string `gorm:"type:text;comment:失败原因" json:"error"`
+	// StartedAt：开始时间
+	StartedAt time.Time `gorm:"index;not null;comment:开始时间" json:"started_at"`
+	// FinishedAt：结束时间
+	FinishedAt *time.Time `gorm:"comment:结束时间" json:"finished_at"`
+	// DurationMs：耗时（毫秒）
+	DurationMs int64 `gorm:"default:0;not null;comment:耗时，单位毫秒" json:"duration_ms"`
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// TableName 指定表名
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// JobRunStatusText 获取任务执行状态说明
+func JobRunStatusText(status int) string {
+	switch status {
+	case JobRunStatusRunning:
+		return "执行中"
+	case JobRunStatusSuccess:
+		return "成功"
+	case JobRunStatusFailed:
+		return "失败"
+	default:
+		return "未知"
+	}
+}
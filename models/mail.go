@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 邮件模板名称
+const (
+	MailTemplateTest           = "test"            // 测试邮件
+	MailTemplateAdminAlert     = "admin_alert"     // 管理员告警
+	MailTemplatePasswordReset  = "password_reset"  // 找回密码验证码
+	MailTemplateExpiryReminder = "expiry_reminder" // 卡密到期提醒
+)
+
+// 邮件发送状态
+const (
+	MailStatusPending = 0 // 等待发送（含等待重试）
+	MailStatusSent    = 1 // 已发送
+	MailStatusFailed  = 2 // 发送失败（已达最大尝试次数）
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// MailTemplateOption 邮件模板说明（管理后台展示）
+type MailTemplateOption struct {
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Variables string `json:"variables"` // 模板中可用的变量说明
+}
+
+// MailMessage 邮件发送队列表模型
+// 每个收件人一条记录，内容在入队时渲染，重试时不再读取模板
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type MailMessage struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:邮件ID，自增主键" json:"id"`
+	// Template：使用的模板名称
+	Template string `gorm:"size:64;index;comment:模板名称" json:"template"`
+	// To：收件人地址
+	To string `gorm:"size:255;not null;comment:收件人地址" json:"to"`
+	// Subject：邮件主题
+	Subject string `gorm:"size:255;comment:邮件主题" json:"subject"`
+	// HTMLBody：HTML 正文
+	HTMLBody string `gorm:"type:text;comment:HTML正文" json:"html_body"`
+	// TextBody：纯文本正文
+	TextBody string `gorm:"type:text;comment:纯文本正文" json:"text_body"`
+	// Status：发送状态（0=等待发送，1=已发送，2=失败）
+	Status int `gorm:"default:0;not null;index;comment:发送状态，0=等待发送，1=已发送，2=失败" json:"status"`
+	// Attempts：已尝试次数
+	Attempts int `gorm:"default:0;not null;comment:已尝试次数" json:"attempts"`
+	// Error：最近一次失败原因
+	Error string `gorm:"type:text;comment:最近一次失败原因" json:"error"`
+	// NextRetryAt：下次发送时间，已结束的记录为空
+	NextRetryAt *time.Time `gorm:"index;comment:下次发送时间" json:"next_retry_at"`
+	// SentAt：发送成功时间
+	SentAt *time.Time `gorm:"comment:发送成功时间" json:"sent_at"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"index;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// MailTemplateOptions 内置邮件模板
+// 所有模板都可以使用 {{.SiteTitle}}（站点标题）与 {{.Now}}（发送时间）
+var MailTemplateOptions = []MailTemplateOption{
+	{Name: MailTemplateTest, Title: "测试邮件", Variables: "无"},
+	{Name: MailTemplateAdminAlert, Title: "管理员告警", Variables: "{{.Title}} 标题、{{.Severity}} 级别、{{.Lines}} 正文（每项一行）"},
+	{Name: MailTemplatePasswordReset, Title: "找回密码验证码", Variables: "{{.Username}} 用户名、{{.Code}} 验证码、{{.Minutes}} 有效分钟数"},
+	{Name: MailTemplateExpiryReminder, Title: "卡密到期提醒", Variables: "{{.Days}} 提醒天数、{{.Total}} 卡密总数、{{.Apps}} 按应用汇总（.Name 应用名称、.Count 数量、.Earliest 最早到期时间）"},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// IsMailTemplate 判断是否为内置邮件模板
+func IsMailTemplate(name string) bool {
+	for _, option := range MailTemplateOptions {
+		if option.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (MailMessage) TableName() string {
+	return "mail_queue"
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 通知渠道类型
+const (
+	NotifyChannelDingTalk = "dingtalk" // 钉钉群机器人
+	NotifyChannelWeCom    = "wecom"    // 企业微信群机器人
+	NotifyChannelFeishu   = "feishu"   // 飞书群机器人
+	NotifyChannelTelegram = "telegram" // Telegram Bot
+)
+
+// 通知级别
+const (
+	NotifySeverityInfo     = 1 // 提示
+	NotifySeverityWarning  = 2 // 警告
+	NotifySeverityCritical = 3 // 严重
+)
+
+// 通知事件类型
+const (
+	NotifyEventAdminLoginNewIP     = "admin_login_new_ip"    // 管理员从新IP登录
+	NotifyEventDatabaseHealth      = "database_health"       // 数据库健康检查失败/恢复
+	NotifyEventClientLoginFailures = "client_login_failures" // 客户端登录失败次数激增
+	NotifyEventDailySummary        = "daily_summary"         // 每日汇总
+	NotifyEventTest                = "test"                  // 后台发送的测试通知
+)
+
+// 通知渠道状态
+const (
+	NotifyChannelDisabled = 0 // 停用
+	NotifyChannelEnabled  = 1 // 启用
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// NotifyOption 通知渠道类型、事件或级别选项（管理后台展示）
+type NotifyOption struct {
+	Value string `json:"value"`
+	Title string `json:"title"`
+}
+
+// NotifyChannel 通知渠道表模型
+// 按事件类型与最低级别把告警推送到群机器人，每个渠道独立限制发送频率
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type NotifyChannel struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:通知渠道ID，自增主键" json:"id"`
+	// Name：名称
+	Name string `gorm:"size:100;not null;comment:名称" json:"name"`
+	// Type：渠道类型（dingtalk/wecom/feishu/telegram）
+	Type string `gorm:"size:16;not null;comment:渠道类型，dingtalk/wecom/feishu/telegram" json:"type"`
+	// URL：机器人 Webhook 地址；Telegram 为 Bot API 地址，留空使用官方地址
+	URL string `gorm:"size:500;comment:机器人Webhook地址，Telegram为Bot API地址" json:"url"`
+	// Secret：钉钉/飞书的签名密钥、Telegram 的 Bot Token（加密存储，读取时自动解密为明文）
+	Secret string `gorm:"type:text;serializer:encrypted;comment:签名密钥或Bot Token，加密存储" json:"secret"`
+	// ChatID：Telegram 的会话ID
+	ChatID string `gorm:"size:64;comment:Telegram会话ID" json:"chat_id"`
+	// Events：订阅的事件，逗号分隔
+	Events string `gorm:"type:text;comment:订阅的事件，逗号分隔" json:"events"`
+	// MinSeverity：最低通知级别（1=提示，2=警告，3=严重）
+	MinSeverity int `gorm:"default:1;not null;comment:最低通知级别，1=提示，2=警告，3=严重" json:"min_severity"`
+	// RateLimit：每分钟最多发送条数，超出的通知被丢弃
+	RateLimit int `gorm:"default:20;not null;comment:每分钟最多发送条数" json:"rate_limit"`
+	// Status：状态（1=启用，0=停用）
+	Status int `gorm:"default:0;not null;comment:状态，1=启用，0=停用" json:"status"`
+	// Remark：备注
+	Remark string `gorm:"type:text;comment:备注" json:"remark"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// NotifyChannelTypes 支持的通知渠道类型
+var NotifyChannelTypes = []NotifyOption{
+	{Value: NotifyChannelDingTalk, Title: "钉钉"},
+	{Value: NotifyChannelWeCom, Title: "企业微信"},
+	{Value: NotifyChannelFeishu, Title: "飞书"},
+	{Value: NotifyChannelTelegram, Title: "Telegram"},
+}
+
+// NotifyEventOptions 可订阅的通知事件
+var NotifyEventOptions = []NotifyOption{
+	{Value: NotifyEventAdminLoginNewIP, Title: "管理员新IP登录"},
+	{Value: NotifyEventDatabaseHealth, Title: "数据库健康检查"},
+	{Value: NotifyEventClientLoginFailures, Title: "客户端登录失败激增"},
+	{Value: NotifyEventDailySummary, Title: "每日汇总"},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// IsNotifyChannelType 判断是否为支持的通知渠道类型
+func IsNotifyChannelType(channelType string) bool {
+	for _, option := range NotifyChannelTypes {
+		if option.Value == channelType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotifyEvent 判断是否为可订阅的通知事件
+func IsNotifyEvent(event string) bool {
+	for _, option := range NotifyEventOptions {
+		if option.Value == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifySeverityText 获取通知级别说明
+func NotifySeverityText(severity int) string {
+	switch severity {
+	case NotifySeverityInfo:
+		return "提示"
+	case NotifySeverityWarning:
+		return "警告"
+	case NotifySeverityCritical:
+		return "严重"
+	default:
+		return "未知"
+	}
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (NotifyChannel) TableName() string {
+	return "notify_channels"
+}
+
+// Subscribes 是否接收指定事件与级别的通知
+func (channel *NotifyChannel) Subscribes(event string, severity int) bool {
+	if severity < channel.MinSeverity {
+		return false
+	}
+	for _, subscribed := range strings.Split(channel.Events, ",") {
+		if strings.TrimSpace(subscribed) == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// Webhook 事件类型
+const (
+	WebhookEventCardActivated     = "card.activated"     // 卡密激活
+	WebhookEventAccountRegistered = "account.registered" // 账号注册
+	WebhookEventAccountRecharged  = "account.recharged"  // 账号充值
+	WebhookEventDeviceRebind      = "device.rebind"      // 机器/IP重绑
+	WebhookEventAccountBanned     = "account.banned"     // 账号封禁
+	WebhookEventBlacklistAdded    = "blacklist.added"    // 加入黑名单
+	WebhookEventAppStatusChanged  = "app.status_changed" // 应用状态变更
+	WebhookEventAdminLogin        = "admin.login"        // 管理员登录
+	WebhookEventTest              = "webhook.test"       // 后台发送的测试事件
+)
+
+// Webhook 状态
+const (
+	WebhookStatusDisabled = 0 // 停用
+	WebhookStatusEnabled  = 1 // 启用
+)
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending = 0 // 等待投递（包括等待重试）
+	WebhookDeliverySuccess = 1 // 投递成功
+	WebhookDeliveryFailed  = 2 // 重试次数用尽，投递失败
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// WebhookEventOption 可订阅的事件（管理后台展示）
+type WebhookEventOption struct {
+	Event string `json:"event"`
+	Title string `json:"title"`
+}
+
+// Webhook 推送地址表模型
+// 订阅指定应用（或全部应用）的业务事件，事件发生时以签名的JSON推送到 URL
+// CreatedAt/UpdatedAt 由 GORM 自动维护
+type Webhook struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:Webhook ID，自增主键" json:"id"`
+	// UUID：唯一标识符，自动生成
+	UUID string `gorm:"uniqueIndex;size:36;not null;comment:Webhook UUID，唯一标识符" json:"uuid"`
+	// AppUUID：订阅的应用，"0"表示订阅全部应用的事件
+	AppUUID string `gorm:"index;size:36;not null;default:'0';comment:订阅的应用UUID，0表示全部应用" json:"app_uuid"`
+	// Name：名称
+	Name string `gorm:"size:100;not null;comment:名称" json:"name"`
+	// URL：推送地址
+	URL string `gorm:"size:500;not null;comment:推送地址" json:"url"`
+	// Secret：签名密钥（加密存储，读取时自动解密为明文）
+	Secret string `gorm:"type:text;not null;serializer:encrypted;comment:签名密钥，加密存储" json:"secret"`
+	// Events：订阅的事件，逗号分隔
+	Events string `gorm:"type:text;comment:订阅的事件，逗号分隔" json:"events"`
+	// Status：状态（1=启用，0=停用）
+	Status int `gorm:"default:0;not null;comment:状态，1=启用，0=停用" json:"status"`
+	// Remark：备注
+	Remark string `gorm:"type:text;comment:备注" json:"remark"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// WebhookDelivery Webhook 投递记录表模型
+// 事件发生时为每个订阅的 Webhook 写入一条记录，投递失败时按指数退避重试
+type WebhookDelivery struct {
+	// ID：主键，自增
+	ID uint `gorm:"primaryKey;comment:投递记录ID，自增主键" json:"id"`
+	// UUID：投递唯一标识符，通过 X-Webhook-ID 请求头发送，重试时保持不变，接收方可据此去重
+	UUID string `gorm:"uniqueIndex;size:36;not null;comment:投递UUID，唯一标识符" json:"uuid"`
+	// WebhookID：所属 Webhook
+	WebhookID uint `gorm:"index;not null;comment:所属Webhook ID" json:"webhook_id"`
+	// Event：事件类型
+	Event string `gorm:"size:64;not null;comment:事件类型" json:"event"`
+	// Payload：推送的JSON内容
+	Payload string `gorm:"type:text;comment:推送的JSON内容" json:"payload"`
+	// Status：投递状态（0=等待投递，1=成功，2=失败）
+	Status int `gorm:"default:0;not null;index;comment:投递状态，0=等待投递，1=成功，2=失败" json:"status"`
+	// Attempts：已尝试次数
+	Attempts int `gorm:"default:0;not null;comment:已尝试次数" json:"attempts"`
+	// ResponseCode：最近一次尝试的HTTP状态码，0 表示未收到响应
+	ResponseCode int `gorm:"default:0;not null;comment:最近一次尝试的HTTP状态码" json:"response_code"`
+	// ResponseBody：最近一次尝试的响应内容（截断）
+	ResponseBody string `gorm:"type:text;comment:最近一次尝试的响应内容" json:"response_body"`
+	// Error：最近一次尝试的失败原因
+	Error string `gorm:"type:text;comment:最近一次尝试的失败原因" json:"error"`
+	// DurationMs：最近一次尝试的耗时（毫秒）
+	DurationMs int64 `gorm:"default:0;not null;comment:最近一次尝试的耗时，单位毫秒" json:"duration_ms"`
+	// NextRetryAt：下次投递时间，投递结束后为空
+	NextRetryAt *time.Time `gorm:"index;comment:下次投递时间" json:"next_retry_at"`
+
+	// 时间字段
+	CreatedAt time.Time `gorm:"index;comment:创建时间" json:"created_at"`
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// WebhookEventOptions 可订阅的事件列表
+var WebhookEventOptions = []WebhookEventOption{
+	{Event: WebhookEventCardActivated, Title: "卡密激活"},
+	{Event: WebhookEventAccountRegistered, Title: "账号注册"},
+	{Event: WebhookEventAccountRecharged, Title: "账号充值"},
+	{Event: WebhookEventDeviceRebind, Title: "机器/IP重绑"},
+	{Event: WebhookEventAccountBanned, Title: "账号封禁"},
+	{Event: WebhookEventBlacklistAdded, Title: "加入黑名单"},
+	{Event: WebhookEventAppStatusChanged, Title: "应用状态变更"},
+	{Event: WebhookEventAdminLogin, Title: "管理员登录"},
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Redact 对敏感字段进行脱敏，用于列表响应
+func (webhook *Webhook) Redact() {
+	webhook.Secret = RedactField(webhook.Secret)
+}
+
+// GenerateWebhookSecret 生成64位16进制随机签名密钥
+func GenerateWebhookSecret() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// IsWebhookEvent 判断是否为可订阅的事件
+func IsWebhookEvent(event string) bool {
+	for _, option := range WebhookEventOptions {
+		if option.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// TableName 指定表名
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// TableName 指定表名
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// BeforeCreate 在创建记录前自动生成UUID和签名密钥
+func (webhook *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if webhook.UUID == "" {
+		webhook.UUID = strings.ToUpper(uuid.New().String())
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = GenerateWebhookSecret()
+	}
+	return nil
+}
+
+// BeforeCreate 在创建记录前自动生成UUID
+func (delivery *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if delivery.UUID == "" {
+		delivery.UUID = uuid.New().String()
+	}
+	return nil
+}
+
+// EventList 订阅的事件列表
+func (webhook *Webhook) EventList() []string {
+	var events []string
+	for _, event := range strings.Split(webhook.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Subscribes 是否订阅了指定事件
+func (webhook *Webhook) Subscribes(event string) bool {
+	for _, subscribed := range webhook.EventList() {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// ignoredRoutePrefixes 不需要登记的路由（静态资源）
+var ignoredRoutePrefixes = []string{"/static/", "/assets/", "/favicon.ico"}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Check 比对路由表与接口登记表
+// 返回已注册但未登记的路由（undocumented）与已登记但未注册的接口（stale），格式为 "METHOD /path"
+func Check(routes gin.RoutesInfo, opts Options) (undocumented, stale []string) {
+	registered := map[string]bool{}
+	for _, route := range routes {
+		if route.Method == "HEAD" || isIgnoredRoute(route.Path) {
+			continue
+		}
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	documented := map[string]bool{}
+	for _, ep := range Endpoints(opts) {
+		key := ep.Method + " " + ep.FullPath(opts.AdminPrefix)
+		documented[key] = true
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	for key := range registered {
+		if !documented[key] {
+			undocumented = append(undocumented, key)
+		}
+	}
+
+	sort.Strings(undocumented)
+	sort.Strings(stale)
+	return undocumented, stale
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// isIgnoredRoute 判断路由是否不需要登记
+func isIgnoredRoute(path string) bool {
+	for _, prefix := range ignoredRoutePrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"networkDev/openapi"
+	"networkDev/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// TestOperationsCoverRoutes 每个注册的路由都必须在 operations.go 中登记，登记的接口也必须存在
+// 覆盖默认部署、自定义后台前缀、独立后台监听地址三种路由布局
+func TestOperationsCoverRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name        string
+		adminPrefix string
+		adminListen string
+	}{
+		{name: "default"},
+		{name: "custom_prefix", adminPrefix: "/console"},
+		{name: "separate_admin_listener", adminListen: "127.0.0.1:0"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("server.admin.prefix", tc.adminPrefix)
+			viper.Set("server.admin.listen", tc.adminListen)
+			viper.Set("metrics.enabled", true)
+
+			router := gin.New()
+			server.RegisterRoutes(router)
+			routes := router.Routes()
+			if tc.adminListen != "" {
+				// 独立监听时后台路由注册在单独的引擎上
+				adminRouter := gin.New()
+				server.RegisterAdminRoutes(adminRouter)
+				routes = append(routes, adminRouter.Routes()...)
+			}
+
+			opts := openapi.Options{AdminPrefix: tc.adminPrefix, MetricsPath: "/metrics"}
+			if opts.AdminPrefix == "" {
+				opts.AdminPrefix = "/admin"
+			}
+
+			undocumented, stale := openapi.Check(routes, opts)
+			for _, route := range undocumented {
+				t.Errorf("路由未在 openapi/operations.go 中登记: %s", route)
+			}
+			for _, route := range stale {
+				t.Errorf("已登记的接口没有注册路由: %s", route)
+			}
+		})
+	}
+}
@@ -0,0 +1,901 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/services"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Endpoint 接口登记项
+// 新增路由时需要同步登记，docs check 命令会比对路由表与登记表
+type Endpoint struct {
+	Method string
+	// Path 路由路径；Admin 为 true 时为相对后台路径前缀的路径
+	Path        string
+	Admin       bool
+	Tag         string
+	Summary     string
+	Description string
+	Auth        int
+	Query       []Param
+	// Body 请求体示例值（结构体），按 json 标签生成 schema
+	Body interface{}
+	// Data 响应数据示例值；ResponseList 时为列表元素，ResponseRaw 时为整个响应体
+	Data     interface{}
+	Response int
+	// NotFound 是否可能返回 404
+	NotFound bool
+}
+
+// Param 查询参数
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// appMultiConfig 应用多开配置
+type appMultiConfig struct {
+	LoginType      int `json:"login_type" doc:"登录方式，0=顶号登录，1=非顶号登录"`
+	MultiOpenScope int `json:"multi_open_scope" doc:"多开范围，0=单电脑，1=单IP，2=全部电脑"`
+	CleanInterval  int `json:"clean_interval" doc:"清理间隔，单位小时"`
+	CheckInterval  int `json:"check_interval" doc:"校验间隔，单位分钟"`
+	MultiOpenCount int `json:"multi_open_count" doc:"多开数量"`
+}
+
+// appBindConfig 应用机器码/IP绑定配置
+type appBindConfig struct {
+	MachineVerify        int `json:"machine_verify" doc:"机器验证，0=关闭，1=开启"`
+	MachineRebindEnabled int `json:"machine_rebind_enabled" doc:"机器重绑开关，0=关闭，1=开启"`
+	MachineRebindLimit   int `json:"machine_rebind_limit" doc:"机器重绑限制"`
+	MachineFreeCount     int `json:"machine_free_count" doc:"机器免费重绑次数"`
+	MachineRebindCount   int `json:"machine_rebind_count" doc:"机器重绑次数"`
+	MachineRebindDeduct  int `json:"machine_rebind_deduct" doc:"机器重绑扣除时长"`
+	IPVerify             int `json:"ip_verify" doc:"IP验证，0=关闭，1=开启"`
+	IPRebindEnabled      int `json:"ip_rebind_enabled" doc:"IP重绑开关，0=关闭，1=开启"`
+	IPRebindLimit        int `json:"ip_rebind_limit" doc:"IP重绑限制"`
+	IPFreeCount          int `json:"ip_free_count" doc:"IP免费重绑次数"`
+	IPRebindCount        int `json:"ip_rebind_count" doc:"IP重绑次数"`
+	IPRebindDeduct       int `json:"ip_rebind_deduct" doc:"IP重绑扣除时长"`
+}
+
+// appRegisterConfig 应用注册与试用配置
+type appRegisterConfig struct {
+	RegisterEnabled      int `json:"register_enabled" doc:"注册开关，0=关闭，1=开启"`
+	RegisterLimitEnabled int `json:"register_limit_enabled" doc:"注册限制开关，0=关闭，1=开启"`
+	RegisterLimitTime    int `json:"register_limit_time" doc:"注册限制时间"`
+	RegisterCount        int `json:"register_count" doc:"限制时间内允许注册的次数"`
+	TrialEnabled         int `json:"trial_enabled" doc:"试用开关，0=关闭，1=开启"`
+	TrialLimitTime       int `json:"trial_limit_time" doc:"试用限制时间"`
+	TrialDuration        int `json:"trial_duration" doc:"试用时长"`
+}
+
+// appMaintenanceConfig 应用维护配置
+type appMaintenanceConfig struct {
+	MaintenanceMode    int    `json:"maintenance_mode" doc:"维护模式，0=关闭，1=开启"`
+	MaintenanceMessage string `json:"maintenance_message" doc:"维护提示信息"`
+}
+
+// apiListItem 接口列表项
+type apiListItem struct {
+	models.API
+	AppName        string `json:"app_name" doc:"所属应用名称"`
+	APITypeName    string `json:"api_type_name" doc:"接口类型名称"`
+	StatusName     string `json:"status_name" doc:"状态名称"`
+	AlgorithmNames struct {
+		Submit string `json:"submit"`
+		Return string `json:"return"`
+	} `json:"algorithm_names" doc:"提交与返回算法名称"`
+}
+
+// scriptItem 变量/函数列表项
+type scriptItem struct {
+	ID        uint   `json:"id"`
+	UUID      string `json:"uuid"`
+	Number    string `json:"number" doc:"编号，13位Unix毫秒时间戳"`
+	AppUUID   string `json:"app_uuid" doc:"绑定的应用UUID，0 表示全局"`
+	Alias     string `json:"alias" doc:"别名"`
+	Data      string `json:"data,omitempty" doc:"变量数据（仅变量）"`
+	Code      string `json:"code,omitempty" doc:"函数代码（仅函数）"`
+	Remark    string `json:"remark"`
+	CreatedAt string `json:"created_at" doc:"创建时间，格式 2006-01-02 15:04:05"`
+	UpdatedAt string `json:"updated_at" doc:"更新时间，格式 2006-01-02 15:04:05"`
+}
+
+// apiKeyBody API密钥新增/编辑请求体
+type apiKeyBody struct {
+	ID        uint     `json:"id" doc:"密钥ID，编辑时必填"`
+	Name      string   `json:"name" doc:"名称"`
+	Scopes    []string `json:"scopes" doc:"权限范围，例如 cards:write；写权限包含读权限"`
+	AppUUIDs  []string `json:"app_uuids" doc:"限定的应用UUID，为空表示不限；限定应用时只能授予卡密权限"`
+	ExpiresAt string   `json:"expires_at" doc:"过期时间，支持 2006-01-02、2006-01-02 15:04:05 与 RFC3339，留空表示长期有效"`
+	Status    int      `json:"status" doc:"状态，1=启用，0=停用"`
+	Remark    string   `json:"remark"`
+}
+
+// webhookBody Webhook新增/编辑请求体
+type webhookBody struct {
+	ID      uint     `json:"id" doc:"Webhook ID，编辑时必填"`
+	AppUUID string   `json:"app_uuid" doc:"订阅的应用UUID，0 或留空表示全部应用"`
+	Name    string   `json:"name"`
+	URL     string   `json:"url" doc:"推送地址，http/https"`
+	Secret  string   `json:"secret" doc:"签名密钥，新增时留空自动生成，编辑时留空保持不变"`
+	Events  []string `json:"events" doc:"订阅的事件"`
+	Status  int      `json:"status" doc:"状态，1=启用，0=停用"`
+	Remark  string   `json:"remark"`
+}
+
+// notifyChannel 告警通知渠道（接口返回时不包含签名密钥）
+type notifyChannel struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type" doc:"渠道类型，dingtalk/wecom/feishu/telegram"`
+	URL         string `json:"url" doc:"机器人Webhook地址，Telegram为Bot API地址"`
+	HasSecret   bool   `json:"has_secret" doc:"是否已配置签名密钥或Bot Token"`
+	ChatID      string `json:"chat_id" doc:"Telegram会话ID"`
+	Events      string `json:"events" doc:"订阅的事件，逗号分隔"`
+	MinSeverity int    `json:"min_severity" doc:"最低通知级别，1=提示，2=警告，3=严重"`
+	RateLimit   int    `json:"rate_limit" doc:"每分钟最多发送条数"`
+	Status      int    `json:"status" doc:"状态，1=启用，0=停用"`
+	Remark      string `json:"remark"`
+}
+
+// notifyChannelBody 告警通知渠道新增/编辑请求体
+type notifyChannelBody struct {
+	ID          uint     `json:"id" doc:"渠道ID，编辑时必填"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type" doc:"渠道类型，dingtalk/wecom/feishu/telegram"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret" doc:"签名密钥或Bot Token，编辑时留空保持不变"`
+	ChatID      string   `json:"chat_id"`
+	Events      []string `json:"events"`
+	MinSeverity int      `json:"min_severity"`
+	RateLimit   int      `json:"rate_limit"`
+	Status      int      `json:"status"`
+	Remark      string   `json:"remark"`
+}
+
+// mailTemplateBody 邮件模板保存/预览请求体
+type mailTemplateBody struct {
+	Name    string `json:"name" doc:"模板名称"`
+	Subject string `json:"subject" doc:"邮件主题模板"`
+	HTML    string `json:"html" doc:"HTML正文模板（html/template 语法）"`
+	Text    string `json:"text" doc:"纯文本正文模板（text/template 语法）"`
+}
+
+// member 普通用户（不包含密码哈希与盐值）
+type member struct {
+	ID        uint   `json:"id"`
+	UUID      string `json:"uuid"`
+	Username  string `json:"username"`
+	CreatedAt string `json:"created_at" doc:"创建时间，RFC3339"`
+	UpdatedAt string `json:"updated_at" doc:"更新时间，RFC3339"`
+}
+
+// dependencyCheck 就绪检查中的单项依赖检查结果
+type dependencyCheck struct {
+	Status    string  `json:"status" doc:"up/down/disabled"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// idBody 只包含ID的请求体
+type idBody struct {
+	ID uint `json:"id"`
+}
+
+// idsBody 只包含ID列表的请求体
+type idsBody struct {
+	IDs []uint `json:"ids"`
+}
+
+// uuidBody 只包含应用UUID的请求体
+type uuidBody struct {
+	UUID string `json:"uuid" doc:"应用UUID"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// documentDescription 文档说明
+var documentDescription = strings.TrimSpace(`
+networkDev 后台管理与服务探活接口。
+
+## 认证
+- 管理员会话：登录后服务端设置 admin_session Cookie；写接口需同时携带 X-CSRF-Token 请求头。
+- API密钥：请求头 Authorization: Bearer ndk_...，按接口的 x-api-key-scope 校验权限范围；不需要CSRF令牌。
+- 标记为仅限管理员会话的接口（如API密钥管理）不接受API密钥。
+
+## 响应结构
+所有接口返回统一结构 {"code": 0, "msg": "...", "data": ..., "request_id": "..."}：
+- code 为数字错误码，0 表示成功；失败时 error 字段为对应的字符串错误码，msg 为错误原因。
+- request_id 与响应头 X-Request-ID 一致，用于检索服务端日志。
+
+分页列表默认使用 LayUI 表格格式 {"code": 0, "msg": "success", "count": 总条数, "data": [...]}，
+配置 server.list_format 为 standard 或请求参数 format=standard 时返回 {"code": 0, "msg": "success", "data": {"items": [...], "total": 总条数, "page": 页码, "page_size": 每页条数}}。
+
+## 错误码
+错误码的数字与名称一经发布不再修改，客户端应按数字判断。3xxx 为客户端验证业务错误，统一返回 HTTP 200。
+`)
+
+// tags 接口分组
+var tags = []Tag{
+	{Name: "认证", Description: "登录、退出与CSRF令牌"},
+	{Name: "页面", Description: "后台页面与布局片段（HTML）"},
+	{Name: "系统", Description: "系统信息、仪表盘、个人资料与系统设置"},
+	{Name: "应用", Description: "应用管理"},
+	{Name: "接口", Description: "应用下的客户端接口配置"},
+	{Name: "变量", Description: "云变量管理"},
+	{Name: "函数", Description: "云函数管理"},
+	{Name: "卡密", Description: "卡密生成与管理"},
+	{Name: "用户", Description: "普通用户管理"},
+	{Name: "定时任务", Description: "内置定时任务与执行记录"},
+	{Name: "Webhook", Description: "事件推送与投递记录"},
+	{Name: "告警通知", Description: "IM机器人告警渠道"},
+	{Name: "邮件", Description: "邮件模板与发送记录"},
+	{Name: "API密钥", Description: "API密钥与审计日志（仅限管理员会话）"},
+	{Name: "探活", Description: "存活、就绪检查与监控指标"},
+	{Name: "文档", Description: "接口文档（仅开发模式）"},
+}
+
+// pageParams 分页参数（page/page_size）
+var pageParams = []Param{
+	{Name: "page", Type: "integer", Description: "页码，从1开始，默认1"},
+	{Name: "page_size", Type: "integer", Description: "每页条数，1~100，默认10"},
+}
+
+// limitParams 分页参数（page/limit）
+var limitParams = []Param{
+	{Name: "page", Type: "integer", Description: "页码，从1开始，默认1"},
+	{Name: "limit", Type: "integer", Description: "每页条数，默认10"},
+}
+
+// listFormatParam 列表响应格式参数，所有分页列表接口自动附加
+var listFormatParam = Param{Name: "format", Type: "string", Description: "列表响应格式：layui（默认，可由 server.list_format 修改）或 standard"}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Endpoints 返回所有登记的接口
+func Endpoints(opts Options) []Endpoint {
+	list := make([]Endpoint, 0, len(publicEndpoints)+len(adminEndpoints)+1)
+	list = append(list, publicEndpoints...)
+	if opts.MetricsPath != "" {
+		list = append(list, Endpoint{
+			Method: http.MethodGet, Path: opts.MetricsPath, Tag: "探活", Summary: "Prometheus 指标",
+			Description: "配置了 metrics.token 时需要 Authorization: Bearer <token>，否则仅允许本机访问",
+			Response:    ResponseText,
+		})
+	}
+	return append(list, adminEndpoints...)
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// FullPath 获取接口的完整路由路径
+func (ep Endpoint) FullPath(adminPrefix string) string {
+	if ep.Admin {
+		return adminPrefix + ep.Path
+	}
+	return ep.Path
+}
+
+// ============================================================================
+// 接口登记表
+// ============================================================================
+
+// publicEndpoints 后台路径前缀之外的接口
+var publicEndpoints = []Endpoint{
+	{Method: http.MethodGet, Path: "/", Tag: "页面", Summary: "站点主页", Response: ResponseHTML},
+	{
+		Method: http.MethodGet, Path: "/healthz", Tag: "探活", Summary: "存活检查",
+		Description: "进程存活即返回 200，不检查依赖",
+		Data: struct {
+			Status string `json:"status" doc:"固定为 alive"`
+			Uptime string `json:"uptime" doc:"运行时长"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/readyz", Tag: "探活", Summary: "就绪检查",
+		Description: "检查数据库、Redis、迁移与模板，全部正常且未进入关闭流程时返回 200（code=0），否则返回 503（code=5003）",
+		Data: struct {
+			Status string                     `json:"status" doc:"ready/not_ready/shutting_down"`
+			Checks map[string]dependencyCheck `json:"checks" doc:"各依赖检查结果，键为 database/redis/migrations/templates"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/client", Tag: "客户端", Summary: "客户端验证接口",
+		Description: "所有接口类型共用的入口。sign 为应用密钥对 app_uuid、api_type、timestamp、nonce、data 以换行连接后的 HMAC-SHA256（十六进制）；" +
+			"timestamp 与服务器时间相差不超过 300 秒，nonce 在窗口内不能重复。data 为业务参数 JSON 使用接口提交算法加密后的内容，" +
+			"成功时 payload 为返回数据 JSON 使用接口返回算法加密后的内容，sign 为应用密钥对 payload、timestamp、nonce 以换行连接后的 HMAC-SHA256。" +
+			"失败时返回 3xxx 错误码，HTTP 状态码为 200，错误响应不加密也不签名",
+		Body: struct {
+			AppUUID   string `json:"app_uuid" doc:"应用UUID"`
+			APIType   int    `json:"api_type" doc:"接口类型，与后台「接口」页面一致"`
+			Timestamp int64  `json:"timestamp" doc:"Unix 时间戳（秒）"`
+			Nonce     string `json:"nonce" doc:"随机串，8~64 个字母、数字、下划线或连字符"`
+			Data      string `json:"data" doc:"加密后的业务参数，如 {\"card\":\"...\",\"machine_code\":\"...\"}"`
+			Sign      string `json:"sign" doc:"请求签名"`
+		}{},
+		Data: struct {
+			Payload   string `json:"payload" doc:"加密后的返回数据"`
+			Timestamp int64  `json:"timestamp" doc:"服务器时间戳（秒）"`
+			Nonce     string `json:"nonce" doc:"请求中的随机串"`
+			Sign      string `json:"sign" doc:"响应签名"`
+		}{},
+	},
+	{Method: http.MethodGet, Path: "/docs", Tag: "文档", Summary: "交互式接口文档页面", Description: "仅开发模式（server.dev_mode）可访问，否则返回 404", Response: ResponseHTML},
+	{
+		Method: http.MethodGet, Path: "/docs/openapi.json", Tag: "文档", Summary: "OpenAPI 3 文档",
+		Description: "仅开发模式可访问；也可使用 docs export 命令导出", Response: ResponseRaw, Data: map[string]interface{}{},
+	},
+}
+
+// adminEndpoints 后台接口，路径相对于后台路径前缀
+var adminEndpoints = []Endpoint{
+	// 认证
+	{Method: http.MethodGet, Path: "/login", Admin: true, Tag: "认证", Summary: "登录页面", Response: ResponseHTML},
+	{
+		Method: http.MethodPost, Path: "/login", Admin: true, Tag: "认证", Summary: "管理员登录",
+		Description: "需要携带 X-CSRF-Token；开发模式下跳过验证码校验。成功后设置 admin_session Cookie",
+		Body: struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Captcha  string `json:"captcha" doc:"图形验证码"`
+		}{},
+		Data: struct {
+			Redirect string `json:"redirect" doc:"登录后跳转地址"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/logout", Admin: true, Tag: "认证", Summary: "退出登录",
+		Description: "清理会话Cookie，幂等",
+		Data: struct {
+			Redirect string `json:"redirect" doc:"登录页地址"`
+		}{},
+	},
+	{Method: http.MethodGet, Path: "/captcha", Admin: true, Tag: "认证", Summary: "图形验证码", Response: ResponseImage},
+	{
+		Method: http.MethodGet, Path: "/api/csrf-token", Admin: true, Tag: "认证", Summary: "获取CSRF令牌",
+		Description: "同时写入 csrf_token Cookie 与 X-CSRF-Token 响应头",
+		Data: struct {
+			CSRFToken string `json:"csrf_token"`
+		}{},
+	},
+
+	// 页面
+	{Method: http.MethodGet, Path: "", Admin: true, Tag: "页面", Summary: "后台首页", Description: "已登录时渲染后台布局页，否则302跳转到登录页", Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/", Admin: true, Tag: "页面", Summary: "后台首页", Description: "已登录时渲染后台布局页，否则302跳转到登录页", Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/layout", Admin: true, Tag: "页面", Summary: "后台布局页", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/dashboard", Admin: true, Tag: "页面", Summary: "仪表盘片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/user", Admin: true, Tag: "页面", Summary: "个人资料片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/settings", Admin: true, Tag: "页面", Summary: "系统设置片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/apps", Admin: true, Tag: "页面", Summary: "应用管理片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/apis", Admin: true, Tag: "页面", Summary: "接口管理片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/variables", Admin: true, Tag: "页面", Summary: "变量管理片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/functions", Admin: true, Tag: "页面", Summary: "函数管理片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/jobs", Admin: true, Tag: "页面", Summary: "定时任务片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/webhooks", Admin: true, Tag: "页面", Summary: "Webhook片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/notify", Admin: true, Tag: "页面", Summary: "告警通知片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/mail", Admin: true, Tag: "页面", Summary: "邮件片段", Auth: AuthSession, Response: ResponseHTML},
+	{Method: http.MethodGet, Path: "/apikeys", Admin: true, Tag: "页面", Summary: "API密钥片段", Auth: AuthSession, Response: ResponseHTML},
+
+	// 系统
+	{
+		Method: http.MethodGet, Path: "/api/system/info", Admin: true, Tag: "系统", Summary: "系统信息", Auth: AuthAdmin,
+		Data: struct {
+			Version string `json:"version"`
+			Mode    bool   `json:"mode" doc:"是否为开发模式"`
+			DBType  string `json:"db_type" doc:"数据库类型"`
+			Uptime  string `json:"uptime" doc:"运行时长"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/dashboard/stats", Admin: true, Tag: "系统", Summary: "仪表盘统计", Auth: AuthAdmin,
+		Data: struct {
+			TotalApps      int64 `json:"total_apps"`
+			EnabledApps    int64 `json:"enabled_apps"`
+			DisabledApps   int64 `json:"disabled_apps"`
+			TotalVariables int64 `json:"total_variables"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/user/profile", Admin: true, Tag: "系统", Summary: "当前管理员资料", Auth: AuthSession,
+		Data: struct {
+			Username string `json:"username"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/user/profile/update", Admin: true, Tag: "系统", Summary: "修改管理员用户名", Auth: AuthSession,
+		Description: "修改用户名时需要校验当前密码，成功后重新签发会话",
+		Body: struct {
+			Username    string `json:"username"`
+			OldPassword string `json:"old_password" doc:"当前密码，用户名变化时必填"`
+		}{},
+		Data: struct {
+			Username string `json:"username"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/user/password", Admin: true, Tag: "系统", Summary: "修改管理员密码", Auth: AuthSession,
+		Body: struct {
+			OldPassword     string `json:"old_password"`
+			NewPassword     string `json:"new_password"`
+			ConfirmPassword string `json:"confirm_password"`
+		}{},
+	},
+	{Method: http.MethodGet, Path: "/api/settings", Admin: true, Tag: "系统", Summary: "查询系统设置", Auth: AuthAdmin, Data: map[string]string{}},
+	{
+		Method: http.MethodPost, Path: "/api/settings/update", Admin: true, Tag: "系统", Summary: "更新系统设置", Auth: AuthAdmin,
+		Description: "请求体为设置名到值的映射，也可包在 settings 字段中；非字符串值会转换为字符串",
+		Body:        map[string]string{},
+	},
+
+	// 定时任务
+	{Method: http.MethodGet, Path: "/api/jobs/list", Admin: true, Tag: "定时任务", Summary: "定时任务列表", Auth: AuthAdmin, Data: []services.JobStatus{}},
+	{
+		Method: http.MethodPost, Path: "/api/jobs/update", Admin: true, Tag: "定时任务", Summary: "修改定时任务", Auth: AuthAdmin,
+		Body: struct {
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+			Cron    string `json:"cron" doc:"执行计划（5段cron表达式），为空时恢复默认"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/jobs/run", Admin: true, Tag: "定时任务", Summary: "立即执行定时任务", Auth: AuthAdmin,
+		Body: struct {
+			Name string `json:"name"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/jobs/runs", Admin: true, Tag: "定时任务", Summary: "执行记录", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "job_name", Type: "string", Description: "任务名称"},
+			{Name: "status", Type: "string", Description: "执行状态，0=执行中，1=成功，2=失败"},
+		}, pageParams...),
+		Data: models.JobRun{},
+	},
+
+	// 应用
+	{
+		Method: http.MethodGet, Path: "/api/apps/list", Admin: true, Tag: "应用", Summary: "应用列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "search", Type: "string", Description: "按名称或UUID搜索"},
+			{Name: "reveal", Type: "string", Description: "为 1 时返回应用密钥明文，否则脱敏；使用API密钥时不能为 1"},
+		}, limitParams...),
+		Data: models.App{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/simple", Admin: true, Tag: "应用", Summary: "启用的应用（下拉选择用）", Auth: AuthAdmin,
+		Data: []struct {
+			ID   uint   `json:"id"`
+			UUID string `json:"uuid"`
+			Name string `json:"name"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/create", Admin: true, Tag: "应用", Summary: "新增应用", Auth: AuthAdmin,
+		Description: "同时为应用创建全部默认接口",
+		Body: struct {
+			Name         string `json:"name"`
+			Version      string `json:"version"`
+			Status       int    `json:"status" doc:"1=启用，0=禁用"`
+			DownloadType int    `json:"download_type" doc:"0=不启用更新，1=自动更新，2=手动下载"`
+			ForceUpdate  int    `json:"force_update" doc:"0=不开启，1=开启"`
+			DownloadURL  string `json:"download_url"`
+		}{},
+		Data: models.App{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update", Admin: true, Tag: "应用", Summary: "编辑应用", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			ID           uint   `json:"id"`
+			Name         string `json:"name"`
+			Version      string `json:"version"`
+			Status       int    `json:"status"`
+			DownloadType int    `json:"download_type"`
+			DownloadURL  string `json:"download_url"`
+			ForceUpdate  int    `json:"force_update"`
+		}{},
+		Data: models.App{},
+	},
+	{Method: http.MethodPost, Path: "/api/apps/delete", Admin: true, Tag: "应用", Summary: "删除应用", Description: "同时删除应用下的接口", Auth: AuthAdmin, NotFound: true, Body: idBody{}},
+	{Method: http.MethodPost, Path: "/api/apps/batch_delete", Admin: true, Tag: "应用", Summary: "批量删除应用", Auth: AuthAdmin, Body: idsBody{}},
+	{
+		Method: http.MethodPost, Path: "/api/apps/batch_update_status", Admin: true, Tag: "应用", Summary: "批量启用/禁用应用", Auth: AuthAdmin,
+		Body: struct {
+			IDs    []uint `json:"ids"`
+			Status int    `json:"status" doc:"1=启用，0=禁用"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_status", Admin: true, Tag: "应用", Summary: "启用/禁用应用", Auth: AuthAdmin,
+		Body: struct {
+			ID     uint `json:"id"`
+			Status int  `json:"status" doc:"1=启用，0=禁用"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/reset_secret", Admin: true, Tag: "应用", Summary: "重置应用密钥", Auth: AuthAdmin, NotFound: true,
+		Description: "响应包含新密钥明文，不能使用API密钥调用",
+		Body:        uuidBody{},
+		Data: struct {
+			Secret string `json:"secret" doc:"新的应用密钥"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/get_app_data", Admin: true, Tag: "应用", Summary: "获取应用数据", Auth: AuthAdmin, NotFound: true,
+		Query: []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Data: struct {
+			AppData string `json:"app_data" doc:"应用数据（已解码）"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_app_data", Admin: true, Tag: "应用", Summary: "更新应用数据", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			UUID    string `json:"uuid"`
+			AppData string `json:"app_data"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/get_announcement", Admin: true, Tag: "应用", Summary: "获取程序公告", Auth: AuthAdmin, NotFound: true,
+		Query: []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Data: struct {
+			Announcement string `json:"announcement" doc:"公告内容（已解码）"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_announcement", Admin: true, Tag: "应用", Summary: "更新程序公告", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			UUID         string `json:"uuid"`
+			Announcement string `json:"announcement"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/get_multi_config", Admin: true, Tag: "应用", Summary: "获取多开配置", Auth: AuthAdmin, NotFound: true,
+		Query: []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Data:  appMultiConfig{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_multi_config", Admin: true, Tag: "应用", Summary: "更新多开配置", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			uuidBody
+			appMultiConfig
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/get_bind_config", Admin: true, Tag: "应用", Summary: "获取绑定配置", Auth: AuthAdmin, NotFound: true,
+		Query: []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Data:  appBindConfig{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_bind_config", Admin: true, Tag: "应用", Summary: "更新绑定配置", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			uuidBody
+			appBindConfig
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/get_register_config", Admin: true, Tag: "应用", Summary: "获取注册配置", Auth: AuthAdmin, NotFound: true,
+		Query: []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Data:  appRegisterConfig{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_register_config", Admin: true, Tag: "应用", Summary: "更新注册配置", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			uuidBody
+			appRegisterConfig
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/get_maintenance_config", Admin: true, Tag: "应用", Summary: "获取应用维护配置", Auth: AuthAdmin, NotFound: true,
+		Query: []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Data:  appMaintenanceConfig{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apps/update_maintenance_config", Admin: true, Tag: "应用", Summary: "更新应用维护配置", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			uuidBody
+			appMaintenanceConfig
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apps/integration_bundle", Admin: true, Tag: "应用", Summary: "导出客户端接入包", Auth: AuthAdmin, NotFound: true,
+		Description: "下载 zip 压缩包，包含 manifest.json（应用与全部接口的UUID、算法及客户端所需密钥）和易语言、C#、Python、C++ 接入源码。" +
+			"RSA 算法只导出提交公钥与返回私钥，服务端解密提交数据所用的私钥不会导出；密钥变更后重新导出即可。不能使用API密钥导出",
+		Query:    []Param{{Name: "uuid", Type: "string", Description: "应用UUID", Required: true}},
+		Response: ResponseZip,
+	},
+
+	// 接口
+	{
+		Method: http.MethodGet, Path: "/api/apis/list", Admin: true, Tag: "接口", Summary: "接口列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "app_uuid", Type: "string", Description: "按应用筛选"},
+			{Name: "api_type", Type: "integer", Description: "按接口类型筛选"},
+			{Name: "reveal", Type: "string", Description: "为 1 时返回接口私钥明文，否则脱敏；使用API密钥时不能为 1"},
+		}, limitParams...),
+		Data: apiListItem{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apis/get", Admin: true, Tag: "接口", Summary: "接口详情", Auth: AuthAdmin, NotFound: true,
+		Description: "返回完整的接口私钥，使用API密钥时私钥脱敏",
+		Query:       []Param{{Name: "uuid", Type: "string", Description: "接口UUID", Required: true}},
+		Data:        models.API{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apis/update", Admin: true, Tag: "接口", Summary: "编辑接口", Auth: AuthAdmin,
+		Body: struct {
+			UUID             string `json:"uuid"`
+			Status           int    `json:"status"`
+			SubmitAlgorithm  int    `json:"submit_algorithm"`
+			ReturnAlgorithm  int    `json:"return_algorithm"`
+			SubmitPublicKey  string `json:"submit_public_key"`
+			SubmitPrivateKey string `json:"submit_private_key"`
+			ReturnPublicKey  string `json:"return_public_key"`
+			ReturnPrivateKey string `json:"return_private_key"`
+		}{},
+		Data: models.API{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apis/update_status", Admin: true, Tag: "接口", Summary: "启用/禁用接口", Auth: AuthAdmin,
+		Body: struct {
+			ID     uint `json:"id"`
+			Status int  `json:"status" doc:"1=启用，0=禁用"`
+		}{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/apis/types", Admin: true, Tag: "接口", Summary: "接口类型列表", Auth: AuthAdmin,
+		Data: []struct {
+			Value int    `json:"value"`
+			Name  string `json:"name"`
+		}{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apis/generate_keys", Admin: true, Tag: "接口", Summary: "生成加密密钥", Auth: AuthAdmin,
+		Body: struct {
+			Side      string `json:"side" doc:"submit 或 return"`
+			Algorithm int    `json:"algorithm" doc:"加密算法"`
+		}{},
+		Data: struct {
+			PublicKey  string `json:"public_key"`
+			PrivateKey string `json:"private_key"`
+		}{},
+	},
+
+	// Webhook
+	{
+		Method: http.MethodGet, Path: "/api/webhooks/list", Admin: true, Tag: "Webhook", Summary: "Webhook列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "app_uuid", Type: "string", Description: "按订阅的应用筛选"},
+			{Name: "reveal", Type: "string", Description: "为 1 时返回签名密钥明文，否则脱敏；使用API密钥时不能为 1"},
+		}, pageParams...),
+		Data: models.Webhook{},
+	},
+	{Method: http.MethodPost, Path: "/api/webhooks/create", Admin: true, Tag: "Webhook", Summary: "新增Webhook", Auth: AuthAdmin, Body: webhookBody{}, Data: models.Webhook{}},
+	{Method: http.MethodPost, Path: "/api/webhooks/update", Admin: true, Tag: "Webhook", Summary: "编辑Webhook", Auth: AuthAdmin, NotFound: true, Body: webhookBody{}, Data: models.Webhook{}},
+	{Method: http.MethodPost, Path: "/api/webhooks/delete", Admin: true, Tag: "Webhook", Summary: "删除Webhook", Auth: AuthAdmin, NotFound: true, Body: idBody{}},
+	{
+		Method: http.MethodPost, Path: "/api/webhooks/test", Admin: true, Tag: "Webhook", Summary: "发送测试事件", Auth: AuthAdmin, NotFound: true,
+		Description: "推送失败时返回 HTTP 200、code=1，data 为投递记录",
+		Body:        idBody{}, Data: models.WebhookDelivery{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/webhooks/deliveries", Admin: true, Tag: "Webhook", Summary: "投递记录", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "webhook_id", Type: "integer", Description: "Webhook ID"},
+			{Name: "event", Type: "string", Description: "事件类型"},
+			{Name: "status", Type: "integer", Description: "投递状态，0=等待投递，1=成功，2=失败"},
+		}, pageParams...),
+		Data: models.WebhookDelivery{},
+	},
+
+	// 告警通知
+	{
+		Method: http.MethodGet, Path: "/api/notify/list", Admin: true, Tag: "告警通知", Summary: "通知渠道列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{{Name: "type", Type: "string", Description: "渠道类型"}}, pageParams...),
+		Data:  notifyChannel{},
+	},
+	{Method: http.MethodPost, Path: "/api/notify/create", Admin: true, Tag: "告警通知", Summary: "新增通知渠道", Auth: AuthAdmin, Body: notifyChannelBody{}, Data: notifyChannel{}},
+	{Method: http.MethodPost, Path: "/api/notify/update", Admin: true, Tag: "告警通知", Summary: "编辑通知渠道", Auth: AuthAdmin, NotFound: true, Body: notifyChannelBody{}, Data: notifyChannel{}},
+	{Method: http.MethodPost, Path: "/api/notify/delete", Admin: true, Tag: "告警通知", Summary: "删除通知渠道", Auth: AuthAdmin, NotFound: true, Body: idBody{}},
+	{
+		Method: http.MethodPost, Path: "/api/notify/test", Admin: true, Tag: "告警通知", Summary: "发送测试通知", Auth: AuthAdmin, NotFound: true,
+		Description: "发送失败时返回 HTTP 200、code=1，msg 为失败原因",
+		Body:        idBody{},
+	},
+
+	// 邮件
+	{
+		Method: http.MethodGet, Path: "/api/mail/template", Admin: true, Tag: "邮件", Summary: "获取邮件模板", Auth: AuthAdmin,
+		Description: "未自定义时返回内置默认模板",
+		Query:       []Param{{Name: "name", Type: "string", Description: "模板名称", Required: true}},
+		Data:        database.MailTemplate{},
+	},
+	{Method: http.MethodPost, Path: "/api/mail/template/update", Admin: true, Tag: "邮件", Summary: "保存邮件模板", Description: "保存前会用示例数据渲染校验", Auth: AuthAdmin, Body: mailTemplateBody{}},
+	{
+		Method: http.MethodPost, Path: "/api/mail/template/reset", Admin: true, Tag: "邮件", Summary: "恢复默认邮件模板", Auth: AuthAdmin,
+		Body: struct {
+			Name string `json:"name" doc:"模板名称"`
+		}{},
+		Data: database.MailTemplate{},
+	},
+	{Method: http.MethodPost, Path: "/api/mail/template/preview", Admin: true, Tag: "邮件", Summary: "预览邮件模板", Description: "使用示例数据渲染，不保存", Auth: AuthAdmin, Body: mailTemplateBody{}, Data: database.MailTemplate{}},
+	{
+		Method: http.MethodPost, Path: "/api/mail/test", Admin: true, Tag: "邮件", Summary: "发送测试邮件", Auth: AuthAdmin,
+		Description: "同步发送；发送失败时返回 HTTP 200、code=1，data 为发送记录",
+		Body: struct {
+			To string `json:"to" doc:"收件人地址"`
+		}{},
+		Data: models.MailMessage{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/mail/queue", Admin: true, Tag: "邮件", Summary: "邮件发送记录", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "template", Type: "string", Description: "模板名称"},
+			{Name: "status", Type: "integer", Description: "发送状态，0=等待发送，1=已发送，2=失败"},
+			{Name: "to", Type: "string", Description: "收件人地址"},
+		}, pageParams...),
+		Data: models.MailMessage{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/mail/resend", Admin: true, Tag: "邮件", Summary: "重新发送邮件", Auth: AuthAdmin,
+		Description: "同步发送；发送失败时返回 HTTP 200、code=1，data 为发送记录",
+		Body:        idBody{}, Data: models.MailMessage{},
+	},
+
+	// 卡密
+	{
+		Method: http.MethodGet, Path: "/api/cards/list", Admin: true, Tag: "卡密", Summary: "卡密列表", Auth: AuthAdmin, Response: ResponseList,
+		Description: "限定应用的API密钥只能查询授权应用的卡密",
+		Query: append([]Param{
+			{Name: "app_uuid", Type: "string", Description: "应用UUID"},
+			{Name: "status", Type: "integer", Description: "卡密状态，0=未使用，1=已使用，2=已禁用，3=已过期"},
+			{Name: "batch_no", Type: "string", Description: "生成批次号"},
+			{Name: "card_key", Type: "string", Description: "卡密"},
+		}, pageParams...),
+		Data: models.Card{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/cards/generate", Admin: true, Tag: "卡密", Summary: "批量生成卡密", Auth: AuthAdmin, NotFound: true,
+		Body: struct {
+			AppUUID  string `json:"app_uuid"`
+			Count    int    `json:"count" doc:"生成数量，1~1000"`
+			Duration string `json:"duration" doc:"卡密时长，例如 30d、12h、90m，不带后缀按分钟计算"`
+			Valid    string `json:"valid" doc:"有效期，格式与 duration 相同，留空表示长期有效"`
+			Remark   string `json:"remark"`
+		}{},
+		Data: []models.Card{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/cards/update_status", Admin: true, Tag: "卡密", Summary: "批量启用/禁用卡密", Auth: AuthAdmin,
+		Description: "只在未使用与已禁用之间切换，已使用与已过期的卡密不受影响",
+		Body: struct {
+			IDs    []uint `json:"ids"`
+			Status int    `json:"status" doc:"0=启用（未使用），2=禁用"`
+		}{},
+		Data: struct {
+			Updated int64 `json:"updated" doc:"实际更新的条数"`
+		}{},
+	},
+
+	// 用户
+	{
+		Method: http.MethodGet, Path: "/api/users/list", Admin: true, Tag: "用户", Summary: "普通用户列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{{Name: "keyword", Type: "string", Description: "按用户名搜索"}}, pageParams...),
+		Data:  member{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/users/create", Admin: true, Tag: "用户", Summary: "新增普通用户", Auth: AuthAdmin,
+		Body: struct {
+			Username string `json:"username" doc:"3~64个字符，不含空白字符"`
+			Password string `json:"password" doc:"至少6位"`
+		}{},
+		Data: member{},
+	},
+	{Method: http.MethodPost, Path: "/api/users/delete", Admin: true, Tag: "用户", Summary: "删除普通用户", Auth: AuthAdmin, NotFound: true, Body: idBody{}},
+
+	// API密钥
+	{
+		Method: http.MethodGet, Path: "/api/apikeys/list", Admin: true, Tag: "API密钥", Summary: "API密钥列表", Auth: AuthSession, Response: ResponseList,
+		Query: pageParams, Data: models.AdminAPIKey{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/apikeys/create", Admin: true, Tag: "API密钥", Summary: "创建API密钥", Auth: AuthSession,
+		Description: "密钥明文只在本次响应中返回",
+		Body:        apiKeyBody{},
+		Data: struct {
+			Key    string             `json:"key" doc:"密钥明文"`
+			APIKey models.AdminAPIKey `json:"api_key"`
+		}{},
+	},
+	{Method: http.MethodPost, Path: "/api/apikeys/update", Admin: true, Tag: "API密钥", Summary: "编辑API密钥", Auth: AuthSession, NotFound: true, Body: apiKeyBody{}, Data: models.AdminAPIKey{}},
+	{Method: http.MethodPost, Path: "/api/apikeys/delete", Admin: true, Tag: "API密钥", Summary: "删除API密钥", Auth: AuthSession, NotFound: true, Body: idBody{}},
+	{
+		Method: http.MethodGet, Path: "/api/audit/list", Admin: true, Tag: "API密钥", Summary: "审计日志", Auth: AuthSession, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "actor_type", Type: "string", Description: "操作者类型，admin/api_key"},
+			{Name: "api_key_id", Type: "integer", Description: "API密钥ID"},
+			{Name: "path", Type: "string", Description: "请求路径"},
+		}, pageParams...),
+		Data: models.AuditLog{},
+	},
+
+	// 变量
+	{
+		Method: http.MethodGet, Path: "/variable/list", Admin: true, Tag: "变量", Summary: "变量列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "search", Type: "string", Description: "按编号、别名或数据搜索"},
+			{Name: "app_uuid", Type: "string", Description: "按绑定应用筛选"},
+			{Name: "page_size", Type: "integer", Description: "每页条数，与 limit 等价"},
+		}, limitParams...),
+		Data: scriptItem{},
+	},
+	{
+		Method: http.MethodPost, Path: "/variable/create", Admin: true, Tag: "变量", Summary: "新增变量", Auth: AuthAdmin,
+		Body: struct {
+			Alias   string `json:"alias"`
+			AppUUID string `json:"app_uuid" doc:"绑定的应用UUID，0 表示全局"`
+			Data    string `json:"data"`
+			Remark  string `json:"remark"`
+		}{},
+		Data: models.Variable{},
+	},
+	{
+		Method: http.MethodPost, Path: "/variable/update", Admin: true, Tag: "变量", Summary: "编辑变量", Auth: AuthAdmin,
+		Body: struct {
+			UUID    string `json:"uuid"`
+			AppUUID string `json:"app_uuid"`
+			Data    string `json:"data"`
+			Remark  string `json:"remark"`
+		}{},
+		Data: models.Variable{},
+	},
+	{Method: http.MethodPost, Path: "/variable/delete", Admin: true, Tag: "变量", Summary: "删除变量", Auth: AuthAdmin, Body: idBody{}},
+	{Method: http.MethodPost, Path: "/variable/batch_delete", Admin: true, Tag: "变量", Summary: "批量删除变量", Auth: AuthAdmin, Body: idsBody{}},
+
+	// 函数
+	{
+		Method: http.MethodGet, Path: "/function/list", Admin: true, Tag: "函数", Summary: "函数列表", Auth: AuthAdmin, Response: ResponseList,
+		Query: append([]Param{
+			{Name: "search", Type: "string", Description: "按编号、别名或代码搜索"},
+			{Name: "app_uuid", Type: "string", Description: "按绑定应用筛选"},
+			{Name: "page_size", Type: "integer", Description: "每页条数，与 limit 等价"},
+		}, limitParams...),
+		Data: scriptItem{},
+	},
+	{
+		Method: http.MethodPost, Path: "/function/create", Admin: true, Tag: "函数", Summary: "新增函数", Auth: AuthAdmin,
+		Body: struct {
+			Alias   string `json:"alias"`
+			AppUUID string `json:"app_uuid" doc:"绑定的应用UUID，0 表示全局"`
+			Code    string `json:"code"`
+			Remark  string `json:"remark"`
+		}{},
+		Data: models.Function{},
+	},
+	{
+		Method: http.MethodPost, Path: "/function/update", Admin: true, Tag: "函数", Summary: "编辑函数", Auth: AuthAdmin,
+		Body: struct {
+			UUID    string `json:"uuid"`
+			AppUUID string `json:"app_uuid"`
+			Code    string `json:"code"`
+			Remark  string `json:"remark"`
+		}{},
+		Data: models.Function{},
+	},
+	{Method: http.MethodPost, Path: "/function/delete", Admin: true, Tag: "函数", Summary: "删除函数", Auth: AuthAdmin, Body: idBody{}},
+	{Method: http.MethodPost, Path: "/function/batch_delete", Admin: true, Tag: "函数", Summary: "批量删除函数", Auth: AuthAdmin, Body: idsBody{}},
+}
@@ -0,0 +1,573 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/models"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 接口认证方式
+const (
+	// AuthNone 无需认证
+	AuthNone = iota
+	// AuthAdmin 管理员会话或API密钥
+	AuthAdmin
+	// AuthSession 仅限管理员会话（API密钥无权调用）
+	AuthSession
+)
+
+// 响应类型
+const (
+	// ResponseEnvelope {code, msg, data} 结构（默认）
+	ResponseEnvelope = iota
+	// ResponseList {code, msg, count, data[]} 分页列表结构
+	ResponseList
+	// ResponseRaw 非统一结构的JSON响应，直接使用 Data 描述整个响应体
+	ResponseRaw
+	// ResponseHTML HTML页面
+	ResponseHTML
+	// ResponseText 纯文本（如 Prometheus 指标）
+	ResponseText
+	// ResponseImage 图片（如验证码）
+	ResponseImage
+	// ResponseZip zip 压缩包下载（如客户端接入包）
+	ResponseZip
+	// ResponseRedirect 302跳转
+	ResponseRedirect
+)
+
+// 安全方案名称
+const (
+	securitySession = "adminSession"
+	securityAPIKey  = "apiKey"
+	securityCSRF    = "csrfToken"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Document OpenAPI 3 文档
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+// Info 文档基本信息
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server 服务地址
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag 接口分组
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem 单个路径下各请求方法的接口
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+}
+
+// Operation 单个接口
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+	// Scope API密钥调用所需的权限范围，为空表示API密钥无权调用
+	Scope string `json:"x-api-key-scope,omitempty"`
+}
+
+// Parameter 请求参数
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody 请求体
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response 响应
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Header 响应头
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType 内容类型
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema JSON Schema（OpenAPI 3.0 子集）
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Components 可复用组件
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	Responses       map[string]*Response       `json:"responses"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme 安全方案
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Options 生成文档的运行时参数
+type Options struct {
+	// AdminPrefix 后台路径前缀（server.admin.prefix）
+	AdminPrefix string
+	// MetricsPath 主服务上的指标接口路径，未在主服务注册时为空
+	MetricsPath string
+}
+
+// builder 文档生成器，负责收集命名结构体的 schema
+type builder struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Build 根据接口登记表生成 OpenAPI 文档
+func Build(opts Options) *Document {
+	b := &builder{schemas: map[string]*Schema{}, names: map[reflect.Type]string{}}
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "networkDev API",
+			Version:     constants.AppVersion,
+			Description: documentDescription + "\n\n" + errorCodeTable(),
+		},
+		Servers: []Server{{URL: "/", Description: "当前服务"}},
+		Tags:    tags,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas:         b.schemas,
+			Responses:       errorResponses(),
+			SecuritySchemes: securitySchemes(),
+		},
+	}
+
+	envelope := envelopeSchema(nil)
+	envelope.Properties["error"].Enum = errorNames()
+	b.schemas["Envelope"] = envelope
+	for _, ep := range Endpoints(opts) {
+		path := ep.FullPath(opts.AdminPrefix)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		op := b.operation(ep)
+		switch ep.Method {
+		case http.MethodGet:
+			item.Get = op
+		case http.MethodPost:
+			item.Post = op
+		}
+	}
+	return doc
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// operation 生成单个接口描述
+func (b *builder) operation(ep Endpoint) *Operation {
+	op := &Operation{
+		Tags:        []string{ep.Tag},
+		Summary:     ep.Summary,
+		Description: ep.Description,
+		OperationID: operationID(ep),
+		Responses:   map[string]*Response{},
+		Security:    []map[string][]string{},
+	}
+
+	query := ep.Query
+	if ep.Response == ResponseList {
+		query = append(query[:len(query):len(query)], listFormatParam)
+	}
+	for _, p := range query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        p.Name,
+			In:          "query",
+			Description: p.Description,
+			Required:    p.Required,
+			Schema:      &Schema{Type: p.Type},
+		})
+	}
+	if ep.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: b.schemaOf(reflect.TypeOf(ep.Body))}},
+		}
+	}
+
+	op.Responses["200"] = b.successResponse(ep)
+	if ep.Response == ResponseRedirect {
+		delete(op.Responses, "200")
+		op.Responses["302"] = &Response{Description: "跳转", Headers: map[string]*Header{
+			"Location": {Description: "跳转地址", Schema: &Schema{Type: "string"}},
+		}}
+	}
+
+	switch ep.Auth {
+	case AuthAdmin, AuthSession:
+		op.Security = append(op.Security, map[string][]string{securitySession: {}})
+		if ep.Admin {
+			if scope, ok := models.APIKeyScopeForRequest(ep.Method, ep.Path); ok && ep.Auth == AuthAdmin {
+				op.Scope = scope
+				op.Security = append(op.Security, map[string][]string{securityAPIKey: {}})
+			}
+		}
+		if ep.Method != http.MethodGet {
+			op.Security[0][securityCSRF] = []string{}
+		}
+		if ep.Response != ResponseHTML {
+			op.Responses["401"] = &Response{Ref: "#/components/responses/Unauthorized"}
+			op.Responses["403"] = &Response{Ref: "#/components/responses/Forbidden"}
+		}
+	}
+	// 文件下载接口失败时同样返回统一结构的JSON错误
+	if isJSON(ep.Response) || ep.Response == ResponseZip {
+		if ep.Body != nil || len(ep.Query) > 0 {
+			op.Responses["400"] = &Response{Ref: "#/components/responses/BadRequest"}
+		}
+		if ep.NotFound {
+			op.Responses["404"] = &Response{Ref: "#/components/responses/NotFound"}
+		}
+		op.Responses["500"] = &Response{Ref: "#/components/responses/InternalError"}
+	}
+	return op
+}
+
+// successResponse 生成成功响应
+func (b *builder) successResponse(ep Endpoint) *Response {
+	var data *Schema
+	if ep.Data != nil {
+		data = b.schemaOf(reflect.TypeOf(ep.Data))
+	}
+	switch ep.Response {
+	case ResponseList:
+		return jsonResponse("分页列表（LayUI表格格式；format=standard 时 data 为 {items, total, page, page_size}，不返回 count）", &Schema{
+			Type:     "object",
+			Required: []string{"code", "msg", "count", "data"},
+			Properties: map[string]*Schema{
+				"code":       {Type: "integer", Description: "错误码，0 表示成功", Example: 0},
+				"msg":        {Type: "string", Description: "提示信息"},
+				"count":      {Type: "integer", Description: "符合条件的总条数"},
+				"data":       {Type: "array", Items: data},
+				"request_id": {Type: "string", Description: "请求ID"},
+			},
+		})
+	case ResponseRaw:
+		return jsonResponse("成功", data)
+	case ResponseHTML:
+		return &Response{Description: "HTML页面", Content: map[string]*MediaType{"text/html": {Schema: &Schema{Type: "string"}}}}
+	case ResponseText:
+		return &Response{Description: "文本", Content: map[string]*MediaType{"text/plain": {Schema: &Schema{Type: "string"}}}}
+	case ResponseImage:
+		return &Response{Description: "图片", Content: map[string]*MediaType{"image/png": {Schema: &Schema{Type: "string", Format: "binary"}}}}
+	case ResponseZip:
+		return &Response{Description: "zip 压缩包（附件下载）", Content: map[string]*MediaType{"application/zip": {Schema: &Schema{Type: "string", Format: "binary"}}}}
+	default:
+		return jsonResponse("成功", envelopeSchema(data))
+	}
+}
+
+// schemaOf 根据 Go 类型生成 schema，命名结构体登记到 components 后以引用返回
+func (b *builder) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var s *Schema
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		s = &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		name, ok := b.names[t]
+		if !ok {
+			name = b.schemaName(t)
+			// 先登记名称再展开字段，避免自引用结构体无限递归
+			b.names[t] = name
+			b.schemas[name] = &Schema{}
+			*b.schemas[name] = *b.structSchema(t)
+		}
+		s = &Schema{Ref: "#/components/schemas/" + name}
+	case t.Kind() == reflect.Struct:
+		s = b.structSchema(t)
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		s = &Schema{Type: "string", Format: "byte"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		s = &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		s = &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		s = &Schema{Type: "integer"}
+		if t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64 {
+			s.Format = "int64"
+		}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		s = &Schema{Type: "number"}
+	case t.Kind() == reflect.String:
+		s = &Schema{Type: "string"}
+	default:
+		s = &Schema{}
+	}
+
+	if nullable {
+		if s.Ref != "" {
+			// OpenAPI 3.0 中 $ref 的兄弟字段会被忽略，可空引用需要包一层 allOf
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+	}
+	return s
+}
+
+// structSchema 生成结构体 schema
+// 字段名取 json 标签，说明优先取 doc 标签，其次取 gorm 标签中的 comment；匿名嵌入的结构体字段展开到上层
+func (b *builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := b.structSchema(embedded)
+				for k, v := range inner.Properties {
+					s.Properties[k] = v
+				}
+				s.Required = append(s.Required, inner.Required...)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := b.schemaOf(field.Type)
+		if desc := fieldDescription(field); desc != "" {
+			if prop.Ref != "" {
+				prop = &Schema{AllOf: []*Schema{prop}}
+			}
+			prop.Description = desc
+		}
+		s.Properties[name] = prop
+		if field.Tag.Get("binding") == "required" {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// schemaName 生成 components 中的 schema 名称，不同包的同名结构体加包名区分
+func (b *builder) schemaName(t reflect.Type) string {
+	name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+	if _, exists := b.schemas[name]; exists {
+		pkg := t.PkgPath()
+		if idx := strings.LastIndex(pkg, "/"); idx >= 0 {
+			pkg = pkg[idx+1:]
+		}
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	return name
+}
+
+// fieldDescription 获取字段说明
+func fieldDescription(field reflect.StructField) string {
+	if desc := field.Tag.Get("doc"); desc != "" {
+		return desc
+	}
+	for _, part := range strings.Split(field.Tag.Get("gorm"), ";") {
+		if comment, ok := strings.CutPrefix(part, "comment:"); ok {
+			return comment
+		}
+	}
+	return ""
+}
+
+// envelopeSchema 生成统一响应结构 {code, msg, error, data, request_id}
+func envelopeSchema(data *Schema) *Schema {
+	if data == nil {
+		data = &Schema{Nullable: true, Description: "响应数据"}
+	}
+	return &Schema{
+		Type:     "object",
+		Required: []string{"code", "msg", "data"},
+		Properties: map[string]*Schema{
+			"code":       {Type: "integer", Description: "错误码，0 表示成功，其余取值见错误码表", Example: 0},
+			"msg":        {Type: "string", Description: "提示信息，失败时为错误原因"},
+			"error":      {Type: "string", Description: "字符串错误码，仅失败时返回"},
+			"data":       data,
+			"request_id": {Type: "string", Description: "请求ID，与响应头 X-Request-ID 一致"},
+		},
+	}
+}
+
+// errorNames 错误码目录中的全部字符串错误码（不含成功码）
+func errorNames() []interface{} {
+	var names []interface{}
+	for _, ec := range constants.ErrorCodes() {
+		if ec.Code != constants.CodeOK.Code {
+			names = append(names, ec.Name)
+		}
+	}
+	return names
+}
+
+// errorCodeTable 按错误码目录生成 Markdown 表格
+func errorCodeTable() string {
+	var sb strings.Builder
+	sb.WriteString("| code | error | HTTP状态码 | 含义 |\n| --- | --- | --- | --- |")
+	for _, ec := range constants.ErrorCodes() {
+		fmt.Fprintf(&sb, "\n| %d | %s | %d | %s |", ec.Code, ec.Name, ec.Status, ec.Message)
+	}
+	return sb.String()
+}
+
+// jsonResponse 生成 JSON 响应
+func jsonResponse(description string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content:     map[string]*MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+// errorResponses 生成通用错误响应
+// 失败时 code 与 error 取自错误码目录，msg 为错误原因，data 一般为 null
+func errorResponses() map[string]*Response {
+	ref := &Schema{Ref: "#/components/schemas/Envelope"}
+	return map[string]*Response{
+		"BadRequest":    jsonResponse("参数错误（VALIDATION_ERROR/INVALID_REQUEST 等，msg 为具体原因）", ref),
+		"Unauthorized":  jsonResponse("未登录、会话已过期（UNAUTHORIZED）或API密钥无效/停用/过期（API_KEY_*）", ref),
+		"Forbidden":     jsonResponse("CSRF校验失败（CSRF_INVALID）、IP不在白名单（IP_FORBIDDEN）、API密钥缺少权限范围（API_KEY_SCOPE_DENIED）或无权操作该应用（API_KEY_APP_DENIED）", ref),
+		"NotFound":      jsonResponse("资源不存在（NOT_FOUND）", ref),
+		"InternalError": jsonResponse("服务器内部错误（INTERNAL_ERROR/DATABASE_ERROR），详情见服务端日志（按 request_id 检索）", ref),
+	}
+}
+
+// securitySchemes 生成安全方案
+func securitySchemes() map[string]*SecurityScheme {
+	return map[string]*SecurityScheme{
+		securitySession: {
+			Type:        "apiKey",
+			In:          "cookie",
+			Name:        "admin_session",
+			Description: "管理员登录后由服务端设置的会话Cookie",
+		},
+		securityAPIKey: {
+			Type:         "http",
+			Scheme:       "bearer",
+			BearerFormat: models.APIKeyPrefix + "...",
+			Description:  "后台「API密钥」页面创建的密钥，按接口的 x-api-key-scope 校验权限范围",
+		},
+		securityCSRF: {
+			Type:        "apiKey",
+			In:          "header",
+			Name:        "X-CSRF-Token",
+			Description: "使用会话Cookie调用写接口时必须携带，令牌可通过 GET {admin}/api/csrf-token 获取；使用API密钥时不需要",
+		},
+	}
+}
+
+// operationID 生成接口ID，例如后台的 POST /api/apps/create -> postAdminApiAppsCreate
+func operationID(ep Endpoint) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(ep.Method))
+	if ep.Admin {
+		sb.WriteString("Admin")
+	}
+	for _, part := range strings.FieldsFunc(ep.Path, func(r rune) bool {
+		return r == '/' || r == '_' || r == '-' || r == '.'
+	}) {
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	if ep.Path == "/" {
+		sb.WriteString("Index")
+	}
+	return sb.String()
+}
+
+// isJSON 判断响应是否为 JSON
+func isJSON(response int) bool {
+	return response == ResponseEnvelope || response == ResponseList || response == ResponseRaw
+}
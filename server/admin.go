@@ -1,10 +1,14 @@
 package server
 
 import (
+	"net/http"
+
 	adminctl "networkDev/controllers/admin"
+	"networkDev/middleware"
 	"networkDev/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
 // ============================================================================
@@ -12,66 +16,66 @@ import (
 // ============================================================================
 
 // RegisterAdminRoutes 注册管理员后台相关路由
+// 所有路由挂载在 server.admin.prefix（默认 /admin）下，并应用 server.admin.allow_ips 白名单
 // - /admin/login: 支持GET渲染登录页、POST提交登录
 // - /admin/logout: 管理员退出登录
 // - /admin/dashboard: 管理员仪表盘（示例）
 // - /admin/fragment/*: 布局内动态片段加载
 // - /admin/api/settings*: 设置接口（查询/更新）
+// - /admin/api/jobs*: 定时任务接口（列表/修改/立即执行/执行记录）
+// - /admin/api/webhooks*: Webhook接口（增删改查/发送测试事件/投递记录）
+// - /admin/api/notify*: 告警通知渠道接口（增删改查/发送测试通知）
+// - /admin/api/mail*: 邮件接口（模板编辑/预览/发送测试邮件/发送记录/重发）
+// - /admin/api/cards*, /admin/api/users*: 卡密与普通用户接口（供订单系统等外部系统调用）
+// - /admin/api/apikeys*, /admin/api/audit*: API密钥管理与审计日志接口（仅限管理员会话）
+//
+// 需要认证的路由均使用 AdminAuthRequired，同时接受管理员会话与 Authorization: Bearer <API密钥>
+// 新增或修改路由后需同步 openapi/operations.go 中的接口登记表，并执行 docs check 检查
 func RegisterAdminRoutes(router *gin.Engine) {
+	admin := router.Group(utils.AdminPrefix(), middleware.IPAllowlist(viper.GetStringSlice("server.admin.allow_ips")))
+
 	// /admin 根与前缀统一入口：根据是否登录跳转
-	router.GET("/admin", adminctl.AdminIndexHandler)
-	router.GET("/admin/", adminctl.AdminIndexHandler)
+	admin.GET("", adminctl.AdminIndexHandler)
+	admin.GET("/", adminctl.AdminIndexHandler)
 
 	// Admin 认证相关路由
-	router.GET("/admin/login", adminctl.LoginPageHandler)
-	router.POST("/admin/login", adminctl.LoginHandler) // CSRF验证在控制器内部处理
+	admin.GET("/login", adminctl.LoginPageHandler)
+	admin.POST("/login", adminctl.LoginHandler) // CSRF验证在控制器内部处理
 
 	// 退出登录（无需拦截，幂等清理）
-	router.POST("/admin/logout", adminctl.LogoutHandler)
+	admin.POST("/logout", adminctl.LogoutHandler)
 
 	// 验证码生成路由（无需认证）
-	router.GET("/admin/captcha", adminctl.CaptchaHandler)
+	admin.GET("/captcha", adminctl.CaptchaHandler)
 
 	// CSRF令牌获取API（无需认证，但需要在登录页面等地方获取）
-	router.GET("/admin/api/csrf-token", func(c *gin.Context) {
-		// 生成新的CSRF令牌
-		token, err := utils.GenerateCSRFToken()
-		if err != nil {
-			c.JSON(500, gin.H{"success": false, "message": "生成CSRF令牌失败"})
-			return
-		}
-
-		// 设置令牌到Cookie和响应头
-		utils.SetCSRFToken(c, token)
-
-		// 返回令牌给前端
-		c.JSON(200, gin.H{
-			"success":    true,
-			"message":    "CSRF令牌生成成功",
-			"csrf_token": token,
-		})
-	})
+	admin.GET("/api/csrf-token", utils.CSRFTokenHandler)
 
 	// 后台布局页（需要管理员认证）
-	router.GET("/admin/layout", adminctl.AdminAuthRequired(), adminctl.AdminLayoutHandler)
+	admin.GET("/layout", adminctl.AdminAuthRequired(), adminctl.AdminLayoutHandler)
 
 	// 片段路由（需要管理员认证）
-	router.GET("/admin/dashboard", adminctl.AdminAuthRequired(), adminctl.DashboardFragmentHandler)
-	router.GET("/admin/user", adminctl.AdminAuthRequired(), adminctl.UserFragmentHandler)
-	router.GET("/admin/settings", adminctl.AdminAuthRequired(), adminctl.SettingsFragmentHandler)
-	router.GET("/admin/apps", adminctl.AdminAuthRequired(), adminctl.AppsFragmentHandler)
-	router.GET("/admin/apis", adminctl.AdminAuthRequired(), adminctl.APIFragmentHandler)
-	router.GET("/admin/variables", adminctl.AdminAuthRequired(), adminctl.VariableFragmentHandler)
-	router.GET("/admin/functions", adminctl.AdminAuthRequired(), adminctl.FunctionFragmentHandler)
+	admin.GET("/dashboard", adminctl.AdminAuthRequired(), adminctl.DashboardFragmentHandler)
+	admin.GET("/user", adminctl.AdminAuthRequired(), adminctl.UserFragmentHandler)
+	admin.GET("/settings", adminctl.AdminAuthRequired(), adminctl.SettingsFragmentHandler)
+	admin.GET("/apps", adminctl.AdminAuthRequired(), adminctl.AppsFragmentHandler)
+	admin.GET("/apis", adminctl.AdminAuthRequired(), adminctl.APIFragmentHandler)
+	admin.GET("/variables", adminctl.AdminAuthRequired(), adminctl.VariableFragmentHandler)
+	admin.GET("/functions", adminctl.AdminAuthRequired(), adminctl.FunctionFragmentHandler)
+	admin.GET("/jobs", adminctl.AdminAuthRequired(), adminctl.JobsFragmentHandler)
+	admin.GET("/webhooks", adminctl.AdminAuthRequired(), adminctl.WebhooksFragmentHandler)
+	admin.GET("/notify", adminctl.AdminAuthRequired(), adminctl.NotifyFragmentHandler)
+	admin.GET("/mail", adminctl.AdminAuthRequired(), adminctl.MailFragmentHandler)
+	admin.GET("/apikeys", adminctl.AdminAuthRequired(), adminctl.APIKeysFragmentHandler)
 
 	// 系统信息API（用于仪表盘定时刷新）
-	router.GET("/admin/api/system/info", adminctl.AdminAuthRequired(), adminctl.SystemInfoHandler)
+	admin.GET("/api/system/info", adminctl.AdminAuthRequired(), adminctl.SystemInfoHandler)
 
 	// 仪表盘统计数据API
-	router.GET("/admin/api/dashboard/stats", adminctl.AdminAuthRequired(), adminctl.DashboardStatsHandler)
+	admin.GET("/api/dashboard/stats", adminctl.AdminAuthRequired(), adminctl.DashboardStatsHandler)
 
 	// 个人资料API
-	userGroup := router.Group("/admin/api/user", adminctl.AdminAuthRequired())
+	userGroup := admin.Group("/api/user", adminctl.AdminAuthRequired())
 	{
 		userGroup.GET("/profile", adminctl.UserProfileQueryHandler)
 		userGroup.POST("/profile/update", adminctl.UserProfileUpdateHandler)
@@ -79,14 +83,23 @@ func RegisterAdminRoutes(router *gin.Engine) {
 	}
 
 	// 系统设置API
-	settingsGroup := router.Group("/admin/api/settings", adminctl.AdminAuthRequired())
+	settingsGroup := admin.Group("/api/settings", adminctl.AdminAuthRequired())
 	{
 		settingsGroup.GET("", adminctl.SettingsQueryHandler)
 		settingsGroup.POST("/update", adminctl.SettingsUpdateHandler)
 	}
 
+	// 定时任务API
+	jobsGroup := admin.Group("/api/jobs", adminctl.AdminAuthRequired())
+	{
+		jobsGroup.GET("/list", adminctl.JobListHandler)
+		jobsGroup.POST("/update", adminctl.JobUpdateHandler)
+		jobsGroup.POST("/run", adminctl.JobRunHandler)
+		jobsGroup.GET("/runs", adminctl.JobRunsListHandler)
+	}
+
 	// 应用管理API
-	appsGroup := router.Group("/admin/api/apps", adminctl.AdminAuthRequired())
+	appsGroup := admin.Group("/api/apps", adminctl.AdminAuthRequired())
 	{
 		appsGroup.GET("/list", adminctl.AppsListHandler)
 		appsGroup.GET("/simple", adminctl.AppsSimpleListHandler)
@@ -107,20 +120,83 @@ func RegisterAdminRoutes(router *gin.Engine) {
 		appsGroup.POST("/update_bind_config", adminctl.AppUpdateBindConfigHandler)
 		appsGroup.GET("/get_register_config", adminctl.AppGetRegisterConfigHandler)
 		appsGroup.POST("/update_register_config", adminctl.AppUpdateRegisterConfigHandler)
+		appsGroup.GET("/get_maintenance_config", adminctl.AppGetMaintenanceConfigHandler)
+		appsGroup.POST("/update_maintenance_config", adminctl.AppUpdateMaintenanceConfigHandler)
+		appsGroup.GET("/integration_bundle", adminctl.AppIntegrationBundleHandler)
 	}
 
 	// API接口管理API
-	apisGroup := router.Group("/admin/api/apis", adminctl.AdminAuthRequired())
+	apisGroup := admin.Group("/api/apis", adminctl.AdminAuthRequired())
 	{
 		apisGroup.GET("/list", adminctl.APIListHandler)
+		apisGroup.GET("/get", adminctl.APIGetHandler)
 		apisGroup.POST("/update", adminctl.APIUpdateHandler)
 		apisGroup.POST("/update_status", adminctl.APIUpdateStatusHandler)
 		apisGroup.GET("/types", adminctl.APIGetTypesHandler)
 		apisGroup.POST("/generate_keys", adminctl.APIGenerateKeysHandler)
 	}
 
+	// Webhook管理API
+	webhooksGroup := admin.Group("/api/webhooks", adminctl.AdminAuthRequired())
+	{
+		webhooksGroup.GET("/list", adminctl.WebhookListHandler)
+		webhooksGroup.POST("/create", adminctl.WebhookCreateHandler)
+		webhooksGroup.POST("/update", adminctl.WebhookUpdateHandler)
+		webhooksGroup.POST("/delete", adminctl.WebhookDeleteHandler)
+		webhooksGroup.POST("/test", adminctl.WebhookTestHandler)
+		webhooksGroup.GET("/deliveries", adminctl.WebhookDeliveriesListHandler)
+	}
+
+	// 告警通知渠道API
+	notifyGroup := admin.Group("/api/notify", adminctl.AdminAuthRequired())
+	{
+		notifyGroup.GET("/list", adminctl.NotifyChannelListHandler)
+		notifyGroup.POST("/create", adminctl.NotifyChannelCreateHandler)
+		notifyGroup.POST("/update", adminctl.NotifyChannelUpdateHandler)
+		notifyGroup.POST("/delete", adminctl.NotifyChannelDeleteHandler)
+		notifyGroup.POST("/test", adminctl.NotifyChannelTestHandler)
+	}
+
+	// 邮件API
+	mailGroup := admin.Group("/api/mail", adminctl.AdminAuthRequired())
+	{
+		mailGroup.GET("/template", adminctl.MailTemplateGetHandler)
+		mailGroup.POST("/template/update", adminctl.MailTemplateUpdateHandler)
+		mailGroup.POST("/template/reset", adminctl.MailTemplateResetHandler)
+		mailGroup.POST("/template/preview", adminctl.MailTemplatePreviewHandler)
+		mailGroup.POST("/test", adminctl.MailTestHandler)
+		mailGroup.GET("/queue", adminctl.MailQueueListHandler)
+		mailGroup.POST("/resend", adminctl.MailResendHandler)
+	}
+
+	// 卡密管理API
+	cardsGroup := admin.Group("/api/cards", adminctl.AdminAuthRequired())
+	{
+		cardsGroup.GET("/list", adminctl.CardsListHandler)
+		cardsGroup.POST("/generate", adminctl.CardsGenerateHandler)
+		cardsGroup.POST("/update_status", adminctl.CardsUpdateStatusHandler)
+	}
+
+	// 普通用户管理API
+	usersGroup := admin.Group("/api/users", adminctl.AdminAuthRequired())
+	{
+		usersGroup.GET("/list", adminctl.UsersListHandler)
+		usersGroup.POST("/create", adminctl.UsersCreateHandler)
+		usersGroup.POST("/delete", adminctl.UsersDeleteHandler)
+	}
+
+	// API密钥与审计日志API
+	apiKeysGroup := admin.Group("/api/apikeys", adminctl.AdminAuthRequired())
+	{
+		apiKeysGroup.GET("/list", adminctl.APIKeyListHandler)
+		apiKeysGroup.POST("/create", adminctl.APIKeyCreateHandler)
+		apiKeysGroup.POST("/update", adminctl.APIKeyUpdateHandler)
+		apiKeysGroup.POST("/delete", adminctl.APIKeyDeleteHandler)
+	}
+	admin.GET("/api/audit/list", adminctl.AdminAuthRequired(), adminctl.AuditLogListHandler)
+
 	// 变量管理API
-	variableGroup := router.Group("/admin/variable", adminctl.AdminAuthRequired())
+	variableGroup := admin.Group("/variable", adminctl.AdminAuthRequired())
 	{
 		variableGroup.GET("/list", adminctl.VariableListHandler)
 		variableGroup.POST("/create", adminctl.VariableCreateHandler)
@@ -130,7 +206,7 @@ func RegisterAdminRoutes(router *gin.Engine) {
 	}
 
 	// 函数管理API
-	functionGroup := router.Group("/admin/function", adminctl.AdminAuthRequired())
+	functionGroup := admin.Group("/function", adminctl.AdminAuthRequired())
 	{
 		functionGroup.GET("/list", adminctl.FunctionListHandler)
 		functionGroup.POST("/create", adminctl.FunctionCreateHandler)
@@ -138,5 +214,16 @@ func RegisterAdminRoutes(router *gin.Engine) {
 		functionGroup.POST("/delete", adminctl.FunctionDeleteHandler)
 		functionGroup.POST("/batch_delete", adminctl.FunctionsBatchDeleteHandler)
 	}
+}
+
+// RegisterAdminServerRoutes 注册独立后台监听的路由
+// 仅包含后台路由与其依赖的静态资源，根路径跳转到后台首页
+func RegisterAdminServerRoutes(router *gin.Engine) {
+	registerStaticRoutes(router)
+	registerFaviconRoute(router)
+	RegisterAdminRoutes(router)
 
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, utils.AdminPrefix())
+	})
 }
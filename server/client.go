@@ -0,0 +1,17 @@
+package server
+
+import (
+	clientctl "networkDev/controllers/client"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// 路由注册函数
+// ============================================================================
+
+// RegisterClientRoutes 注册客户端验证接口
+// 所有接口类型共用一个入口，请求体中的 api_type 决定调用的接口
+func RegisterClientRoutes(router *gin.Engine) {
+	router.POST("/api/client", clientctl.ClientHandler)
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"net/http"
+
+	"networkDev/middleware"
+	"networkDev/openapi"
+	"networkDev/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// RegisterDocsRoutes 注册接口文档路由
+// - /docs：交互式接口文档页面
+// - /docs/openapi.json：OpenAPI 3 文档
+// 仅开发模式可访问，其余情况返回 404；开发模式支持热重载，因此在请求时判断
+func RegisterDocsRoutes(router *gin.Engine) {
+	middleware.ExemptFromMaintenance("/docs")
+	middleware.ExemptFromMaintenance("/docs/openapi.json")
+
+	router.GET("/docs", devModeOnly(), DocsPageHandler)
+	router.GET("/docs/openapi.json", devModeOnly(), OpenAPIHandler)
+}
+
+// DocsPageHandler 接口文档页面
+func DocsPageHandler(c *gin.Context) {
+	c.HTML(http.StatusOK, "docs.html", gin.H{
+		"AdminPrefix": utils.AdminPrefix(),
+	})
+}
+
+// OpenAPIHandler 返回 OpenAPI 文档
+func OpenAPIHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, OpenAPIDocument())
+}
+
+// OpenAPIDocument 按当前配置生成 OpenAPI 文档
+func OpenAPIDocument() *openapi.Document {
+	return openapi.Build(docsOptions())
+}
+
+// CheckDocs 比对实际注册的路由与接口登记表
+// 按当前配置构建一份完整路由表（包含独立监听的后台路由），返回未登记的路由与已登记但未注册的接口
+func CheckDocs() (undocumented, stale []string) {
+	router := gin.New()
+	RegisterRoutes(router)
+	if viper.GetString("server.admin.listen") != "" {
+		RegisterAdminRoutes(router)
+	}
+	return openapi.Check(router.Routes(), docsOptions())
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// docsOptions 获取生成文档的运行时参数
+func docsOptions() openapi.Options {
+	opts := openapi.Options{AdminPrefix: utils.AdminPrefix()}
+	// 与 RegisterMetricsRoutes 保持一致：只有在主服务上注册时才登记指标接口
+	if viper.GetBool("metrics.enabled") && viper.GetString("metrics.listen") == "" {
+		opts.MetricsPath = metricsPath()
+	}
+	return opts
+}
+
+// devModeOnly 非开发模式时返回 404
+func devModeOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !middleware.IsDevMode() {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Next()
+	}
+}
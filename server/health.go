@@ -0,0 +1,180 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/database"
+	"networkDev/middleware"
+	"networkDev/utils"
+	"networkDev/utils/timeutil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// healthCheckTimeout 单项依赖检查的超时时间
+const healthCheckTimeout = 2 * time.Second
+
+// 依赖检查状态
+const (
+	checkStatusUp       = "up"
+	checkStatusDown     = "down"
+	checkStatusDisabled = "disabled"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// dependencyCheck 单项依赖检查结果
+type dependencyCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// templatesLoaded 模板是否已加载
+	templatesLoaded atomic.Bool
+	// shuttingDown 服务是否正在关闭
+	shuttingDown atomic.Bool
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// RegisterHealthRoutes 注册探活与就绪检查接口
+// - /healthz：进程存活即返回 200
+// - /readyz：检查数据库、Redis、迁移与模板，全部正常且未进入关闭流程时返回 200，否则返回 503
+func RegisterHealthRoutes(router *gin.Engine) {
+	middleware.ExemptFromMaintenance("/healthz")
+	middleware.ExemptFromMaintenance("/readyz")
+
+	router.GET("/healthz", HealthzHandler)
+	router.GET("/readyz", ReadyzHandler)
+}
+
+// SetTemplatesLoaded 标记模板已加载
+func SetTemplatesLoaded() {
+	templatesLoaded.Store(true)
+}
+
+// SetShuttingDown 标记服务进入关闭流程，之后 /readyz 返回 503
+func SetShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// HealthzHandler 存活检查
+func HealthzHandler(c *gin.Context) {
+	utils.WriteSuccessResponse(c, "ok", gin.H{
+		"status": "alive",
+		"uptime": timeutil.GetServerUptimeString(),
+	})
+}
+
+// ReadyzHandler 就绪检查
+func ReadyzHandler(c *gin.Context) {
+	checks := map[string]dependencyCheck{
+		"database":   checkDatabase(),
+		"redis":      checkRedis(),
+		"migrations": checkMigrations(),
+		"templates":  checkTemplates(),
+	}
+
+	ready := !shuttingDown.Load()
+	for _, check := range checks {
+		if check.Status == checkStatusDown {
+			ready = false
+		}
+	}
+
+	ec, msg, state := constants.CodeOK, "ready", "ready"
+	if shuttingDown.Load() {
+		state = "shutting_down"
+	}
+	if !ready {
+		ec, msg = constants.CodeServiceUnavailable, "not ready"
+		if state == "ready" {
+			state = "not_ready"
+		}
+	}
+
+	c.JSON(ec.Status, utils.NewResponse(c, ec, msg, gin.H{
+		"status": state,
+		"checks": checks,
+	}))
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// checkDatabase 检查数据库连通性
+func checkDatabase() dependencyCheck {
+	start := time.Now()
+	db, err := database.GetDB()
+	if err == nil {
+		err = utils.PingDatabase(db, healthCheckTimeout)
+	}
+	return newDependencyCheck("database", start, err)
+}
+
+// checkRedis 检查Redis连通性
+// - 未配置Redis时视为未启用，不影响就绪状态
+// - 已配置但启动时连接失败时报告不可用：会话、验证码、任务锁与缓存已回退为单实例模式，需要重启恢复
+func checkRedis() dependencyCheck {
+	if !utils.IsRedisConfigured() {
+		return dependencyCheck{Status: checkStatusDisabled}
+	}
+	if !utils.IsRedisAvailable() {
+		return dependencyCheck{Status: checkStatusDown, Error: "Redis启动时连接失败，已回退为单实例模式"}
+	}
+	start := time.Now()
+	return newDependencyCheck("redis", start, utils.PingRedis(healthCheckTimeout))
+}
+
+// checkMigrations 检查是否存在未执行的迁移
+func checkMigrations() dependencyCheck {
+	start := time.Now()
+	pending, err := database.PendingMigrationCount()
+	if err == nil && pending > 0 {
+		check := newDependencyCheck("migrations", start, nil)
+		check.Status = checkStatusDown
+		check.Error = "存在未执行的数据库迁移"
+		return check
+	}
+	return newDependencyCheck("migrations", start, err)
+}
+
+// checkTemplates 检查模板是否已加载
+func checkTemplates() dependencyCheck {
+	if !templatesLoaded.Load() {
+		return dependencyCheck{Status: checkStatusDown, Error: "模板未加载"}
+	}
+	return dependencyCheck{Status: checkStatusUp}
+}
+
+// newDependencyCheck 根据检查耗时与错误生成检查结果
+// /readyz 无需认证，错误详情可能包含数据库地址等内部信息，只写入日志，响应中仅返回状态
+func newDependencyCheck(name string, start time.Time, err error) dependencyCheck {
+	check := dependencyCheck{
+		Status:    checkStatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		check.Status = checkStatusDown
+		logrus.WithError(err).WithField("check", name).Warn("就绪检查失败")
+	}
+	return check
+}
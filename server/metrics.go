@@ -0,0 +1,80 @@
+package server
+
+import (
+	"net"
+	"net/http"
+
+	"networkDev/middleware"
+	"networkDev/utils/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// RegisterMetricsRoutes 在主服务上注册 Prometheus 指标接口
+// 未启用或配置了独立监听地址（metrics.listen）时不注册
+func RegisterMetricsRoutes(router *gin.Engine) {
+	if !viper.GetBool("metrics.enabled") || viper.GetString("metrics.listen") != "" {
+		return
+	}
+
+	path := metricsPath()
+	token := viper.GetString("metrics.token")
+	handler := gin.WrapH(metrics.Handler(token))
+
+	middleware.ExemptFromMaintenance(path)
+	if token == "" {
+		// 未配置令牌时仅允许本机访问
+		router.GET(path, loopbackOnly(), handler)
+		return
+	}
+	router.GET(path, handler)
+}
+
+// NewMetricsServer 创建独立监听的指标服务
+// 未启用或未配置 metrics.listen 时返回 nil
+func NewMetricsServer() *http.Server {
+	listen := viper.GetString("metrics.listen")
+	if !viper.GetBool("metrics.enabled") || listen == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath(), metrics.Handler(viper.GetString("metrics.token")))
+	return &http.Server{
+		Addr:    listen,
+		Handler: mux,
+	}
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// metricsPath 获取指标接口路径
+func metricsPath() string {
+	if path := viper.GetString("metrics.path"); path != "" {
+		return path
+	}
+	return "/metrics"
+}
+
+// loopbackOnly 仅允许来自本机回环地址的请求
+// 直接使用连接的远端地址，不信任 X-Forwarded-For 等可伪造的头部
+func loopbackOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		host, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+		if err != nil {
+			host = c.Request.RemoteAddr
+		}
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	adminctl "networkDev/controllers/admin"
+	"networkDev/middleware"
 	"networkDev/web"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
 // ============================================================================
@@ -15,10 +18,20 @@ import (
 
 // RegisterRoutes 聚合注册所有路由
 func RegisterRoutes(router *gin.Engine) {
+	// 维护模式中间件需要在注册路由前挂载，才能作用于所有路由
+	router.Use(middleware.MaintenanceMiddleware(adminctl.IsAdminAuthenticated))
+
 	registerStaticRoutes(router)
 	registerFaviconRoute(router)
 	RegisterHomeRoutes(router)
-	RegisterAdminRoutes(router)
+	RegisterClientRoutes(router)
+	// 配置了独立后台监听地址时，后台路由只在该地址上提供
+	if viper.GetString("server.admin.listen") == "" {
+		RegisterAdminRoutes(router)
+	}
+	RegisterMetricsRoutes(router)
+	RegisterHealthRoutes(router)
+	RegisterDocsRoutes(router)
 
 }
 
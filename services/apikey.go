@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// apiKeyRandomBytes 密钥随机部分的字节数（编码后32个字符）
+	apiKeyRandomBytes = 24
+	// apiKeyDisplayLength 列表中展示的密钥前缀长度
+	apiKeyDisplayLength = 12
+	// apiKeyTouchInterval 最近使用时间的更新间隔，避免每次调用都写数据库
+	apiKeyTouchInterval = time.Minute
+	// defaultAuditHistoryDays 配置文件中缺少 security.audit_history_days 时的审计日志保留天数
+	defaultAuditHistoryDays = 180
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// ErrAPIKeyInvalid 密钥不存在或格式错误
+	ErrAPIKeyInvalid = errors.New("API密钥无效")
+	// ErrAPIKeyDisabled 密钥已停用
+	ErrAPIKeyDisabled = errors.New("API密钥已停用")
+	// ErrAPIKeyExpired 密钥已过期
+	ErrAPIKeyExpired = errors.New("API密钥已过期")
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// CreateAPIKey 生成新的API密钥并保存，返回只展示一次的密钥明文
+func CreateAPIKey(ctx context.Context, key *models.AdminAPIKey) (string, error) {
+	buf := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成API密钥失败: %w", err)
+	}
+	plain := models.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+	key.Prefix = plain[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKey(plain)
+	if err := db.WithContext(ctx).Create(key).Error; err != nil {
+		return "", fmt.Errorf("保存API密钥失败: %w", err)
+	}
+	return plain, nil
+}
+
+// AuthenticateAPIKey 校验密钥明文并返回对应的密钥记录
+// 每次调用都查询数据库，停用或删除后在所有实例上立即生效；最近使用时间按分钟更新
+func AuthenticateAPIKey(ctx context.Context, plain, ip string) (*models.AdminAPIKey, error) {
+	if !strings.HasPrefix(plain, models.APIKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	db = db.WithContext(ctx)
+
+	var key models.AdminAPIKey
+	if err := db.Where("key_hash = ?", hashAPIKey(plain)).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyInvalid
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if key.Status != models.APIKeyEnabled {
+		return nil, ErrAPIKeyDisabled
+	}
+	if key.Expired(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval || key.LastUsedIP != ip {
+		err := db.Model(&models.AdminAPIKey{}).Where("id = ?", key.ID).
+			UpdateColumns(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+		if err != nil {
+			logrus.WithError(err).WithField("api_key_id", key.ID).Warn("更新API密钥使用时间失败")
+		}
+		key.LastUsedAt = &now
+		key.LastUsedIP = ip
+	}
+	return &key, nil
+}
+
+// RecordAudit 写入一条审计日志，失败时只记录日志不影响请求
+func RecordAudit(ctx context.Context, entry *models.AuditLog) {
+	db, err := database.GetDB()
+	if err == nil {
+		err = db.WithContext(ctx).Create(entry).Error
+	}
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"actor":  entry.Actor,
+			"method": entry.Method,
+			"path":   entry.Path,
+		}).Warn("写入审计日志失败")
+	}
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// hashAPIKey 计算密钥明文的 SHA-256 摘要
+// 密钥为高熵随机值，直接摘要即可，无需加盐慢哈希
+func hashAPIKey(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// cleanupAuditLogs 删除超过 security.audit_history_days 天的审计日志
+func cleanupAuditLogs(db *gorm.DB) (int64, error) {
+	historyDays := defaultAuditHistoryDays
+	if viper.IsSet("security.audit_history_days") {
+		historyDays = viper.GetInt("security.audit_history_days")
+	}
+	if historyDays <= 0 {
+		return 0, nil
+	}
+	result := db.Where("created_at < ?", time.Now().AddDate(0, 0, -historyDays)).Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"networkDev/database"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// backupCron 定时备份的默认执行计划，每隔 backup.interval 小时执行一次
+func backupCron() string {
+	interval := viper.GetInt("backup.interval")
+	if interval < 1 {
+		interval = 24
+	}
+	return fmt.Sprintf("@every %dh", interval)
+}
+
+// runBackupJob 生成一次备份并按 backup.retention 清理旧备份
+func runBackupJob(ctx context.Context) (string, error) {
+	dir := viper.GetString("backup.dir")
+	if dir == "" {
+		dir = "./backups"
+	}
+	retention := viper.GetInt("backup.retention")
+
+	file, manifest, err := database.CreateBackupFile(dir)
+	if err != nil {
+		return "", fmt.Errorf("生成备份失败: %w", err)
+	}
+	logrus.WithFields(logrus.Fields{
+		"file":   file,
+		"tables": len(manifest.Tables),
+	}).Info("定时备份完成")
+
+	removed, err := database.PruneBackups(dir, retention)
+	if err != nil {
+		return "", fmt.Errorf("备份已生成，清理旧备份失败: %w", err)
+	}
+	return fmt.Sprintf("已生成备份 %s，清理旧备份 %d 个", file, len(removed)), nil
+}
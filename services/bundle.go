@@ -0,0 +1,202 @@
+package services
+
+import (
+	"archive/zip"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"networkDev/models"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// IntegrationManifestVersion 接入包清单格式版本，字段含义变化时递增
+// 版本 2 起清单不再包含任何私钥与对称密钥
+const IntegrationManifestVersion = 2
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// IntegrationManifest 客户端接入包清单（manifest.json）
+type IntegrationManifest struct {
+	Version     int              `json:"version"`      // 清单格式版本
+	GeneratedAt time.Time        `json:"generated_at"` // 生成时间
+	App         IntegrationApp   `json:"app"`          // 应用信息
+	APIs        []IntegrationAPI `json:"apis"`         // 接口列表，按接口类型排序
+}
+
+// IntegrationApp 接入包中的应用信息
+type IntegrationApp struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// IntegrationAPI 接入包中的接口配置
+type IntegrationAPI struct {
+	UUID    string            `json:"uuid"`
+	APIType int               `json:"api_type"`
+	Name    string            `json:"name"`
+	Enabled bool              `json:"enabled"`
+	Submit  IntegrationCipher `json:"submit"` // 提交方向：客户端加密、服务端解密
+	Return  IntegrationCipher `json:"return"` // 返回方向：服务端加密、客户端解密
+}
+
+// IntegrationCipher 接入包中一个方向的算法与公开密钥
+// 接入包只包含公开信息：RSA/RSA动态 的提交方向导出公钥
+// RSA/RSA动态 返回方向的私钥与 RC4/易加密 的对称密钥保存在服务端，不导出，
+// 以 KeyRequired 标记，由接入方从后台接口配置中取得后在运行时设置
+type IntegrationCipher struct {
+	Algorithm     int    `json:"algorithm"`
+	AlgorithmName string `json:"algorithm_name"`
+	PublicKey     string `json:"public_key,omitempty"` // RSA公钥（PEM），客户端加密使用
+	KeyRequired   bool   `json:"key_required"`         // 是否需要接入方另行设置密钥
+}
+
+// bundleFile 接入包中由模板生成的源码文件
+type bundleFile struct {
+	template string // 模板文件名（bundle 目录下）
+	name     string // 压缩包内的路径
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+//go:embed bundle/*.tmpl
+var bundleTemplatesFS embed.FS
+
+// bundleFiles 接入包源码文件列表
+var bundleFiles = []bundleFile{
+	{template: "python.tmpl", name: "python/netdev_client.py"},
+	{template: "csharp.tmpl", name: "csharp/NetDevClient.cs"},
+	{template: "cpp.tmpl", name: "cpp/netdev_client.hpp"},
+	{template: "elang.tmpl", name: "elang/网络验证接入.txt"},
+}
+
+// bundleTemplates 解析后的源码模板
+var bundleTemplates = template.Must(template.New("bundle").Funcs(template.FuncMap{
+	"quote":   quoteBundleString,
+	"equote":  quoteElangString,
+	"comment": bundleComment,
+}).ParseFS(bundleTemplatesFS, "bundle/*.tmpl"))
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// BuildIntegrationManifest 根据应用与其接口配置生成接入包清单
+func BuildIntegrationManifest(app *models.App, apis []models.API) *IntegrationManifest {
+	manifest := &IntegrationManifest{
+		Version:     IntegrationManifestVersion,
+		GeneratedAt: time.Now(),
+		App:         IntegrationApp{UUID: app.UUID, Name: app.Name, Version: app.Version},
+		APIs:        make([]IntegrationAPI, 0, len(apis)),
+	}
+	for _, api := range apis {
+		manifest.APIs = append(manifest.APIs, IntegrationAPI{
+			UUID:    api.UUID,
+			APIType: api.APIType,
+			Name:    models.GetAPITypeName(api.APIType),
+			Enabled: api.Status == 1,
+			Submit:  integrationCipher(api.SubmitAlgorithm, api.SubmitPublicKey, true),
+			Return:  integrationCipher(api.ReturnAlgorithm, api.ReturnPublicKey, false),
+		})
+	}
+	return manifest
+}
+
+// WriteIntegrationBundle 将接入包（manifest.json 与各语言源码）以 zip 格式写入 w
+func WriteIntegrationBundle(w io.Writer, manifest *IntegrationManifest) error {
+	zw := zip.NewWriter(w)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化接入清单失败: %w", err)
+	}
+	if err := writeBundleEntry(zw, "manifest.json", manifest.GeneratedAt, func(fw io.Writer) error {
+		_, err := fw.Write(manifestBytes)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for _, file := range bundleFiles {
+		if err := writeBundleEntry(zw, file.name, manifest.GeneratedAt, func(fw io.Writer) error {
+			return bundleTemplates.ExecuteTemplate(fw, file.template, manifest)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// integrationCipher 生成一个方向的公开配置
+// submit 为 true 时表示提交方向；只有 RSA 提交方向导出公钥，其余需要密钥的方向只做标记
+func integrationCipher(algorithm int, publicKey string, submit bool) IntegrationCipher {
+	cipher := IntegrationCipher{Algorithm: algorithm, AlgorithmName: models.GetAlgorithmName(algorithm)}
+	switch algorithm {
+	case models.AlgorithmRSA, models.AlgorithmRSADynamic:
+		if submit {
+			cipher.PublicKey = publicKey
+		} else {
+			cipher.KeyRequired = true
+		}
+	case models.AlgorithmRC4, models.AlgorithmEasy:
+		cipher.KeyRequired = true
+	}
+	return cipher
+}
+
+// writeBundleEntry 在压缩包中写入一个文件
+func writeBundleEntry(zw *zip.Writer, name string, modified time.Time, write func(io.Writer) error) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	if err := write(fw); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// quoteBundleString 生成 Python/C#/C++ 通用的双引号字符串字面量
+// 只转义反斜杠、双引号与换行，其余字符（包括中文）原样输出
+func quoteBundleString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", `\r`, "\n", `\n`, "\t", `\t`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// quoteElangString 生成易语言文本常量表达式
+// 易语言文本不支持转义，多行文本以 #换行符 连接，全角引号替换为半角
+func quoteElangString(s string) string {
+	s = strings.NewReplacer("“", `"`, "”", `"`, "\r", "").Replace(s)
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	parts := make([]string, 0, len(lines))
+	for i, line := range lines {
+		part := "“" + line + "”"
+		if i < len(lines)-1 || strings.HasSuffix(s, "\n") {
+			part += " ＋ #换行符"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ＋ ")
+}
+
+// bundleComment 去除换行，避免应用名称等内容破坏单行注释
+func bundleComment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
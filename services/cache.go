@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+	"networkDev/utils/cache"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 缓存键前缀，Redis 与进程内缓存使用相同的键
+const (
+	cacheKeyApp      = "cache:app:"
+	cacheKeyAPI      = "cache:api:"
+	cacheKeyVariable = "cache:variable:"
+	cacheKeyFunction = "cache:function:"
+)
+
+// 缓存失效的实例间事件主题
+const (
+	topicCacheInvalidate = "cache.invalidate"
+	topicCachePurge      = "cache.purge"
+)
+
+// 缓存配置默认值（配置文件中缺少 cache 配置时使用）
+const (
+	defaultCacheLocalSize = 1000
+	defaultCacheLocalTTL  = 60
+	defaultCacheRedisTTL  = 300
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// APIConfig 缓存的接口配置
+// 包含接口记录与已解析密钥的提交、返回加解密器；为共享缓存对象，调用方不得修改
+type APIConfig struct {
+	API    models.API
+	Submit *APICipher
+	Return *APICipher
+}
+
+// sealedEntry Redis 中的缓存内容
+// 应用密钥、接口私钥等敏感字段在数据库中加密存储，写入 Redis 前同样整体加密
+type sealedEntry struct {
+	Data string `json:"data"`
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// localCache 进程内缓存，首次使用时按配置创建
+	localCache     *cache.LRU[string, any]
+	localCacheOnce sync.Once
+)
+
+// ============================================================================
+// 初始化
+// ============================================================================
+
+func init() {
+	// 其他实例修改数据后只需删除本实例的进程内缓存，Redis 中的缓存已由修改方删除
+	OnClusterEvent(topicCacheInvalidate, func(keys []string) {
+		getLocalCache().Delete(keys...)
+	})
+	OnClusterEvent(topicCachePurge, func([]string) {
+		getLocalCache().Purge()
+	})
+}
+
+// ============================================================================
+// 查询函数
+// ============================================================================
+
+// GetApp 根据UUID获取应用（进程内缓存 -> Redis -> 数据库）
+// 应用不存在时返回 gorm.ErrRecordNotFound
+func GetApp(ctx context.Context, appUUID string) (*models.App, error) {
+	app, err := readThrough(ctx, cacheKeyApp+appUUID, func(db *gorm.DB) (*models.App, error) {
+		var app models.App
+		if err := db.Where("uuid = ?", appUUID).First(&app).Error; err != nil {
+			return nil, err
+		}
+		return &app, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	copied := *app
+	return &copied, nil
+}
+
+// GetAPIConfig 根据应用UUID与接口类型获取接口配置与已解析的加解密器
+// 接口不存在时返回 gorm.ErrRecordNotFound
+func GetAPIConfig(ctx context.Context, appUUID string, apiType int) (*APIConfig, error) {
+	key := apiCacheKey(appUUID, apiType)
+	if cached, ok := getLocal(key).(*APIConfig); ok {
+		return cached, nil
+	}
+
+	api, err := readRedis(ctx, key, func(db *gorm.DB) (*models.API, error) {
+		var api models.API
+		if err := db.Where("app_uuid = ? AND api_type = ?", appUUID, apiType).First(&api).Error; err != nil {
+			return nil, err
+		}
+		return &api, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	submit, err := NewAPICipher(api.SubmitAlgorithm, api.SubmitPublicKey, api.SubmitPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("解析接口提交密钥失败: %w", err)
+	}
+	ret, err := NewAPICipher(api.ReturnAlgorithm, api.ReturnPublicKey, api.ReturnPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("解析接口返回密钥失败: %w", err)
+	}
+
+	config := &APIConfig{API: *api, Submit: submit, Return: ret}
+	getLocalCache().Set(key, config)
+	return config, nil
+}
+
+// GetVariable 根据别名获取变量
+// 变量不存在时返回 gorm.ErrRecordNotFound
+func GetVariable(ctx context.Context, alias string) (*models.Variable, error) {
+	variable, err := readThrough(ctx, cacheKeyVariable+alias, func(db *gorm.DB) (*models.Variable, error) {
+		var variable models.Variable
+		if err := db.Where("alias = ?", alias).First(&variable).Error; err != nil {
+			return nil, err
+		}
+		return &variable, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	copied := *variable
+	return &copied, nil
+}
+
+// GetFunction 根据别名获取函数
+// 函数不存在时返回 gorm.ErrRecordNotFound
+func GetFunction(ctx context.Context, alias string) (*models.Function, error) {
+	function, err := readThrough(ctx, cacheKeyFunction+alias, func(db *gorm.DB) (*models.Function, error) {
+		var function models.Function
+		if err := db.Where("alias = ?", alias).First(&function).Error; err != nil {
+			return nil, err
+		}
+		return &function, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	copied := *function
+	return &copied, nil
+}
+
+// ============================================================================
+// 失效函数
+// ============================================================================
+
+// InvalidateApps 使应用缓存失效
+// 应在数据库修改提交之后调用
+func InvalidateApps(ctx context.Context, appUUIDs ...string) {
+	keys := make([]string, 0, len(appUUIDs))
+	for _, appUUID := range appUUIDs {
+		keys = append(keys, cacheKeyApp+appUUID)
+	}
+	invalidate(ctx, keys)
+}
+
+// InvalidateAPI 使单个接口配置缓存失效
+func InvalidateAPI(ctx context.Context, appUUID string, apiType int) {
+	invalidate(ctx, []string{apiCacheKey(appUUID, apiType)})
+}
+
+// InvalidateAppAPIs 使应用下所有接口配置缓存失效（用于删除应用）
+func InvalidateAppAPIs(ctx context.Context, appUUIDs ...string) {
+	apiTypes := models.GetDefaultAPITypes()
+	keys := make([]string, 0, len(appUUIDs)*len(apiTypes))
+	for _, appUUID := range appUUIDs {
+		for _, apiType := range apiTypes {
+			keys = append(keys, apiCacheKey(appUUID, apiType))
+		}
+	}
+	invalidate(ctx, keys)
+}
+
+// InvalidateVariables 使变量缓存失效
+// 修改别名时需要同时传入新旧别名
+func InvalidateVariables(ctx context.Context, aliases ...string) {
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		keys = append(keys, cacheKeyVariable+alias)
+	}
+	invalidate(ctx, keys)
+}
+
+// InvalidateFunctions 使函数缓存失效
+// 修改别名时需要同时传入新旧别名
+func InvalidateFunctions(ctx context.Context, aliases ...string) {
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		keys = append(keys, cacheKeyFunction+alias)
+	}
+	invalidate(ctx, keys)
+}
+
+// InvalidateAllCache 清空全部客户端接口缓存（用于恢复备份等批量修改）
+func InvalidateAllCache(ctx context.Context) {
+	getLocalCache().Purge()
+
+	client := utils.GetRedis()
+	if client == nil {
+		return
+	}
+	for _, prefix := range []string{cacheKeyApp, cacheKeyAPI, cacheKeyVariable, cacheKeyFunction} {
+		iter := client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+		var keys []string
+		for iter.Next(ctx) {
+			keys = append(keys, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			logrus.WithError(err).WithField("prefix", prefix).Warn("扫描Redis缓存键失败")
+			continue
+		}
+		if len(keys) > 0 {
+			_ = utils.RedisDel(ctx, keys...)
+		}
+	}
+	Broadcast(ctx, topicCachePurge)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// getLocalCache 获取进程内缓存
+func getLocalCache() *cache.LRU[string, any] {
+	localCacheOnce.Do(func() {
+		size := cacheSetting("cache.local_size", defaultCacheLocalSize)
+		ttl := cacheSetting("cache.local_ttl", defaultCacheLocalTTL)
+		localCache = cache.NewLRU[string, any](size, time.Duration(ttl)*time.Second)
+	})
+	return localCache
+}
+
+// getLocal 读取进程内缓存，不存在时返回 nil
+func getLocal(key string) any {
+	value, _ := getLocalCache().Get(key)
+	return value
+}
+
+// readThrough 依次从进程内缓存、Redis、数据库读取，并回填各级缓存
+func readThrough[T any](ctx context.Context, key string, loader func(db *gorm.DB) (*T, error)) (*T, error) {
+	if cached, ok := getLocal(key).(*T); ok {
+		return cached, nil
+	}
+	value, err := readRedis(ctx, key, loader)
+	if err != nil {
+		return nil, err
+	}
+	getLocalCache().Set(key, value)
+	return value, nil
+}
+
+// readRedis 从Redis读取（加密存储），未命中时从数据库加载并写回
+func readRedis[T any](ctx context.Context, key string, loader func(db *gorm.DB) (*T, error)) (*T, error) {
+	// 数据库加载的结果直接返回，避免写入 Redis 后再解密一次
+	var loaded *T
+	entry, err := utils.RedisGetOrSet(ctx, key, time.Duration(cacheSetting("cache.redis_ttl", defaultCacheRedisTTL))*time.Second, func() (*sealedEntry, error) {
+		db, err := database.GetDB()
+		if err != nil {
+			return nil, err
+		}
+		value, err := loader(db.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		loaded = value
+		return seal(value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if loaded != nil {
+		return loaded, nil
+	}
+	if entry == nil {
+		return nil, errors.New("缓存内容为空")
+	}
+	return unseal[T](entry)
+}
+
+// seal 序列化并加密缓存内容
+func seal(value any) (*sealedEntry, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptString(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return &sealedEntry{Data: encrypted}, nil
+}
+
+// unseal 解密并反序列化缓存内容
+func unseal[T any](entry *sealedEntry) (*T, error) {
+	data, err := utils.DecryptString(entry.Data)
+	if err != nil {
+		return nil, fmt.Errorf("解密缓存内容失败: %w", err)
+	}
+	var value T
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		return nil, fmt.Errorf("解析缓存内容失败: %w", err)
+	}
+	return &value, nil
+}
+
+// invalidate 删除本实例与Redis中的缓存，并通知其他实例
+func invalidate(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	getLocalCache().Delete(keys...)
+	_ = utils.RedisDel(ctx, keys...)
+	Broadcast(ctx, topicCacheInvalidate, keys...)
+}
+
+// apiCacheKey 接口配置的缓存键
+func apiCacheKey(appUUID string, apiType int) string {
+	return fmt.Sprintf("%s%s:%d", cacheKeyAPI, appUUID, apiType)
+}
+
+// cacheSetting 读取缓存配置，配置文件中未设置时使用默认值
+func cacheSetting(key string, defaultValue int) int {
+	if !viper.IsSet(key) {
+		return defaultValue
+	}
+	return viper.GetInt(key)
+}
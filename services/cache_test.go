@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+)
+
+// createTestApp 创建测试应用
+func createTestApp(t *testing.T, name string) *models.App {
+	t.Helper()
+	db, err := database.GetDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &models.App{Name: name, Status: 1}
+	if err := db.Create(app).Error; err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
+
+// TestSealUnseal 缓存内容加密后写入 Redis，不包含敏感字段明文
+func TestSealUnseal(t *testing.T) {
+	app := &models.App{UUID: "APP-1", Name: "sealed", Secret: "plain-app-secret"}
+	entry, err := seal(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(entry.Data, "plain-app-secret") || strings.Contains(entry.Data, "sealed") {
+		t.Fatalf("缓存内容包含明文: %s", entry.Data)
+	}
+
+	restored, err := unseal[models.App](entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.UUID != app.UUID || restored.Name != app.Name || restored.Secret != app.Secret {
+		t.Fatalf("unseal = %+v", restored)
+	}
+
+	// 密文被篡改或内容不是 JSON 时返回错误
+	tampered := &sealedEntry{Data: entry.Data[:len(entry.Data)-4] + "AAAA"}
+	if _, err := unseal[models.App](tampered); err == nil {
+		t.Fatal("篡改的密文应解密失败")
+	}
+	invalid, err := utils.EncryptString("not json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unseal[models.App](&sealedEntry{Data: invalid}); err == nil {
+		t.Fatal("非 JSON 内容应解析失败")
+	}
+}
+
+// TestAppCacheInvalidation 修改数据后未失效前读取缓存，失效后读取新数据
+func TestAppCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	db, _ := database.GetDB()
+	app := createTestApp(t, "before")
+
+	cached, err := GetApp(ctx, app.UUID)
+	if err != nil || cached.Name != "before" {
+		t.Fatalf("GetApp = %+v, %v", cached, err)
+	}
+	// 返回的是副本，修改不影响缓存
+	cached.Name = "modified"
+
+	db.Model(&models.App{}).Where("id = ?", app.ID).Update("name", "after")
+	if got, _ := GetApp(ctx, app.UUID); got.Name != "before" {
+		t.Fatalf("失效前应读取缓存，实际 %q", got.Name)
+	}
+	InvalidateApps(ctx, app.UUID)
+	if got, _ := GetApp(ctx, app.UUID); got.Name != "after" {
+		t.Fatalf("失效后应读取新数据，实际 %q", got.Name)
+	}
+}
+
+// TestAPIConfigInvalidation 接口配置按应用与接口类型失效，删除应用时失效全部接口
+func TestAPIConfigInvalidation(t *testing.T) {
+	ctx := context.Background()
+	db, _ := database.GetDB()
+	app := createTestApp(t, "api-cache")
+	api := &models.API{AppUUID: app.UUID, APIType: models.APITypeGetBulletin, Status: 0}
+	if err := db.Create(api).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := GetAPIConfig(ctx, app.UUID, models.APITypeGetBulletin)
+	if err != nil || config.API.Status != 0 {
+		t.Fatalf("GetAPIConfig = %+v, %v", config, err)
+	}
+	db.Model(api).Update("status", 1)
+	InvalidateAPI(ctx, app.UUID, models.APITypeGetUpdateUrl)
+	if config, _ := GetAPIConfig(ctx, app.UUID, models.APITypeGetBulletin); config.API.Status != 0 {
+		t.Fatal("失效其他接口类型不应影响该接口")
+	}
+	InvalidateAPI(ctx, app.UUID, models.APITypeGetBulletin)
+	if config, _ := GetAPIConfig(ctx, app.UUID, models.APITypeGetBulletin); config.API.Status != 1 {
+		t.Fatal("失效后应读取新配置")
+	}
+
+	db.Model(api).Update("status", 0)
+	InvalidateAppAPIs(ctx, app.UUID)
+	if config, _ := GetAPIConfig(ctx, app.UUID, models.APITypeGetBulletin); config.API.Status != 0 {
+		t.Fatal("InvalidateAppAPIs 后应读取新配置")
+	}
+}
+
+// TestClusterCacheEvents 其他实例发送的失效与清空事件删除本实例的进程内缓存
+func TestClusterCacheEvents(t *testing.T) {
+	ctx := context.Background()
+	db, _ := database.GetDB()
+	app := createTestApp(t, "cluster-1")
+	if _, err := GetApp(ctx, app.UUID); err != nil {
+		t.Fatal(err)
+	}
+
+	db.Model(&models.App{}).Where("id = ?", app.ID).Update("name", "cluster-2")
+	dispatchClusterEvent(clusterMessage{Topic: topicCacheInvalidate, Keys: []string{cacheKeyApp + app.UUID}})
+	if got, _ := GetApp(ctx, app.UUID); got.Name != "cluster-2" {
+		t.Fatalf("失效事件后应读取新数据，实际 %q", got.Name)
+	}
+
+	db.Model(&models.App{}).Where("id = ?", app.ID).Update("name", "cluster-3")
+	dispatchClusterEvent(clusterMessage{Topic: topicCachePurge})
+	if got, _ := GetApp(ctx, app.UUID); got.Name != "cluster-3" {
+		t.Fatalf("清空事件后应读取新数据，实际 %q", got.Name)
+	}
+
+	db.Model(&models.App{}).Where("id = ?", app.ID).Update("name", "cluster-4")
+	InvalidateAllCache(ctx)
+	if got, _ := GetApp(ctx, app.UUID); got.Name != "cluster-4" {
+		t.Fatalf("InvalidateAllCache 后应读取新数据，实际 %q", got.Name)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"networkDev/utils"
+
+	"github.com/mojocn/base64Captcha"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// captchaKeyPrefix 验证码在Redis中的键前缀
+	captchaKeyPrefix = "captcha:"
+	// captchaTTL 验证码有效期，与验证码Cookie的有效期一致
+	captchaTTL = 5 * time.Minute
+	// captchaTimeout 单次Redis操作超时时间
+	captchaTimeout = 3 * time.Second
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// redisCaptchaStore 基于Redis的验证码存储
+// 多实例部署时生成验证码与校验验证码的请求可能落在不同实例上，验证码答案需要共享
+type redisCaptchaStore struct {
+	client *redis.Client
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	captchaStore     base64Captcha.Store
+	captchaStoreOnce sync.Once
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// GetCaptchaStore 获取验证码存储
+// 配置并能连接Redis时使用Redis存储，否则使用进程内存储（仅适用于单实例部署）
+func GetCaptchaStore() base64Captcha.Store {
+	captchaStoreOnce.Do(func() {
+		if client := utils.GetRedis(); client != nil {
+			captchaStore = &redisCaptchaStore{client: client}
+			return
+		}
+		captchaStore = base64Captcha.DefaultMemStore
+	})
+	return captchaStore
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Set 保存验证码答案
+func (s *redisCaptchaStore) Set(id string, value string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), captchaTimeout)
+	defer cancel()
+	return s.client.Set(ctx, captchaKeyPrefix+id, value, captchaTTL).Err()
+}
+
+// Get 读取验证码答案，clear 为 true 时读取后删除
+func (s *redisCaptchaStore) Get(id string, clear bool) string {
+	ctx, cancel := context.WithTimeout(context.Background(), captchaTimeout)
+	defer cancel()
+
+	key := captchaKeyPrefix + id
+	if !clear {
+		value, err := s.client.Get(ctx, key).Result()
+		if err != nil && err != redis.Nil {
+			logrus.WithError(err).Warn("读取验证码失败")
+		}
+		return value
+	}
+
+	// 使用事务读取并删除，兼容不支持 GETDEL 的 Redis 版本
+	var get *redis.StringCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		logrus.WithError(err).Warn("读取验证码失败")
+		return ""
+	}
+	return get.Val()
+}
+
+// Verify 校验验证码（不区分大小写），clear 为 true 时无论是否正确都删除验证码
+func (s *redisCaptchaStore) Verify(id, answer string, clear bool) bool {
+	value := s.Get(id, clear)
+	if value == "" || answer == "" {
+		return false
+	}
+	return strings.EqualFold(value, answer)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// CardMaxGenerateCount 单次最多生成的卡密数量
+const CardMaxGenerateCount = 10000
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// CardGenerateOptions 批量生成卡密参数
+type CardGenerateOptions struct {
+	AppUUID   string     // 所属应用UUID
+	Count     int        // 生成数量
+	Duration  int        // 卡密时长（分钟）
+	ExpiresAt *time.Time // 有效期截止时间，为空表示长期有效
+	Remark    string     // 备注信息
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// ParseCardDuration 解析卡密时长，返回分钟数
+// 支持 m（分钟）、h（小时）、d（天）后缀，不带后缀时按分钟计算
+func ParseCardDuration(text string) (int, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return 0, fmt.Errorf("卡密时长不能为空")
+	}
+
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(text, "d"):
+		multiplier = 24 * 60
+		text = strings.TrimSuffix(text, "d")
+	case strings.HasSuffix(text, "h"):
+		multiplier = 60
+		text = strings.TrimSuffix(text, "h")
+	case strings.HasSuffix(text, "m"):
+		text = strings.TrimSuffix(text, "m")
+	}
+
+	value, err := strconv.Atoi(text)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("无效的卡密时长: %s", text)
+	}
+	return value * multiplier, nil
+}
+
+// GenerateCards 为应用批量生成卡密，同一次生成的卡密共享一个批次号
+func GenerateCards(ctx context.Context, opts CardGenerateOptions) ([]models.Card, error) {
+	if opts.Count < 1 || opts.Count > CardMaxGenerateCount {
+		return nil, fmt.Errorf("生成数量必须在 1~%d 之间", CardMaxGenerateCount)
+	}
+	if opts.Duration <= 0 {
+		return nil, fmt.Errorf("卡密时长必须大于0")
+	}
+
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	batchNo := time.Now().Format("20060102150405")
+	cards := make([]models.Card, opts.Count)
+	for i := range cards {
+		cards[i] = models.Card{
+			AppUUID:   opts.AppUUID,
+			Duration:  opts.Duration,
+			Status:    models.CardStatusUnused,
+			BatchNo:   batchNo,
+			Remark:    strings.TrimSpace(opts.Remark),
+			ExpiresAt: opts.ExpiresAt,
+		}
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&cards, 200).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
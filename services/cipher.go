@@ -0,0 +1,129 @@
+package services
+
+import (
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"networkDev/models"
+	"networkDev/utils/encrypt"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// APICipher 接口一侧（提交或返回）的加解密器
+// 创建时一次性解析密钥（RSA PEM、RC4 十六进制、易加密整数数组），随接口配置缓存复用
+type APICipher struct {
+	Algorithm  int
+	rc4Key     []byte
+	easyKey    []int
+	publicKey  *rsa.PublicKey
+	privateKey *rsa.PrivateKey
+}
+
+// ============================================================================
+// 构造函数
+// ============================================================================
+
+// NewAPICipher 根据算法与密钥创建加解密器
+// - RC4：privateKey 为十六进制密钥（兼容 base64）
+// - RSA/RSA动态：publicKey、privateKey 为 PEM，可只配置其中之一
+// - 易加密：privateKey 为逗号分隔的整数数组
+func NewAPICipher(algorithm int, publicKey, privateKey string) (*APICipher, error) {
+	cipher := &APICipher{Algorithm: algorithm}
+
+	switch algorithm {
+	case models.AlgorithmNone:
+	case models.AlgorithmRC4:
+		key, err := parseRC4Key(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		cipher.rc4Key = key
+	case models.AlgorithmRSA, models.AlgorithmRSADynamic:
+		var err error
+		if strings.TrimSpace(publicKey) != "" {
+			if cipher.publicKey, err = encrypt.PublicKeyFromPEM(publicKey); err != nil {
+				return nil, err
+			}
+		}
+		if strings.TrimSpace(privateKey) != "" {
+			if cipher.privateKey, err = encrypt.PrivateKeyFromPEM(privateKey); err != nil {
+				return nil, err
+			}
+		}
+	case models.AlgorithmEasy:
+		cipher.easyKey = encrypt.ParseKeyFromString(privateKey)
+		if len(cipher.easyKey) == 0 {
+			return nil, errors.New("易加密密钥为空或格式错误")
+		}
+	default:
+		return nil, fmt.Errorf("不支持的算法类型: %d", algorithm)
+	}
+	return cipher, nil
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Encrypt 按算法加密数据，不加密时原样返回
+func (c *APICipher) Encrypt(plaintext string) (string, error) {
+	switch c.Algorithm {
+	case models.AlgorithmNone:
+		return plaintext, nil
+	case models.AlgorithmRC4:
+		return encrypt.NewRC4Encrypt(c.rc4Key).Encrypt(plaintext)
+	case models.AlgorithmRSA:
+		return encrypt.NewRSAEncrypt(c.publicKey, c.privateKey).EncryptLargeData(plaintext)
+	case models.AlgorithmRSADynamic:
+		return encrypt.NewRSADynamicEncryptFromKeys(c.publicKey, c.privateKey).Encrypt(plaintext)
+	case models.AlgorithmEasy:
+		return encrypt.EncryptWithKey(plaintext, c.easyKey), nil
+	default:
+		return "", fmt.Errorf("不支持的算法类型: %d", c.Algorithm)
+	}
+}
+
+// Decrypt 按算法解密数据，不加密时原样返回
+func (c *APICipher) Decrypt(ciphertext string) (string, error) {
+	switch c.Algorithm {
+	case models.AlgorithmNone:
+		return ciphertext, nil
+	case models.AlgorithmRC4:
+		return encrypt.NewRC4Encrypt(c.rc4Key).Decrypt(ciphertext)
+	case models.AlgorithmRSA:
+		return encrypt.NewRSAEncrypt(c.publicKey, c.privateKey).DecryptLargeData(ciphertext)
+	case models.AlgorithmRSADynamic:
+		return encrypt.NewRSADynamicEncryptFromKeys(c.publicKey, c.privateKey).Decrypt(ciphertext)
+	case models.AlgorithmEasy:
+		plain := encrypt.DecryptWithKey(ciphertext, c.easyKey)
+		if plain == "" && ciphertext != "" {
+			return "", errors.New("易加密解密失败")
+		}
+		return plain, nil
+	default:
+		return "", fmt.Errorf("不支持的算法类型: %d", c.Algorithm)
+	}
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// parseRC4Key 解析RC4密钥
+// 后台生成的密钥为十六进制字符串，无法按十六进制解析时尝试 base64
+func parseRC4Key(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, errors.New("RC4密钥不能为空")
+	}
+	if decoded, err := hex.DecodeString(key); err == nil {
+		return decoded, nil
+	}
+	return encrypt.ParseRC4KeyFromString(key)
+}
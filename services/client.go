@@ -0,0 +1,1230 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"networkDev/constants"
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+	"networkDev/utils/metrics"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 客户端登录主体类型
+const (
+	ClientKindCard    = "card"    // 卡密登录
+	ClientKindAccount = "account" // 账号登录
+)
+
+// 客户端会话相关的存储ID前缀
+// 与会话保存在同一个会话存储中，但不以应用会话前缀开头，不计入在线会话统计
+const (
+	clientIndexPrefix  = "client_index:"  // 登录主体当前的会话令牌列表
+	clientKickedPrefix = "client_kicked:" // 被顶号或被下线的会话令牌，用于心跳返回明确的错误码
+)
+
+// 登录主体锁
+// 登录、退出、换绑等修改同一主体会话或绑定信息的操作串行执行
+const (
+	clientLockStripes = 64                    // 进程内锁的分段数
+	clientLockTTL     = 10 * time.Second      // 分布式锁有效期
+	clientLockWait    = 3 * time.Second       // 等待分布式锁的最长时间
+	clientLockRetry   = 50 * time.Millisecond // 重试获取分布式锁的间隔
+)
+
+// 客户端会话参数
+const (
+	clientCheckMinInterval = 1  // 校验间隔最小值（分钟）
+	clientSessionTTLFactor = 3  // 会话有效期为校验间隔的倍数
+	clientTokenBytes       = 16 // 会话令牌随机字节数
+)
+
+// clientDateLayout 换绑、注册、试用按天计数使用的日期格式
+const clientDateLayout = "2006-01-02"
+
+// 账号用户名与密码长度限制
+const (
+	ClientUsernameMinLength = 3
+	ClientUsernameMaxLength = 64
+	ClientPasswordMinLength = 6
+	ClientPasswordMaxLength = 64
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// ClientCaller 客户端请求来源
+type ClientCaller struct {
+	App         *models.App // 请求的应用
+	IP          string      // 客户端IP
+	MachineCode string      // 客户端上报的机器码，可为空
+}
+
+// ClientSession 客户端会话内容
+type ClientSession struct {
+	Token       string    `json:"-"`
+	Kind        string    `json:"kind"`
+	SubjectUUID string    `json:"subject_uuid"`
+	Name        string    `json:"name"`
+	MachineCode string    `json:"machine_code"`
+	IP          string    `json:"ip"`
+	LoginAt     time.Time `json:"login_at"`
+}
+
+// ClientStatus 登录主体的到期状态
+type ClientStatus struct {
+	Kind          string     `json:"kind"`
+	Name          string     `json:"name"`
+	EndAt         *time.Time `json:"end_at"`
+	Remaining     int64      `json:"remaining"`      // 剩余秒数
+	CheckInterval int        `json:"check_interval"` // 建议的心跳间隔（秒）
+}
+
+// ClientLoginResult 登录结果
+type ClientLoginResult struct {
+	Token string `json:"token"`
+	ClientStatus
+}
+
+// ClientIdentity 不依赖会话的身份凭据，用于换绑等登录失败后仍需执行的操作
+// 卡密登录传入 CardKey，账号登录传入 Username 与 Password
+type ClientIdentity struct {
+	CardKey  string
+	Username string
+	Password string
+}
+
+// ClientRegisterInput 注册账号参数
+type ClientRegisterInput struct {
+	Username string
+	Password string
+	Email    string
+}
+
+// ClientRebindResult 换绑结果
+type ClientRebindResult struct {
+	EndAt    *time.Time `json:"end_at"`
+	Rebinds  int        `json:"rebinds"`  // 当前计数周期内已换绑次数
+	Deducted int        `json:"deducted"` // 本次扣除的时长（分钟）
+}
+
+// clientSubject 登录主体（卡密或账号）
+type clientSubject struct {
+	card    *models.Card
+	account *models.Account
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// clientSubjectLocks 进程内登录主体锁
+// 未配置Redis时分布式锁总是成功，需要进程内锁保证同一实例内串行
+var clientSubjectLocks [clientLockStripes]sync.Mutex
+
+// clientBindingColumns 到期时间与设备绑定字段对应的数据库列
+var clientBindingColumns = []string{
+	"end_at", "machine_code", "bind_ip", "machine_rebinds", "machine_rebind_date",
+	"ip_rebinds", "ip_rebind_date", "last_login_at", "last_login_ip",
+}
+
+// ============================================================================
+// 登录函数
+// ============================================================================
+
+// ClientLoginCard 卡密登录
+// 未使用的卡密在首次登录时激活，到期时间为激活时间加卡密面值时长；已充值到账号的卡密不能直接登录
+func ClientLoginCard(ctx context.Context, caller ClientCaller, cardKey string) (*ClientLoginResult, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	card, err := findClientCard(db, caller.App.UUID, cardKey)
+	if err != nil {
+		return nil, err
+	}
+	if card.AccountUUID != "" {
+		return nil, constants.CodeCardUsed
+	}
+	if card.Status == models.CardStatusUnused {
+		if err := activateClientCard(ctx, db, caller, card, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return clientLogin(ctx, db, caller, clientSubject{card: card})
+}
+
+// ClientLoginAccount 账号登录
+func ClientLoginAccount(ctx context.Context, caller ClientCaller, username, password string) (*ClientLoginResult, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := verifyClientAccount(db, caller.App.UUID, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return clientLogin(ctx, db, caller, clientSubject{account: account})
+}
+
+// ClientLogout 退出登录，删除会话
+func ClientLogout(ctx context.Context, caller ClientCaller, token string) error {
+	session, err := ClientCheckSession(ctx, caller, token)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockClientSubject(ctx, caller.App.UUID, session.SubjectUUID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := GetSessionStore().Delete(ctx, AppSessionID(caller.App.UUID, token)); err != nil {
+		return err
+	}
+	sessions, err := loadClientSessions(ctx, caller.App.UUID, session.SubjectUUID)
+	if err != nil {
+		return err
+	}
+	return saveClientSessionIndex(ctx, caller.App, session.SubjectUUID, sessions)
+}
+
+// ============================================================================
+// 会话函数
+// ============================================================================
+
+// ClientCheckSession 读取会话，不检查登录主体的状态
+// 会话不存在时返回 CodeSessionInvalid，被顶号或被下线时返回 CodeSessionKicked
+func ClientCheckSession(ctx context.Context, caller ClientCaller, token string) (*ClientSession, error) {
+	if token == "" {
+		return nil, constants.CodeSessionInvalid
+	}
+	store := GetSessionStore()
+	value, ok, err := store.Get(ctx, AppSessionID(caller.App.UUID, token))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if _, kicked, err := store.Get(ctx, clientKickedID(caller.App.UUID, token)); err == nil && kicked {
+			return nil, constants.CodeSessionKicked
+		}
+		return nil, constants.CodeSessionInvalid
+	}
+
+	var session ClientSession
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, constants.CodeSessionInvalid
+	}
+	session.Token = token
+	return &session, nil
+}
+
+// ClientHeartbeat 心跳校验
+// 检查会话、黑名单与登录主体的状态和到期时间，校验通过后延长会话有效期；
+// 登录主体已被禁用或已到期时删除会话
+func ClientHeartbeat(ctx context.Context, caller ClientCaller, token string) (*ClientStatus, error) {
+	session, err := ClientCheckSession(ctx, caller, token)
+	if err != nil {
+		return nil, err
+	}
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := loadClientSubject(db, caller.App.UUID, session)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = checkClientBlacklist(db, caller.App.UUID, session.MachineCode, session.IP)
+	if err == nil {
+		err = subject.usable(now)
+	}
+	if err != nil {
+		_ = GetSessionStore().Delete(ctx, AppSessionID(caller.App.UUID, token))
+		return nil, err
+	}
+
+	ttl := clientSessionTTL(caller.App)
+	store := GetSessionStore()
+	if ok, err := store.Touch(ctx, AppSessionID(caller.App.UUID, token), ttl); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, constants.CodeSessionInvalid
+	}
+	_, _ = store.Touch(ctx, clientIndexID(caller.App.UUID, session.SubjectUUID), ttl)
+	return subject.status(caller.App, now), nil
+}
+
+// ClientSubjectStatus 获取会话所属登录主体的到期状态
+func ClientSubjectStatus(ctx context.Context, caller ClientCaller, session *ClientSession) (*ClientStatus, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := loadClientSubject(db, caller.App.UUID, session)
+	if err != nil {
+		return nil, err
+	}
+	return subject.status(caller.App, time.Now()), nil
+}
+
+// ============================================================================
+// 账号函数
+// ============================================================================
+
+// ClientRegister 注册账号
+// - 开启注册限制时按机器码（未上报机器码时按IP）统计注册次数
+// - 开启试用时，同一机器码在限制时间内首次注册的账号获得试用时长
+func ClientRegister(ctx context.Context, caller ClientCaller, input ClientRegisterInput) (*models.Account, error) {
+	app := caller.App
+	if app.RegisterEnabled != 1 {
+		return nil, constants.CodeRegisterDisabled
+	}
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkClientBlacklist(db, app.UUID, caller.MachineCode, caller.IP); err != nil {
+		return nil, err
+	}
+
+	// 同一设备的注册串行执行，保证注册次数与试用的统计准确
+	device := caller.MachineCode
+	deviceColumn := "register_machine"
+	if device == "" {
+		device = caller.IP
+		deviceColumn = "register_ip"
+	}
+	unlock, err := lockClientSubject(ctx, app.UUID, "register:"+device)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var exists int64
+	if err := db.Model(&models.Account{}).Where("app_uuid = ? AND username = ?", app.UUID, input.Username).Count(&exists).Error; err != nil {
+		return nil, err
+	}
+	if exists > 0 {
+		return nil, constants.CodeAccountExists
+	}
+
+	now := time.Now()
+	if app.RegisterLimitEnabled == 1 {
+		var count int64
+		query := db.Model(&models.Account{}).Where("app_uuid = ? AND "+deviceColumn+" = ?", app.UUID, device)
+		if app.RegisterLimitTime == 0 {
+			query = query.Where("created_at >= ?", startOfDay(now))
+		}
+		if err := query.Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count >= int64(app.RegisterCount) {
+			return nil, constants.CodeRegisterLimited
+		}
+	}
+
+	salt, err := utils.GenerateRandomSalt()
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := utils.HashPasswordWithSalt(input.Password, salt)
+	if err != nil {
+		return nil, err
+	}
+	account := &models.Account{
+		AppUUID:         app.UUID,
+		Username:        input.Username,
+		Password:        hashed,
+		PasswordSalt:    salt,
+		Email:           input.Email,
+		Status:          models.AccountStatusNormal,
+		RegisterMachine: caller.MachineCode,
+		RegisterIP:      caller.IP,
+	}
+
+	if app.TrialEnabled == 1 && app.TrialDuration > 0 {
+		var granted int64
+		query := db.Model(&models.Account{}).
+			Where("app_uuid = ? AND "+deviceColumn+" = ? AND trial_granted_at IS NOT NULL", app.UUID, device)
+		if app.TrialLimitTime == 0 {
+			query = query.Where("trial_granted_at >= ?", startOfDay(now))
+		}
+		if err := query.Count(&granted).Error; err != nil {
+			return nil, err
+		}
+		if granted == 0 {
+			endAt := now.Add(time.Duration(app.TrialDuration) * time.Minute)
+			account.TrialGrantedAt = &now
+			account.EndAt = &endAt
+		}
+	}
+
+	if err := db.Create(account).Error; err != nil {
+		// 其他设备同时注册了相同的用户名
+		if db.Model(&models.Account{}).Where("app_uuid = ? AND username = ?", app.UUID, input.Username).Count(&exists).Error == nil && exists > 0 {
+			return nil, constants.CodeAccountExists
+		}
+		return nil, err
+	}
+	EmitWebhookEvent(ctx, models.WebhookEventAccountRegistered, app.UUID, map[string]interface{}{
+		"uuid":         account.UUID,
+		"username":     account.Username,
+		"email":        account.Email,
+		"trial":        account.TrialGrantedAt != nil,
+		"end_at":       account.EndAt,
+		"machine_code": caller.MachineCode,
+		"ip":           caller.IP,
+	})
+	return account, nil
+}
+
+// ClientRecharge 使用卡密为账号充值
+// 卡密面值时长从账号当前到期时间（已到期时从当前时间）开始累加，充值后卡密标记为已使用
+func ClientRecharge(ctx context.Context, caller ClientCaller, username, cardKey string) (*models.Account, *models.Card, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var account models.Account
+	if err := db.Where("app_uuid = ? AND username = ?", caller.App.UUID, username).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, constants.CodeAccountNotFound
+		}
+		return nil, nil, err
+	}
+	card, err := findClientCard(db, caller.App.UUID, cardKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock, err := lockClientSubject(ctx, caller.App.UUID, account.UUID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&account, account.ID).Error; err != nil {
+			return err
+		}
+		if err := checkCardUnused(card, now); err != nil {
+			return err
+		}
+		result := tx.Model(&models.Card{}).
+			Where("id = ? AND status = ?", card.ID, models.CardStatusUnused).
+			Updates(map[string]interface{}{"status": models.CardStatusUsed, "used_at": now, "account_uuid": account.UUID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return constants.CodeCardUsed
+		}
+
+		start := now
+		if account.EndAt != nil && account.EndAt.After(now) {
+			start = *account.EndAt
+		}
+		endAt := start.Add(time.Duration(card.Duration) * time.Minute)
+		account.EndAt = &endAt
+		return tx.Model(&account).Update("end_at", endAt).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	card.Status = models.CardStatusUsed
+	card.UsedAt = &now
+	card.AccountUUID = account.UUID
+	metrics.RecordCardActivation(card.AppUUID)
+	EmitWebhookEvent(ctx, models.WebhookEventAccountRecharged, account.AppUUID, map[string]interface{}{
+		"uuid":     account.UUID,
+		"username": account.Username,
+		"card":     card.CardKey,
+		"duration": card.Duration,
+		"end_at":   account.EndAt,
+	})
+	return &account, card, nil
+}
+
+// FindClientCard 查找应用下的卡密，卡密不存在时返回 CodeCardNotFound
+func FindClientCard(ctx context.Context, appUUID, cardKey string) (*models.Card, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return findClientCard(db, appUUID, cardKey)
+}
+
+// ClientChangePassword 修改账号密码，修改后账号的所有会话下线
+func ClientChangePassword(ctx context.Context, caller ClientCaller, username, password, newPassword string) error {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return err
+	}
+	account, err := verifyClientAccount(db, caller.App.UUID, username, password)
+	if err != nil {
+		return err
+	}
+	return setClientAccountPassword(ctx, db, caller.App, account, newPassword)
+}
+
+// ClientSendResetCode 向账号的邮箱发送找回密码验证码
+// 账号不存在或邮箱与账号登记的邮箱不一致时返回 CodeAccountNotFound；
+// 未启用邮件发送时返回 ErrMailDisabled，发送过于频繁时返回 ErrMailTooFrequent
+func ClientSendResetCode(ctx context.Context, caller ClientCaller, username, email string) error {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return err
+	}
+	account, err := findClientAccountByEmail(db, caller.App.UUID, username, email)
+	if err != nil {
+		return err
+	}
+	return SendPasswordResetCode(ctx, account.Email, account.Username)
+}
+
+// ClientResetPassword 使用邮箱验证码重置账号密码，重置后账号的所有会话下线
+// 验证码错误、过期或校验次数过多时返回 CodeResetCodeInvalid
+func ClientResetPassword(ctx context.Context, caller ClientCaller, username, email, code, newPassword string) error {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return err
+	}
+	account, err := findClientAccountByEmail(db, caller.App.UUID, username, email)
+	if err != nil {
+		return err
+	}
+	ok, err := VerifyPasswordResetCode(ctx, account.Email, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return constants.CodeResetCodeInvalid
+	}
+	return setClientAccountPassword(ctx, db, caller.App, account, newPassword)
+}
+
+// ============================================================================
+// 换绑与风控函数
+// ============================================================================
+
+// ClientRebind 换绑机器码（machine 为 true）或IP
+// - 新的机器码取请求上报的机器码，新的IP取请求来源IP
+// - 未绑定或与当前绑定一致时直接绑定，不计入换绑次数
+// - 每个计数周期（每天或永久）内可换绑的次数为免费次数与换绑次数中的较大值，超过免费次数后每次扣除设置的时长
+// 换绑成功后登录主体的所有会话下线，需要重新登录
+func ClientRebind(ctx context.Context, caller ClientCaller, identity ClientIdentity, machine bool) (*ClientRebindResult, error) {
+	app := caller.App
+	enabled, daily, free, count, deduct := app.IPRebindEnabled, app.IPRebindLimit == 0, app.IPFreeCount, app.IPRebindCount, app.IPRebindDeduct
+	target := caller.IP
+	if machine {
+		enabled, daily, free, count, deduct = app.MachineRebindEnabled, app.MachineRebindLimit == 0, app.MachineFreeCount, app.MachineRebindCount, app.MachineRebindDeduct
+		target = caller.MachineCode
+	}
+	if enabled != 1 {
+		return nil, constants.CodeRebindDisabled
+	}
+
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := findClientIdentity(db, app.UUID, identity)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockClientSubject(ctx, app.UUID, subject.uuid())
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	binding := subject.binding()
+	// 未激活的卡密还没有绑定，首次登录时直接绑定
+	if subject.card != nil && subject.card.Status == models.CardStatusUnused {
+		return &ClientRebindResult{}, nil
+	}
+	now := time.Now()
+	if err := subject.usable(now); err != nil {
+		return nil, err
+	}
+
+	current, rebinds, date := &binding.BindIP, &binding.IPRebinds, &binding.IPRebindDate
+	if machine {
+		current, rebinds, date = &binding.MachineCode, &binding.MachineRebinds, &binding.MachineRebindDate
+	}
+	today := now.Format(clientDateLayout)
+	if daily && *date != today {
+		*rebinds = 0
+	}
+
+	result := &ClientRebindResult{}
+	previous := *current
+	if previous != "" && previous != target {
+		if *rebinds >= max(free, count) {
+			return nil, constants.CodeRebindExhausted
+		}
+		if *rebinds >= free && deduct > 0 {
+			endAt := binding.EndAt.Add(-time.Duration(deduct) * time.Minute)
+			binding.EndAt = &endAt
+			result.Deducted = deduct
+		}
+		*rebinds++
+		*date = today
+	}
+	*current = target
+
+	if err := saveClientBinding(db, subject); err != nil {
+		return nil, err
+	}
+	if err := kickClientSubjectLocked(ctx, app, subject.uuid()); err != nil {
+		return nil, err
+	}
+	result.EndAt = binding.EndAt
+	result.Rebinds = *rebinds
+	if previous != target {
+		bindType := "ip"
+		if machine {
+			bindType = "machine"
+		}
+		EmitWebhookEvent(ctx, models.WebhookEventDeviceRebind, app.UUID, map[string]interface{}{
+			"kind":     subject.kind(),
+			"uuid":     subject.uuid(),
+			"name":     subject.name(),
+			"type":     bindType,
+			"old":      previous,
+			"new":      target,
+			"rebinds":  result.Rebinds,
+			"deducted": result.Deducted,
+			"end_at":   result.EndAt,
+		})
+	}
+	return result, nil
+}
+
+// ClientBan 封停会话所属的登录主体并使其所有会话下线
+// 卡密标记为已禁用，账号标记为已封禁并记录原因
+func ClientBan(ctx context.Context, caller ClientCaller, session *ClientSession, reason string) error {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockClientSubject(ctx, caller.App.UUID, session.SubjectUUID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if session.Kind == ClientKindCard {
+		err = db.Model(&models.Card{}).Where("uuid = ?", session.SubjectUUID).Update("status", models.CardStatusDisabled).Error
+	} else {
+		err = db.Model(&models.Account{}).Where("uuid = ?", session.SubjectUUID).
+			Updates(map[string]interface{}{"status": models.AccountStatusBanned, "ban_reason": truncate(reason, 255)}).Error
+	}
+	if err != nil {
+		return err
+	}
+	EmitWebhookEvent(ctx, models.WebhookEventAccountBanned, caller.App.UUID, map[string]interface{}{
+		"kind":   session.Kind,
+		"uuid":   session.SubjectUUID,
+		"name":   session.Name,
+		"reason": reason,
+	})
+	return kickClientSubjectLocked(ctx, caller.App, session.SubjectUUID)
+}
+
+// ClientBlacklist 将会话的机器码与IP加入应用黑名单，并使登录主体的所有会话下线
+// 返回加入黑名单的记录，已存在的记录不重复添加
+func ClientBlacklist(ctx context.Context, caller ClientCaller, session *ClientSession, reason string) ([]models.Blacklist, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := []models.Blacklist{{AppUUID: caller.App.UUID, Type: models.BlacklistTypeIP, Value: session.IP, Reason: truncate(reason, 255)}}
+	if session.MachineCode != "" {
+		entries = append(entries, models.Blacklist{AppUUID: caller.App.UUID, Type: models.BlacklistTypeMachine, Value: session.MachineCode, Reason: truncate(reason, 255)})
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entries).Error; err != nil {
+		return nil, err
+	}
+	values := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		values = append(values, map[string]interface{}{"type": entry.Type, "value": entry.Value})
+	}
+	EmitWebhookEvent(ctx, models.WebhookEventBlacklistAdded, caller.App.UUID, map[string]interface{}{
+		"kind":    session.Kind,
+		"uuid":    session.SubjectUUID,
+		"name":    session.Name,
+		"entries": values,
+		"reason":  reason,
+	})
+
+	unlock, err := lockClientSubject(ctx, caller.App.UUID, session.SubjectUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return entries, kickClientSubjectLocked(ctx, caller.App, session.SubjectUUID)
+}
+
+// ClientDeductTime 扣除会话所属登录主体的剩余时长
+func ClientDeductTime(ctx context.Context, caller ClientCaller, session *ClientSession, minutes int) (*ClientStatus, error) {
+	db, err := clientDB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockClientSubject(ctx, caller.App.UUID, session.SubjectUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	subject, err := loadClientSubject(db, caller.App.UUID, session)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := subject.usable(now); err != nil {
+		return nil, err
+	}
+	binding := subject.binding()
+	endAt := binding.EndAt.Add(-time.Duration(minutes) * time.Minute)
+	binding.EndAt = &endAt
+	if err := saveClientBinding(db, subject); err != nil {
+		return nil, err
+	}
+	return subject.status(caller.App, now), nil
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// kind 登录主体类型
+func (s clientSubject) kind() string {
+	if s.card != nil {
+		return ClientKindCard
+	}
+	return ClientKindAccount
+}
+
+// uuid 登录主体UUID
+func (s clientSubject) uuid() string {
+	if s.card != nil {
+		return s.card.UUID
+	}
+	return s.account.UUID
+}
+
+// name 登录主体名称（卡密或用户名）
+func (s clientSubject) name() string {
+	if s.card != nil {
+		return s.card.CardKey
+	}
+	return s.account.Username
+}
+
+// binding 到期时间与设备绑定
+func (s clientSubject) binding() *models.ClientBinding {
+	if s.card != nil {
+		return &s.card.ClientBinding
+	}
+	return &s.account.ClientBinding
+}
+
+// record 对应的数据库记录
+func (s clientSubject) record() interface{} {
+	if s.card != nil {
+		return s.card
+	}
+	return s.account
+}
+
+// usable 检查登录主体是否可以登录
+func (s clientSubject) usable(now time.Time) error {
+	if s.card != nil {
+		switch {
+		case s.card.Status == models.CardStatusDisabled:
+			return constants.CodeCardDisabled
+		case s.card.Status == models.CardStatusExpired, s.card.Expired(now):
+			return constants.CodeCardExpired
+		}
+		return nil
+	}
+	switch {
+	case s.account.Status == models.AccountStatusBanned:
+		return constants.CodeAccountDisabled
+	case s.account.Expired(now):
+		return constants.CodeAccountExpired
+	}
+	return nil
+}
+
+// status 到期状态
+func (s clientSubject) status(app *models.App, now time.Time) *ClientStatus {
+	binding := s.binding()
+	status := &ClientStatus{
+		Kind:          s.kind(),
+		Name:          s.name(),
+		EndAt:         binding.EndAt,
+		CheckInterval: clientCheckInterval(app) * 60,
+	}
+	if binding.EndAt != nil && binding.EndAt.After(now) {
+		status.Remaining = int64(binding.EndAt.Sub(now).Seconds())
+	}
+	return status
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// clientLogin 登录主体通过凭据校验后的公共流程
+// 依次检查黑名单、状态与到期时间、机器码与IP绑定，然后按多开设置创建会话
+func clientLogin(ctx context.Context, db *gorm.DB, caller ClientCaller, subject clientSubject) (*ClientLoginResult, error) {
+	app := caller.App
+	now := time.Now()
+	if err := checkClientBlacklist(db, app.UUID, caller.MachineCode, caller.IP); err != nil {
+		return nil, err
+	}
+	if err := subject.usable(now); err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockClientSubject(ctx, app.UUID, subject.uuid())
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// IP验证的市、省级别需要IP归属地数据，目前按IP精确匹配
+	binding := subject.binding()
+	if app.MachineVerify == 1 {
+		if binding.MachineCode == "" {
+			binding.MachineCode = caller.MachineCode
+		} else if binding.MachineCode != caller.MachineCode {
+			return nil, constants.CodeMachineMismatch
+		}
+	}
+	if app.IPVerify != 0 {
+		if binding.BindIP == "" {
+			binding.BindIP = caller.IP
+		} else if binding.BindIP != caller.IP {
+			return nil, constants.CodeIPMismatch
+		}
+	}
+
+	session := &ClientSession{
+		Kind:        subject.kind(),
+		SubjectUUID: subject.uuid(),
+		Name:        subject.name(),
+		MachineCode: caller.MachineCode,
+		IP:          caller.IP,
+		LoginAt:     now,
+	}
+	if err := openClientSession(ctx, app, session); err != nil {
+		return nil, err
+	}
+
+	binding.LastLoginAt = &now
+	binding.LastLoginIP = caller.IP
+	if err := saveClientBinding(db, subject); err != nil {
+		_ = GetSessionStore().Delete(ctx, AppSessionID(app.UUID, session.Token))
+		return nil, err
+	}
+	return &ClientLoginResult{Token: session.Token, ClientStatus: *subject.status(app, now)}, nil
+}
+
+// openClientSession 按多开设置创建会话
+// - 多开范围为单电脑或单IP时，其他电脑或IP上的会话视为冲突
+// - 会话数量达到多开数量时，最早登录的会话视为冲突
+// 顶号登录时冲突的会话被下线，非顶号登录时拒绝本次登录
+// 调用方需要持有登录主体锁
+func openClientSession(ctx context.Context, app *models.App, session *ClientSession) error {
+	sessions, err := loadClientSessions(ctx, app.UUID, session.SubjectUUID)
+	if err != nil {
+		return err
+	}
+
+	var conflicts, kept []*ClientSession
+	for _, existing := range sessions {
+		switch {
+		case app.MultiOpenScope == 0 && existing.MachineCode != session.MachineCode,
+			app.MultiOpenScope == 1 && existing.IP != session.IP:
+			conflicts = append(conflicts, existing)
+		default:
+			kept = append(kept, existing)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].LoginAt.Before(kept[j].LoginAt) })
+	if over := len(kept) - max(app.MultiOpenCount, 1) + 1; over > 0 {
+		conflicts = append(conflicts, kept[:over]...)
+		kept = kept[over:]
+	}
+	if len(conflicts) > 0 && app.LoginType == 1 {
+		return constants.CodeMultiOpenLimit
+	}
+
+	ttl := clientSessionTTL(app)
+	for _, conflict := range conflicts {
+		if err := kickClientSession(ctx, app.UUID, conflict.Token, ttl); err != nil {
+			return err
+		}
+	}
+
+	token := make([]byte, clientTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+	session.Token = hex.EncodeToString(token)
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	if err := GetSessionStore().Set(ctx, AppSessionID(app.UUID, session.Token), string(value), ttl); err != nil {
+		return err
+	}
+	return saveClientSessionIndex(ctx, app, session.SubjectUUID, append(kept, session))
+}
+
+// loadClientSessions 读取登录主体当前的有效会话，已过期的会话被忽略
+func loadClientSessions(ctx context.Context, appUUID, subjectUUID string) ([]*ClientSession, error) {
+	store := GetSessionStore()
+	value, ok, err := store.Get(ctx, clientIndexID(appUUID, subjectUUID))
+	if err != nil || !ok {
+		return nil, err
+	}
+	var tokens []string
+	if err := json.Unmarshal([]byte(value), &tokens); err != nil {
+		return nil, nil
+	}
+
+	sessions := make([]*ClientSession, 0, len(tokens))
+	for _, token := range tokens {
+		raw, ok, err := store.Get(ctx, AppSessionID(appUUID, token))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		var session ClientSession
+		if err := json.Unmarshal([]byte(raw), &session); err != nil {
+			continue
+		}
+		session.Token = token
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
+// saveClientSessionIndex 保存登录主体的会话令牌列表，列表为空时删除
+func saveClientSessionIndex(ctx context.Context, app *models.App, subjectUUID string, sessions []*ClientSession) error {
+	store := GetSessionStore()
+	id := clientIndexID(app.UUID, subjectUUID)
+	if len(sessions) == 0 {
+		return store.Delete(ctx, id)
+	}
+	tokens := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		tokens = append(tokens, session.Token)
+	}
+	value, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return store.Set(ctx, id, string(value), clientSessionTTL(app))
+}
+
+// kickClientSession 下线会话，并记录下线标记供心跳返回明确的错误码
+func kickClientSession(ctx context.Context, appUUID, token string, ttl time.Duration) error {
+	store := GetSessionStore()
+	if err := store.Delete(ctx, AppSessionID(appUUID, token)); err != nil {
+		return err
+	}
+	return store.Set(ctx, clientKickedID(appUUID, token), "1", ttl)
+}
+
+// kickClientSubjectLocked 下线登录主体的所有会话，调用方需要持有登录主体锁
+func kickClientSubjectLocked(ctx context.Context, app *models.App, subjectUUID string) error {
+	sessions, err := loadClientSessions(ctx, app.UUID, subjectUUID)
+	if err != nil {
+		return err
+	}
+	ttl := clientSessionTTL(app)
+	for _, session := range sessions {
+		if err := kickClientSession(ctx, app.UUID, session.Token, ttl); err != nil {
+			return err
+		}
+	}
+	return GetSessionStore().Delete(ctx, clientIndexID(app.UUID, subjectUUID))
+}
+
+// lockClientSubject 获取登录主体锁，返回释放函数
+// 先获取进程内锁，再在等待时间内重试获取分布式锁；等待超时返回 CodeTooManyRequests
+func lockClientSubject(ctx context.Context, appUUID, subjectUUID string) (func(), error) {
+	name := "client:" + appUUID + ":" + subjectUUID
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name))
+	local := &clientSubjectLocks[hash.Sum32()%clientLockStripes]
+	local.Lock()
+
+	deadline := time.Now().Add(clientLockWait)
+	for {
+		lock, ok, err := utils.TryLock(ctx, name, clientLockTTL)
+		if err != nil {
+			local.Unlock()
+			return nil, err
+		}
+		if ok {
+			return func() {
+				_ = lock.Release(context.WithoutCancel(ctx))
+				local.Unlock()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			local.Unlock()
+			return nil, constants.CodeTooManyRequests
+		}
+		select {
+		case <-ctx.Done():
+			local.Unlock()
+			return nil, ctx.Err()
+		case <-time.After(clientLockRetry):
+		}
+	}
+}
+
+// findClientCard 查找应用下的卡密
+func findClientCard(db *gorm.DB, appUUID, cardKey string) (*models.Card, error) {
+	var card models.Card
+	if err := db.Where("app_uuid = ? AND card_key = ?", appUUID, strings.TrimSpace(cardKey)).First(&card).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, constants.CodeCardNotFound
+		}
+		return nil, err
+	}
+	return &card, nil
+}
+
+// checkCardUnused 检查卡密是否可以激活或充值
+func checkCardUnused(card *models.Card, now time.Time) error {
+	switch card.Status {
+	case models.CardStatusUsed:
+		return constants.CodeCardUsed
+	case models.CardStatusDisabled:
+		return constants.CodeCardDisabled
+	case models.CardStatusExpired:
+		return constants.CodeCardExpired
+	}
+	if card.ExpiresAt != nil && !now.Before(*card.ExpiresAt) {
+		return constants.CodeCardExpired
+	}
+	return nil
+}
+
+// activateClientCard 激活卡密，并发激活时以先完成的为准
+func activateClientCard(ctx context.Context, db *gorm.DB, caller ClientCaller, card *models.Card, now time.Time) error {
+	if err := checkCardUnused(card, now); err != nil {
+		return err
+	}
+	endAt := now.Add(time.Duration(card.Duration) * time.Minute)
+	result := db.Model(&models.Card{}).
+		Where("id = ? AND status = ?", card.ID, models.CardStatusUnused).
+		Updates(map[string]interface{}{"status": models.CardStatusUsed, "used_at": now, "end_at": endAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return db.First(card, card.ID).Error
+	}
+	card.Status = models.CardStatusUsed
+	card.UsedAt = &now
+	card.EndAt = &endAt
+	metrics.RecordCardActivation(card.AppUUID)
+	EmitWebhookEvent(ctx, models.WebhookEventCardActivated, card.AppUUID, map[string]interface{}{
+		"uuid":         card.UUID,
+		"card":         card.CardKey,
+		"duration":     card.Duration,
+		"end_at":       card.EndAt,
+		"machine_code": caller.MachineCode,
+		"ip":           caller.IP,
+	})
+	return nil
+}
+
+// verifyClientAccount 校验账号密码，账号不存在与密码错误返回相同的错误码
+func verifyClientAccount(db *gorm.DB, appUUID, username, password string) (*models.Account, error) {
+	var account models.Account
+	if err := db.Where("app_uuid = ? AND username = ?", appUUID, username).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, constants.CodePasswordIncorrect
+		}
+		return nil, err
+	}
+	if !utils.VerifyPasswordWithSalt(password, account.PasswordSalt, account.Password) {
+		return nil, constants.CodePasswordIncorrect
+	}
+	return &account, nil
+}
+
+// setClientAccountPassword 设置账号密码并使账号的所有会话下线
+func setClientAccountPassword(ctx context.Context, db *gorm.DB, app *models.App, account *models.Account, password string) error {
+	salt, err := utils.GenerateRandomSalt()
+	if err != nil {
+		return err
+	}
+	hashed, err := utils.HashPasswordWithSalt(password, salt)
+	if err != nil {
+		return err
+	}
+	unlock, err := lockClientSubject(ctx, app.UUID, account.UUID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := db.Model(account).Updates(map[string]interface{}{"password": hashed, "password_salt": salt}).Error; err != nil {
+		return err
+	}
+	return kickClientSubjectLocked(ctx, app, account.UUID)
+}
+
+// findClientAccountByEmail 查找用户名与邮箱匹配的账号，邮箱不区分大小写
+func findClientAccountByEmail(db *gorm.DB, appUUID, username, email string) (*models.Account, error) {
+	var account models.Account
+	if err := db.Where("app_uuid = ? AND username = ?", appUUID, username).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, constants.CodeAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Email == "" || !strings.EqualFold(account.Email, strings.TrimSpace(email)) {
+		return nil, constants.CodeAccountNotFound
+	}
+	return &account, nil
+}
+
+// findClientIdentity 按身份凭据查找登录主体
+func findClientIdentity(db *gorm.DB, appUUID string, identity ClientIdentity) (clientSubject, error) {
+	if identity.CardKey != "" {
+		card, err := findClientCard(db, appUUID, identity.CardKey)
+		if err != nil {
+			return clientSubject{}, err
+		}
+		if card.AccountUUID != "" {
+			return clientSubject{}, constants.CodeCardUsed
+		}
+		return clientSubject{card: card}, nil
+	}
+	account, err := verifyClientAccount(db, appUUID, identity.Username, identity.Password)
+	if err != nil {
+		return clientSubject{}, err
+	}
+	return clientSubject{account: account}, nil
+}
+
+// loadClientSubject 加载会话所属的登录主体，主体已被删除时返回 CodeSessionInvalid
+func loadClientSubject(db *gorm.DB, appUUID string, session *ClientSession) (clientSubject, error) {
+	var subject clientSubject
+	var err error
+	if session.Kind == ClientKindCard {
+		subject.card = &models.Card{}
+		err = db.Where("app_uuid = ? AND uuid = ?", appUUID, session.SubjectUUID).First(subject.card).Error
+	} else {
+		subject.account = &models.Account{}
+		err = db.Where("app_uuid = ? AND uuid = ?", appUUID, session.SubjectUUID).First(subject.account).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return subject, constants.CodeSessionInvalid
+	}
+	return subject, err
+}
+
+// saveClientBinding 保存登录主体的到期时间与设备绑定
+func saveClientBinding(db *gorm.DB, subject clientSubject) error {
+	return db.Model(subject.record()).Select(clientBindingColumns).Updates(subject.record()).Error
+}
+
+// checkClientBlacklist 检查机器码与IP是否在应用黑名单中
+func checkClientBlacklist(db *gorm.DB, appUUID, machineCode, ip string) error {
+	query := db.Model(&models.Blacklist{}).Where("app_uuid = ?", appUUID)
+	if machineCode != "" {
+		query = query.Where("(type = ? AND value = ?) OR (type = ? AND value = ?)",
+			models.BlacklistTypeMachine, machineCode, models.BlacklistTypeIP, ip)
+	} else {
+		query = query.Where("type = ? AND value = ?", models.BlacklistTypeIP, ip)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return constants.CodeDeviceBlacklisted
+	}
+	return nil
+}
+
+// clientDB 获取绑定请求上下文的数据库连接
+func clientDB(ctx context.Context) (*gorm.DB, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.WithContext(ctx), nil
+}
+
+// clientCheckInterval 应用的校验间隔（分钟）
+func clientCheckInterval(app *models.App) int {
+	return max(app.CheckInterval, clientCheckMinInterval)
+}
+
+// clientSessionTTL 会话有效期，客户端超过校验间隔的若干倍未发送心跳后会话失效
+func clientSessionTTL(app *models.App) time.Duration {
+	return time.Duration(clientCheckInterval(app)*clientSessionTTLFactor) * time.Minute
+}
+
+// clientIndexID 登录主体会话令牌列表的存储ID
+func clientIndexID(appUUID, subjectUUID string) string {
+	return clientIndexPrefix + appUUID + ":" + subjectUUID
+}
+
+// clientKickedID 会话下线标记的存储ID
+func clientKickedID(appUUID, token string) string {
+	return clientKickedPrefix + appUUID + ":" + token
+}
+
+// startOfDay 当天零点（服务器时区）
+func startOfDay(now time.Time) time.Time {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+// truncate 截断字符串到最多 n 个字符
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"networkDev/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// clusterChannel 实例间事件的 Redis 发布订阅频道
+const clusterChannel = "networkdev:cluster"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// clusterMessage 实例间事件消息
+type clusterMessage struct {
+	Source string   `json:"source"`         // 发送消息的实例ID，实例忽略自己发送的消息
+	Topic  string   `json:"topic"`          // 事件主题
+	Keys   []string `json:"keys,omitempty"` // 事件涉及的键
+}
+
+// ClusterHandler 实例间事件处理函数
+type ClusterHandler func(keys []string)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// instanceID 当前实例ID
+	instanceID = uuid.NewString()
+
+	// clusterHandlers 按主题注册的事件处理函数
+	clusterHandlers   = make(map[string][]ClusterHandler)
+	clusterHandlersMu sync.RWMutex
+
+	// clusterSyncOnce 确保只启动一次事件订阅
+	clusterSyncOnce sync.Once
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// InstanceID 获取当前实例ID（进程启动时生成）
+func InstanceID() string {
+	return instanceID
+}
+
+// OnClusterEvent 注册实例间事件处理函数
+// 处理函数只在收到其他实例发送的事件时调用，本实例的修改应在发送事件前自行处理
+func OnClusterEvent(topic string, handler ClusterHandler) {
+	clusterHandlersMu.Lock()
+	defer clusterHandlersMu.Unlock()
+	clusterHandlers[topic] = append(clusterHandlers[topic], handler)
+}
+
+// Broadcast 向其他实例发送事件
+// 未配置或无法连接Redis时为单实例部署，直接返回
+func Broadcast(ctx context.Context, topic string, keys ...string) {
+	client := utils.GetRedis()
+	if client == nil {
+		return
+	}
+	payload, err := json.Marshal(clusterMessage{Source: instanceID, Topic: topic, Keys: keys})
+	if err != nil {
+		return
+	}
+	if err := client.Publish(ctx, clusterChannel, payload).Err(); err != nil {
+		logrus.WithError(err).WithField("topic", topic).Warn("发送实例间事件失败")
+	}
+}
+
+// StartClusterSync 订阅其他实例发送的事件（缓存失效、设置刷新等）
+// 未配置或无法连接Redis时为单实例部署，直接返回
+func StartClusterSync() {
+	client := utils.GetRedis()
+	if client == nil {
+		return
+	}
+
+	clusterSyncOnce.Do(func() {
+		pubsub := client.Subscribe(context.Background(), clusterChannel)
+		go func() {
+			for msg := range pubsub.Channel() {
+				var message clusterMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+					logrus.WithError(err).Warn("实例间事件消息格式错误")
+					continue
+				}
+				if message.Source == instanceID {
+					continue
+				}
+				dispatchClusterEvent(message)
+			}
+		}()
+		logrus.WithField("instance", instanceID).Info("已订阅实例间事件")
+	})
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// dispatchClusterEvent 调用事件主题对应的处理函数
+func dispatchClusterEvent(message clusterMessage) {
+	clusterHandlersMu.RLock()
+	handlers := clusterHandlers[message.Topic]
+	clusterHandlersMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(message.Keys)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// functionTimeout 远程函数单次执行的最长时间
+const functionTimeout = 3 * time.Second
+
+// functionEntry 远程函数的入口函数名
+const functionEntry = "main"
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// RunFunction 使用 Goja 引擎执行远程函数
+// - 代码需要定义 main 函数，args 依次作为 main 的参数传入
+// - 返回 main 的返回值（导出为 Go 值），需要能序列化为 JSON
+// - 每次调用使用独立的运行时，超过 functionTimeout 或 ctx 取消时中断执行
+func RunFunction(ctx context.Context, code string, args []interface{}) (interface{}, error) {
+	vm := goja.New()
+	vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
+
+	timer := time.AfterFunc(functionTimeout, func() {
+		vm.Interrupt(fmt.Sprintf("执行超过 %s", functionTimeout))
+	})
+	defer timer.Stop()
+	stop := context.AfterFunc(ctx, func() {
+		vm.Interrupt(ctx.Err())
+	})
+	defer stop()
+
+	if _, err := vm.RunString(code); err != nil {
+		return nil, err
+	}
+	entry, ok := goja.AssertFunction(vm.Get(functionEntry))
+	if !ok {
+		return nil, errors.New("函数代码未定义 main 函数")
+	}
+
+	values := make([]goja.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, vm.ToValue(arg))
+	}
+	result, err := entry(goja.Undefined(), values...)
+	if err != nil {
+		return nil, err
+	}
+	return result.Export(), nil
+}
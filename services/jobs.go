@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils/metrics"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 内置任务名称
+const (
+	JobSessionCleanup = "session_cleanup"
+	JobCardExpire     = "card_expire"
+	JobLogRetention   = "log_retention"
+	JobBackup         = "backup"
+	JobWebhookRetry   = "webhook_retry"
+	JobDailySummary   = "daily_summary"
+	JobMailQueue      = "mail_queue"
+	JobExpiryReminder = "card_expiry_reminder"
+)
+
+const (
+	// defaultJobHistoryDays 配置文件中缺少 scheduler.history_days 时的执行记录保留天数
+	defaultJobHistoryDays = 30
+	// staleJobRunAge 执行中状态超过该时长的记录视为实例退出导致的中断
+	staleJobRunAge = 24 * time.Hour
+	// lumberjackTimeFormat 日志切割后旧文件名中的时间格式
+	lumberjackTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// sessionCleanedAt 各应用上次清理会话的时间，用于按应用的清理间隔执行
+	sessionCleanedAt   = make(map[string]time.Time)
+	sessionCleanedAtMu sync.Mutex
+)
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// builtinJobs 内置任务定义
+func builtinJobs() []JobDefinition {
+	return []JobDefinition{
+		{
+			Name:        JobSessionCleanup,
+			Title:       "清理过期会话",
+			Description: "按应用的清理间隔删除过期的客户端会话，并更新在线会话数指标",
+			Cron:        "*/5 * * * *",
+			Enabled:     true,
+			Run:         cleanupSessions,
+		},
+		{
+			Name:        JobCardExpire,
+			Title:       "更新过期卡密",
+			Description: "将超过有效期仍未使用的卡密标记为已过期",
+			Cron:        "*/10 * * * *",
+			Enabled:     true,
+			Run:         expireCards,
+		},
+		{
+			Name:        JobLogRetention,
+			Title:       "清理日志与执行记录",
+			Description: "删除超过保留天数的任务执行记录、Webhook投递记录、邮件发送记录、审计日志与切割后的旧日志文件",
+			Cron:        "30 3 * * *",
+			Enabled:     true,
+			Run:         cleanupLogs,
+		},
+		{
+			Name:        JobBackup,
+			Title:       "定时备份",
+			Description: "生成数据库备份并按保留数量清理旧备份",
+			Cron:        backupCron(),
+			Enabled:     viper.GetBool("backup.enabled"),
+			Timeout:     6 * time.Hour,
+			Run:         runBackupJob,
+		},
+		{
+			Name:        JobWebhookRetry,
+			Title:       "重试Webhook推送",
+			Description: "重新推送失败后到达重试时间的Webhook投递记录",
+			Cron:        "* * * * *",
+			Enabled:     true,
+			Timeout:     30 * time.Minute,
+			Run:         RetryWebhookDeliveries,
+		},
+		{
+			Name:        JobDailySummary,
+			Title:       "每日汇总通知",
+			Description: "汇总最近24小时的应用、卡密、定时任务与Webhook情况，发送到订阅每日汇总的通知渠道",
+			Cron:        "0 9 * * *",
+			Enabled:     true,
+			Run:         sendDailySummary,
+		},
+		{
+			Name:        JobMailQueue,
+			Title:       "发送邮件队列",
+			Description: "发送队列中到达发送时间的邮件，失败的邮件按指数退避重试",
+			Cron:        "* * * * *",
+			Enabled:     true,
+			Timeout:     30 * time.Minute,
+			Run:         RetryMailQueue,
+		},
+		{
+			Name:        JobExpiryReminder,
+			Title:       "卡密到期提醒",
+			Description: "汇总即将到期的未使用卡密，发送邮件到告警邮箱",
+			Cron:        "0 8 * * *",
+			Enabled:     true,
+			Run:         sendExpiryReminders,
+		},
+	}
+}
+
+// cleanupSessions 按应用的清理间隔（App.CleanInterval，小时）清理过期会话
+func cleanupSessions(ctx context.Context) (string, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+	var apps []models.App
+	if err := db.WithContext(ctx).Select("uuid", "clean_interval").Find(&apps).Error; err != nil {
+		return "", fmt.Errorf("查询应用失败: %w", err)
+	}
+
+	store := GetSessionStore()
+	now := time.Now()
+	cleaned, active := 0, 0
+
+	sessionCleanedAtMu.Lock()
+	defer sessionCleanedAtMu.Unlock()
+	for _, app := range apps {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		interval := time.Duration(max(app.CleanInterval, 1)) * time.Hour
+		if last, ok := sessionCleanedAt[app.UUID]; ok && now.Sub(last) < interval {
+			continue
+		}
+
+		count, err := store.Cleanup(ctx, AppSessionPrefix(app.UUID))
+		if err != nil {
+			return "", fmt.Errorf("清理应用 %s 的会话失败: %w", app.UUID, err)
+		}
+		sessionCleanedAt[app.UUID] = now
+		metrics.SetActiveSessions(app.UUID, count)
+		cleaned++
+		active += count
+	}
+	return fmt.Sprintf("清理 %d 个应用，有效会话 %d 个", cleaned, active), nil
+}
+
+// expireCards 将超过有效期仍未使用的卡密标记为已过期
+func expireCards(ctx context.Context) (string, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+	result := db.WithContext(ctx).Model(&models.Card{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at < ?", models.CardStatusUnused, time.Now()).
+		Update("status", models.CardStatusExpired)
+	if result.Error != nil {
+		return "", fmt.Errorf("更新卡密状态失败: %w", result.Error)
+	}
+	return fmt.Sprintf("标记 %d 个卡密为已过期", result.RowsAffected), nil
+}
+
+// cleanupLogs 清理任务执行记录、Webhook投递记录、邮件发送记录、审计日志与旧日志文件
+// - 删除超过 scheduler.history_days 天的执行记录，并将长时间处于执行中的记录标记为失败
+// - 删除超过 webhook.history_days 天且已结束的Webhook投递记录
+// - 删除超过 mail.history_days 天且已结束的邮件发送记录
+// - 删除超过 security.audit_history_days 天的审计日志
+// - 删除日志切割产生的、超过 log.max_age 天的旧日志文件（lumberjack 只在切割时清理）
+func cleanupLogs(ctx context.Context) (string, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+	db = db.WithContext(ctx)
+
+	stale := db.Model(&models.JobRun{}).
+		Where("status = ? AND started_at < ?", models.JobRunStatusRunning, time.Now().Add(-staleJobRunAge)).
+		Updates(map[string]interface{}{"status": models.JobRunStatusFailed, "error": "执行中断（实例退出或超时）"})
+	if stale.Error != nil {
+		return "", fmt.Errorf("更新中断的执行记录失败: %w", stale.Error)
+	}
+
+	historyDays := defaultJobHistoryDays
+	if viper.IsSet("scheduler.history_days") {
+		historyDays = viper.GetInt("scheduler.history_days")
+	}
+	var removedRuns int64
+	if historyDays > 0 {
+		result := db.Where("status <> ? AND started_at < ?", models.JobRunStatusRunning, time.Now().AddDate(0, 0, -historyDays)).
+			Delete(&models.JobRun{})
+		if result.Error != nil {
+			return "", fmt.Errorf("删除执行记录失败: %w", result.Error)
+		}
+		removedRuns = result.RowsAffected
+	}
+
+	removedDeliveries, err := cleanupWebhookDeliveries(db)
+	if err != nil {
+		return "", fmt.Errorf("删除Webhook投递记录失败: %w", err)
+	}
+
+	removedMails, err := cleanupMailQueue(db)
+	if err != nil {
+		return "", fmt.Errorf("删除邮件发送记录失败: %w", err)
+	}
+
+	removedAudits, err := cleanupAuditLogs(db)
+	if err != nil {
+		return "", fmt.Errorf("删除审计日志失败: %w", err)
+	}
+
+	removedFiles, err := removeOldLogFiles(viper.GetString("log.file"), viper.GetInt("log.max_age"))
+	if err != nil {
+		return "", fmt.Errorf("删除旧日志文件失败: %w", err)
+	}
+	return fmt.Sprintf("删除执行记录 %d 条、投递记录 %d 条、邮件记录 %d 条、审计日志 %d 条、旧日志文件 %d 个", removedRuns, removedDeliveries, removedMails, removedAudits, removedFiles), nil
+}
+
+// removeOldLogFiles 删除日志切割产生的超过 maxAge 天的旧日志文件
+// 旧文件名格式为 <文件名>-<时间><扩展名>，压缩后追加 .gz，当前日志文件不会被删除
+func removeOldLogFiles(logFile string, maxAge int) (int, error) {
+	if logFile == "" || maxAge <= 0 {
+		return 0, nil
+	}
+
+	dir := filepath.Dir(logFile)
+	ext := filepath.Ext(logFile)
+	prefix := strings.TrimSuffix(filepath.Base(logFile), ext) + "-"
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -maxAge)
+	removed := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimSuffix(name[len(prefix):], ".gz"), ext)
+		rotatedAt, err := time.Parse(lumberjackTimeFormat, stamp)
+		if err != nil || !rotatedAt.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			logrus.WithError(err).WithField("file", name).Warn("删除旧日志文件失败")
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,757 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+	"networkDev/utils/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// defaultMailTimeout 配置文件中缺少 mail.timeout 时的单次发送超时时间
+	defaultMailTimeout = 15 * time.Second
+	// defaultMailMaxAttempts 配置文件中缺少 mail.max_attempts 时的最大尝试次数
+	defaultMailMaxAttempts = 5
+	// defaultMailHistoryDays 配置文件中缺少 mail.history_days 时的发送记录保留天数
+	defaultMailHistoryDays = 30
+	// mailRetryBatch 重试任务每次处理的邮件数量
+	mailRetryBatch = 50
+	// passwordResetTTL 找回密码验证码有效期
+	passwordResetTTL = 15 * time.Minute
+	// passwordResetMaxVerify 每个验证码最多校验次数，超过后验证码作废
+	passwordResetMaxVerify = 5
+	// passwordResetKeyPrefix 找回密码验证码在会话存储中的键前缀
+	passwordResetKeyPrefix = "mail_reset:"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// expiryReminderApp 卡密到期提醒中按应用汇总的一行
+type expiryReminderApp struct {
+	Name     string
+	Count    int64
+	Earliest string
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// ErrMailDisabled 未启用邮件发送
+	ErrMailDisabled = errors.New("未启用邮件发送（mail.enabled）")
+	// ErrMailTooFrequent 验证码发送过于频繁
+	ErrMailTooFrequent = errors.New("发送过于频繁，请稍后再试")
+
+	// errMailTaken 邮件已由其他请求或实例处理
+	errMailTaken = errors.New("邮件已由其他实例处理")
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// MailEnabled 是否启用邮件发送
+func MailEnabled() bool {
+	return viper.GetBool("mail.enabled")
+}
+
+// LoadMailTemplate 读取邮件模板，设置项不存在时使用内置默认内容
+func LoadMailTemplate(name string) database.MailTemplate {
+	defaults, _ := database.DefaultMailTemplate(name)
+	subject, html, text := database.MailTemplateSettingNames(name)
+	settings := GetSettingsService()
+	return database.MailTemplate{
+		Subject: settings.GetString(subject, defaults.Subject),
+		HTML:    settings.GetString(html, defaults.HTML),
+		Text:    settings.GetString(text, defaults.Text),
+	}
+}
+
+// SaveMailTemplate 保存邮件模板并刷新设置缓存
+// 保存前使用示例数据渲染一次，模板语法错误时返回错误
+func SaveMailTemplate(ctx context.Context, name string, tpl database.MailTemplate) error {
+	if _, err := RenderMail(tpl, MailTemplateSampleData(name)); err != nil {
+		return err
+	}
+	db, err := database.GetDB()
+	if err != nil {
+		return err
+	}
+
+	subject, html, text := database.MailTemplateSettingNames(name)
+	values := map[string]string{subject: tpl.Subject, html: tpl.HTML, text: tpl.Text}
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for key, value := range values {
+			result := tx.Model(&models.Settings{}).Where("name = ?", key).Update("value", value)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				if err := tx.Create(&models.Settings{Name: key, Value: value}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("保存邮件模板失败: %w", err)
+	}
+	GetSettingsService().RefreshCache(ctx)
+	return nil
+}
+
+// RenderMail 渲染邮件模板
+// 主题与纯文本正文使用 text/template，HTML 正文使用 html/template（自动转义变量）；
+// data 中会补充 SiteTitle（站点标题）与 Now（当前时间）
+func RenderMail(tpl database.MailTemplate, data map[string]interface{}) (database.MailTemplate, error) {
+	values := map[string]interface{}{
+		"SiteTitle": GetSettingsService().GetString("site_title", "NetworkDev"),
+		"Now":       time.Now().Format("2006-01-02 15:04:05"),
+	}
+	for key, value := range data {
+		values[key] = value
+	}
+
+	var rendered database.MailTemplate
+	var buf bytes.Buffer
+	subject, err := texttemplate.New("subject").Parse(tpl.Subject)
+	if err == nil {
+		err = subject.Execute(&buf, values)
+	}
+	if err != nil {
+		return rendered, fmt.Errorf("邮件主题模板错误: %w", err)
+	}
+	rendered.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	html, err := htmltemplate.New("html").Parse(tpl.HTML)
+	if err == nil {
+		err = html.Execute(&buf, values)
+	}
+	if err != nil {
+		return rendered, fmt.Errorf("HTML正文模板错误: %w", err)
+	}
+	rendered.HTML = buf.String()
+
+	buf.Reset()
+	text, err := texttemplate.New("text").Parse(tpl.Text)
+	if err == nil {
+		err = text.Execute(&buf, values)
+	}
+	if err != nil {
+		return rendered, fmt.Errorf("纯文本正文模板错误: %w", err)
+	}
+	rendered.Text = buf.String()
+
+	if rendered.Subject == "" {
+		return rendered, errors.New("邮件主题不能为空")
+	}
+	if strings.TrimSpace(rendered.HTML) == "" && strings.TrimSpace(rendered.Text) == "" {
+		return rendered, errors.New("HTML正文与纯文本正文不能同时为空")
+	}
+	return rendered, nil
+}
+
+// MailTemplateSampleData 邮件模板的示例数据，用于后台预览与保存前校验
+func MailTemplateSampleData(name string) map[string]interface{} {
+	switch name {
+	case models.MailTemplateAdminAlert:
+		return map[string]interface{}{
+			"Title":    "数据库健康检查失败",
+			"Severity": models.NotifySeverityText(models.NotifySeverityCritical),
+			"Lines":    []string{"错误：connection refused"},
+		}
+	case models.MailTemplatePasswordReset:
+		return map[string]interface{}{
+			"Username": "demo",
+			"Code":     "123456",
+			"Minutes":  int(passwordResetTTL.Minutes()),
+		}
+	case models.MailTemplateExpiryReminder:
+		return map[string]interface{}{
+			"Days":  3,
+			"Total": int64(12),
+			"Apps": []expiryReminderApp{
+				{Name: "示例应用", Count: 12, Earliest: time.Now().Add(24 * time.Hour).Format("2006-01-02 15:04")},
+			},
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// QueueMail 使用模板渲染邮件并写入发送队列，每个收件人一封，写入后在后台立即发送
+// 失败时由 mail_queue 任务按指数退避重试；队列写入失败（如数据库故障）时直接发送一次
+func QueueMail(ctx context.Context, name string, to []string, data map[string]interface{}) error {
+	if !MailEnabled() {
+		return ErrMailDisabled
+	}
+	rendered, err := RenderMail(LoadMailTemplate(name), data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	messages := make([]*models.MailMessage, 0, len(to))
+	for _, address := range to {
+		messages = append(messages, &models.MailMessage{
+			Template:    name,
+			To:          address,
+			Subject:     rendered.Subject,
+			HTMLBody:    rendered.HTML,
+			TextBody:    rendered.Text,
+			Status:      models.MailStatusPending,
+			NextRetryAt: &now,
+		})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	db, err := database.GetDB()
+	if err == nil {
+		err = db.WithContext(ctx).Create(&messages).Error
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("template", name).Warn("写入邮件队列失败，直接发送")
+		go func() {
+			for _, message := range messages {
+				if err := sendMail(ctx, message); err != nil {
+					logrus.WithError(err).WithField("template", name).Error("发送邮件失败")
+				}
+			}
+		}()
+		return nil
+	}
+
+	go func() {
+		for _, message := range messages {
+			if _, err := attemptMailDelivery(ctx, message.ID); err != nil && !errors.Is(err, errMailTaken) {
+				logrus.WithError(err).WithField("mail_id", message.ID).Warn("发送邮件失败")
+			}
+		}
+	}()
+	return nil
+}
+
+// SendTestMail 同步发送一封测试邮件并返回发送结果，失败时不重试
+func SendTestMail(ctx context.Context, to string) (*models.MailMessage, error) {
+	if !MailEnabled() {
+		return nil, ErrMailDisabled
+	}
+	rendered, err := RenderMail(LoadMailTemplate(models.MailTemplateTest), nil)
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	message := &models.MailMessage{
+		Template:    models.MailTemplateTest,
+		To:          to,
+		Subject:     rendered.Subject,
+		HTMLBody:    rendered.HTML,
+		TextBody:    rendered.Text,
+		Status:      models.MailStatusPending,
+		NextRetryAt: &now,
+	}
+	if err := db.WithContext(ctx).Create(message).Error; err != nil {
+		return nil, fmt.Errorf("写入邮件队列失败: %w", err)
+	}
+	return attemptMailDelivery(ctx, message.ID)
+}
+
+// ResendMail 重新发送一封已失败的邮件（重置尝试次数）
+func ResendMail(ctx context.Context, id uint) (*models.MailMessage, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	result := db.WithContext(ctx).Model(&models.MailMessage{}).
+		Where("id = ? AND status = ?", id, models.MailStatusFailed).
+		Updates(map[string]interface{}{
+			"status":        models.MailStatusPending,
+			"attempts":      0,
+			"next_retry_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("重置邮件状态失败: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("只能重新发送失败的邮件")
+	}
+	return attemptMailDelivery(ctx, id)
+}
+
+// RetryMailQueue 发送到达发送时间的邮件（mail_queue 任务）
+func RetryMailQueue(ctx context.Context) (string, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+
+	var ids []uint
+	err = db.WithContext(ctx).Model(&models.MailMessage{}).
+		Where("status = ? AND next_retry_at <= ?", models.MailStatusPending, time.Now()).
+		Order("next_retry_at").Limit(mailRetryBatch).Pluck("id", &ids).Error
+	if err != nil {
+		return "", fmt.Errorf("查询待发送邮件失败: %w", err)
+	}
+
+	attempted, succeeded := 0, 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		message, err := attemptMailDelivery(ctx, id)
+		if errors.Is(err, errMailTaken) {
+			continue
+		}
+		attempted++
+		if err == nil && message.Status == models.MailStatusSent {
+			succeeded++
+		}
+	}
+	return fmt.Sprintf("发送 %d 封，成功 %d 封", attempted, succeeded), nil
+}
+
+// SendPasswordResetCode 生成找回密码验证码并发送到邮箱
+// 验证码在 passwordResetTTL 内有效，同一邮箱每分钟最多发送一次；多实例部署时验证码保存在Redis中
+func SendPasswordResetCode(ctx context.Context, email, username string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return errors.New("邮箱地址格式错误")
+	}
+	if !MailEnabled() {
+		return ErrMailDisabled
+	}
+	count, err := utils.IncrCounter(ctx, "mail_reset_send:"+email, time.Minute)
+	if err != nil {
+		return err
+	}
+	if count > 1 {
+		return ErrMailTooFrequent
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	if err := GetSessionStore().Set(ctx, passwordResetKeyPrefix+email, hashResetCode(email, code), passwordResetTTL); err != nil {
+		return fmt.Errorf("保存验证码失败: %w", err)
+	}
+	return QueueMail(ctx, models.MailTemplatePasswordReset, []string{email}, map[string]interface{}{
+		"Username": username,
+		"Code":     code,
+		"Minutes":  int(passwordResetTTL.Minutes()),
+	})
+}
+
+// VerifyPasswordResetCode 校验找回密码验证码，校验通过后验证码作废
+// 同一验证码最多校验 passwordResetMaxVerify 次，超过后需要重新获取
+func VerifyPasswordResetCode(ctx context.Context, email, code string) (bool, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	store := GetSessionStore()
+	key := passwordResetKeyPrefix + email
+
+	expected, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	count, err := utils.IncrCounter(ctx, "mail_reset_verify:"+email, passwordResetTTL)
+	if err != nil {
+		return false, err
+	}
+	if count > passwordResetMaxVerify {
+		return false, store.Delete(ctx, key)
+	}
+	if !hmac.Equal([]byte(expected), []byte(hashResetCode(email, strings.TrimSpace(code)))) {
+		return false, nil
+	}
+	return true, store.Delete(ctx, key)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// attemptMailDelivery 发送一次队列中的邮件并保存结果
+// 发送前通过条件更新占用记录，保证同一邮件不会被多个实例同时发送
+func attemptMailDelivery(ctx context.Context, id uint) (*models.MailMessage, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	db = db.WithContext(ctx)
+
+	now := time.Now()
+	claim := db.Model(&models.MailMessage{}).
+		Where("id = ? AND status = ? AND next_retry_at <= ?", id, models.MailStatusPending, now).
+		Updates(map[string]interface{}{
+			"attempts":      gorm.Expr("attempts + 1"),
+			"next_retry_at": now.Add(mailTimeout() + time.Minute),
+		})
+	if claim.Error != nil {
+		return nil, fmt.Errorf("占用邮件失败: %w", claim.Error)
+	}
+	if claim.RowsAffected == 0 {
+		return nil, errMailTaken
+	}
+
+	var message models.MailMessage
+	if err := db.First(&message, id).Error; err != nil {
+		return nil, fmt.Errorf("查询邮件失败: %w", err)
+	}
+
+	var sendErr error
+	if !MailEnabled() {
+		sendErr = ErrMailDisabled
+	} else {
+		sendErr = sendMail(ctx, &message)
+	}
+
+	message.Error = ""
+	message.NextRetryAt = nil
+	switch {
+	case sendErr == nil:
+		sentAt := time.Now()
+		message.Status = models.MailStatusSent
+		message.SentAt = &sentAt
+	case message.Attempts >= mailMaxAttempts() || message.Template == models.MailTemplateTest:
+		message.Status = models.MailStatusFailed
+		message.Error = sendErr.Error()
+	default:
+		message.Status = models.MailStatusPending
+		message.Error = sendErr.Error()
+		next := time.Now().Add(retryBackoff(message.Attempts))
+		message.NextRetryAt = &next
+	}
+
+	err = db.Model(&message).Updates(map[string]interface{}{
+		"status":        message.Status,
+		"error":         message.Error,
+		"next_retry_at": message.NextRetryAt,
+		"sent_at":       message.SentAt,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("保存发送结果失败: %w", err)
+	}
+	return &message, nil
+}
+
+// sendMail 通过 SMTP 发送邮件
+// - tls：连接后直接建立TLS（通常为 465 端口）
+// - starttls：明文连接后使用 STARTTLS 升级，服务器不支持时拒绝发送（通常为 587 端口）
+// - none：不加密，仅用于本地测试；此时只允许对 localhost 使用密码认证
+func sendMail(ctx context.Context, message *models.MailMessage) (err error) {
+	host := viper.GetString("mail.host")
+	encryption := viper.GetString("mail.encryption")
+	if encryption == "" {
+		encryption = "starttls"
+	}
+	port := viper.GetInt("mail.port")
+	if port == 0 {
+		port = 587
+		if encryption == "tls" {
+			port = 465
+		}
+	}
+
+	ctx, span := tracing.Start(ctx, "mail.send",
+		attribute.String("mail.template", message.Template),
+		attribute.String("server.address", host),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout())
+	defer cancel()
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: viper.GetBool("mail.insecure_skip_verify")}
+	var conn net.Conn
+	if encryption == "tls" {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接SMTP服务器失败: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP握手失败: %w", err)
+	}
+	defer client.Close()
+
+	if encryption == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP服务器不支持STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS失败: %w", err)
+		}
+	}
+	if username := viper.GetString("mail.username"); username != "" {
+		if err := client.Auth(smtp.PlainAuth("", username, viper.GetString("mail.password"), host)); err != nil {
+			return fmt.Errorf("SMTP认证失败: %w", err)
+		}
+	}
+
+	from := viper.GetString("mail.from")
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("设置发件人失败: %w", err)
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return fmt.Errorf("设置收件人失败: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("发送邮件内容失败: %w", err)
+	}
+	if _, err := writer.Write(buildMailBody(from, message)); err != nil {
+		writer.Close()
+		return fmt.Errorf("发送邮件内容失败: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("发送邮件内容失败: %w", err)
+	}
+	return client.Quit()
+}
+
+// buildMailBody 生成 MIME 邮件内容，同时包含纯文本与 HTML 正文时使用 multipart/alternative
+func buildMailBody(from string, message *models.MailMessage) []byte {
+	fromName := viper.GetString("mail.from_name")
+	if fromName == "" {
+		fromName = GetSettingsService().GetString("site_title", "")
+	}
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = from[at+1:]
+	}
+
+	headers := [][2]string{
+		{"From", (&mail.Address{Name: fromName, Address: from}).String()},
+		{"To", message.To},
+		{"Subject", mime.BEncoding.Encode("UTF-8", message.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", "<" + uuid.NewString() + "@" + domain + ">"},
+		{"MIME-Version", "1.0"},
+	}
+
+	parts := make([][2]string, 0, 2)
+	if strings.TrimSpace(message.TextBody) != "" {
+		parts = append(parts, [2]string{"text/plain; charset=UTF-8", message.TextBody})
+	}
+	if strings.TrimSpace(message.HTMLBody) != "" {
+		parts = append(parts, [2]string{"text/html; charset=UTF-8", message.HTMLBody})
+	}
+
+	var body bytes.Buffer
+	if len(parts) == 1 {
+		headers = append(headers, [2]string{"Content-Type", parts[0][0]}, [2]string{"Content-Transfer-Encoding", "quoted-printable"})
+		writeQuotedPrintable(&body, parts[0][1])
+	} else {
+		writer := multipart.NewWriter(&body)
+		for _, part := range parts {
+			partWriter, _ := writer.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {part[0]},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			})
+			writeQuotedPrintable(partWriter, part[1])
+		}
+		writer.Close()
+		headers = append(headers, [2]string{"Content-Type", "multipart/alternative; boundary=" + writer.Boundary()})
+	}
+
+	var buf bytes.Buffer
+	for _, header := range headers {
+		buf.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes()
+}
+
+// writeQuotedPrintable 以 quoted-printable 编码写入正文，换行统一为 CRLF
+func writeQuotedPrintable(w io.Writer, content string) {
+	encoder := quotedprintable.NewWriter(w)
+	encoder.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")))
+	encoder.Close()
+}
+
+// hashResetCode 验证码摘要，会话存储中不保存验证码明文
+func hashResetCode(email, code string) string {
+	sum := sha256.Sum256([]byte(email + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// notifyByMail 将告警通知发送到 notify_mail_to 设置的邮箱
+// 仅在启用邮件且通知级别不低于 notify_mail_min_severity 时发送，返回是否已写入发送队列
+func notifyByMail(ctx context.Context, n Notification) bool {
+	settings := GetSettingsService()
+	if !MailEnabled() || n.Severity < settings.GetInt("notify_mail_min_severity", models.NotifySeverityWarning) {
+		return false
+	}
+	recipients := mailRecipients(settings.GetString("notify_mail_to", ""))
+	if len(recipients) == 0 {
+		return false
+	}
+	err := QueueMail(ctx, models.MailTemplateAdminAlert, recipients, map[string]interface{}{
+		"Title":    n.Title,
+		"Severity": models.NotifySeverityText(n.Severity),
+		"Lines":    n.Lines,
+	})
+	if err != nil {
+		logrus.WithError(err).WithField("event", n.Event).Warn("发送告警邮件失败")
+		return false
+	}
+	return true
+}
+
+// mailRecipients 解析逗号分隔的收件人列表，忽略格式错误的地址
+func mailRecipients(value string) []string {
+	var recipients []string
+	for _, item := range strings.Split(value, ",") {
+		if address, err := mail.ParseAddress(strings.TrimSpace(item)); err == nil {
+			recipients = append(recipients, address.Address)
+		}
+	}
+	return recipients
+}
+
+// sendExpiryReminders 汇总即将到期的未使用卡密并发送邮件（card_expiry_reminder 任务）
+// 提醒天数取自 mail_expiry_remind_days，收件人取自 notify_mail_to
+func sendExpiryReminders(ctx context.Context) (string, error) {
+	settings := GetSettingsService()
+	days := settings.GetInt("mail_expiry_remind_days", 3)
+	recipients := mailRecipients(settings.GetString("notify_mail_to", ""))
+	if !MailEnabled() || days <= 0 || len(recipients) == 0 {
+		return "未启用邮件、提醒天数为0或未设置收件人，跳过", nil
+	}
+
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+	db = db.WithContext(ctx)
+	now := time.Now()
+	expiring := db.Model(&models.Card{}).
+		Where("status = ? AND expires_at > ? AND expires_at <= ?", models.CardStatusUnused, now, now.AddDate(0, 0, days))
+
+	var groups []struct {
+		AppUUID string
+		Count   int64
+	}
+	if err := expiring.Session(&gorm.Session{}).Select("app_uuid, COUNT(*) AS count").Group("app_uuid").Scan(&groups).Error; err != nil {
+		return "", fmt.Errorf("统计即将到期的卡密失败: %w", err)
+	}
+	if len(groups) == 0 {
+		return "没有即将到期的卡密", nil
+	}
+
+	var total int64
+	apps := make([]expiryReminderApp, 0, len(groups))
+	for _, group := range groups {
+		var earliest models.Card
+		if err := expiring.Session(&gorm.Session{}).Where("app_uuid = ?", group.AppUUID).Order("expires_at").First(&earliest).Error; err != nil {
+			return "", fmt.Errorf("查询即将到期的卡密失败: %w", err)
+		}
+		name := group.AppUUID
+		var app models.App
+		if err := db.Select("name").Where("uuid = ?", group.AppUUID).First(&app).Error; err == nil {
+			name = app.Name
+		}
+		apps = append(apps, expiryReminderApp{
+			Name:     name,
+			Count:    group.Count,
+			Earliest: earliest.ExpiresAt.Format("2006-01-02 15:04"),
+		})
+		total += group.Count
+	}
+
+	err = QueueMail(ctx, models.MailTemplateExpiryReminder, recipients, map[string]interface{}{
+		"Days":  days,
+		"Total": total,
+		"Apps":  apps,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d 个应用共 %d 张卡密即将到期，已发送给 %d 个收件人", len(apps), total, len(recipients)), nil
+}
+
+// cleanupMailQueue 删除超过 mail.history_days 天且已结束的邮件记录
+func cleanupMailQueue(db *gorm.DB) (int64, error) {
+	historyDays := defaultMailHistoryDays
+	if viper.IsSet("mail.history_days") {
+		historyDays = viper.GetInt("mail.history_days")
+	}
+	if historyDays <= 0 {
+		return 0, nil
+	}
+	result := db.Where("status <> ? AND created_at < ?", models.MailStatusPending, time.Now().AddDate(0, 0, -historyDays)).
+		Delete(&models.MailMessage{})
+	return result.RowsAffected, result.Error
+}
+
+// mailTimeout 单次发送超时时间
+func mailTimeout() time.Duration {
+	if seconds := viper.GetInt("mail.timeout"); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultMailTimeout
+}
+
+// mailMaxAttempts 最大尝试次数
+func mailMaxAttempts() int {
+	if attempts := viper.GetInt("mail.max_attempts"); attempts > 0 {
+		return attempts
+	}
+	return defaultMailMaxAttempts
+}
@@ -0,0 +1,557 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+	"networkDev/utils/tracing"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// topicNotifyReload 通知渠道变更的实例间事件主题
+	topicNotifyReload = "notify.reload"
+	// notifyTimeout 单次发送超时时间
+	notifyTimeout = 10 * time.Second
+	// defaultNotifyRateLimit 渠道未设置发送频率时每分钟最多发送条数
+	defaultNotifyRateLimit = 20
+	// notifyDedupeTTL 多实例部署时同一告警的去重时间
+	notifyDedupeTTL = 10 * time.Minute
+	// maxKnownLoginIPs 记录的管理员登录IP数量上限
+	maxKnownLoginIPs = 20
+	// maxNotifyResponseLength 读取的机器人响应内容最大长度
+	maxNotifyResponseLength = 1024
+	// defaultTelegramAPI Telegram Bot API 官方地址
+	defaultTelegramAPI = "https://api.telegram.org"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Notification 告警通知内容
+type Notification struct {
+	Event    string   // 事件类型，见 models.NotifyEvent*
+	Severity int      // 通知级别，见 models.NotifySeverity*
+	Title    string   // 标题
+	Lines    []string // 正文，每项一行
+	// DedupeKey 非空时多个实例在 notifyDedupeTTL 内只发送一次（如各实例同时检测到数据库故障）
+	DedupeKey string
+}
+
+// notifyResponse 各平台机器人的响应，只解析判断成功与否所需的字段
+type notifyResponse struct {
+	ErrCode     *int   `json:"errcode"`     // 钉钉、企业微信
+	ErrMsg      string `json:"errmsg"`      // 钉钉、企业微信
+	Code        *int   `json:"code"`        // 飞书
+	Msg         string `json:"msg"`         // 飞书
+	OK          *bool  `json:"ok"`          // Telegram
+	Description string `json:"description"` // Telegram
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// notifyClient 发送使用的HTTP客户端
+	notifyClient = &http.Client{Timeout: notifyTimeout}
+
+	// notifyChannels 启用中的通知渠道缓存，数据库不可用时仍可发送告警
+	notifyChannels       []models.NotifyChannel
+	notifyChannelsLoaded bool
+	notifyChannelsMu     sync.RWMutex
+
+	// databaseDownSince 数据库健康检查开始失败的时间，健康时为零值
+	databaseDownSince   time.Time
+	databaseDownSinceMu sync.Mutex
+
+	// errNotifyRateLimited 超过渠道的发送频率限制
+	errNotifyRateLimited = errors.New("超过发送频率限制")
+)
+
+// ============================================================================
+// 初始化
+// ============================================================================
+
+func init() {
+	// 其他实例修改通知渠道后重新加载本实例的渠道缓存
+	OnClusterEvent(topicNotifyReload, func([]string) {
+		if err := loadNotifyChannels(context.Background()); err != nil {
+			logrus.WithError(err).Warn("重新加载通知渠道失败")
+		}
+	})
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// StartNotifier 加载通知渠道并订阅数据库健康检查结果
+// 需要在数据库初始化之后调用
+func StartNotifier() {
+	if err := loadNotifyChannels(context.Background()); err != nil {
+		logrus.WithError(err).Warn("加载通知渠道失败")
+	}
+	utils.SetDatabaseHealthHook(onDatabaseHealth)
+}
+
+// ReloadNotifyChannels 重新加载通知渠道缓存并通知其他实例
+func ReloadNotifyChannels(ctx context.Context) {
+	if err := loadNotifyChannels(ctx); err != nil {
+		logrus.WithError(err).Warn("重新加载通知渠道失败")
+	}
+	Broadcast(ctx, topicNotifyReload)
+}
+
+// Notify 发送告警通知到订阅该事件且满足最低级别的启用中渠道
+// 在后台发送，不影响调用方的业务流程；超过渠道发送频率的通知被丢弃
+func Notify(ctx context.Context, n Notification) {
+	ctx = context.WithoutCancel(ctx)
+	go dispatchNotification(ctx, n)
+}
+
+// SendTestNotification 向渠道同步发送一条测试通知
+// 测试通知不受渠道启用状态与订阅事件限制，但计入发送频率
+func SendTestNotification(ctx context.Context, channel *models.NotifyChannel) error {
+	return sendNotification(ctx, channel, Notification{
+		Event:    models.NotifyEventTest,
+		Severity: models.NotifySeverityInfo,
+		Title:    "测试通知",
+		Lines:    []string{"渠道「" + channel.Name + "」配置正确，可以正常接收告警通知"},
+	})
+}
+
+// NotifyAdminLogin 管理员登录成功后检查登录IP
+// 登录IP不在最近登录过的IP列表（设置项 admin_login_ips）中时发送告警，并记录该IP；
+// 列表为空（首次登录或已清空）时只记录不告警
+func NotifyAdminLogin(ctx context.Context, username, ip string) {
+	settings := GetSettingsService()
+	if ip == "" || !settings.GetBool("notify_login_new_ip", true) {
+		return
+	}
+
+	var known []string
+	for _, item := range strings.Split(settings.GetString("admin_login_ips", ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			known = append(known, item)
+		}
+	}
+	for _, item := range known {
+		if item == ip {
+			return
+		}
+	}
+
+	updated := append([]string{ip}, known...)
+	if len(updated) > maxKnownLoginIPs {
+		updated = updated[:maxKnownLoginIPs]
+	}
+	db, err := database.GetDB()
+	if err != nil {
+		logrus.WithError(err).Warn("记录管理员登录IP失败")
+		return
+	}
+	err = db.WithContext(ctx).Model(&models.Settings{}).Where("name = ?", "admin_login_ips").
+		Update("value", strings.Join(updated, ",")).Error
+	if err != nil {
+		logrus.WithError(err).Warn("记录管理员登录IP失败")
+		return
+	}
+	settings.RefreshCache(ctx)
+
+	if len(known) == 0 {
+		return
+	}
+	Notify(ctx, Notification{
+		Event:    models.NotifyEventAdminLoginNewIP,
+		Severity: models.NotifySeverityWarning,
+		Title:    "管理员从新IP登录",
+		Lines: []string{
+			"用户名：" + username,
+			"登录IP：" + ip,
+			"最近登录IP：" + strings.Join(known[:min(len(known), 3)], "、"),
+		},
+	})
+}
+
+// RecordClientLoginFailure 记录一次客户端登录失败
+// 同一应用在 notify_login_failure_window 分钟内失败次数达到 notify_login_failure_threshold 时发送一次告警，
+// 多个实例通过Redis共享计数
+func RecordClientLoginFailure(ctx context.Context, appUUID string) {
+	settings := GetSettingsService()
+	threshold := settings.GetInt("notify_login_failure_threshold", 20)
+	window := settings.GetInt("notify_login_failure_window", 5)
+	if threshold <= 0 || window <= 0 {
+		return
+	}
+
+	count, err := utils.IncrCounter(ctx, "login_failures:"+appUUID, time.Duration(window)*time.Minute)
+	if err != nil {
+		logrus.WithError(err).Warn("记录客户端登录失败次数失败")
+		return
+	}
+	if count != int64(threshold) {
+		return
+	}
+	Notify(ctx, Notification{
+		Event:    models.NotifyEventClientLoginFailures,
+		Severity: models.NotifySeverityWarning,
+		Title:    "客户端登录失败次数激增",
+		Lines: []string{
+			"应用：" + appUUID,
+			fmt.Sprintf("%d 分钟内登录失败已达 %d 次", window, threshold),
+		},
+	})
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// loadNotifyChannels 从数据库加载启用中的通知渠道，失败时保留原有缓存
+func loadNotifyChannels(ctx context.Context) error {
+	db, err := database.GetDB()
+	if err != nil {
+		return err
+	}
+	var channels []models.NotifyChannel
+	if err := db.WithContext(ctx).Where("status = ?", models.NotifyChannelEnabled).Find(&channels).Error; err != nil {
+		return fmt.Errorf("查询通知渠道失败: %w", err)
+	}
+
+	notifyChannelsMu.Lock()
+	notifyChannels = channels
+	notifyChannelsLoaded = true
+	notifyChannelsMu.Unlock()
+	return nil
+}
+
+// dispatchNotification 发送告警通知到通知渠道与告警邮箱，返回发送成功的渠道数量（告警邮件计为一个渠道）
+func dispatchNotification(ctx context.Context, n Notification) int {
+	notifyChannelsMu.RLock()
+	loaded := notifyChannelsLoaded
+	notifyChannelsMu.RUnlock()
+	if !loaded {
+		if err := loadNotifyChannels(ctx); err != nil {
+			logrus.WithError(err).Warn("加载通知渠道失败")
+		}
+	}
+
+	notifyChannelsMu.RLock()
+	var channels []models.NotifyChannel
+	for _, channel := range notifyChannels {
+		if channel.Subscribes(n.Event, n.Severity) {
+			channels = append(channels, channel)
+		}
+	}
+	notifyChannelsMu.RUnlock()
+
+	if n.DedupeKey != "" {
+		if _, ok, err := utils.TryLock(ctx, "notify:"+n.Event+":"+n.DedupeKey, notifyDedupeTTL); err == nil && !ok {
+			return 0
+		}
+	}
+
+	sent := 0
+	for i := range channels {
+		err := sendNotification(ctx, &channels[i], n)
+		if err != nil {
+			logrus.WithError(err).WithFields(logrus.Fields{
+				"channel_id": channels[i].ID,
+				"event":      n.Event,
+			}).Warn("发送告警通知失败")
+			continue
+		}
+		sent++
+	}
+	if notifyByMail(ctx, n) {
+		sent++
+	}
+	return sent
+}
+
+// sendNotification 按渠道类型格式化并发送通知
+func sendNotification(ctx context.Context, channel *models.NotifyChannel, n Notification) (err error) {
+	ctx, span := tracing.Start(ctx, "notify.send",
+		attribute.String("notify.channel", channel.Type),
+		attribute.String("notify.event", n.Event),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	limit := channel.RateLimit
+	if limit <= 0 {
+		limit = defaultNotifyRateLimit
+	}
+	count, err := utils.IncrCounter(ctx, "notify:"+strconv.FormatUint(uint64(channel.ID), 10), time.Minute)
+	if err != nil {
+		return fmt.Errorf("检查发送频率失败: %w", err)
+	}
+	if count > int64(limit) {
+		return errNotifyRateLimited
+	}
+
+	target, payload, err := buildNotifyRequest(channel, n, time.Now())
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化通知内容失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建发送请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", redactNotifyError(err))
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxNotifyResponseLength))
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("机器人返回状态码 %d: %s", resp.StatusCode, strings.ToValidUTF8(string(data), ""))
+	}
+	return checkNotifyResponse(data)
+}
+
+// buildNotifyRequest 生成各平台机器人的请求地址与消息内容（含签名）
+// - 钉钉：markdown 消息，设置了加签密钥时在地址上追加 timestamp 与 sign
+// - 企业微信：markdown 消息，密钥包含在 Webhook 地址的 key 参数中
+// - 飞书：消息卡片，设置了签名校验密钥时在消息中携带 timestamp 与 sign
+// - Telegram：调用 sendMessage，Bot Token 取自密钥字段
+func buildNotifyRequest(channel *models.NotifyChannel, n Notification, now time.Time) (string, map[string]interface{}, error) {
+	heading := "【" + models.NotifySeverityText(n.Severity) + "】" + n.Title
+	footer := "时间：" + now.Format("2006-01-02 15:04:05")
+
+	switch channel.Type {
+	case models.NotifyChannelDingTalk:
+		target := channel.URL
+		if channel.Secret != "" {
+			timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+			target = appendQuery(target, url.Values{
+				"timestamp": {timestamp},
+				"sign":      {signDingTalk(channel.Secret, timestamp)},
+			})
+		}
+		return target, map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": heading,
+				"text":  "### " + heading + "\n\n" + markdownLines(n.Lines) + "\n\n" + footer,
+			},
+		}, nil
+
+	case models.NotifyChannelWeCom:
+		return channel.URL, map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"content": "### " + heading + "\n" + markdownLines(n.Lines) + "\n> " + footer,
+			},
+		}, nil
+
+	case models.NotifyChannelFeishu:
+		template := "blue"
+		switch n.Severity {
+		case models.NotifySeverityWarning:
+			template = "orange"
+		case models.NotifySeverityCritical:
+			template = "red"
+		}
+		payload := map[string]interface{}{
+			"msg_type": "interactive",
+			"card": map[string]interface{}{
+				"header": map[string]interface{}{
+					"title":    map[string]string{"tag": "plain_text", "content": heading},
+					"template": template,
+				},
+				"elements": []interface{}{
+					map[string]interface{}{
+						"tag":  "div",
+						"text": map[string]string{"tag": "lark_md", "content": strings.Join(n.Lines, "\n") + "\n" + footer},
+					},
+				},
+			},
+		}
+		if channel.Secret != "" {
+			timestamp := strconv.FormatInt(now.Unix(), 10)
+			payload["timestamp"] = timestamp
+			payload["sign"] = signFeishu(channel.Secret, timestamp)
+		}
+		return channel.URL, payload, nil
+
+	case models.NotifyChannelTelegram:
+		base := strings.TrimRight(channel.URL, "/")
+		if base == "" {
+			base = defaultTelegramAPI
+		}
+		lines := make([]string, 0, len(n.Lines)+2)
+		lines = append(lines, "<b>"+html.EscapeString(heading)+"</b>")
+		for _, line := range n.Lines {
+			lines = append(lines, html.EscapeString(line))
+		}
+		lines = append(lines, "<i>"+html.EscapeString(footer)+"</i>")
+		return base + "/bot" + channel.Secret + "/sendMessage", map[string]interface{}{
+			"chat_id":                  channel.ChatID,
+			"text":                     strings.Join(lines, "\n"),
+			"parse_mode":               "HTML",
+			"disable_web_page_preview": true,
+		}, nil
+	}
+	return "", nil, fmt.Errorf("不支持的渠道类型: %s", channel.Type)
+}
+
+// signDingTalk 钉钉加签：以密钥对 "<毫秒时间戳>\n<密钥>" 做 HMAC-SHA256 后 Base64 编码
+func signDingTalk(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signFeishu 飞书签名校验：以 "<秒级时间戳>\n<密钥>" 为密钥对空字符串做 HMAC-SHA256 后 Base64 编码
+func signFeishu(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// checkNotifyResponse 根据各平台的响应字段判断是否发送成功
+func checkNotifyResponse(data []byte) error {
+	var resp notifyResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("机器人响应格式错误: %s", strings.ToValidUTF8(string(data), ""))
+	}
+	switch {
+	case resp.ErrCode != nil && *resp.ErrCode != 0:
+		return fmt.Errorf("机器人返回错误 %d: %s", *resp.ErrCode, resp.ErrMsg)
+	case resp.Code != nil && *resp.Code != 0:
+		return fmt.Errorf("机器人返回错误 %d: %s", *resp.Code, resp.Msg)
+	case resp.OK != nil && !*resp.OK:
+		return fmt.Errorf("机器人返回错误: %s", resp.Description)
+	}
+	return nil
+}
+
+// redactNotifyError 去掉请求错误中的地址，避免 Telegram Bot Token 等密钥写入日志
+func redactNotifyError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// appendQuery 在地址上追加查询参数
+func appendQuery(target string, values url.Values) string {
+	if strings.Contains(target, "?") {
+		return target + "&" + values.Encode()
+	}
+	return target + "?" + values.Encode()
+}
+
+// markdownLines 将正文转换为 markdown 列表
+func markdownLines(lines []string) string {
+	items := make([]string, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, "- "+line)
+	}
+	return strings.Join(items, "\n")
+}
+
+// onDatabaseHealth 数据库健康检查回调，在健康与失败状态切换时发送告警
+func onDatabaseHealth(err error) {
+	databaseDownSinceMu.Lock()
+	defer databaseDownSinceMu.Unlock()
+
+	ctx := context.Background()
+	switch {
+	case err != nil && databaseDownSince.IsZero():
+		databaseDownSince = time.Now()
+		Notify(ctx, Notification{
+			Event:     models.NotifyEventDatabaseHealth,
+			Severity:  models.NotifySeverityCritical,
+			Title:     "数据库健康检查失败",
+			Lines:     []string{"错误：" + err.Error()},
+			DedupeKey: "down",
+		})
+	case err == nil && !databaseDownSince.IsZero():
+		downtime := time.Since(databaseDownSince).Round(time.Second)
+		databaseDownSince = time.Time{}
+		Notify(ctx, Notification{
+			Event:     models.NotifyEventDatabaseHealth,
+			Severity:  models.NotifySeverityInfo,
+			Title:     "数据库已恢复",
+			Lines:     []string{"故障持续：" + downtime.String()},
+			DedupeKey: "up",
+		})
+	}
+}
+
+// sendDailySummary 汇总最近24小时的运行情况并发送通知（daily_summary 任务）
+func sendDailySummary(ctx context.Context) (string, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+	db = db.WithContext(ctx)
+	since := time.Now().Add(-24 * time.Hour)
+
+	var apps, enabledApps, createdCards, usedCards, failedRuns, failedDeliveries, pendingDeliveries int64
+	counts := []struct {
+		query *gorm.DB
+		value *int64
+	}{
+		{db.Model(&models.App{}), &apps},
+		{db.Model(&models.App{}).Where("status = ?", 1), &enabledApps},
+		{db.Model(&models.Card{}).Where("created_at >= ?", since), &createdCards},
+		{db.Model(&models.Card{}).Where("used_at >= ?", since), &usedCards},
+		{db.Model(&models.JobRun{}).Where("status = ? AND started_at >= ?", models.JobRunStatusFailed, since), &failedRuns},
+		{db.Model(&models.WebhookDelivery{}).Where("status = ? AND created_at >= ?", models.WebhookDeliveryFailed, since), &failedDeliveries},
+		{db.Model(&models.WebhookDelivery{}).Where("status = ?", models.WebhookDeliveryPending), &pendingDeliveries},
+	}
+	for _, count := range counts {
+		if err := count.query.Count(count.value).Error; err != nil {
+			return "", fmt.Errorf("统计运行情况失败: %w", err)
+		}
+	}
+
+	sent := dispatchNotification(ctx, Notification{
+		Event:    models.NotifyEventDailySummary,
+		Severity: models.NotifySeverityInfo,
+		Title:    "每日汇总",
+		Lines: []string{
+			fmt.Sprintf("应用：共 %d 个，启用 %d 个", apps, enabledApps),
+			fmt.Sprintf("卡密：新增 %d 张，使用 %d 张", createdCards, usedCards),
+			fmt.Sprintf("定时任务：失败 %d 次", failedRuns),
+			fmt.Sprintf("Webhook：投递失败 %d 条，待重试 %d 条", failedDeliveries, pendingDeliveries),
+		},
+	})
+	return fmt.Sprintf("发送到 %d 个渠道", sent), nil
+}
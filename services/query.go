@@ -18,9 +18,14 @@ import (
 // name: 设置名称
 // db: 数据库连接
 // 返回: 设置信息和错误
+// 缓存读写使用 db 绑定的上下文，传入 db.WithContext 后可计入请求链路
 func FindSettingByName(name string, db *gorm.DB) (*models.Settings, error) {
 	key := fmt.Sprintf("setting:%s", name)
-	return utils.RedisGetOrSet(context.Background(), key, 5*time.Minute, func() (*models.Settings, error) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return utils.RedisGetOrSet(ctx, key, 5*time.Minute, func() (*models.Settings, error) {
 		var setting models.Settings
 		err := db.Where("name = ?", name).First(&setting).Error
 		if err != nil {
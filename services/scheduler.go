@@ -0,0 +1,570 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+	"networkDev/utils/cron"
+	"networkDev/utils/tracing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// topicJobsReload 任务设置变更的实例间事件主题
+	topicJobsReload = "jobs.reload"
+	// defaultJobTimeout 未指定超时时间的任务默认超时时间
+	defaultJobTimeout = time.Hour
+	// jobTickLockTTL 计划执行锁有效期，保证同一计划时间只有一个实例执行
+	jobTickLockTTL = 10 * time.Minute
+	// maxJobWait 调度循环最长等待时间，避免系统时间调整后长时间不检查
+	maxJobWait = time.Minute
+	// maxJobResultLength 执行结果摘要最大长度，与 job_runs.result 字段长度一致
+	maxJobResultLength = 255
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// JobFunc 任务执行函数，返回执行结果摘要
+// 调度器关闭或超时时 ctx 会被取消，任务应尽快返回
+type JobFunc func(ctx context.Context) (string, error)
+
+// JobDefinition 内置任务定义
+type JobDefinition struct {
+	Name        string        // 任务名称，唯一
+	Title       string        // 显示名称
+	Description string        // 任务说明
+	Cron        string        // 默认执行计划
+	Enabled     bool          // 默认是否启用
+	Timeout     time.Duration // 单次执行超时时间，0 使用默认值
+	Run         JobFunc       // 执行函数
+}
+
+// JobStatus 任务状态（管理后台展示）
+type JobStatus struct {
+	Name        string         `json:"name"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Cron        string         `json:"cron"`
+	DefaultCron string         `json:"default_cron"`
+	Enabled     bool           `json:"enabled"`
+	Running     bool           `json:"running"`
+	NextRunAt   *time.Time     `json:"next_run_at"`
+	LastRun     *models.JobRun `json:"last_run"`
+}
+
+// scheduledJob 调度中的任务
+type scheduledJob struct {
+	def JobDefinition
+
+	mu       sync.Mutex
+	enabled  bool
+	spec     string
+	schedule cron.Schedule
+	next     time.Time
+
+	running atomic.Bool
+}
+
+// Scheduler 定时任务调度器
+// - 每个实例都运行调度循环，同一计划时间通过分布式锁只由一个实例执行
+// - 同一任务在集群内不会重叠执行，上一次未结束时跳过本次执行
+// - 关闭时取消正在执行任务的 ctx，并等待任务返回
+type Scheduler struct {
+	jobs  map[string]*scheduledJob
+	order []string
+	wake  chan struct{}
+
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+	stopped bool
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	scheduler     *Scheduler
+	schedulerOnce sync.Once
+
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errors.New("任务不存在")
+	// ErrJobRunning 任务正在执行
+	ErrJobRunning = errors.New("任务正在执行")
+	// ErrSchedulerStopped 调度器已关闭
+	ErrSchedulerStopped = errors.New("调度器已关闭")
+	// errJobTaken 同一计划时间已由其他实例执行
+	errJobTaken = errors.New("任务已由其他实例执行")
+)
+
+// ============================================================================
+// 初始化
+// ============================================================================
+
+func init() {
+	// 其他实例修改任务设置后重新加载
+	OnClusterEvent(topicJobsReload, func([]string) {
+		GetScheduler().loadStates(context.Background())
+	})
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// GetScheduler 获取调度器单例
+// 首次调用时注册内置任务并从数据库加载任务设置，需要在数据库初始化之后调用
+func GetScheduler() *Scheduler {
+	schedulerOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		scheduler = &Scheduler{
+			jobs:   make(map[string]*scheduledJob),
+			wake:   make(chan struct{}, 1),
+			ctx:    ctx,
+			cancel: cancel,
+		}
+		for _, def := range builtinJobs() {
+			scheduler.register(def)
+		}
+		scheduler.loadStates(context.Background())
+	})
+	return scheduler
+}
+
+// StartScheduler 启动定时任务调度
+// scheduler.enabled 为 false 时不按计划执行，仍可在管理后台手动执行
+func StartScheduler() {
+	if viper.IsSet("scheduler.enabled") && !viper.GetBool("scheduler.enabled") {
+		logrus.Info("定时任务调度器未启用")
+		return
+	}
+
+	s := GetScheduler()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	s.wg.Add(1)
+	go s.loop()
+	logrus.WithField("jobs", len(s.order)).Info("定时任务调度器已启动")
+}
+
+// StopScheduler 停止调度并等待正在执行的任务结束
+// ctx 到期时不再等待，返回 ctx 的错误
+func StopScheduler(ctx context.Context) error {
+	if scheduler == nil {
+		return nil
+	}
+	return scheduler.Stop(ctx)
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Stop 停止调度并等待正在执行的任务结束
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.cancel()
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Jobs 获取所有任务的状态
+func (s *Scheduler) Jobs(ctx context.Context) []JobStatus {
+	lastRuns := s.lastRuns(ctx)
+
+	list := make([]JobStatus, 0, len(s.order))
+	for _, name := range s.order {
+		job := s.jobs[name]
+		job.mu.Lock()
+		status := JobStatus{
+			Name:        job.def.Name,
+			Title:       job.def.Title,
+			Description: job.def.Description,
+			Cron:        job.spec,
+			DefaultCron: job.def.Cron,
+			Enabled:     job.enabled,
+			Running:     job.running.Load(),
+			LastRun:     lastRuns[name],
+		}
+		if !job.next.IsZero() {
+			next := job.next
+			status.NextRunAt = &next
+		}
+		job.mu.Unlock()
+		list = append(list, status)
+	}
+	return list
+}
+
+// UpdateJob 修改任务的启用状态与执行计划，并通知其他实例
+// spec 为空时恢复默认执行计划
+func (s *Scheduler) UpdateJob(ctx context.Context, name string, enabled bool, spec string) error {
+	job, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	if spec == job.def.Cron {
+		spec = ""
+	}
+	if spec != "" {
+		if err := cron.Validate(spec); err != nil {
+			return err
+		}
+	}
+
+	db, err := database.GetDB()
+	if err != nil {
+		return err
+	}
+	record := models.Job{Name: name, Enabled: enabled, Cron: spec}
+	err = db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "cron", "updated_at"}),
+	}).Create(&record).Error
+	if err != nil {
+		return err
+	}
+
+	s.apply(job, enabled, spec)
+	Broadcast(ctx, topicJobsReload, name)
+	return nil
+}
+
+// RunNow 立即执行任务（不受启用状态影响）
+// 任务在后台执行，正在执行时返回 ErrJobRunning
+func (s *Scheduler) RunNow(name string) error {
+	job, ok := s.jobs[name]
+	if !ok {
+		return ErrJobNotFound
+	}
+	release, err := s.acquire(job, models.JobTriggerManual, time.Time{})
+	if err != nil {
+		return err
+	}
+	go s.execute(job, models.JobTriggerManual, release)
+	return nil
+}
+
+// register 注册任务
+func (s *Scheduler) register(def JobDefinition) {
+	if _, exists := s.jobs[def.Name]; exists {
+		panic(fmt.Sprintf("定时任务重复注册: %s", def.Name))
+	}
+	job := &scheduledJob{def: def}
+	s.jobs[def.Name] = job
+	s.order = append(s.order, def.Name)
+	s.apply(job, def.Enabled, "")
+}
+
+// loadStates 从数据库加载任务设置，没有记录的任务恢复默认设置
+func (s *Scheduler) loadStates(ctx context.Context) {
+	states := make(map[string]models.Job)
+	if db, err := database.GetDB(); err != nil {
+		logrus.WithError(err).Warn("加载定时任务设置失败，使用默认设置")
+	} else {
+		var records []models.Job
+		if err := db.WithContext(ctx).Find(&records).Error; err != nil {
+			logrus.WithError(err).Warn("加载定时任务设置失败，使用默认设置")
+		}
+		for _, record := range records {
+			states[record.Name] = record
+		}
+	}
+
+	for name, job := range s.jobs {
+		if state, ok := states[name]; ok {
+			s.apply(job, state.Enabled, state.Cron)
+		} else {
+			s.apply(job, job.def.Enabled, "")
+		}
+	}
+}
+
+// apply 更新任务的启用状态与执行计划，并唤醒调度循环重新计算等待时间
+// 执行计划无法解析时（如数据库中的旧记录）使用默认执行计划
+func (s *Scheduler) apply(job *scheduledJob, enabled bool, spec string) {
+	if spec == "" {
+		spec = job.def.Cron
+	}
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		logrus.WithError(err).WithField("job", job.def.Name).Warn("任务执行计划错误，使用默认执行计划")
+		spec = job.def.Cron
+		schedule, err = cron.Parse(spec)
+		if err != nil {
+			logrus.WithError(err).WithField("job", job.def.Name).Error("任务默认执行计划错误，任务不会执行")
+			enabled = false
+		}
+	}
+
+	job.mu.Lock()
+	job.enabled = enabled
+	job.spec = spec
+	job.schedule = schedule
+	job.next = time.Time{}
+	if enabled {
+		job.next = schedule.Next(time.Now())
+	}
+	job.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop 调度循环
+func (s *Scheduler) loop() {
+	defer s.wg.Done()
+
+	for {
+		timer := time.NewTimer(time.Until(s.nextWakeup()))
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+			s.runDue(time.Now())
+		}
+	}
+}
+
+// nextWakeup 计算下一次需要检查的时间
+func (s *Scheduler) nextWakeup() time.Time {
+	wakeup := time.Now().Add(maxJobWait)
+	for _, job := range s.jobs {
+		job.mu.Lock()
+		if job.enabled && !job.next.IsZero() && job.next.Before(wakeup) {
+			wakeup = job.next
+		}
+		job.mu.Unlock()
+	}
+	return wakeup
+}
+
+// runDue 执行到期的任务
+func (s *Scheduler) runDue(now time.Time) {
+	for _, name := range s.order {
+		job := s.jobs[name]
+
+		job.mu.Lock()
+		due := job.enabled && !job.next.IsZero() && !job.next.After(now)
+		scheduledAt := job.next
+		if due {
+			job.next = job.schedule.Next(now)
+		}
+		job.mu.Unlock()
+
+		if !due {
+			continue
+		}
+		go func() {
+			release, err := s.acquire(job, models.JobTriggerSchedule, scheduledAt)
+			if err != nil {
+				if errors.Is(err, ErrJobRunning) {
+					logrus.WithField("job", job.def.Name).Warn("上一次执行尚未结束，跳过本次执行")
+				}
+				return
+			}
+			s.execute(job, models.JobTriggerSchedule, release)
+		}()
+	}
+}
+
+// acquire 获取任务执行权
+// - 本实例与集群内同一任务正在执行时返回 ErrJobRunning
+// - 按计划触发时，同一计划时间已由其他实例执行返回 errJobTaken
+// 成功时返回释放函数，任务结束后必须调用
+func (s *Scheduler) acquire(job *scheduledJob, trigger string, scheduledAt time.Time) (func(), error) {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil, ErrSchedulerStopped
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	if !job.running.CompareAndSwap(false, true) {
+		s.wg.Done()
+		return nil, ErrJobRunning
+	}
+	fail := func(err error) (func(), error) {
+		job.running.Store(false)
+		s.wg.Done()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if trigger == models.JobTriggerSchedule {
+		tick := "job:" + job.def.Name + ":" + strconv.FormatInt(scheduledAt.Unix(), 10)
+		_, ok, err := utils.TryLock(ctx, tick, jobTickLockTTL)
+		if err != nil {
+			logrus.WithError(err).WithField("job", job.def.Name).Warn("获取任务锁失败，跳过本次执行")
+			return fail(err)
+		}
+		if !ok {
+			return fail(errJobTaken)
+		}
+	}
+
+	lock, ok, err := utils.TryLock(ctx, "job-running:"+job.def.Name, job.timeout()+time.Minute)
+	if err != nil {
+		logrus.WithError(err).WithField("job", job.def.Name).Warn("获取任务锁失败，跳过本次执行")
+		return fail(err)
+	}
+	if !ok {
+		return fail(ErrJobRunning)
+	}
+
+	return func() {
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer releaseCancel()
+		if err := lock.Release(releaseCtx); err != nil {
+			logrus.WithError(err).WithField("job", job.def.Name).Warn("释放任务锁失败")
+		}
+		job.running.Store(false)
+		s.wg.Done()
+	}, nil
+}
+
+// execute 执行任务并记录执行结果
+func (s *Scheduler) execute(job *scheduledJob, trigger string, release func()) {
+	defer release()
+
+	name := job.def.Name
+	log := logrus.WithFields(logrus.Fields{"job": name, "trigger": trigger})
+	run := &models.JobRun{
+		JobName:   name,
+		Trigger:   trigger,
+		Instance:  InstanceID(),
+		Status:    models.JobRunStatusRunning,
+		StartedAt: time.Now(),
+	}
+	db, dbErr := database.GetDB()
+	if dbErr == nil {
+		if err := db.Create(run).Error; err != nil {
+			log.WithError(err).Warn("写入任务执行记录失败")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, job.timeout())
+	defer cancel()
+	ctx, span := tracing.Start(ctx, "job."+name, attribute.String("job.trigger", trigger))
+
+	log.Info("定时任务开始执行")
+	result, err := runJobSafely(ctx, job.def.Run)
+	tracing.End(span, err)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Result = truncateJobText(result)
+	run.Status = models.JobRunStatusSuccess
+	if err != nil {
+		run.Status = models.JobRunStatusFailed
+		run.Error = err.Error()
+		log.WithError(err).WithField("duration_ms", run.DurationMs).Error("定时任务执行失败")
+	} else {
+		log.WithFields(logrus.Fields{"duration_ms": run.DurationMs, "result": result}).Info("定时任务执行完成")
+	}
+
+	if dbErr == nil && run.ID != 0 {
+		if err := db.Save(run).Error; err != nil {
+			log.WithError(err).Warn("更新任务执行记录失败")
+		}
+	}
+}
+
+// lastRuns 查询每个任务最近一次执行记录
+func (s *Scheduler) lastRuns(ctx context.Context) map[string]*models.JobRun {
+	result := make(map[string]*models.JobRun, len(s.jobs))
+	db, err := database.GetDB()
+	if err != nil {
+		return result
+	}
+	for name := range s.jobs {
+		var run models.JobRun
+		err := db.WithContext(ctx).Where("job_name = ?", name).Order("started_at DESC").Order("id DESC").First(&run).Error
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				logrus.WithError(err).WithField("job", name).Warn("查询任务执行记录失败")
+			}
+			continue
+		}
+		result[name] = &run
+	}
+	return result
+}
+
+// timeout 单次执行超时时间
+func (job *scheduledJob) timeout() time.Duration {
+	if job.def.Timeout > 0 {
+		return job.def.Timeout
+	}
+	return defaultJobTimeout
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// runJobSafely 执行任务，任务 panic 时转换为错误
+func runJobSafely(ctx context.Context, run JobFunc) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("任务执行异常: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+// truncateJobText 截断执行结果摘要
+func truncateJobText(text string) string {
+	runes := []rune(text)
+	if len(runes) <= maxJobResultLength {
+		return text
+	}
+	return string(runes[:maxJobResultLength])
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+)
+
+// newTestScheduler 创建只包含测试任务的调度器（不启动调度循环）
+func newTestScheduler(t *testing.T, defs ...JobDefinition) *Scheduler {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		jobs:   make(map[string]*scheduledJob),
+		wake:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, def := range defs {
+		s.register(def)
+	}
+	t.Cleanup(func() { _ = s.Stop(context.Background()) })
+	return s
+}
+
+// blockingJob 创建执行后阻塞到 unblock 关闭的测试任务，每次运行使用不同任务名避免执行记录互相干扰
+func blockingJob(t *testing.T, started chan<- struct{}, unblock <-chan struct{}) JobDefinition {
+	t.Helper()
+	name := fmt.Sprintf("test_job_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		if db, err := database.GetDB(); err == nil {
+			db.Where("job_name = ?", name).Delete(&models.JobRun{})
+			db.Where("name = ?", name).Delete(&models.Job{})
+		}
+	})
+	return JobDefinition{
+		Name: name,
+		Cron: "* * * * *",
+		Run: func(ctx context.Context) (string, error) {
+			started <- struct{}{}
+			select {
+			case <-unblock:
+				return "done", nil
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		},
+	}
+}
+
+// waitJobIdle 等待任务执行结束并释放执行权
+func waitJobIdle(t *testing.T, job *scheduledJob) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for job.running.Load() {
+		if time.Now().After(deadline) {
+			t.Fatalf("任务 %s 未结束", job.def.Name)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestSchedulerOverlap 同一任务执行中时手动与计划触发都被拒绝，结束后可再次执行
+func TestSchedulerOverlap(t *testing.T) {
+	started := make(chan struct{}, 1)
+	unblock := make(chan struct{})
+	def := blockingJob(t, started, unblock)
+	s := newTestScheduler(t, def)
+	job := s.jobs[def.Name]
+
+	if err := s.RunNow(def.Name); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	<-started
+
+	if err := s.RunNow(def.Name); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("执行中再次 RunNow = %v，期望 ErrJobRunning", err)
+	}
+	if _, err := s.acquire(job, models.JobTriggerSchedule, time.Now().Truncate(time.Minute)); !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("执行中按计划触发 = %v，期望 ErrJobRunning", err)
+	}
+
+	close(unblock)
+	waitJobIdle(t, job)
+
+	db, _ := database.GetDB()
+	var run models.JobRun
+	if err := db.Where("job_name = ?", def.Name).First(&run).Error; err != nil {
+		t.Fatalf("查询执行记录: %v", err)
+	}
+	if run.Status != models.JobRunStatusSuccess || run.Trigger != models.JobTriggerManual || run.Result != "done" {
+		t.Fatalf("执行记录 = %+v", run)
+	}
+
+	if err := s.RunNow(def.Name); err != nil {
+		t.Fatalf("结束后 RunNow: %v", err)
+	}
+	<-started
+	waitJobIdle(t, job)
+}
+
+// TestSchedulerStop 关闭时取消正在执行的任务并等待其返回，之后拒绝执行
+func TestSchedulerStop(t *testing.T) {
+	started := make(chan struct{}, 1)
+	def := blockingJob(t, started, make(chan struct{}))
+	s := newTestScheduler(t, def)
+
+	if err := s.RunNow(def.Name); err != nil {
+		t.Fatalf("RunNow: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if s.jobs[def.Name].running.Load() {
+		t.Fatal("Stop 返回时任务应已结束")
+	}
+
+	db, _ := database.GetDB()
+	var run models.JobRun
+	if err := db.Where("job_name = ?", def.Name).First(&run).Error; err != nil {
+		t.Fatalf("查询执行记录: %v", err)
+	}
+	if run.Status != models.JobRunStatusFailed || run.FinishedAt == nil {
+		t.Fatalf("被取消的执行记录 = %+v", run)
+	}
+
+	if err := s.RunNow(def.Name); !errors.Is(err, ErrSchedulerStopped) {
+		t.Fatalf("关闭后 RunNow = %v，期望 ErrSchedulerStopped", err)
+	}
+}
+
+// TestSchedulerUpdateJob 修改执行计划时校验表达式，与默认计划相同时恢复默认
+func TestSchedulerUpdateJob(t *testing.T) {
+	def := blockingJob(t, make(chan struct{}, 1), make(chan struct{}))
+	s := newTestScheduler(t, def)
+	ctx := context.Background()
+
+	if err := s.UpdateJob(ctx, "missing", true, ""); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("UpdateJob(missing) = %v，期望 ErrJobNotFound", err)
+	}
+	if err := s.RunNow("missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("RunNow(missing) = %v，期望 ErrJobNotFound", err)
+	}
+	if err := s.UpdateJob(ctx, def.Name, true, "61 * * * *"); err == nil {
+		t.Fatal("无效的执行计划应返回错误")
+	}
+
+	if err := s.UpdateJob(ctx, def.Name, true, "0 3 * * *"); err != nil {
+		t.Fatalf("UpdateJob: %v", err)
+	}
+	status := s.Jobs(ctx)[0]
+	if !status.Enabled || status.Cron != "0 3 * * *" || status.NextRunAt == nil {
+		t.Fatalf("修改后任务状态 = %+v", status)
+	}
+
+	if err := s.UpdateJob(ctx, def.Name, false, def.Cron); err != nil {
+		t.Fatalf("UpdateJob: %v", err)
+	}
+	db, _ := database.GetDB()
+	var record models.Job
+	if err := db.Where("name = ?", def.Name).First(&record).Error; err != nil {
+		t.Fatalf("查询任务设置: %v", err)
+	}
+	if record.Enabled || record.Cron != "" {
+		t.Fatalf("恢复默认计划后的任务设置 = %+v", record)
+	}
+	if status := s.Jobs(ctx)[0]; status.Enabled || status.Cron != def.Cron || status.NextRunAt != nil {
+		t.Fatalf("禁用后任务状态 = %+v", status)
+	}
+}
+
+// TestRunJobSafely 任务 panic 转换为错误
+func TestRunJobSafely(t *testing.T) {
+	_, err := runJobSafely(context.Background(), func(context.Context) (string, error) {
+		panic("boom")
+	})
+	if err == nil {
+		t.Fatal("panic 应转换为错误")
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"networkDev/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// sessionKeyPrefix 会话在Redis中的键前缀
+const sessionKeyPrefix = "session:"
+
+// ============================================================================
+// 接口定义
+// ============================================================================
+
+// SessionStore 会话存储
+// 会话内容由调用方序列化，存储只负责按ID保存与过期；多实例部署时需要使用Redis实现
+// 客户端会话ID使用 AppSessionID 生成，便于按应用清理与统计
+type SessionStore interface {
+	// Set 保存会话，ttl 到期后自动删除
+	Set(ctx context.Context, id string, value string, ttl time.Duration) error
+	// Get 读取会话，不存在或已过期时返回 false
+	Get(ctx context.Context, id string) (string, bool, error)
+	// Touch 延长会话有效期，会话不存在时返回 false
+	Touch(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Delete 删除会话
+	Delete(ctx context.Context, id string) error
+	// Cleanup 清理ID以 prefix 开头的过期会话，返回剩余的有效会话数
+	Cleanup(ctx context.Context, prefix string) (int, error)
+}
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// memorySessionStore 进程内会话存储，仅适用于单实例部署
+// 读取时忽略已过期的会话，过期会话由会话清理任务删除
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+// memorySession 进程内会话
+type memorySession struct {
+	value    string
+	expireAt time.Time
+}
+
+// redisSessionStore 基于Redis的会话存储
+type redisSessionStore struct {
+	client *redis.Client
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	sessionStore     SessionStore
+	sessionStoreOnce sync.Once
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// GetSessionStore 获取会话存储
+// 配置并能连接Redis时使用Redis存储，否则使用进程内存储
+func GetSessionStore() SessionStore {
+	sessionStoreOnce.Do(func() {
+		if client := utils.GetRedis(); client != nil {
+			sessionStore = &redisSessionStore{client: client}
+			return
+		}
+		sessionStore = &memorySessionStore{sessions: make(map[string]memorySession)}
+	})
+	return sessionStore
+}
+
+// AppSessionID 生成应用客户端会话ID
+func AppSessionID(appUUID, token string) string {
+	return AppSessionPrefix(appUUID) + token
+}
+
+// AppSessionPrefix 应用客户端会话ID前缀
+func AppSessionPrefix(appUUID string) string {
+	return appUUID + ":"
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Set 保存会话
+func (s *memorySessionStore) Set(_ context.Context, id string, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = memorySession{value: value, expireAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Get 读取会话
+func (s *memorySessionStore) Get(_ context.Context, id string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok || time.Now().After(session.expireAt) {
+		return "", false, nil
+	}
+	return session.value, true, nil
+}
+
+// Touch 延长会话有效期
+func (s *memorySessionStore) Touch(_ context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok || time.Now().After(session.expireAt) {
+		return false, nil
+	}
+	session.expireAt = time.Now().Add(ttl)
+	s.sessions[id] = session
+	return true, nil
+}
+
+// Delete 删除会话
+func (s *memorySessionStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// Cleanup 清理过期会话并统计有效会话数
+func (s *memorySessionStore) Cleanup(_ context.Context, prefix string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	active := 0
+	for id, session := range s.sessions {
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		if now.After(session.expireAt) {
+			delete(s.sessions, id)
+			continue
+		}
+		active++
+	}
+	return active, nil
+}
+
+// Set 保存会话
+func (s *redisSessionStore) Set(ctx context.Context, id string, value string, ttl time.Duration) error {
+	return s.client.Set(ctx, sessionKeyPrefix+id, value, ttl).Err()
+}
+
+// Get 读取会话
+func (s *redisSessionStore) Get(ctx context.Context, id string) (string, bool, error) {
+	value, err := s.client.Get(ctx, sessionKeyPrefix+id).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// Touch 延长会话有效期
+func (s *redisSessionStore) Touch(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return s.client.Expire(ctx, sessionKeyPrefix+id, ttl).Result()
+}
+
+// Delete 删除会话
+func (s *redisSessionStore) Delete(ctx context.Context, id string) error {
+	return s.client.Del(ctx, sessionKeyPrefix+id).Err()
+}
+
+// Cleanup 统计有效会话数，过期会话由Redis自动删除
+func (s *redisSessionStore) Cleanup(ctx context.Context, prefix string) (int, error) {
+	iter := s.client.Scan(ctx, 0, sessionKeyPrefix+prefix+"*", 500).Iterator()
+	active := 0
+	for iter.Next(ctx) {
+		active++
+	}
+	return active, iter.Err()
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"networkDev/database"
 	"networkDev/models"
 	"strconv"
@@ -19,6 +20,13 @@ type SettingsService struct {
 	cache map[string]string
 }
 
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// topicSettingsRefresh 设置刷新的实例间事件主题
+const topicSettingsRefresh = "settings.refresh"
+
 // ============================================================================
 // 全局变量
 // ============================================================================
@@ -26,6 +34,17 @@ type SettingsService struct {
 var settingsService *SettingsService
 var settingsOnce sync.Once
 
+// ============================================================================
+// 初始化
+// ============================================================================
+
+func init() {
+	// 其他实例修改设置后重新加载本实例的设置缓存
+	OnClusterEvent(topicSettingsRefresh, func([]string) {
+		GetSettingsService().loadAllSettings()
+	})
+}
+
 // ============================================================================
 // 公共函数
 // ============================================================================
@@ -104,9 +123,10 @@ func (s *SettingsService) GetBool(name string, defaultValue bool) bool {
 	return strValue == "1" || strValue == "true"
 }
 
-// RefreshCache 刷新设置缓存
-func (s *SettingsService) RefreshCache() {
+// RefreshCache 刷新设置缓存，并通知其他实例重新加载
+func (s *SettingsService) RefreshCache(ctx context.Context) {
 	s.loadAllSettings()
+	Broadcast(ctx, topicSettingsRefresh)
 }
 
 // GetSessionTimeout 获取会话超时时间（秒）
@@ -118,3 +138,13 @@ func (s *SettingsService) GetSessionTimeout() int {
 func (s *SettingsService) IsMaintenanceMode() bool {
 	return s.GetBool("maintenance_mode", false)
 }
+
+// GetMaintenanceMessage 获取维护说明
+func (s *SettingsService) GetMaintenanceMessage() string {
+	return s.GetString("maintenance_message", "系统维护中，请稍后再试")
+}
+
+// GetMaintenanceEndTime 获取预计维护结束时间（格式 2006-01-02 15:04:05，可为空）
+func (s *SettingsService) GetMaintenanceEndTime() string {
+	return s.GetString("maintenance_end_time", "")
+}
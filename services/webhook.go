@@ -0,0 +1,406 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils/tracing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// defaultWebhookTimeout 配置文件中缺少 webhook.timeout 时的单次推送超时时间
+	defaultWebhookTimeout = 10 * time.Second
+	// defaultWebhookMaxAttempts 配置文件中缺少 webhook.max_attempts 时的最大尝试次数
+	defaultWebhookMaxAttempts = 6
+	// defaultWebhookHistoryDays 配置文件中缺少 webhook.history_days 时的投递记录保留天数
+	defaultWebhookHistoryDays = 30
+	// retryBackoffBase 首次重试间隔，之后每次翻倍（Webhook 推送与邮件发送共用）
+	retryBackoffBase = time.Minute
+	// retryBackoffMax 重试间隔上限
+	retryBackoffMax = time.Hour
+	// webhookRetryBatch 重试任务每次处理的投递记录数量
+	webhookRetryBatch = 100
+	// maxWebhookResponseLength 投递记录中保存的响应内容最大长度
+	maxWebhookResponseLength = 1024
+	// webhookUserAgent 推送请求的 User-Agent
+	webhookUserAgent = "networkDev-Webhook/1.0"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// WebhookPayload 推送的JSON内容
+type WebhookPayload struct {
+	ID        string      `json:"id"`         // 投递ID，与 X-Webhook-ID 请求头一致，重试时不变
+	Event     string      `json:"event"`      // 事件类型
+	AppUUID   string      `json:"app_uuid"`   // 事件所属应用，与应用无关的事件为空
+	CreatedAt time.Time   `json:"created_at"` // 事件发生时间
+	Data      interface{} `json:"data"`       // 事件内容
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// webhookClient 推送使用的HTTP客户端，不跟随重定向，3xx 视为推送失败
+	webhookClient = &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	// errWebhookDeliveryTaken 投递记录已由其他请求或实例处理
+	errWebhookDeliveryTaken = errors.New("投递记录已由其他实例处理")
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// EmitWebhookEvent 触发业务事件，推送到订阅该事件的 Webhook
+// - appUUID 为事件所属应用，订阅该应用或全部应用的 Webhook 都会收到；与应用无关的事件传空字符串
+// - 投递记录写入数据库后在后台立即推送，失败时由 webhook_retry 任务按指数退避重试
+// - 推送在后台进行，不影响调用方的业务流程
+func EmitWebhookEvent(ctx context.Context, event, appUUID string, data interface{}) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		log := logrus.WithFields(logrus.Fields{"event": event, "app_uuid": appUUID})
+		deliveries, err := createWebhookDeliveries(ctx, event, appUUID, data)
+		if err != nil {
+			log.WithError(err).Error("创建Webhook投递记录失败")
+			return
+		}
+		for _, delivery := range deliveries {
+			if _, err := attemptWebhookDelivery(ctx, delivery.ID); err != nil && !errors.Is(err, errWebhookDeliveryTaken) {
+				log.WithError(err).WithField("delivery_id", delivery.ID).Warn("Webhook推送失败")
+			}
+		}
+	}()
+}
+
+// QueueWebhookEvent 同步写入业务事件的投递记录，不在当前进程推送
+// 供命令行等随即退出的进程使用，投递记录由服务端的 webhook_retry 任务推送
+func QueueWebhookEvent(ctx context.Context, event, appUUID string, data interface{}) error {
+	_, err := createWebhookDeliveries(ctx, event, appUUID, data)
+	return err
+}
+
+// AppStatusChangedData app.status_changed 事件内容
+func AppStatusChangedData(app *models.App, oldStatus int) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         app.ID,
+		"uuid":       app.UUID,
+		"name":       app.Name,
+		"status":     app.Status,
+		"old_status": oldStatus,
+	}
+}
+
+// SendTestWebhook 向 Webhook 同步发送一条测试事件并返回投递结果
+// 测试事件不受 Webhook 启用状态与订阅事件限制，失败时不重试
+func SendTestWebhook(ctx context.Context, webhook *models.Webhook) (*models.WebhookDelivery, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := newWebhookDelivery(webhook, models.WebhookEventTest, webhook.AppUUID, map[string]interface{}{
+		"webhook_id": webhook.UUID,
+		"message":    "这是一条测试事件",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WithContext(ctx).Create(delivery).Error; err != nil {
+		return nil, fmt.Errorf("写入投递记录失败: %w", err)
+	}
+	return attemptWebhookDelivery(ctx, delivery.ID)
+}
+
+// RetryWebhookDeliveries 推送到达重试时间的投递记录（webhook_retry 任务）
+func RetryWebhookDeliveries(ctx context.Context) (string, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return "", err
+	}
+
+	var ids []uint
+	err = db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("status = ? AND next_retry_at <= ?", models.WebhookDeliveryPending, time.Now()).
+		Order("next_retry_at").Limit(webhookRetryBatch).Pluck("id", &ids).Error
+	if err != nil {
+		return "", fmt.Errorf("查询待投递记录失败: %w", err)
+	}
+
+	attempted, succeeded := 0, 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		delivery, err := attemptWebhookDelivery(ctx, id)
+		if errors.Is(err, errWebhookDeliveryTaken) {
+			continue
+		}
+		attempted++
+		if err == nil && delivery.Status == models.WebhookDeliverySuccess {
+			succeeded++
+		}
+	}
+	return fmt.Sprintf("推送 %d 条，成功 %d 条", attempted, succeeded), nil
+}
+
+// SignWebhookPayload 计算推送签名
+// 签名内容为 "<X-Webhook-Timestamp>.<请求体>"，使用 Webhook 密钥做 HMAC-SHA256，结果为 "sha256=<16进制>"
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// createWebhookDeliveries 为订阅事件的启用中 Webhook 写入投递记录
+func createWebhookDeliveries(ctx context.Context, event, appUUID string, data interface{}) ([]*models.WebhookDelivery, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []string{"0"}
+	if appUUID != "" && appUUID != "0" {
+		scopes = append(scopes, appUUID)
+	}
+	var webhooks []models.Webhook
+	if err := db.WithContext(ctx).Where("status = ? AND app_uuid IN ?", models.WebhookStatusEnabled, scopes).Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("查询Webhook失败: %w", err)
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event) {
+			continue
+		}
+		delivery, err := newWebhookDelivery(&webhooks[i], event, appUUID, data)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+	if err := db.WithContext(ctx).Create(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("写入投递记录失败: %w", err)
+	}
+	return deliveries, nil
+}
+
+// newWebhookDelivery 生成待投递记录
+func newWebhookDelivery(webhook *models.Webhook, event, appUUID string, data interface{}) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	id := uuid.New().String()
+	if appUUID == "0" {
+		appUUID = ""
+	}
+	payload, err := json.Marshal(WebhookPayload{
+		ID:        id,
+		Event:     event,
+		AppUUID:   appUUID,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化事件内容失败: %w", err)
+	}
+	return &models.WebhookDelivery{
+		UUID:        id,
+		WebhookID:   webhook.ID,
+		Event:       event,
+		Payload:     string(payload),
+		Status:      models.WebhookDeliveryPending,
+		NextRetryAt: &now,
+	}, nil
+}
+
+// attemptWebhookDelivery 推送一次投递记录并保存结果
+// 推送前通过条件更新占用记录（尝试次数加一、下次投递时间推迟到推送超时之后），
+// 保证同一记录不会被多个实例同时推送；实例在推送中退出时，记录在占用到期后重新投递
+func attemptWebhookDelivery(ctx context.Context, id uint) (*models.WebhookDelivery, error) {
+	db, err := database.GetDB()
+	if err != nil {
+		return nil, err
+	}
+	db = db.WithContext(ctx)
+
+	now := time.Now()
+	timeout := webhookTimeout()
+	claim := db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_retry_at <= ?", id, models.WebhookDeliveryPending, now).
+		Updates(map[string]interface{}{
+			"attempts":      gorm.Expr("attempts + 1"),
+			"next_retry_at": now.Add(timeout + time.Minute),
+		})
+	if claim.Error != nil {
+		return nil, fmt.Errorf("占用投递记录失败: %w", claim.Error)
+	}
+	if claim.RowsAffected == 0 {
+		return nil, errWebhookDeliveryTaken
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.First(&delivery, id).Error; err != nil {
+		return nil, fmt.Errorf("查询投递记录失败: %w", err)
+	}
+
+	var webhook models.Webhook
+	var sendErr error
+	if err := db.First(&webhook, delivery.WebhookID).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("查询Webhook失败: %w", err)
+		}
+		sendErr = errors.New("Webhook已删除")
+	} else if webhook.Status != models.WebhookStatusEnabled && delivery.Event != models.WebhookEventTest {
+		sendErr = errors.New("Webhook已停用")
+	}
+
+	delivery.ResponseCode, delivery.ResponseBody, delivery.DurationMs = 0, "", 0
+	if sendErr == nil {
+		started := time.Now()
+		delivery.ResponseCode, delivery.ResponseBody, sendErr = sendWebhook(ctx, &webhook, &delivery, timeout)
+		delivery.DurationMs = time.Since(started).Milliseconds()
+	}
+
+	delivery.Error = ""
+	delivery.NextRetryAt = nil
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.WebhookDeliverySuccess
+	case delivery.Attempts >= webhookMaxAttempts() || delivery.Event == models.WebhookEventTest || webhook.ID == 0:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.Error = sendErr.Error()
+	default:
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.Error = sendErr.Error()
+		next := time.Now().Add(retryBackoff(delivery.Attempts))
+		delivery.NextRetryAt = &next
+	}
+
+	err = db.Model(&delivery).Updates(map[string]interface{}{
+		"status":        delivery.Status,
+		"response_code": delivery.ResponseCode,
+		"response_body": delivery.ResponseBody,
+		"error":         delivery.Error,
+		"duration_ms":   delivery.DurationMs,
+		"next_retry_at": delivery.NextRetryAt,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("保存投递结果失败: %w", err)
+	}
+	return &delivery, nil
+}
+
+// sendWebhook 发送推送请求，返回响应状态码与截断后的响应内容，非 2xx 响应视为失败
+func sendWebhook(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, timeout time.Duration) (code int, body string, err error) {
+	ctx, span := tracing.Start(ctx, "webhook.deliver",
+		attribute.String("webhook.event", delivery.Event),
+		attribute.Int("webhook.attempt", delivery.Attempts),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", fmt.Errorf("创建推送请求失败: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-ID", delivery.UUID)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(webhook.Secret, timestamp, payload))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("推送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseLength))
+	body = strings.ToValidUTF8(string(data), "")
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, body, fmt.Errorf("接收方返回状态码 %d", resp.StatusCode)
+	}
+	return resp.StatusCode, body, nil
+}
+
+// cleanupWebhookDeliveries 删除超过 webhook.history_days 天且已结束的投递记录
+func cleanupWebhookDeliveries(db *gorm.DB) (int64, error) {
+	historyDays := defaultWebhookHistoryDays
+	if viper.IsSet("webhook.history_days") {
+		historyDays = viper.GetInt("webhook.history_days")
+	}
+	if historyDays <= 0 {
+		return 0, nil
+	}
+	result := db.Where("status <> ? AND created_at < ?", models.WebhookDeliveryPending, time.Now().AddDate(0, 0, -historyDays)).
+		Delete(&models.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
+
+// retryBackoff 第 attempts 次尝试失败后的重试间隔：1、2、4、8... 分钟，最长 1 小时
+func retryBackoff(attempts int) time.Duration {
+	delay := retryBackoffBase
+	for i := 1; i < attempts && delay < retryBackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, retryBackoffMax)
+}
+
+// webhookTimeout 单次推送超时时间
+func webhookTimeout() time.Duration {
+	if seconds := viper.GetInt("webhook.timeout"); seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultWebhookTimeout
+}
+
+// webhookMaxAttempts 最大尝试次数
+func webhookMaxAttempts() int {
+	if attempts := viper.GetInt("webhook.max_attempts"); attempts > 0 {
+		return attempts
+	}
+	return defaultWebhookMaxAttempts
+}
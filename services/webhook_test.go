@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+
+	"github.com/spf13/viper"
+)
+
+// webhookReceiver 记录收到的推送并按设置的状态码响应
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+// ServeHTTP 实现 http.Handler
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	status := r.status
+	r.mu.Unlock()
+	if status == http.StatusFound {
+		w.Header().Set("Location", "/elsewhere")
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte("received"))
+}
+
+// createTestWebhook 创建 Webhook，测试结束后连同投递记录一起删除
+func createTestWebhook(t *testing.T, appUUID, url string, status int, events ...string) *models.Webhook {
+	t.Helper()
+	db, _ := database.GetDB()
+	webhook := &models.Webhook{
+		AppUUID: appUUID, Name: "test", URL: url, Secret: "whsec-" + appUUID,
+		Events: strings.Join(events, ","), Status: status,
+	}
+	if err := db.Create(webhook).Error; err != nil {
+		t.Fatal(err)
+	}
+	// 避免其他用例的事件与重试任务处理到该 Webhook
+	t.Cleanup(func() {
+		db.Where("webhook_id = ?", webhook.ID).Delete(&models.WebhookDelivery{})
+		db.Delete(webhook)
+	})
+	return webhook
+}
+
+// TestSignWebhookPayload 签名为密钥对 "<时间戳>.<请求体>" 的 HMAC-SHA256
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"event":"webhook.test"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhookPayload("secret", 1700000000, body); got != want {
+		t.Fatalf("SignWebhookPayload = %s，期望 %s", got, want)
+	}
+	if SignWebhookPayload("secret", 1700000001, body) == want {
+		t.Fatal("时间戳不同时签名应不同")
+	}
+	if SignWebhookPayload("other", 1700000000, body) == want {
+		t.Fatal("密钥不同时签名应不同")
+	}
+}
+
+// TestRetryBackoff 重试间隔按 1、2、4... 分钟翻倍，最长 1 小时
+func TestRetryBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		0: time.Minute, 1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute,
+		6: 32 * time.Minute, 7: time.Hour, 50: time.Hour,
+	}
+	for attempts, want := range cases {
+		if got := retryBackoff(attempts); got != want {
+			t.Errorf("retryBackoff(%d) = %s，期望 %s", attempts, got, want)
+		}
+	}
+}
+
+// TestWebhookDeliverySubscription 只为订阅该事件、订阅该应用或全部应用且已启用的 Webhook 写入投递记录
+func TestWebhookDeliverySubscription(t *testing.T) {
+	ctx := context.Background()
+	receiver := &webhookReceiver{status: http.StatusOK}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	appUUID := "WEBHOOK-SUB-APP"
+	scoped := createTestWebhook(t, appUUID, srv.URL, models.WebhookStatusEnabled, models.WebhookEventCardActivated)
+	global := createTestWebhook(t, "0", srv.URL, models.WebhookStatusEnabled, models.WebhookEventCardActivated, models.WebhookEventAdminLogin)
+	createTestWebhook(t, appUUID, srv.URL, models.WebhookStatusDisabled, models.WebhookEventCardActivated)
+	createTestWebhook(t, appUUID, srv.URL, models.WebhookStatusEnabled, models.WebhookEventAccountBanned)
+
+	deliveries, err := createWebhookDeliveries(ctx, models.WebhookEventCardActivated, appUUID, map[string]interface{}{"card": "K1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[uint]bool{}
+	for _, delivery := range deliveries {
+		got[delivery.WebhookID] = true
+	}
+	if len(deliveries) != 2 || !got[scoped.ID] || !got[global.ID] {
+		t.Fatalf("投递记录的 Webhook = %v，期望 %d 与 %d", got, scoped.ID, global.ID)
+	}
+
+	// 与应用无关的事件只推送到订阅全部应用的 Webhook，app_uuid 为空
+	deliveries, err = createWebhookDeliveries(ctx, models.WebhookEventAdminLogin, "", nil)
+	if err != nil || len(deliveries) != 1 || deliveries[0].WebhookID != global.ID {
+		t.Fatalf("admin.login 投递记录 = %+v, %v", deliveries, err)
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil || payload.AppUUID != "" || payload.ID != deliveries[0].UUID {
+		t.Fatalf("推送内容 = %s", deliveries[0].Payload)
+	}
+}
+
+// TestWebhookDeliverySigned 推送请求头包含事件、投递ID、时间戳与可验证的签名
+func TestWebhookDeliverySigned(t *testing.T) {
+	ctx := context.Background()
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	webhook := createTestWebhook(t, "WEBHOOK-SIGN-APP", srv.URL, models.WebhookStatusEnabled, models.WebhookEventAccountRegistered)
+	deliveries, err := createWebhookDeliveries(ctx, models.WebhookEventAccountRegistered, webhook.AppUUID, map[string]interface{}{"username": "alice"})
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("createWebhookDeliveries = %v, %v", deliveries, err)
+	}
+	delivery, err := attemptWebhookDelivery(ctx, deliveries[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != models.WebhookDeliverySuccess || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusNoContent || delivery.NextRetryAt != nil {
+		t.Fatalf("投递结果 = %+v", delivery)
+	}
+
+	if len(receiver.requests) != 1 {
+		t.Fatalf("收到 %d 次推送，期望 1", len(receiver.requests))
+	}
+	req, body := receiver.requests[0], receiver.bodies[0]
+	if req.Header.Get("X-Webhook-Event") != models.WebhookEventAccountRegistered || req.Header.Get("X-Webhook-ID") != delivery.UUID {
+		t.Fatalf("请求头 = %v", req.Header)
+	}
+	timestamp, err := strconv.ParseInt(req.Header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get("X-Webhook-Signature") != SignWebhookPayload(webhook.Secret, timestamp, body) {
+		t.Fatal("签名校验失败")
+	}
+	if string(body) != delivery.Payload {
+		t.Fatalf("请求体 = %s，期望 %s", body, delivery.Payload)
+	}
+
+	// 已完成的记录不再推送
+	if _, err := attemptWebhookDelivery(ctx, delivery.ID); !errors.Is(err, errWebhookDeliveryTaken) {
+		t.Fatalf("重复推送 err = %v", err)
+	}
+}
+
+// TestWebhookDeliveryRetry 推送失败后按退避间隔重试，达到最大尝试次数后标记为失败
+func TestWebhookDeliveryRetry(t *testing.T) {
+	ctx := context.Background()
+	db, _ := database.GetDB()
+	viper.Set("webhook.max_attempts", 2)
+	t.Cleanup(func() { viper.Set("webhook.max_attempts", 0) })
+
+	receiver := &webhookReceiver{status: http.StatusInternalServerError}
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	webhook := createTestWebhook(t, "WEBHOOK-RETRY-APP", srv.URL, models.WebhookStatusEnabled, models.WebhookEventDeviceRebind)
+	deliveries, err := createWebhookDeliveries(ctx, models.WebhookEventDeviceRebind, webhook.AppUUID, nil)
+	if err != nil || len(deliveries) != 1 {
+		t.Fatalf("createWebhookDeliveries = %v, %v", deliveries, err)
+	}
+	id := deliveries[0].ID
+
+	before := time.Now()
+	delivery, err := attemptWebhookDelivery(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != models.WebhookDeliveryPending || delivery.ResponseCode != http.StatusInternalServerError || delivery.Error == "" {
+		t.Fatalf("第一次投递结果 = %+v", delivery)
+	}
+	if delivery.NextRetryAt == nil || delivery.NextRetryAt.Before(before.Add(time.Minute)) || delivery.NextRetryAt.After(time.Now().Add(time.Minute)) {
+		t.Fatalf("下次投递时间 = %v，期望约 1 分钟后", delivery.NextRetryAt)
+	}
+
+	// 未到重试时间的记录不会被推送
+	if _, err := attemptWebhookDelivery(ctx, id); !errors.Is(err, errWebhookDeliveryTaken) {
+		t.Fatalf("未到重试时间 err = %v", err)
+	}
+	if msg, err := RetryWebhookDeliveries(ctx); err != nil || !strings.HasPrefix(msg, "推送 0 条") {
+		t.Fatalf("RetryWebhookDeliveries = %q, %v", msg, err)
+	}
+
+	// 到达重试时间后由重试任务推送；重定向视为失败
+	receiver.mu.Lock()
+	receiver.status = http.StatusFound
+	receiver.mu.Unlock()
+	db.Model(&models.WebhookDelivery{}).Where("id = ?", id).Update("next_retry_at", time.Now().Add(-time.Second))
+	if msg, err := RetryWebhookDeliveries(ctx); err != nil || msg != "推送 1 条，成功 0 条" {
+		t.Fatalf("RetryWebhookDeliveries = %q, %v", msg, err)
+	}
+	var final models.WebhookDelivery
+	db.First(&final, id)
+	if final.Status != models.WebhookDeliveryFailed || final.Attempts != 2 || final.ResponseCode != http.StatusFound || final.NextRetryAt != nil {
+		t.Fatalf("最终投递结果 = %+v", final)
+	}
+	if len(receiver.requests) != 2 {
+		t.Fatalf("收到 %d 次推送，期望 2", len(receiver.requests))
+	}
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// DefaultAdminPrefix 默认后台路径前缀
+const DefaultAdminPrefix = "/admin"
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// AdminPrefix 获取后台路径前缀（server.admin.prefix）
+// 返回值以 / 开头且不带结尾的 /，未配置时为 /admin
+func AdminPrefix() string {
+	prefix := strings.TrimRight(viper.GetString("server.admin.prefix"), "/")
+	if prefix == "" {
+		return DefaultAdminPrefix
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
+// AdminPath 拼接后台路径，如 AdminPath("/login") 返回 /admin/login
+func AdminPath(path string) string {
+	return AdminPrefix() + path
+}
+
+// IsAdminPath 判断请求路径是否属于后台
+func IsAdminPath(path string) bool {
+	prefix := AdminPrefix()
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// LRU 带过期时间的进程内LRU缓存，并发安全
+// - 超过容量时淘汰最久未访问的条目
+// - 条目超过 ttl 后视为不存在，ttl 为 0 表示不过期
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[K]*list.Element
+	order    *list.List
+}
+
+// lruEntry LRU缓存条目
+type lruEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// ============================================================================
+// 构造函数
+// ============================================================================
+
+// NewLRU 创建LRU缓存
+// capacity 小于等于 0 时缓存不保存任何条目（相当于关闭进程内缓存）
+func NewLRU[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[K]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Get 获取缓存值，不存在或已过期时返回 false
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	elem, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	entry := elem.Value.(*lruEntry[K, V])
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.removeElement(elem)
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set 写入缓存值
+func (c *LRU[K, V]) Set(key K, value V) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Delete 删除缓存值
+func (c *LRU[K, V]) Delete(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// DeleteFunc 删除满足条件的所有缓存值
+func (c *LRU[K, V]) DeleteFunc(match func(key K) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if match(key) {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// Purge 清空缓存
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+// Len 获取当前缓存条目数（包含尚未清理的过期条目）
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// removeElement 移除链表节点与索引，调用方需持有锁
+func (c *LRU[K, V]) removeElement(elem *list.Element) {
+	entry := c.order.Remove(elem).(*lruEntry[K, V])
+	delete(c.items, entry.key)
+}
@@ -0,0 +1,124 @@
+package cache_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"networkDev/utils/cache"
+)
+
+// TestLRUEvictsLeastRecentlyUsed 超过容量时淘汰最久未访问的条目，读取与覆盖写入都会刷新访问顺序
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU[string, int](2, 0)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a 应该存在")
+	}
+	c.Set("c", 3) // b 最久未访问
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b 应该被淘汰")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %v, %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d，期望 2", c.Len())
+	}
+
+	c.Set("c", 30) // 覆盖写入不增加条目并刷新访问顺序
+	c.Set("d", 4)  // a 最久未访问
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a 应该被淘汰")
+	}
+	if v, ok := c.Get("c"); !ok || v != 30 {
+		t.Fatalf("Get(c) = %v, %v", v, ok)
+	}
+}
+
+// TestLRUExpires 条目超过 ttl 后视为不存在，覆盖写入会重新计算过期时间
+func TestLRUExpires(t *testing.T) {
+	c := cache.NewLRU[string, int](10, 50*time.Millisecond)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	time.Sleep(30 * time.Millisecond)
+	c.Set("b", 20)
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a 应该已过期")
+	}
+	if v, ok := c.Get("b"); !ok || v != 20 {
+		t.Fatalf("Get(b) = %v, %v", v, ok)
+	}
+	// 过期条目在读取时清理
+	if c.Len() != 1 {
+		t.Fatalf("Len = %d，期望 1", c.Len())
+	}
+}
+
+// TestLRUZeroCapacity 容量为 0 时不保存任何条目
+func TestLRUZeroCapacity(t *testing.T) {
+	c := cache.NewLRU[string, int](0, 0)
+	c.Set("a", 1)
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Fatal("容量为 0 时不应保存条目")
+	}
+}
+
+// TestLRUDelete 按键、按条件删除与清空
+func TestLRUDelete(t *testing.T) {
+	c := cache.NewLRU[string, int](10, 0)
+	for i, key := range []string{"app:1", "app:2", "api:1", "api:2", "variable:1"} {
+		c.Set(key, i)
+	}
+
+	c.Delete("app:1", "missing")
+	if _, ok := c.Get("app:1"); ok {
+		t.Fatal("app:1 应该已删除")
+	}
+	c.DeleteFunc(func(key string) bool { return strings.HasPrefix(key, "api:") })
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d，期望 2", c.Len())
+	}
+	if _, ok := c.Get("app:2"); !ok {
+		t.Fatal("app:2 不应被删除")
+	}
+
+	c.Purge()
+	if _, ok := c.Get("variable:1"); ok || c.Len() != 0 {
+		t.Fatal("Purge 后缓存应为空")
+	}
+	// 清空后仍可正常写入与淘汰
+	c.Set("x", 1)
+	if v, ok := c.Get("x"); !ok || v != 1 {
+		t.Fatalf("Get(x) = %v, %v", v, ok)
+	}
+}
+
+// TestLRUConcurrent 并发读写时容量不超过上限（配合 -race 检查数据竞争）
+func TestLRUConcurrent(t *testing.T) {
+	c := cache.NewLRU[int, int](16, time.Minute)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := (g*1000 + i) % 64
+				c.Set(key, i)
+				c.Get(key)
+				if i%100 == 0 {
+					c.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if c.Len() > 16 {
+		t.Fatalf("Len = %d，超过容量 16", c.Len())
+	}
+}
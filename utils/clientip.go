@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// trustedProxies 可信代理网段（server.trusted_proxies）
+// 仅当请求来自可信代理时才解析 X-Forwarded-For / X-Real-IP，支持配置热重载
+var trustedProxies atomic.Pointer[[]*net.IPNet]
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// SetTrustedProxies 设置可信代理列表（IP或CIDR），为空表示不信任任何代理
+func SetTrustedProxies(entries []string) error {
+	networks, err := ParseIPNets(entries)
+	if err != nil {
+		return err
+	}
+	trustedProxies.Store(&networks)
+	return nil
+}
+
+// ClientIP 获取客户端真实IP地址
+// - 连接来自可信代理时，从 X-Forwarded-For 右侧开始跳过可信代理，取第一个不可信地址
+// - 没有 X-Forwarded-For 时使用 X-Real-IP
+// - 其他情况直接使用连接的远端地址，避免客户端伪造头部
+func ClientIP(c *gin.Context) string {
+	remote := RemoteIP(c.Request.RemoteAddr)
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
+	if forwarded := c.GetHeader("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			if i == 0 || !isTrustedProxy(hop) {
+				return hop
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(c.GetHeader("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return remote
+}
+
+// RemoteIP 从 host:port 形式的远端地址中提取IP
+func RemoteIP(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+// ParseIPNets 解析IP或CIDR列表，单个IP按 /32 或 /128 处理
+func ParseIPNets(entries []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("无效的IP或CIDR: %s", entry)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return networks, nil
+}
+
+// ContainsIP 判断IP是否位于任一网段内
+func ContainsIP(networks []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// isTrustedProxy 判断地址是否为可信代理
+func isTrustedProxy(ip string) bool {
+	networks := trustedProxies.Load()
+	return networks != nil && ContainsIP(*networks, ip)
+}
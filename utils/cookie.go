@@ -24,8 +24,8 @@ func CreateSecureCookie(name, value string, maxAge int) *http.Cookie {
 		MaxAge:   maxAge,
 	}
 
-	// 从配置读取安全设置
-	if viper.GetBool("security.cookie.secure") {
+	// 从配置读取安全设置，启用原生HTTPS时自动设置Secure
+	if viper.GetBool("security.cookie.secure") || viper.GetBool("server.tls.enabled") {
 		cookie.Secure = true
 	}
 
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// Redis 键前缀
+const (
+	counterKeyPrefix = "counter:" // 固定窗口计数器
+	onceKeyPrefix    = "once:"    // MarkOnce 判重标记
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// memoryCounter 进程内计数器
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// memoryCounters 未配置Redis时使用的进程内计数器
+	memoryCounters   = make(map[string]*memoryCounter)
+	memoryCountersMu sync.Mutex
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// IncrCounter 固定窗口计数器加一并返回当前窗口内的计数
+// - name: 计数器名称，多个实例使用相同名称共享计数
+// - window: 窗口长度，按 Unix 时间对齐（例如每分钟的计数从整分开始）
+// 未配置或无法连接Redis时使用进程内计数，只统计本实例
+func IncrCounter(ctx context.Context, name string, window time.Duration) (int64, error) {
+	now := time.Now()
+	start := now.Truncate(window)
+	key := counterKeyPrefix + name + ":" + strconv.FormatInt(start.Unix(), 10)
+
+	if client := GetRedis(); client != nil {
+		pipe := client.TxPipeline()
+		incr := pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, window)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return 0, err
+		}
+		return incr.Val(), nil
+	}
+
+	memoryCountersMu.Lock()
+	defer memoryCountersMu.Unlock()
+	for k, counter := range memoryCounters {
+		if !now.Before(counter.expiresAt) {
+			delete(memoryCounters, k)
+		}
+	}
+	counter, ok := memoryCounters[key]
+	if !ok {
+		counter = &memoryCounter{expiresAt: start.Add(window)}
+		memoryCounters[key] = counter
+	}
+	counter.count++
+	return counter.count, nil
+}
+
+// MarkOnce 在 ttl 内标记名称，首次标记返回 true，重复标记返回 false
+// 用于请求防重放等需要跨实例判重的场景；未配置或无法连接Redis时只在本实例内判重
+func MarkOnce(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	key := onceKeyPrefix + name
+
+	if client := GetRedis(); client != nil {
+		return client.SetNX(ctx, key, 1, ttl).Result()
+	}
+
+	now := time.Now()
+	memoryCountersMu.Lock()
+	defer memoryCountersMu.Unlock()
+	if counter, ok := memoryCounters[key]; ok && now.Before(counter.expiresAt) {
+		return false, nil
+	}
+	memoryCounters[key] = &memoryCounter{count: 1, expiresAt: now.Add(ttl)}
+	return true, nil
+}
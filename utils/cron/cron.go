@@ -0,0 +1,250 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 字段取值范围：分 时 日 月 周
+var fieldBounds = [5]struct {
+	name     string
+	min, max int
+}{
+	{"分钟", 0, 59},
+	{"小时", 0, 23},
+	{"日期", 1, 31},
+	{"月份", 1, 12},
+	{"星期", 0, 6},
+}
+
+// descriptors 预定义的表达式
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// maxSearchYears 查找下次执行时间的最大年数，超出时视为永不执行（如 2 月 30 日）
+const maxSearchYears = 5
+
+// ============================================================================
+// 接口定义
+// ============================================================================
+
+// Schedule 执行计划
+type Schedule interface {
+	// Next 返回晚于 t 的下一次执行时间，永不执行时返回零值
+	Next(t time.Time) time.Time
+}
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// specSchedule 标准五段式表达式（分 时 日 月 周）
+// 每个字段用位图表示允许的取值
+type specSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar/dowStar 日期或星期为 * 时的匹配规则与标准 cron 一致：
+	// 两者都有限制时满足任意一个即可
+	domStar, dowStar bool
+}
+
+// everySchedule 固定间隔（@every 1h30m）
+// 按 Unix 时间对齐，多个实例计算出的执行时间一致
+type everySchedule struct {
+	interval time.Duration
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Parse 解析执行计划
+// 支持：
+// - 五段式表达式：分 时 日 月 周，字段支持 *、数字、a-b 范围、a,b 列表、*/n 与 a-b/n 步长，星期 0 和 7 均表示周日
+// - 预定义表达式：@yearly、@monthly、@weekly、@daily、@hourly
+// - 固定间隔：@every <时长>，例如 @every 30m、@every 6h，最小 1 分钟
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.New("执行计划不能为空")
+	}
+
+	if strings.HasPrefix(spec, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("间隔格式错误: %w", err)
+		}
+		if interval < time.Minute {
+			return nil, errors.New("间隔不能小于1分钟")
+		}
+		return everySchedule{interval: interval}, nil
+	}
+	if expanded, ok := descriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("表达式必须包含5个字段（分 时 日 月 周）: %s", spec)
+	}
+
+	var bits [5]uint64
+	for i, field := range fields {
+		max := fieldBounds[i].max
+		if i == 4 {
+			// 星期允许使用 7 表示周日
+			max = 7
+		}
+		value, err := parseField(field, fieldBounds[i].min, max)
+		if err != nil {
+			return nil, fmt.Errorf("%s字段错误: %w", fieldBounds[i].name, err)
+		}
+		bits[i] = value
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &specSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// Validate 检查执行计划是否合法
+func Validate(spec string) error {
+	_, err := Parse(spec)
+	return err
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Next 返回晚于 t 的下一次执行时间（精确到分钟）
+func (s *specSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 判断日期是否满足日期与星期字段
+func (s *specSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next 返回晚于 t 的下一个间隔整数倍时间
+func (s everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.interval).Add(s.interval)
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// parseField 解析单个字段为位图
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("空的列表项: %s", field)
+		}
+
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			rangePart = part[:idx]
+			n, err := strconv.Atoi(part[idx+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("无效的步长: %s", part)
+			}
+			step = n
+		}
+
+		start, end := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = parseValue(bounds[0], min, max); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(bounds[1], min, max); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("范围起始值大于结束值: %s", rangePart)
+			}
+		default:
+			value, err := parseValue(rangePart, min, max)
+			if err != nil {
+				return 0, err
+			}
+			start = value
+			// 单个值带步长时（如 5/15）表示从该值到最大值
+			if step == 1 {
+				end = value
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue 解析字段中的单个数值并检查范围
+func parseValue(text string, min, max int) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("无效的数值: %s", text)
+	}
+	if value < min || value > max {
+		return 0, fmt.Errorf("数值 %d 超出范围 %d-%d", value, min, max)
+	}
+	return value, nil
+}
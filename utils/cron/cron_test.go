@@ -0,0 +1,124 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"networkDev/utils/cron"
+)
+
+// at 构造 UTC 时间
+func at(value string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TestParseErrors 格式错误的表达式返回错误
+func TestParseErrors(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@every 30s",
+		"@every abc",
+		"@hourly2",
+	}
+	for _, spec := range specs {
+		if _, err := cron.Parse(spec); err == nil {
+			t.Errorf("Parse(%q) 应该返回错误", spec)
+		}
+		if err := cron.Validate(spec); err == nil {
+			t.Errorf("Validate(%q) 应该返回错误", spec)
+		}
+	}
+}
+
+// TestNext 计算下一次执行时间（严格晚于给定时间）
+func TestNext(t *testing.T) {
+	cases := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"*/15 * * * *", "2026-10-18 10:07", "2026-10-18 10:15"},
+		{"*/15 * * * *", "2026-10-18 10:15", "2026-10-18 10:30"},
+		{"5/15 * * * *", "2026-10-18 10:21", "2026-10-18 10:35"},
+		{"0,30 9-10 * * *", "2026-10-18 10:30", "2026-10-19 09:00"},
+		{"0 9 * * 1-5", "2026-10-16 10:00", "2026-10-19 09:00"},
+		{"0 0 * * 7", "2026-10-16 10:00", "2026-10-18 00:00"},
+		{"0 0 * * 0", "2026-10-16 10:00", "2026-10-18 00:00"},
+		{"0 0 31 * *", "2026-11-01 00:00", "2026-12-31 00:00"},
+		{"0 0 29 2 *", "2026-03-01 00:00", "2028-02-29 00:00"},
+		{"@hourly", "2026-10-18 10:07", "2026-10-18 11:00"},
+		{"@daily", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"@weekly", "2026-10-18 00:00", "2026-10-25 00:00"},
+		{"@monthly", "2026-10-18 10:00", "2026-11-01 00:00"},
+		{"@yearly", "2026-10-18 10:00", "2027-01-01 00:00"},
+	}
+	for _, tc := range cases {
+		schedule, err := cron.Parse(tc.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.spec, err)
+		}
+		if got := schedule.Next(at(tc.from)); !got.Equal(at(tc.want)) {
+			t.Errorf("%q Next(%s) = %s，期望 %s", tc.spec, tc.from, got.Format("2006-01-02 15:04"), tc.want)
+		}
+	}
+}
+
+// TestNextDayOrWeekday 日与周同时限定时满足其一即执行
+func TestNextDayOrWeekday(t *testing.T) {
+	schedule, err := cron.Parse("0 0 13 * 5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := at("2026-10-01 00:00")
+	want := []string{"2026-10-02 00:00", "2026-10-09 00:00", "2026-10-13 00:00", "2026-10-16 00:00"}
+	for _, w := range want {
+		next = schedule.Next(next)
+		if !next.Equal(at(w)) {
+			t.Fatalf("Next = %s，期望 %s", next.Format("2006-01-02 15:04"), w)
+		}
+	}
+}
+
+// TestNextEvery 固定间隔按间隔对齐，最小间隔 1 分钟
+func TestNextEvery(t *testing.T) {
+	schedule, err := cron.Parse("@every 90m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.Next(at("2026-10-18 10:07")); !got.Equal(at("2026-10-18 10:30")) {
+		t.Fatalf("Next = %s，期望 10:30", got.Format("15:04"))
+	}
+	if got := schedule.Next(at("2026-10-18 10:30")); !got.Equal(at("2026-10-18 12:00")) {
+		t.Fatalf("Next = %s，期望 12:00", got.Format("15:04"))
+	}
+	if err := cron.Validate("@every 1m"); err != nil {
+		t.Fatalf("Validate(@every 1m): %v", err)
+	}
+}
+
+// TestNextImpossible 永远不会满足的日期返回零值
+func TestNextImpossible(t *testing.T) {
+	schedule, err := cron.Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.Next(at("2026-10-18 10:00")); !got.IsZero() {
+		t.Fatalf("Next = %s，期望零值", got)
+	}
+}
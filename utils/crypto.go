@@ -9,22 +9,46 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
+	"networkDev/config"
+
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// DefaultKeyID 单密钥配置（security.encryption_key）对应的密钥ID
+// 没有密钥ID前缀的历史密文同样使用该密钥解密
+const DefaultKeyID = "default"
+
+// devKeyID 未配置任何密钥时，开发环境使用的内置不安全密钥的ID
+// 与 DefaultKeyID 区分，配置正式密钥后用它加密的数据会明确报告为未知密钥，而不是被误认为 default 密钥的密文
+const devKeyID = "insecure-dev"
+
+// devKeySecret 开发环境内置的不安全密钥
+const devKeySecret = "default-secret"
+
+// keyIDSeparator 密文中密钥ID与密文主体之间的分隔符
+// base64 标准字母表不包含冒号，可以安全区分
+const keyIDSeparator = ":"
+
 // ============================================================================
 // 结构体定义
 // ============================================================================
 
 // CryptoManager 加密管理器，提供高性能的加密解密服务
+// 支持配置多个密钥：活动密钥用于加密，其余密钥仅用于解密历史密文
 type CryptoManager struct {
-	key    []byte
-	gcm    cipher.AEAD
-	mutex  sync.RWMutex
-	inited bool
+	keys     map[string]cipher.AEAD
+	activeID string
+	mutex    sync.RWMutex
+	inited   bool
 }
 
 // ============================================================================
@@ -39,8 +63,15 @@ var cryptoManager = &CryptoManager{}
 // ============================================================================
 
 // initCrypto 初始化加密管理器
-// 缓存密钥和GCM实例，避免重复创建
+// 缓存各密钥对应的GCM实例，避免重复创建
 func (cm *CryptoManager) initCrypto() error {
+	cm.mutex.RLock()
+	inited := cm.inited
+	cm.mutex.RUnlock()
+	if inited {
+		return nil
+	}
+
 	cm.mutex.Lock()
 	defer cm.mutex.Unlock()
 
@@ -48,47 +79,120 @@ func (cm *CryptoManager) initCrypto() error {
 		return nil
 	}
 
-	// 从配置中获取密钥
-	secret := viper.GetString("encryption_key")
+	keys, activeID, err := loadEncryptionKeys()
+	if err != nil {
+		return err
+	}
+
+	cm.keys = keys
+	cm.activeID = activeID
+	cm.inited = true
+	return nil
+}
+
+// loadEncryptionKeys 从配置中加载所有加密密钥
+// - security.encryption_key：单密钥配置，密钥ID为 default
+// - security.encryption_keys：多密钥配置，用于密钥轮换
+// - security.active_key_id：用于加密的活动密钥ID
+// 生产模式下未配置任何密钥时直接返回错误；开发环境使用ID为 insecure-dev 的内置密钥并记录警告
+func loadEncryptionKeys() (map[string]cipher.AEAD, string, error) {
+	keys := make(map[string]cipher.AEAD)
+
+	// 单密钥配置（兼容旧版顶层 encryption_key 配置）
+	secret := viper.GetString("security.encryption_key")
 	if secret == "" {
-		secret = "default-secret"
+		secret = viper.GetString("encryption_key")
+	}
+	if secret != "" {
+		gcm, err := newGCM(secret)
+		if err != nil {
+			return nil, "", err
+		}
+		keys[DefaultKeyID] = gcm
 	}
 
-	// 生成AES密钥
-	sum := sha256.Sum256([]byte(secret))
-	cm.key = sum[:]
+	// 多密钥配置
+	var entries []config.EncryptionKeyConfig
+	if err := viper.UnmarshalKey("security.encryption_keys", &entries); err != nil {
+		return nil, "", fmt.Errorf("解析 security.encryption_keys 失败: %w", err)
+	}
+	for _, entry := range entries {
+		if !config.IsValidEncryptionKeyID(entry.ID) {
+			return nil, "", fmt.Errorf("无效的加密密钥ID: %q", entry.ID)
+		}
+		if entry.Key == "" {
+			return nil, "", fmt.Errorf("加密密钥 %s 的值不能为空", entry.ID)
+		}
+		if entry.ID == devKeyID {
+			return nil, "", fmt.Errorf("加密密钥ID %s 为开发环境内置密钥保留", devKeyID)
+		}
+		if _, exists := keys[entry.ID]; exists {
+			return nil, "", fmt.Errorf("加密密钥ID重复: %s", entry.ID)
+		}
+		gcm, err := newGCM(entry.Key)
+		if err != nil {
+			return nil, "", err
+		}
+		keys[entry.ID] = gcm
+	}
 
-	// 创建AES cipher
-	block, err := aes.NewCipher(cm.key)
-	if err != nil {
-		return err
+	// 未配置任何密钥
+	if len(keys) == 0 {
+		if viper.GetString("app.mode") == "production" {
+			return nil, "", errors.New("生产模式下必须配置 security.encryption_key 或 security.encryption_keys")
+		}
+		logrus.WithField("key_id", devKeyID).Warn("未配置加密密钥，使用内置的不安全密钥，仅限开发环境使用；配置正式密钥后，用它加密的数据将无法解密")
+		gcm, err := newGCM(devKeySecret)
+		if err != nil {
+			return nil, "", err
+		}
+		keys[devKeyID] = gcm
 	}
 
-	// 创建GCM
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return err
+	// 确定活动密钥
+	activeID := viper.GetString("security.active_key_id")
+	if activeID == "" {
+		switch {
+		case keys[DefaultKeyID] != nil:
+			activeID = DefaultKeyID
+		case keys[devKeyID] != nil:
+			activeID = devKeyID
+		case len(entries) == 1:
+			activeID = entries[0].ID
+		default:
+			return nil, "", errors.New("配置了多个加密密钥时必须指定 security.active_key_id")
+		}
+	}
+	if _, ok := keys[activeID]; !ok {
+		return nil, "", fmt.Errorf("活动加密密钥 %s 不存在", activeID)
 	}
 
-	cm.gcm = gcm
-	cm.inited = true
-	return nil
+	return keys, activeID, nil
 }
 
-// ============================================================================
-// 加密解密函数
-// ============================================================================
+// newGCM 根据密钥字符串创建AES-256-GCM实例
+// 密钥经过SHA256派生为32字节，兼容任意长度的配置值
+func newGCM(secret string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte(secret))
 
-// EncryptString 字符串加密（AES-256-GCM）
-// 使用缓存的密钥和GCM实例，提高性能
-func EncryptString(plain string) (string, error) {
-	if err := cryptoManager.initCrypto(); err != nil {
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 使用活动密钥加密数据
+// 返回格式：<密钥ID>:<base64(nonce+密文)>
+func (cm *CryptoManager) seal(plain []byte) (string, error) {
+	if err := cm.initCrypto(); err != nil {
 		return "", err
 	}
 
-	cryptoManager.mutex.RLock()
-	gcm := cryptoManager.gcm
-	cryptoManager.mutex.RUnlock()
+	cm.mutex.RLock()
+	activeID := cm.activeID
+	gcm := cm.keys[activeID]
+	cm.mutex.RUnlock()
 
 	// 生成随机nonce
 	nonce := make([]byte, gcm.NonceSize())
@@ -97,31 +201,36 @@ func EncryptString(plain string) (string, error) {
 	}
 
 	// 加密
-	ciphertext := gcm.Seal(nil, nonce, []byte(plain), nil)
+	ciphertext := gcm.Seal(nil, nonce, plain, nil)
 	buf := append(nonce, ciphertext...)
-	return base64.StdEncoding.EncodeToString(buf), nil
+	return activeID + keyIDSeparator + base64.StdEncoding.EncodeToString(buf), nil
 }
 
-// DecryptString 字符串解密（AES-256-GCM）
-// 使用缓存的密钥和GCM实例，提高性能
-func DecryptString(enc string) (string, error) {
-	if err := cryptoManager.initCrypto(); err != nil {
-		return "", err
+// open 根据密文中的密钥ID选择密钥解密
+// 没有密钥ID前缀的历史密文使用 default 密钥
+func (cm *CryptoManager) open(enc string) ([]byte, error) {
+	if err := cm.initCrypto(); err != nil {
+		return nil, err
 	}
 
-	cryptoManager.mutex.RLock()
-	gcm := cryptoManager.gcm
-	cryptoManager.mutex.RUnlock()
+	keyID, payload := splitCiphertext(enc)
+
+	cm.mutex.RLock()
+	gcm, ok := cm.keys[keyID]
+	cm.mutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("未知的加密密钥ID: %s", keyID)
+	}
 
 	// 解码base64
-	data, err := base64.StdEncoding.DecodeString(enc)
+	data, err := base64.StdEncoding.DecodeString(payload)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 检查数据长度
 	if len(data) < gcm.NonceSize() {
-		return "", errors.New("ciphertext too short")
+		return nil, errors.New("ciphertext too short")
 	}
 
 	// 分离nonce和密文
@@ -129,75 +238,86 @@ func DecryptString(enc string) (string, error) {
 	ciphertext := data[gcm.NonceSize():]
 
 	// 解密
-	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return "", err
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// splitCiphertext 拆分密文中的密钥ID与密文主体
+func splitCiphertext(enc string) (keyID, payload string) {
+	if idx := strings.Index(enc, keyIDSeparator); idx > 0 {
+		return enc[:idx], enc[idx+1:]
 	}
+	return DefaultKeyID, enc
+}
 
-	return string(plain), nil
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// InitCrypto 初始化加密管理器并校验密钥配置
+// 服务启动时调用，配置错误时应终止启动
+func InitCrypto() error {
+	return cryptoManager.initCrypto()
 }
 
-// EncryptStringBatch 批量加密字符串
-// 减少锁竞争，提高批量处理性能
-func EncryptStringBatch(plains []string) ([]string, error) {
+// ActiveKeyID 获取当前用于加密的密钥ID
+func ActiveKeyID() (string, error) {
 	if err := cryptoManager.initCrypto(); err != nil {
-		return nil, err
+		return "", err
 	}
 
 	cryptoManager.mutex.RLock()
-	gcm := cryptoManager.gcm
-	cryptoManager.mutex.RUnlock()
+	defer cryptoManager.mutex.RUnlock()
+	return cryptoManager.activeID, nil
+}
+
+// CiphertextKeyID 获取密文所使用的密钥ID
+// 没有密钥ID前缀的历史密文返回 default
+func CiphertextKeyID(enc string) string {
+	keyID, _ := splitCiphertext(enc)
+	return keyID
+}
+
+// ============================================================================
+// 加密解密函数
+// ============================================================================
+
+// EncryptString 字符串加密（AES-256-GCM）
+// 使用活动密钥加密，密文带有密钥ID前缀
+func EncryptString(plain string) (string, error) {
+	return cryptoManager.seal([]byte(plain))
+}
+
+// DecryptString 字符串解密（AES-256-GCM）
+// 根据密文的密钥ID前缀选择对应的密钥
+func DecryptString(enc string) (string, error) {
+	plain, err := cryptoManager.open(enc)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
 
+// EncryptStringBatch 批量加密字符串
+func EncryptStringBatch(plains []string) ([]string, error) {
 	results := make([]string, len(plains))
 	for i, plain := range plains {
-		// 生成随机nonce
-		nonce := make([]byte, gcm.NonceSize())
-		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		enc, err := cryptoManager.seal([]byte(plain))
+		if err != nil {
 			return nil, err
 		}
-
-		// 加密
-		ciphertext := gcm.Seal(nil, nonce, []byte(plain), nil)
-		buf := append(nonce, ciphertext...)
-		results[i] = base64.StdEncoding.EncodeToString(buf)
+		results[i] = enc
 	}
 	return results, nil
 }
 
 // DecryptStringBatch 批量解密字符串
-// 减少锁竞争，提高批量处理性能
 func DecryptStringBatch(encs []string) ([]string, error) {
-	if err := cryptoManager.initCrypto(); err != nil {
-		return nil, err
-	}
-
-	cryptoManager.mutex.RLock()
-	gcm := cryptoManager.gcm
-	cryptoManager.mutex.RUnlock()
-
 	results := make([]string, len(encs))
 	for i, enc := range encs {
-		// 解码base64
-		data, err := base64.StdEncoding.DecodeString(enc)
+		plain, err := cryptoManager.open(enc)
 		if err != nil {
 			return nil, err
 		}
-
-		// 检查数据长度
-		if len(data) < gcm.NonceSize() {
-			return nil, errors.New("ciphertext too short")
-		}
-
-		// 分离nonce和密文
-		nonce := data[:gcm.NonceSize()]
-		ciphertext := data[gcm.NonceSize():]
-
-		// 解密
-		plain, err := gcm.Open(nil, nonce, ciphertext, nil)
-		if err != nil {
-			return nil, err
-		}
-
 		results[i] = string(plain)
 	}
 	return results, nil
@@ -223,62 +343,20 @@ func GenerateRandomSalt() (string, error) {
 // 将明文和盐值组合后进行加密，增强安全性
 // plain: 待加密的明文字符串
 // salt: 加密盐值
-// 返回: base64编码的密文字符串和错误信息
+// 返回: 带密钥ID前缀的密文字符串和错误信息
 func EncryptStringWithSalt(plain, salt string) (string, error) {
-	if err := cryptoManager.initCrypto(); err != nil {
-		return "", err
-	}
-
-	cryptoManager.mutex.RLock()
-	gcm := cryptoManager.gcm
-	cryptoManager.mutex.RUnlock()
-
 	// 将明文和盐值组合
 	combined := plain + salt
-
-	// 生成随机nonce
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
-	}
-
-	// 加密
-	ciphertext := gcm.Seal(nil, nonce, []byte(combined), nil)
-	buf := append(nonce, ciphertext...)
-	return base64.StdEncoding.EncodeToString(buf), nil
+	return cryptoManager.seal([]byte(combined))
 }
 
 // DecryptStringWithSalt 使用盐值进行字符串解密（AES-256-GCM）
 // 解密密文并移除盐值，返回原始明文
-// enc: base64编码的密文字符串
+// enc: 带密钥ID前缀的密文字符串（兼容无前缀的历史密文）
 // salt: 解密盐值
 // 返回: 解密后的明文字符串和错误信息
 func DecryptStringWithSalt(enc, salt string) (string, error) {
-	if err := cryptoManager.initCrypto(); err != nil {
-		return "", err
-	}
-
-	cryptoManager.mutex.RLock()
-	gcm := cryptoManager.gcm
-	cryptoManager.mutex.RUnlock()
-
-	// 解码base64
-	data, err := base64.StdEncoding.DecodeString(enc)
-	if err != nil {
-		return "", err
-	}
-
-	// 检查数据长度
-	if len(data) < gcm.NonceSize() {
-		return "", errors.New("ciphertext too short")
-	}
-
-	// 分离nonce和密文
-	nonce := data[:gcm.NonceSize()]
-	ciphertext := data[gcm.NonceSize():]
-
-	// 解密
-	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	plain, err := cryptoManager.open(enc)
 	if err != nil {
 		return "", err
 	}
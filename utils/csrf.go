@@ -5,6 +5,9 @@ import (
 	"crypto/subtle"
 	"encoding/base64"
 	"net/http"
+	"strings"
+
+	"networkDev/constants"
 
 	"github.com/gin-gonic/gin"
 )
@@ -83,6 +86,16 @@ func GetCSRFTokenFromCookie(c *gin.Context) string {
 	return cookie
 }
 
+// BearerToken 获取 Authorization: Bearer <令牌> 请求头中的令牌
+func BearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(c.GetHeader("Authorization")), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
 // ValidateCSRFToken 验证CSRF令牌
 func ValidateCSRFToken(c *gin.Context) bool {
 	// 获取Cookie中的令牌（服务器端存储的）
@@ -109,12 +122,7 @@ func CSRFProtection() gin.HandlerFunc {
 			// 生成新的CSRF令牌
 			token, err := GenerateCSRFToken()
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"code": 1,
-					"msg":  "Internal Server Error",
-					"data": nil,
-				})
-				c.Abort()
+				AbortWithError(c, constants.CodeInternalError, "")
 				return
 			}
 			SetCSRFToken(c, token)
@@ -122,14 +130,15 @@ func CSRFProtection() gin.HandlerFunc {
 			return
 		}
 
+		// 使用 Authorization 请求头认证的请求（API密钥）不依赖Cookie，浏览器不会自动携带，无需CSRF校验
+		if _, ok := BearerToken(c); ok {
+			c.Next()
+			return
+		}
+
 		// 对于POST、PUT、DELETE等修改性请求，验证CSRF令牌
 		if !ValidateCSRFToken(c) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"code": 1,
-				"msg":  "CSRF令牌验证失败",
-				"data": nil,
-			})
-			c.Abort()
+			AbortWithError(c, constants.CodeCSRFInvalid, "")
 			return
 		}
 
@@ -139,15 +148,15 @@ func CSRFProtection() gin.HandlerFunc {
 }
 
 // RequireCSRFToken 要求CSRF令牌的中间件（用于特定路由）
+// 与 CSRFProtection 相同，使用 Authorization 请求头认证的请求不做校验
 func RequireCSRFToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if _, ok := BearerToken(c); ok {
+			c.Next()
+			return
+		}
 		if !ValidateCSRFToken(c) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"code": 1,
-				"msg":  "CSRF令牌验证失败",
-				"data": nil,
-			})
-			c.Abort()
+			AbortWithError(c, constants.CodeCSRFInvalid, "")
 			return
 		}
 		c.Next()
@@ -172,22 +181,14 @@ func GetCSRFTokenForTemplate(c *gin.Context) string {
 // CSRFTokenHandler 专门用于获取CSRF令牌的API端点
 func CSRFTokenHandler(c *gin.Context) {
 	if c.Request.Method != http.MethodGet {
-		c.JSON(http.StatusMethodNotAllowed, gin.H{
-			"code": 1,
-			"msg":  "只支持GET请求",
-			"data": nil,
-		})
+		WriteErrorResponse(c, constants.CodeMethodNotAllowed, "只支持GET请求", nil)
 		return
 	}
 
 	// 生成新的CSRF令牌
 	token, err := GenerateCSRFToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code": 1,
-			"msg":  "生成CSRF令牌失败",
-			"data": nil,
-		})
+		WriteErrorResponse(c, constants.CodeInternalError, "生成CSRF令牌失败", nil)
 		return
 	}
 
@@ -195,11 +196,7 @@ func CSRFTokenHandler(c *gin.Context) {
 	SetCSRFToken(c, token)
 
 	// 返回令牌给前端
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "CSRF令牌生成成功",
-		"data": gin.H{
-			"csrf_token": token,
-		},
+	WriteSuccessResponse(c, "CSRF令牌生成成功", gin.H{
+		"csrf_token": token,
 	})
 }
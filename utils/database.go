@@ -4,13 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"networkDev/utils/tracing"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
@@ -157,12 +163,16 @@ func StartHealthCheck(db *gorm.DB, config *DatabaseConfig) {
 		defer ticker.Stop()
 
 		for range ticker.C {
-			if err := PingDatabase(db, config.PingTimeout); err != nil {
+			err := PingDatabase(db, config.PingTimeout)
+			if err != nil {
 				// 只在健康检查失败时输出错误日志
 				LogError("数据库健康检查失败", err, map[string]interface{}{
 					"ping_timeout": config.PingTimeout,
 				})
 			}
+			if hook := databaseHealthHook.Load(); hook != nil {
+				(*hook)(err)
+			}
 
 			// 记录连接池统计信息（仅在调试模式下）
 			if logrus.GetLevel() == logrus.DebugLevel {
@@ -177,6 +187,12 @@ func StartHealthCheck(db *gorm.DB, config *DatabaseConfig) {
 	// })
 }
 
+// SetDatabaseHealthHook 设置数据库健康检查结果回调
+// 每次定期检查后在健康检查goroutine中调用，err 为空表示检查通过，回调应尽快返回
+func SetDatabaseHealthHook(hook func(err error)) {
+	databaseHealthHook.Store(&hook)
+}
+
 // ValidateDatabaseConfig 验证数据库配置参数
 // 检查数据库配置参数的有效性，确保所有参数都在合理范围内
 func ValidateDatabaseConfig(config *DatabaseConfig) error {
@@ -216,6 +232,8 @@ var (
 	redisOnce sync.Once
 	// redisAvailable 标记Redis是否可用
 	redisAvailable bool
+	// databaseHealthHook 数据库健康检查结果回调
+	databaseHealthHook atomic.Pointer[func(error)]
 )
 
 // ============================================================================
@@ -264,6 +282,26 @@ func GetRedis() *redis.Client {
 	return redisClient
 }
 
+// IsRedisConfigured 判断是否配置了Redis
+func IsRedisConfigured() bool {
+	return viper.GetString("redis.host") != "" && viper.GetInt("redis.port") != 0
+}
+
+// PingRedis 实时检测Redis连通性
+// 未配置或初始化失败时返回错误
+func PingRedis(timeout time.Duration) error {
+	if !IsRedisConfigured() {
+		return errors.New("未配置Redis")
+	}
+	client := GetRedis()
+	if client == nil {
+		return errors.New("Redis不可用")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return client.Ping(ctx).Err()
+}
+
 // IsRedisAvailable 判断Redis是否可用
 func IsRedisAvailable() bool {
 	if redisClient == nil {
@@ -278,7 +316,18 @@ func IsRedisAvailable() bool {
 // - ttl: 过期时间
 // - loader: 当缓存不存在时的加载函数（一般执行数据库查询）
 // 返回：目标对象指针和错误
-func RedisGetOrSet[T any](ctx context.Context, key string, ttl time.Duration, loader func() (*T, error)) (*T, error) {
+// 启用链路追踪时记录 redis.get_or_set span，标注是否命中缓存，loader 的耗时同样计入其中
+func RedisGetOrSet[T any](ctx context.Context, key string, ttl time.Duration, loader func() (*T, error)) (result *T, err error) {
+	ctx, span := tracing.Start(ctx, "redis.get_or_set",
+		attribute.String("db.system", "redis"),
+		attribute.String("cache.key", key),
+	)
+	hit := false
+	defer func() {
+		span.SetAttributes(attribute.Bool("cache.hit", hit))
+		tracing.End(span, err)
+	}()
+
 	// 如果Redis不可用则直接调用加载函数
 	if !IsRedisAvailable() {
 		return loader()
@@ -293,6 +342,7 @@ func RedisGetOrSet[T any](ctx context.Context, key string, ttl time.Duration, lo
 	if err == nil {
 		var out T
 		if uerr := json.Unmarshal(data, &out); uerr == nil {
+			hit = true
 			return &out, nil
 		}
 		// 反序列化失败时视为未命中，继续加载
@@ -300,6 +350,7 @@ func RedisGetOrSet[T any](ctx context.Context, key string, ttl time.Duration, lo
 	} else if err != redis.Nil {
 		// 非空且非不存在的错误，记录告警但不中断
 		logrus.WithError(err).WithField("key", key).Warn("读取Redis缓存失败")
+		span.AddEvent("读取Redis缓存失败", trace.WithAttributes(attribute.String("error", err.Error())))
 	}
 
 	// 加载数据
@@ -315,6 +366,7 @@ func RedisGetOrSet[T any](ctx context.Context, key string, ttl time.Duration, lo
 	if b, merr := json.Marshal(val); merr == nil {
 		if serr := client.Set(ctx, key, b, ttl).Err(); serr != nil {
 			logrus.WithError(serr).WithField("key", key).Warn("写入Redis缓存失败")
+			span.AddEvent("写入Redis缓存失败", trace.WithAttributes(attribute.String("error", serr.Error())))
 		}
 	}
 	return val, nil
@@ -323,7 +375,7 @@ func RedisGetOrSet[T any](ctx context.Context, key string, ttl time.Duration, lo
 // RedisDel 删除一个或多个Redis键（当Redis不可用时静默返回）
 // - ctx: 上下文
 // - keys: 需要删除的键名
-func RedisDel(ctx context.Context, keys ...string) error {
+func RedisDel(ctx context.Context, keys ...string) (err error) {
 	// 如果Redis不可用则直接返回
 	if !IsRedisAvailable() {
 		return nil
@@ -335,6 +387,13 @@ func RedisDel(ctx context.Context, keys ...string) error {
 	if len(keys) == 0 {
 		return nil
 	}
+
+	ctx, span := tracing.Start(ctx, "redis.del",
+		attribute.String("db.system", "redis"),
+		attribute.StringSlice("cache.keys", keys),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	if _, err := client.Del(ctx, keys...).Result(); err != nil {
 		logrus.WithError(err).WithField("keys", keys).Warn("删除Redis键失败")
 		return err
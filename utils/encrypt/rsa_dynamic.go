@@ -42,6 +42,15 @@ func NewRSADynamicEncrypt(publicKeyPEM, privateKeyPEM string) (*RSADynamicEncryp
 	}, nil
 }
 
+// NewRSADynamicEncryptFromKeys 使用已解析的密钥创建RAS动态加密实例
+// 适用于缓存解析结果的场景，避免每次调用都重新解析PEM
+func NewRSADynamicEncryptFromKeys(publicKey *rsa.PublicKey, privateKey *rsa.PrivateKey) *RSADynamicEncrypt {
+	return &RSADynamicEncrypt{
+		publicKey:  publicKey,
+		privateKey: privateKey,
+	}
+}
+
 // GenerateRSADynamicKeyPair 生成RSA动态加密密钥对
 func GenerateRSADynamicKeyPair(bits int) (string, string, error) {
 	return GenerateRSAKeyPairPEM(bits) // 使用公共函数
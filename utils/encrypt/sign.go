@@ -0,0 +1,29 @@
+package encrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// ClientRequestSign 计算客户端接口请求签名
+// 签名内容为应用UUID、接口类型、时间戳、随机串与加密后的数据按换行符拼接，
+// 使用应用密钥做 HMAC-SHA256，结果为小写十六进制
+func ClientRequestSign(secret, appUUID string, apiType int, timestamp int64, nonce, data string) string {
+	return hmacSHA256Hex(secret, appUUID, strconv.Itoa(apiType), strconv.FormatInt(timestamp, 10), nonce, data)
+}
+
+// ClientResponseSign 计算客户端接口响应签名
+// 签名内容为加密后的返回数据、响应时间戳与请求随机串按换行符拼接，客户端据此确认响应来自服务端且对应本次请求
+func ClientResponseSign(secret, payload string, timestamp int64, nonce string) string {
+	return hmacSHA256Hex(secret, payload, strconv.FormatInt(timestamp, 10), nonce)
+}
+
+// hmacSHA256Hex 按换行符拼接各部分后计算 HMAC-SHA256
+func hmacSHA256Hex(secret string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
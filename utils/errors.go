@@ -1,58 +1,22 @@
 package utils
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
 	"runtime"
 	"time"
 
+	"networkDev/constants"
+	"networkDev/utils/logger"
+
 	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-// ============================================================================
-// 结构体定义
-// ============================================================================
-
-// ErrorResponse 统一的错误响应结构
-// 用于标准化API错误响应格式
-type ErrorResponse struct {
-	Success   bool        `json:"success"`              // 请求是否成功，错误响应时固定为false
-	Message   string      `json:"message"`              // 错误消息描述
-	ErrorCode string      `json:"error_code,omitempty"` // 错误代码，用于客户端识别错误类型
-	Data      interface{} `json:"data"`                 // 附加数据，可为空
-	Timestamp int64       `json:"timestamp"`            // 响应时间戳
-}
-
-// SuccessResponse 统一的成功响应结构
-// 用于标准化API成功响应格式
-type SuccessResponse struct {
-	Success   bool        `json:"success"`   // 请求是否成功，成功响应时固定为true
-	Message   string      `json:"message"`   // 成功消息描述
-	Data      interface{} `json:"data"`      // 响应数据
-	Timestamp int64       `json:"timestamp"` // 响应时间戳
-}
-
 // ============================================================================
 // 常量定义
 // ============================================================================
 
-// ErrorCode 错误代码常量
-// 定义标准化的错误代码，用于客户端识别和处理不同类型的错误
-const (
-	ErrCodeInvalidRequest   = "INVALID_REQUEST"   // 无效请求，通常是请求参数格式错误
-	ErrCodeUnauthorized     = "UNAUTHORIZED"      // 未授权，需要登录或token无效
-	ErrCodeForbidden        = "FORBIDDEN"         // 禁止访问，权限不足
-	ErrCodeNotFound         = "NOT_FOUND"         // 资源不存在
-	ErrCodeConflict         = "CONFLICT"          // 资源冲突，如重复创建
-	ErrCodeInternalError    = "INTERNAL_ERROR"    // 服务器内部错误
-	ErrCodeDatabaseError    = "DATABASE_ERROR"    // 数据库操作错误
-	ErrCodeValidationError  = "VALIDATION_ERROR"  // 数据验证错误
-	ErrCodeTokenExpired     = "TOKEN_EXPIRED"     // 令牌已过期
-	ErrCodeInsufficientData = "INSUFFICIENT_DATA" // 数据不足，缺少必要信息
-)
-
 // LogLevel 日志级别
 // 定义不同的日志记录级别
 type LogLevel int
@@ -67,51 +31,14 @@ const (
 // LogEntry 日志条目结构
 // 包含完整的日志信息，用于结构化日志记录
 type LogEntry struct {
-	Level     LogLevel    `json:"level"`             // 日志级别
-	Message   string      `json:"message"`           // 日志消息
-	Error     string      `json:"error,omitempty"`   // 错误信息，仅在错误日志中存在
-	Context   interface{} `json:"context,omitempty"` // 上下文信息，额外的结构化数据
-	Timestamp time.Time   `json:"timestamp"`         // 日志时间戳
-	File      string      `json:"file"`              // 源文件路径
-	Line      int         `json:"line"`              // 源文件行号
-}
-
-// ============================================================================
-// 响应函数
-// ============================================================================
-
-// WriteErrorResponse 写入错误响应
-// c: Gin上下文
-// statusCode: HTTP状态码
-// message: 错误消息
-// errorCode: 错误代码
-// data: 附加数据
-func WriteErrorResponse(c *gin.Context, statusCode int, message, errorCode string, data interface{}) {
-	response := ErrorResponse{
-		Success:   false,
-		Message:   message,
-		ErrorCode: errorCode,
-		Data:      data,
-		Timestamp: time.Now().Unix(),
-	}
-
-	c.JSON(statusCode, response)
-}
-
-// WriteSuccessResponse 写入成功响应
-// c: Gin上下文
-// statusCode: HTTP状态码
-// message: 成功消息
-// data: 响应数据
-func WriteSuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
-	response := SuccessResponse{
-		Success:   true,
-		Message:   message,
-		Data:      data,
-		Timestamp: time.Now().Unix(),
-	}
-
-	c.JSON(statusCode, response)
+	Level     LogLevel    `json:"level"`                // 日志级别
+	Message   string      `json:"message"`              // 日志消息
+	Error     string      `json:"error,omitempty"`      // 错误信息，仅在错误日志中存在
+	Context   interface{} `json:"context,omitempty"`    // 上下文信息，额外的结构化数据
+	Timestamp time.Time   `json:"timestamp"`            // 日志时间戳
+	File      string      `json:"file"`                 // 源文件路径
+	Line      int         `json:"line"`                 // 源文件行号
+	RequestID string      `json:"request_id,omitempty"` // 请求ID，仅在处理请求时存在
 }
 
 // ============================================================================
@@ -124,18 +51,19 @@ func WriteSuccessResponse(c *gin.Context, statusCode int, message string, data i
 // operation: 操作描述
 func HandleDatabaseError(c *gin.Context, err error, operation string) {
 	if err == gorm.ErrRecordNotFound {
-		LogWarn(fmt.Sprintf("Record not found during %s", operation), map[string]interface{}{
+		logRequest(c, LogLevelWarn, fmt.Sprintf("Record not found during %s", operation), nil, map[string]interface{}{
 			"operation": operation,
 			"error":     err.Error(),
 		})
-		WriteErrorResponse(c, 404, "记录不存在", ErrCodeNotFound, nil)
+		WriteErrorResponse(c, constants.CodeNotFound, "记录不存在", nil)
 		return
 	}
 
-	LogError(fmt.Sprintf("Database error during %s", operation), err, map[string]interface{}{
+	errorStr := err.Error()
+	logRequest(c, LogLevelError, fmt.Sprintf("Database error during %s", operation), &errorStr, map[string]interface{}{
 		"operation": operation,
 	})
-	WriteErrorResponse(c, 500, "数据库操作失败", ErrCodeDatabaseError, nil)
+	WriteErrorResponse(c, constants.CodeDatabaseError, "", nil)
 }
 
 // HandleValidationError 处理验证错误
@@ -143,18 +71,18 @@ func HandleDatabaseError(c *gin.Context, err error, operation string) {
 // message: 验证错误消息
 // details: 验证错误详情
 func HandleValidationError(c *gin.Context, message string, details interface{}) {
-	LogWarn("Validation error: "+message, map[string]interface{}{
+	logRequest(c, LogLevelWarn, "Validation error: "+message, nil, map[string]interface{}{
 		"details": details,
 	})
-	WriteErrorResponse(c, 400, message, ErrCodeValidationError, details)
+	WriteErrorResponse(c, constants.CodeValidationError, message, details)
 }
 
 // HandleUnauthorizedError 处理未授权错误
 // c: Gin上下文
 // message: 错误消息
 func HandleUnauthorizedError(c *gin.Context, message string) {
-	LogWarn("Unauthorized access: "+message, nil)
-	WriteErrorResponse(c, 401, message, ErrCodeUnauthorized, nil)
+	logRequest(c, LogLevelWarn, "Unauthorized access: "+message, nil, nil)
+	WriteErrorResponse(c, constants.CodeUnauthorized, message, nil)
 }
 
 // HandleInternalError 处理内部错误
@@ -162,10 +90,11 @@ func HandleUnauthorizedError(c *gin.Context, message string) {
 // err: 错误
 // operation: 操作描述
 func HandleInternalError(c *gin.Context, err error, operation string) {
-	LogError(fmt.Sprintf("Internal error during %s", operation), err, map[string]interface{}{
+	errorStr := err.Error()
+	logRequest(c, LogLevelError, fmt.Sprintf("Internal error during %s", operation), &errorStr, map[string]interface{}{
 		"operation": operation,
 	})
-	WriteErrorResponse(c, 500, "服务器内部错误", ErrCodeInternalError, nil)
+	WriteErrorResponse(c, constants.CodeInternalError, "", nil)
 }
 
 // ============================================================================
@@ -238,42 +167,39 @@ func createLogEntry(level LogLevel, message string, errorStr *string, context in
 	return entry
 }
 
+// logRequest 记录处理请求过程中的日志，附带请求ID
+func logRequest(c *gin.Context, level LogLevel, message string, errorStr *string, context interface{}) {
+	logEntry := createLogEntry(level, message, errorStr, context)
+	logEntry.RequestID = logger.RequestID(c)
+	printLog(logEntry)
+}
+
 // printLog 打印日志
+// 通过logrus输出，格式跟随 log.format 配置（text/json）
 // entry: 日志条目
 func printLog(entry LogEntry) {
-	levelStr := getLevelString(entry.Level)
-	timestamp := entry.Timestamp.Format("2006-01-02 15:04:05")
-
-	logMessage := fmt.Sprintf("[%s] %s %s", levelStr, timestamp, entry.Message)
-
+	fields := log.Fields{
+		"source": fmt.Sprintf("%s:%d", entry.File, entry.Line),
+	}
 	if entry.Error != "" {
-		logMessage += fmt.Sprintf(" | Error: %s", entry.Error)
+		fields["error"] = entry.Error
 	}
-
 	if entry.Context != nil {
-		contextJSON, _ := json.Marshal(entry.Context)
-		logMessage += fmt.Sprintf(" | Context: %s", string(contextJSON))
+		fields["context"] = entry.Context
+	}
+	if entry.RequestID != "" {
+		fields[logger.RequestIDKey] = entry.RequestID
 	}
 
-	logMessage += fmt.Sprintf(" | %s:%d", entry.File, entry.Line)
-
-	log.Println(logMessage)
-}
-
-// getLevelString 获取日志级别字符串
-// level: 日志级别
-// 返回: 级别字符串
-func getLevelString(level LogLevel) string {
-	switch level {
-	case LogLevelInfo:
-		return "INFO"
+	logEntry := log.WithFields(fields).WithTime(entry.Timestamp)
+	switch entry.Level {
 	case LogLevelWarn:
-		return "WARN"
+		logEntry.Warn(entry.Message)
 	case LogLevelError:
-		return "ERROR"
+		logEntry.Error(entry.Message)
 	case LogLevelDebug:
-		return "DEBUG"
+		logEntry.Debug(entry.Message)
 	default:
-		return "UNKNOWN"
+		logEntry.Info(entry.Message)
 	}
 }
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// lockKeyPrefix 分布式锁在Redis中的键前缀
+const lockKeyPrefix = "lock:"
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// DistributedLock 基于Redis的分布式锁
+// 未配置或无法连接Redis时视为单实例部署，加锁总是成功
+type DistributedLock struct {
+	key   string
+	token string
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// releaseLockScript 仅当锁仍由当前持有者持有时才删除，避免误删其他实例在锁过期后获得的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// TryLock 尝试获取分布式锁，不等待
+// - name: 锁名称，多个实例使用相同名称竞争同一把锁
+// - ttl: 锁的有效期，持有者崩溃时锁在到期后自动释放
+// 返回：获取成功时返回锁对象与 true；锁被其他实例持有时返回 false
+func TryLock(ctx context.Context, name string, ttl time.Duration) (*DistributedLock, bool, error) {
+	lock := &DistributedLock{key: lockKeyPrefix + name, token: uuid.NewString()}
+
+	client := GetRedis()
+	if client == nil {
+		return lock, true, nil
+	}
+
+	ok, err := client.SetNX(ctx, lock.key, lock.token, ttl).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return nil, false, nil
+	}
+	return lock, true, nil
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Release 释放分布式锁
+// 锁已过期或已被其他实例获得时不做任何操作
+func (l *DistributedLock) Release(ctx context.Context) error {
+	if l == nil {
+		return errors.New("锁对象为空")
+	}
+	client := GetRedis()
+	if client == nil {
+		return nil
+	}
+	return releaseLockScript.Run(ctx, client, []string{l.key}, l.token).Err()
+}
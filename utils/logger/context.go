@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"regexp"
+
+	"networkDev/utils/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// RequestIDHeader 请求ID的HTTP头部
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey 请求ID在gin上下文与日志字段中的键
+	RequestIDKey = "request_id"
+	// TraceIDKey 链路追踪ID在日志字段中的键
+	TraceIDKey = "trace_id"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// requestIDPattern 允许沿用的上游请求ID格式，避免日志注入与超长头部
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// EnsureRequestID 获取或生成请求ID
+// 沿用上游（网关、客户端）传入的合法 X-Request-ID，否则生成新的UUID；
+// 结果写入上下文并通过响应头返回，便于客户端反馈问题时提供
+func EnsureRequestID(c *gin.Context) string {
+	id := c.GetHeader(RequestIDHeader)
+	if !requestIDPattern.MatchString(id) {
+		id = uuid.NewString()
+	}
+	c.Set(RequestIDKey, id)
+	c.Header(RequestIDHeader, id)
+	return id
+}
+
+// RequestID 获取当前请求的请求ID，不存在时返回空字符串
+func RequestID(c *gin.Context) string {
+	if c == nil {
+		return ""
+	}
+	return c.GetString(RequestIDKey)
+}
+
+// FromContext 获取带有请求ID字段的日志条目
+// 处理请求过程中记录日志应使用该方法，保证日志可以按请求ID串联；
+// 启用链路追踪时同时附带 trace_id，便于从日志跳转到对应的链路
+func FromContext(c *gin.Context) *log.Entry {
+	entry := log.NewEntry(log.StandardLogger())
+	if id := RequestID(c); id != "" {
+		entry = entry.WithField(RequestIDKey, id)
+	}
+	if c != nil && c.Request != nil {
+		if traceID := tracing.TraceID(c.Request.Context()); traceID != "" {
+			entry = entry.WithField(TraceIDKey, traceID)
+		}
+	}
+	return entry
+}
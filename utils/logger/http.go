@@ -1,11 +1,73 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+const (
+	// FormatText 文本日志格式
+	FormatText = "text"
+	// FormatJSON JSON日志格式，每行一个JSON对象
+	FormatJSON = "json"
 )
 
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// jsonFormat HTTP请求日志是否使用JSON格式
+var jsonFormat atomic.Bool
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// RequestLog HTTP请求日志内容
+type RequestLog struct {
+	Method    string        // HTTP请求方法
+	Path      string        // 请求路径（含查询参数）
+	ClientIP  string        // 客户端IP地址
+	Status    int           // HTTP状态码
+	Duration  time.Duration // 请求处理时长
+	UserAgent string        // 用户代理字符串
+	RequestID string        // 请求ID
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// SetFormat 设置日志格式（text/json）
+// 同时作用于logrus与HTTP请求日志，未知格式按 text 处理
+func SetFormat(format string) {
+	if format == FormatJSON {
+		log.SetFormatter(&log.JSONFormatter{TimestampFormat: time.RFC3339})
+		jsonFormat.Store(true)
+		return
+	}
+	log.SetFormatter(&log.TextFormatter{
+		TimestampFormat: "2006-01-02 15:04:05",
+		FullTimestamp:   true,
+		ForceColors:     false,
+		DisableColors:   true,
+	})
+	jsonFormat.Store(false)
+}
+
+// ============================================================================
+// 方法函数
+// ============================================================================
+
 // LogRequest 记录HTTP请求日志 - 使用标准Apache Common Log Format
 // 格式: IP - - [timestamp] "METHOD path HTTP/1.1" status_code response_size
 // method: HTTP请求方法
@@ -27,24 +89,61 @@ func (l *Logger) LogRequest(method, path, clientIP string, statusCode int, durat
 // referer: 引用页面（已废弃，保留参数兼容性）
 // userAgent: 用户代理字符串
 func (l *Logger) LogRequestWithHeaders(method, path, clientIP string, statusCode int, duration time.Duration, referer, userAgent string) {
-	// 格式化时间戳为Apache标准格式
-	timestamp := time.Now().Format("02/Jan/2006:15:04:05 -0700")
+	l.LogHTTPRequest(RequestLog{
+		Method:    method,
+		Path:      path,
+		ClientIP:  clientIP,
+		Status:    statusCode,
+		Duration:  duration,
+		UserAgent: userAgent,
+	})
+}
 
+// LogHTTPRequest 记录HTTP请求日志
+// - text 格式：修改的Apache Log Format，末尾追加 request_id
+// - json 格式：每行一个JSON对象，字段与logrus的JSON日志保持一致（time/level/msg）
+// 请求日志不受日志级别影响，始终输出
+func (l *Logger) LogHTTPRequest(r RequestLog) {
 	// 处理空值
-	if userAgent == "" {
-		userAgent = "-"
+	if r.UserAgent == "" {
+		r.UserAgent = "-"
 	}
 
+	if jsonFormat.Load() {
+		line, err := json.Marshal(map[string]interface{}{
+			"time":        time.Now().Format(time.RFC3339),
+			"level":       "info",
+			"msg":         "HTTP请求",
+			"method":      r.Method,
+			"path":        r.Path,
+			"client_ip":   r.ClientIP,
+			"status":      r.Status,
+			"duration_ms": r.Duration.Milliseconds(),
+			"user_agent":  r.UserAgent,
+			RequestIDKey:  r.RequestID,
+		})
+		if err == nil {
+			l.writeHTTPLog(string(line))
+		}
+		return
+	}
+
+	// 格式化时间戳为Apache标准格式
+	timestamp := time.Now().Format("02/Jan/2006:15:04:05 -0700")
+
 	// 构建修改的HTTP Log格式（完全移除Referer字段）
 	logLine := fmt.Sprintf(`%s - - [%s] "%s %s HTTP/1.1" %d - "%s" %dms`,
-		clientIP,
+		r.ClientIP,
 		timestamp,
-		method,
-		path,
-		statusCode,
-		userAgent,
-		duration.Milliseconds(),
+		r.Method,
+		r.Path,
+		r.Status,
+		r.UserAgent,
+		r.Duration.Milliseconds(),
 	)
+	if r.RequestID != "" {
+		logLine += " request_id=" + r.RequestID
+	}
 
 	// 直接输出到标准输出和日志文件，不使用logrus格式化
 	l.writeHTTPLog(logLine)
@@ -0,0 +1,231 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"networkDev/database"
+	"networkDev/models"
+	"networkDev/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// namespace 指标名称前缀
+const namespace = "networkdev"
+
+// unmatchedRoute 未匹配任何路由的请求使用的路由标签，避免原始路径导致标签基数膨胀
+const unmatchedRoute = "unmatched"
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// Registry 应用指标注册表，由 /metrics 接口输出
+	Registry = prometheus.NewRegistry()
+
+	// httpRequestsTotal HTTP请求总数
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP请求总数",
+	}, []string{"method", "route", "status"})
+
+	// httpRequestDuration HTTP请求耗时分布
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP请求处理耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// adminLoginsTotal 管理员登录次数
+	adminLoginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "admin_logins_total",
+		Help:      "管理员登录次数",
+	}, []string{"result"})
+
+	// clientLoginsTotal 客户端登录次数（按应用与接口类型）
+	clientLoginsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "client_logins_total",
+		Help:      "客户端登录次数",
+	}, []string{"app", "api_type"})
+
+	// cardActivationsTotal 卡密激活次数（按应用）
+	cardActivationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "card_activations_total",
+		Help:      "卡密激活次数",
+	}, []string{"app"})
+
+	// activeSessions 当前在线会话数（按应用）
+	activeSessions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_sessions",
+		Help:      "当前在线会话数",
+	}, []string{"app"})
+)
+
+// ============================================================================
+// 初始化
+// ============================================================================
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		adminLoginsTotal,
+		clientLoginsTotal,
+		cardActivationsTotal,
+		activeSessions,
+		newStateCollector(),
+	)
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// ObserveHTTPRequest 记录一次HTTP请求
+// route 应为路由模板（如 /admin/api/apps/list），为空时归为 unmatched
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// RecordAdminLogin 记录管理员登录结果
+func RecordAdminLogin(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	adminLoginsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordClientLogin 记录客户端登录（按应用UUID与接口类型）
+func RecordClientLogin(appUUID string, apiType int) {
+	clientLoginsTotal.WithLabelValues(appUUID, models.GetAPITypeName(apiType)).Inc()
+}
+
+// RecordCardActivation 记录卡密激活
+func RecordCardActivation(appUUID string) {
+	cardActivationsTotal.WithLabelValues(appUUID).Inc()
+}
+
+// SetActiveSessions 设置应用当前在线会话数
+func SetActiveSessions(appUUID string, count int) {
+	activeSessions.WithLabelValues(appUUID).Set(float64(count))
+}
+
+// ============================================================================
+// 状态采集器
+// ============================================================================
+
+// stateCollector 在抓取时实时读取的状态指标
+// 包括数据库连接池统计、Redis可用性与卡密数量
+type stateCollector struct {
+	dbOpen        *prometheus.Desc
+	dbInUse       *prometheus.Desc
+	dbIdle        *prometheus.Desc
+	dbWaitCount   *prometheus.Desc
+	dbWaitSeconds *prometheus.Desc
+	redisUp       *prometheus.Desc
+	cards         *prometheus.Desc
+}
+
+// newStateCollector 创建状态采集器
+func newStateCollector() *stateCollector {
+	return &stateCollector{
+		dbOpen:        prometheus.NewDesc(namespace+"_db_open_connections", "数据库已打开的连接数", nil, nil),
+		dbInUse:       prometheus.NewDesc(namespace+"_db_in_use_connections", "数据库正在使用的连接数", nil, nil),
+		dbIdle:        prometheus.NewDesc(namespace+"_db_idle_connections", "数据库空闲连接数", nil, nil),
+		dbWaitCount:   prometheus.NewDesc(namespace+"_db_wait_count_total", "等待数据库连接的总次数", nil, nil),
+		dbWaitSeconds: prometheus.NewDesc(namespace+"_db_wait_duration_seconds_total", "等待数据库连接的总耗时（秒）", nil, nil),
+		redisUp:       prometheus.NewDesc(namespace+"_redis_up", "Redis是否可用（未配置时为0）", nil, nil),
+		cards:         prometheus.NewDesc(namespace+"_cards", "卡密数量（按应用与状态）", []string{"app", "status"}, nil),
+	}
+}
+
+// Describe 输出指标描述
+func (sc *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sc.dbOpen
+	ch <- sc.dbInUse
+	ch <- sc.dbIdle
+	ch <- sc.dbWaitCount
+	ch <- sc.dbWaitSeconds
+	ch <- sc.redisUp
+	ch <- sc.cards
+}
+
+// Collect 采集当前状态
+func (sc *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	if db, err := database.GetDB(); err == nil {
+		if stats, err := utils.GetConnectionStats(db); err == nil {
+			ch <- prometheus.MustNewConstMetric(sc.dbOpen, prometheus.GaugeValue, float64(stats.OpenConnections))
+			ch <- prometheus.MustNewConstMetric(sc.dbInUse, prometheus.GaugeValue, float64(stats.InUse))
+			ch <- prometheus.MustNewConstMetric(sc.dbIdle, prometheus.GaugeValue, float64(stats.Idle))
+			ch <- prometheus.MustNewConstMetric(sc.dbWaitCount, prometheus.CounterValue, float64(stats.WaitCount))
+			ch <- prometheus.MustNewConstMetric(sc.dbWaitSeconds, prometheus.CounterValue, stats.WaitDuration.Seconds())
+		}
+
+		var rows []struct {
+			AppUUID string
+			Status  int
+			Count   int64
+		}
+		if err := db.Model(&models.Card{}).Select("app_uuid, status, COUNT(*) AS count").Group("app_uuid, status").Scan(&rows).Error; err == nil {
+			for _, row := range rows {
+				ch <- prometheus.MustNewConstMetric(sc.cards, prometheus.GaugeValue, float64(row.Count), row.AppUUID, models.CardStatusText(row.Status))
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(sc.redisUp, prometheus.GaugeValue, redisUp())
+}
+
+// redisUp 实时检测Redis可用性
+func redisUp() float64 {
+	if err := utils.PingRedis(time.Second); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// ============================================================================
+// HTTP处理器
+// ============================================================================
+
+// Handler 创建指标输出处理器
+// token 非空时要求请求携带 Authorization: Bearer <token>
+func Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"networkDev/constants"
+	"networkDev/utils/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// 列表响应格式
+const (
+	// ListFormatLayui LayUI表格格式：{code, msg, count, data: [...]}
+	ListFormatLayui = "layui"
+	// ListFormatStandard 标准格式：{code, msg, data: {items, total, page, page_size}}
+	ListFormatStandard = "standard"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// Response 统一响应结构
+// 所有后台接口与客户端接口使用同一结构，code 取自 constants 错误码目录，0 表示成功
+type Response struct {
+	Code      int         `json:"code"`                 // 数字错误码
+	Msg       string      `json:"msg"`                  // 提示信息
+	Error     string      `json:"error,omitempty"`      // 字符串错误码，仅失败时返回
+	Data      interface{} `json:"data"`                 // 响应数据
+	Count     *int64      `json:"count,omitempty"`      // 总条数，仅LayUI表格格式的列表响应返回
+	RequestID string      `json:"request_id,omitempty"` // 请求ID，用于关联服务端日志
+}
+
+// ListData 标准格式的列表数据
+type ListData struct {
+	Items    interface{} `json:"items"`     // 当前页数据
+	Total    int64       `json:"total"`     // 总条数
+	Page     int         `json:"page"`      // 当前页码
+	PageSize int         `json:"page_size"` // 每页条数
+}
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+// listFormatOverride 运行时设置的列表响应格式（配置热重载），为 nil 时读取配置
+var listFormatOverride atomic.Pointer[string]
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// NewResponse 按错误码构建响应，message 为空时使用错误码的默认提示
+func NewResponse(c *gin.Context, ec constants.ErrorCode, message string, data interface{}) Response {
+	if message == "" {
+		message = ec.Message
+	}
+	resp := Response{
+		Code:      ec.Code,
+		Msg:       message,
+		Data:      data,
+		RequestID: logger.RequestID(c),
+	}
+	if ec.Code != constants.CodeOK.Code {
+		resp.Error = ec.Name
+	}
+	return resp
+}
+
+// WriteSuccessResponse 写入成功响应
+func WriteSuccessResponse(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusOK, NewResponse(c, constants.CodeOK, message, data))
+}
+
+// WriteCreatedResponse 写入创建成功响应（HTTP 201）
+func WriteCreatedResponse(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusCreated, NewResponse(c, constants.CodeOK, message, data))
+}
+
+// WriteErrorResponse 写入错误响应，HTTP状态码取自错误码目录
+func WriteErrorResponse(c *gin.Context, ec constants.ErrorCode, message string, data interface{}) {
+	c.JSON(ec.Status, NewResponse(c, ec, message, data))
+}
+
+// AbortWithError 终止请求并写入错误响应，供中间件使用
+func AbortWithError(c *gin.Context, ec constants.ErrorCode, message string) {
+	c.AbortWithStatusJSON(ec.Status, NewResponse(c, ec, message, nil))
+}
+
+// WriteListResponse 写入分页列表响应
+// 格式由请求参数 format（layui/standard）指定，未指定时使用配置 server.list_format
+func WriteListResponse(c *gin.Context, items interface{}, total int64, page, pageSize int) {
+	resp := NewResponse(c, constants.CodeOK, "success", items)
+	if ListFormat(c) == ListFormatStandard {
+		resp.Data = ListData{Items: items, Total: total, Page: page, PageSize: pageSize}
+	} else {
+		resp.Count = &total
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListFormat 获取当前请求使用的列表响应格式
+func ListFormat(c *gin.Context) string {
+	if format := strings.ToLower(strings.TrimSpace(c.Query("format"))); IsValidListFormat(format) {
+		return format
+	}
+	if format := listFormatOverride.Load(); format != nil {
+		return *format
+	}
+	if format := viper.GetString("server.list_format"); IsValidListFormat(format) {
+		return format
+	}
+	return ListFormatLayui
+}
+
+// SetListFormat 运行时切换默认列表响应格式，供配置热重载调用
+func SetListFormat(format string) {
+	if !IsValidListFormat(format) {
+		format = ListFormatLayui
+	}
+	listFormatOverride.Store(&format)
+}
+
+// IsValidListFormat 判断列表响应格式是否有效
+func IsValidListFormat(format string) bool {
+	return format == ListFormatLayui || format == ListFormatStandard
+}
@@ -0,0 +1,205 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// 结构体定义
+// ============================================================================
+
+// CertReloader 支持热更新的证书加载器
+// - 通过 GetCertificate/GetConfigForClient 向 tls.Config 提供当前证书与客户端CA
+// - 重新加载只影响新建立的连接，已建立的连接不会中断
+// - 新证书加载失败时继续使用旧证书
+type CertReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+// ============================================================================
+// 构造函数
+// ============================================================================
+
+// NewCertReloader 创建证书加载器并立即加载证书
+// clientCAFile 为空表示不校验客户端证书
+func NewCertReloader(certFile, keyFile, clientCAFile string) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ============================================================================
+// 结构体方法
+// ============================================================================
+
+// Reload 重新加载证书与客户端CA
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("加载证书失败: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("读取客户端CA失败: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return errors.New("客户端CA文件中没有有效的证书")
+		}
+	}
+
+	modTimes := r.currentModTimes()
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+// GetCertificate 返回当前证书，供 tls.Config.GetCertificate 使用
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// TLSConfig 基于当前证书生成 tls.Config
+// 客户端CA通过 GetConfigForClient 在每次握手时读取，热更新后立即生效
+func (r *CertReloader) TLSConfig(minVersion uint16, clientAuth tls.ClientAuthType) *tls.Config {
+	base := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: r.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+	if r.clientCAFile == "" {
+		return base
+	}
+
+	base.ClientAuth = clientAuth
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		cfg := base.Clone()
+		cfg.GetConfigForClient = nil
+		cfg.ClientCAs = r.clientCAs
+		return cfg, nil
+	}
+	return base
+}
+
+// Watch 定期检查证书文件的修改时间，变化时自动重新加载
+// 适用于 certbot 等工具自动续期证书的场景；stop 关闭时退出
+func (r *CertReloader) Watch(interval time.Duration, stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if !r.changed() {
+					continue
+				}
+				if err := r.Reload(); err != nil {
+					logrus.WithError(err).Error("证书文件已变化，但重新加载失败，继续使用旧证书")
+					continue
+				}
+				logrus.Info("检测到证书文件变化，已重新加载证书")
+			}
+		}
+	}()
+}
+
+// changed 判断证书相关文件是否发生变化
+func (r *CertReloader) changed() bool {
+	current := r.currentModTimes()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for file, modTime := range current {
+		if !modTime.Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentModTimes 获取证书相关文件的修改时间
+func (r *CertReloader) currentModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if file == "" {
+			continue
+		}
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+	return modTimes
+}
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// ParseMinVersion 解析 TLS 最低版本（1.0/1.1/1.2/1.3），为空时默认 1.2
+func ParseMinVersion(version string) (uint16, error) {
+	switch strings.TrimSpace(version) {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	default:
+		return 0, fmt.Errorf("不支持的TLS版本: %s", version)
+	}
+}
+
+// ParseClientAuth 解析客户端证书校验方式
+// - none：不校验（默认）
+// - request：客户端提供证书时校验
+// - require：必须提供有效的客户端证书
+func ParseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("不支持的客户端证书校验方式: %s", mode)
+	}
+}
@@ -0,0 +1,97 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// gormSpanKey 查询span在 gorm 实例上下文中的键
+const gormSpanKey = "tracing:span"
+
+// maxStatementLength 记录到span中的SQL最大长度，避免批量写入产生超大span
+const maxStatementLength = 2048
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// RegisterGormCallbacks 为GORM注册链路追踪回调
+// - 每次 Create/Query/Update/Delete/Row/Raw 操作创建一个子span，父span取自 db.WithContext 传入的上下文
+// - span 中记录数据库类型、表名、SQL（参数使用占位符，不包含实际值）与影响行数
+// - 查询的 span 在扫描结果之前创建，加密字段的解密耗时同样计入其中
+func RegisterGormCallbacks(db *gorm.DB) error {
+	cb := db.Callback()
+	registrations := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"gorm.create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"gorm.query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"gorm.update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"gorm.delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"gorm.row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"gorm.raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+
+	for _, r := range registrations {
+		if err := r.before("tracing:before_"+r.name, startGormSpan(r.name)); err != nil {
+			return err
+		}
+		if err := r.after("tracing:after_"+r.name, endGormSpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// startGormSpan 创建数据库操作的span，并替换语句上下文使后续操作成为其子span
+func startGormSpan(name string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx, span := Start(tx.Statement.Context, name,
+			attribute.String("db.system", tx.Dialector.Name()),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+// endGormSpan 记录SQL与执行结果并结束span
+func endGormSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	statement := tx.Statement.SQL.String()
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+	span.SetAttributes(
+		attribute.String("db.statement", statement),
+		attribute.String("db.sql.table", tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.RowsAffected),
+	)
+
+	// 记录不存在属于正常业务结果，不视为失败
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ============================================================================
+// 常量定义
+// ============================================================================
+
+// instrumentationName 本服务创建span时使用的追踪器名称
+const instrumentationName = "networkDev"
+
+// 导出方式
+const (
+	// ExporterOTLP 通过 OTLP/HTTP 发送
+	ExporterOTLP = "otlp"
+	// ExporterStdout 以JSON格式输出到标准输出
+	ExporterStdout = "stdout"
+	// ExporterFile 以JSON格式追加写入文件
+	ExporterFile = "file"
+)
+
+// ============================================================================
+// 全局变量
+// ============================================================================
+
+var (
+	// provider 已启用的追踪提供者，未启用时为 nil
+	provider *sdktrace.TracerProvider
+	// output file 导出方式打开的文件，关闭时释放
+	output io.Closer
+	// enabled 是否已启用链路追踪
+	enabled atomic.Bool
+)
+
+// ============================================================================
+// 公共函数
+// ============================================================================
+
+// Init 根据 tracing.* 配置初始化链路追踪
+// 未启用时保持 OpenTelemetry 默认的空实现，埋点不产生任何开销以外的影响
+// 启用后同时设置 W3C Trace Context 传播格式，沿用网关或客户端传入的 traceparent
+func Init() error {
+	if !viper.GetBool("tracing.enabled") {
+		return nil
+	}
+
+	exporter, err := newExporter()
+	if err != nil {
+		return err
+	}
+
+	serviceName := viper.GetString("tracing.service_name")
+	if serviceName == "" {
+		serviceName = instrumentationName
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+	))
+	if err != nil {
+		return fmt.Errorf("创建追踪资源信息失败: %w", err)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(viper.GetFloat64("tracing.sample_ratio")))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	enabled.Store(true)
+	return nil
+}
+
+// Shutdown 导出尚未发送的span并关闭追踪提供者
+// 应在HTTP服务关闭之后调用，保证最后的请求也能被记录
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	enabled.Store(false)
+	err := provider.Shutdown(ctx)
+	if output != nil {
+		err = errors.Join(err, output.Close())
+	}
+	return err
+}
+
+// Enabled 判断链路追踪是否已启用
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Tracer 获取本服务的追踪器
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start 创建子span
+// ctx 中没有span时创建新的链路；未启用追踪时返回空实现的span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束span，err 不为空时记录错误并将span标记为失败
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID 获取上下文中span的链路ID，没有有效span时返回空字符串
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// ============================================================================
+// 私有函数
+// ============================================================================
+
+// newExporter 按 tracing.exporter 创建span导出器
+func newExporter() (sdktrace.SpanExporter, error) {
+	switch exporter := viper.GetString("tracing.exporter"); exporter {
+	case ExporterOTLP, "":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(viper.GetString("tracing.endpoint"))}
+		if viper.GetBool("tracing.insecure") {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		// 创建时不会连接接收端，接收端不可用时只在导出时记录错误
+		return otlptracehttp.New(context.Background(), opts...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		path := viper.GetString("tracing.file")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("创建追踪文件目录失败: %w", err)
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("打开追踪文件失败: %w", err)
+		}
+		output = file
+		return stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, fmt.Errorf("不支持的导出方式: %s", exporter)
+	}
+}
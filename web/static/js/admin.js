@@ -6,6 +6,9 @@ const rootPath = (function (src) {
   return src.substring(0, src.lastIndexOf('/') + 1);
 })();
 
+// 后台路径前缀（由布局页根据 server.admin.prefix 注入）
+const ADMIN_PREFIX = window.ADMIN_PREFIX || '/admin';
+
 // CSRF令牌管理
 const CSRFManager = {
   // 缓存的CSRF令牌
@@ -18,7 +21,7 @@ const CSRFManager = {
     }
     
     try {
-      const response = await fetch('/admin/api/csrf-token', {
+      const response = await fetch(ADMIN_PREFIX + '/api/csrf-token', {
         method: 'GET',
         headers: {
           'X-Requested-With': 'XMLHttpRequest'
@@ -54,6 +57,18 @@ const CSRFManager = {
   }
 };
 
+// 统一解析列表响应，兼容两种列表格式（见 server.list_format）：
+// - LayUI表格格式：{code, msg, count, data: [...]}
+// - 标准格式：{code, msg, data: {items, total, page, page_size}}
+window.parseListResponse = function (res) {
+  res = res || {};
+  const data = res.data;
+  if (data && !Array.isArray(data) && Array.isArray(data.items)) {
+    return { code: res.code, msg: res.msg || '', count: data.total || 0, data: data.items };
+  }
+  return { code: res.code, msg: res.msg || '', count: res.count || 0, data: data || [] };
+};
+
 // 增强的fetch函数，自动添加CSRF令牌
 window.fetchWithCSRF = async function(url, options = {}) {
   const headers = await CSRFManager.addCSRFHeader(options.headers || {});
@@ -82,8 +97,11 @@ loadScript(layuijs, function () {
     .extend({
       drawer: 'drawer/drawer',
     });
-  layui.use(['drawer', 'colorMode'], function () {
-    const { $, element, form, layer, util, dropdown, drawer, colorMode } = layui;
+  layui.use(['drawer', 'colorMode', 'table'], function () {
+    const { $, element, form, layer, util, dropdown, drawer, colorMode, table } = layui;
+
+    // 所有数据表格默认使用统一的列表响应解析
+    table.set({ parseData: window.parseListResponse });
 
     const APPERANCE_KEY = 'layui-theme-demo-prefer-dark';
 
@@ -193,7 +211,7 @@ loadScript(layuijs, function () {
     };
     
     // 执行退出登录（箭头函数写法）
-    // 功能：弹出确认框 -> 显示加载层 -> 调用 /admin/logout -> 依据 code===0 判断
+    // 功能：弹出确认框 -> 显示加载层 -> 调用后台登出接口 -> 依据 code===0 判断
     const handleLogout = () => {
       layer.confirm('确定要退出登录吗？', {
         icon: 3,
@@ -207,7 +225,7 @@ loadScript(layuijs, function () {
         });
         
         // 调用登出接口
-        fetchWithCSRF('/admin/logout', {
+        fetchWithCSRF(ADMIN_PREFIX + '/logout', {
           method: 'POST',
           headers: {
             'Content-Type': 'application/json',
@@ -225,7 +243,7 @@ loadScript(layuijs, function () {
               time: 1000
             }, () => {
               // 跳转到登录页或后端返回的地址
-              const redirect = (data && data.data && data.data.redirect) || '/admin/login';
+              const redirect = (data && data.data && data.data.redirect) || ADMIN_PREFIX + '/login';
               window.location.href = redirect;
             });
           } else {
@@ -301,9 +319,19 @@ loadScript(layuijs, function () {
         'site-description': '站点描述：网站的简要描述，用于SEO和搜索引擎结果展示',
         'site-logo': '站点Logo：网站的标志图片路径，建议使用SVG格式',
         // 系统配置 (settings.html)
-        'maintenance-mode': '维护模式：开启后网站将进入维护模式，普通用户无法访问',
+        'maintenance-mode': '维护模式：开启后网站将进入维护模式，普通用户无法访问，客户端接口返回维护提示，已登录的管理员不受影响',
+        'maintenance-message': '维护说明：维护模式下展示在维护页面，并随客户端接口的维护响应一同返回',
+        'maintenance-end-time': '结束时间：预计维护结束的时间，仅用于展示和告知客户端，不会自动关闭维护模式',
+        'app-maintenance-mode': '应用维护：开启后仅暂停该应用的客户端接口，不影响其他应用和后台管理',
+        'app-maintenance-message': '维护说明：返回给该应用客户端的维护提示，留空则使用系统设置中的维护说明',
         'default-user-role': '默认角色：新注册用户的默认权限级别，0为管理员，1为普通成员',
         'session-timeout': '会话超时：用户登录会话的有效时间，单位为秒，超时后需要重新登录',
+        // 告警通知 (settings.html)
+        'notify-login-new-ip': '新IP登录：管理员从未登录过的IP登录时，向订阅该事件的通知渠道发送告警',
+        'admin-login-ips': '已知IP：管理员最近登录过的IP（最多20个），清空后下次登录只记录IP不发送告警',
+        'notify-login-failure': '登录失败：同一应用在统计窗口内客户端登录失败达到阈值时发送一次告警，阈值为0时关闭',
+        'notify-mail-to': '告警邮箱：达到所选级别的告警同时发送到这些邮箱，需要在配置文件中启用SMTP',
+        'mail-expiry-remind-days': '到期提醒：每天检查即将到期的未使用卡密，按应用汇总后发送到告警邮箱',
         // 页脚与备案信息 (settings.html)
         'footer-text': '页脚文本：显示在网站底部的版权信息或其他文本',
         'icp-record': 'ICP备案：网站的ICP备案号，中国大陆网站必须显示',
@@ -369,7 +397,7 @@ loadScript(layuijs, function () {
     function routerTo({
       elem = '#router-view',
       path = 'dashboard',
-      prefix = 'admin/', //路由前缀
+      prefix = ADMIN_PREFIX + '/', //路由前缀
       suffix = '', //路由后缀
     } = {}) {
       var routerView = $(elem);